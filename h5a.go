@@ -0,0 +1,418 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// #include <string.h>
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"unsafe"
+)
+
+// ---- H5A: Attribute Interface ----
+
+// Attribute is a small named value attached to a group, dataset or named
+// datatype. Attributes are stored in the header of their object, whose
+// messages hold at most 64KB, unless the object stores its attributes
+// densely, which the 1.8 file format does for larger attributes. Larger
+// attributes therefore need objects created in a file created or opened
+// with WithLargeAttributes, or a file access property list with
+// SetLibverBounds(F_LIBVER_V18, F_LIBVER_LATEST) or later bounds; creating
+// them on other objects fails with an *AttributeSizeError.
+type Attribute struct {
+	id C.hid_t
+}
+
+// compactAttrMax is the largest object header message, in bytes, and so
+// the largest attribute the earliest file format can store.
+const compactAttrMax = 64 * 1024
+
+// AttributeSizeError reports an attribute too large for the header of its
+// object, which does not support dense attribute storage.
+type AttributeSizeError struct {
+	Name string
+	Size uint // in bytes
+	Err  error
+}
+
+func (e *AttributeSizeError) Error() string {
+	return fmt.Sprintf("hdf5: attribute %q of %d bytes does not fit in the object header; "+
+		"large attributes need objects created with WithLargeAttributes: %s", e.Name, e.Size, e.Err)
+}
+
+func (e *AttributeSizeError) Unwrap() error {
+	return e.Err
+}
+
+func newAttribute(id C.hid_t) *Attribute {
+	a := &Attribute{id: id}
+	runtime.SetFinalizer(a, (*Attribute).finalizer)
+	trackID(id)
+	return a
+}
+
+func createAttribute(id C.hid_t, name string, dtype *Datatype, dspace *Dataspace, acpl *PropList) (*Attribute, error) {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	hid := C.H5Acreate2(id, c_name, dtype.id, dspace.id, acpl.id, P_DEFAULT.id)
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		if size := uint(dspace.SimpleExtentNPoints()) * dtype.Size(); size >= compactAttrMax {
+			return nil, &AttributeSizeError{Name: name, Size: size, Err: err}
+		}
+		return nil, err
+	}
+	return newAttribute(hid), nil
+}
+
+func openAttribute(id C.hid_t, name string) (*Attribute, error) {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	hid := C.H5Aopen(id, c_name, P_DEFAULT.id)
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return nil, err
+	}
+	return newAttribute(hid), nil
+}
+
+func openAttributeByIndex(id C.hid_t, idx uint) (*Attribute, error) {
+	hid := C.H5Aopen_by_idx(id, cdot, C.H5_INDEX_NAME, C.H5_ITER_INC, C.hsize_t(idx), P_DEFAULT.id, P_DEFAULT.id)
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return nil, err
+	}
+	return newAttribute(hid), nil
+}
+
+func numAttributes(id C.hid_t) (uint, error) {
+	var info C.H5O_info_t
+	err := h5err(C.H5Oget_info(id, &info))
+	return uint(info.num_attrs), err
+}
+
+func attributeNameByIndex(id C.hid_t, idx uint) (string, error) {
+	return attributeNameByOrder(id, INDEX_NAME, idx)
+}
+
+func attributeExists(id C.hid_t, name string) (bool, error) {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	rc := C.H5Aexists(id, c_name)
+	if err := h5err(C.herr_t(int(rc))); err != nil {
+		return false, err
+	}
+	return rc > 0, nil
+}
+
+func renameAttribute(id C.hid_t, oldName, newName string) error {
+	c_old := C.CString(oldName)
+	defer C.free(unsafe.Pointer(c_old))
+	c_new := C.CString(newName)
+	defer C.free(unsafe.Pointer(c_new))
+
+	return h5err(C.H5Arename(id, c_old, c_new))
+}
+
+func deleteAttribute(id C.hid_t, name string) error {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return h5err(C.H5Adelete(id, c_name))
+}
+
+// The *ByName helpers act on the attributes of the object objName,
+// relative to the location id, whose path may go through soft links.
+
+func createAttributeByName(id C.hid_t, objName, name string, dtype *Datatype, dspace *Dataspace, acpl *PropList) (*Attribute, error) {
+	c_obj := C.CString(objName)
+	defer C.free(unsafe.Pointer(c_obj))
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	hid := C.H5Acreate_by_name(id, c_obj, c_name, dtype.id, dspace.id, acpl.id, P_DEFAULT.id, P_DEFAULT.id)
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return nil, err
+	}
+	return newAttribute(hid), nil
+}
+
+func openAttributeByName(id C.hid_t, objName, name string) (*Attribute, error) {
+	c_obj := C.CString(objName)
+	defer C.free(unsafe.Pointer(c_obj))
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	hid := C.H5Aopen_by_name(id, c_obj, c_name, P_DEFAULT.id, P_DEFAULT.id)
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return nil, err
+	}
+	return newAttribute(hid), nil
+}
+
+func attributeExistsByName(id C.hid_t, objName, name string) (bool, error) {
+	c_obj := C.CString(objName)
+	defer C.free(unsafe.Pointer(c_obj))
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	rc := C.H5Aexists_by_name(id, c_obj, c_name, P_DEFAULT.id)
+	if err := h5err(C.herr_t(int(rc))); err != nil {
+		return false, err
+	}
+	return rc > 0, nil
+}
+
+func renameAttributeByName(id C.hid_t, objName, oldName, newName string) error {
+	c_obj := C.CString(objName)
+	defer C.free(unsafe.Pointer(c_obj))
+	c_old := C.CString(oldName)
+	defer C.free(unsafe.Pointer(c_old))
+	c_new := C.CString(newName)
+	defer C.free(unsafe.Pointer(c_new))
+
+	return h5err(C.H5Arename_by_name(id, c_obj, c_old, c_new, P_DEFAULT.id))
+}
+
+func deleteAttributeByName(id C.hid_t, objName, name string) error {
+	c_obj := C.CString(objName)
+	defer C.free(unsafe.Pointer(c_obj))
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return h5err(C.H5Adelete_by_name(id, c_obj, c_name, P_DEFAULT.id))
+}
+
+// attributeNameByOrder returns the name of the attribute at position idx
+// of the object id, in increasing order of the given index.
+func attributeNameByOrder(id C.hid_t, index IndexType, idx uint) (string, error) {
+	cidx := C.hsize_t(idx)
+	c_index := C.H5_index_t(index)
+	size := C.H5Aget_name_by_idx(id, cdot, c_index, C.H5_ITER_INC, cidx, nil, 0, C.H5P_DEFAULT)
+	if size < 0 {
+		return "", fmt.Errorf("could not get attribute name")
+	}
+
+	name := make([]C.char, size+1)
+	size = C.H5Aget_name_by_idx(id, cdot, c_index, C.H5_ITER_INC, cidx, &name[0], C.size_t(size)+1, C.H5P_DEFAULT)
+	if size < 0 {
+		return "", fmt.Errorf("could not get attribute name")
+	}
+	return C.GoString(&name[0]), nil
+}
+
+// attributeNamesByOrder returns the names of the attributes of the object
+// id, in increasing order of the given index.
+func attributeNamesByOrder(id C.hid_t, index IndexType) ([]string, error) {
+	n, err := numAttributes(id)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, n)
+	for i := range names {
+		if names[i], err = attributeNameByOrder(id, index, uint(i)); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+// copyAttributes copies every attribute of the object src to the object dst.
+func copyAttributes(src, dst C.hid_t) error {
+	n, err := numAttributes(src)
+	if err != nil {
+		return err
+	}
+	for i := uint(0); i < n; i++ {
+		if err := copyAttribute(src, dst, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyAttribute(src, dst C.hid_t, idx uint) error {
+	a, err := openAttributeByIndex(src, idx)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	dtype, err := a.Type()
+	if err != nil {
+		return err
+	}
+	defer dtype.Close()
+	dspace := a.Space()
+	if dspace == nil {
+		return fmt.Errorf("could not get dataspace of attribute %q", a.Name())
+	}
+	defer dspace.Close()
+
+	o, err := createAttribute(dst, a.Name(), dtype, dspace, P_DEFAULT)
+	if err != nil {
+		return err
+	}
+	defer o.Close()
+
+	buf := make([]byte, dspace.SimpleExtentNPoints()*int(dtype.Size()))
+	if len(buf) == 0 {
+		return nil
+	}
+	c_buf := unsafe.Pointer(&buf[0])
+	if err := h5err(C.H5Aread(a.id, dtype.id, c_buf)); err != nil {
+		return err
+	}
+	err = h5err(C.H5Awrite(o.id, dtype.id, c_buf))
+	reclaim(dtype.id, dspace.id, c_buf)
+	return err
+}
+
+func (a *Attribute) finalizer() {
+	if C.H5Iis_valid(a.id) <= 0 {
+		return // closed by File.CloseWith
+	}
+	err := a.Close()
+	if err != nil {
+		panic(fmt.Sprintf("error closing attr: %s", err))
+	}
+}
+
+// Releases and terminates access to an attribute.
+// herr_t H5Aclose(hid_t attr_id)
+func (a *Attribute) Close() error {
+	if a.id > 0 {
+		err := C.H5Aclose(a.id)
+		a.id = 0
+		return h5err(err)
+	}
+	return nil
+}
+
+func (a *Attribute) Id() int {
+	return int(a.id)
+}
+
+// Name returns the name of the attribute.
+// ssize_t H5Aget_name(hid_t attr_id, size_t buf_size, char *buf)
+func (a *Attribute) Name() string {
+	size := C.H5Aget_name(a.id, 0, nil)
+	if size < 0 {
+		return ""
+	}
+	name := make([]C.char, size+1)
+	C.H5Aget_name(a.id, C.size_t(size)+1, &name[0])
+	return C.GoString(&name[0])
+}
+
+func (a *Attribute) File() *File {
+	return getFile(a.id)
+}
+
+// Returns an identifier for a copy of the dataspace for an attribute.
+// hid_t H5Aget_space(hid_t attr_id)
+func (a *Attribute) Space() *Dataspace {
+	hid := C.H5Aget_space(a.id)
+	if int(hid) > 0 {
+		return newDataspace(hid)
+	}
+	return nil
+}
+
+// Returns an identifier for a copy of the datatype for an attribute.
+// hid_t H5Aget_type(hid_t attr_id)
+func (a *Attribute) Type() (*Datatype, error) {
+	hid := C.H5Aget_type(a.id)
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return nil, err
+	}
+	return NewDatatype(hid, nil), nil
+}
+
+// Reads the value of an attribute into data, which must be a pointer or a
+// slice large enough to hold it. If dtype is nil, the memory datatype is
+// inferred from the Go type of the elements of data.
+// herr_t H5Aread(hid_t attr_id, hid_t mem_type_id, void *buf)
+func (a *Attribute) Read(data interface{}, dtype *Datatype) error {
+	if dtype == nil {
+		dt, owned, err := inferType(data, a.Type)
+		if err != nil {
+			return err
+		}
+		if owned {
+			defer dt.Close()
+		}
+		dtype = dt
+	}
+	v := reflect.ValueOf(data)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Slice:
+		if v.Kind() == reflect.Slice && v.Len() == 0 {
+			return fmt.Errorf("cannot read attribute into an empty slice")
+		}
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String {
+			strs := newStringBuffer(v.Len(), dtype)
+			defer strs.release()
+			if err := h5err(C.H5Aread(a.id, dtype.id, strs.ptr())); err != nil {
+				return err
+			}
+			strs.decode(v)
+			return nil
+		}
+		b, err := pinBuffer(data, false)
+		if err != nil {
+			return err
+		}
+		defer b.unpin()
+		return h5err(C.H5Aread(a.id, dtype.id, b.ptr))
+	}
+	return fmt.Errorf("cannot read attribute into a %s, need a pointer or slice", v.Kind())
+}
+
+// Writes data to an attribute. If dtype is nil, the memory datatype is
+// inferred from the Go type of the elements of data.
+// herr_t H5Awrite(hid_t attr_id, hid_t mem_type_id, const void *buf)
+func (a *Attribute) Write(data interface{}, dtype *Datatype) error {
+	if dtype == nil {
+		dt, owned, err := inferType(data, a.Type)
+		if err != nil {
+			return err
+		}
+		if owned {
+			defer dt.Close()
+		}
+		dtype = dt
+	}
+	v := reflect.ValueOf(data)
+	var c_data unsafe.Pointer
+	var strs *stringBuffer
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return fmt.Errorf("cannot write an empty slice to an attribute")
+		}
+		if v.Type().Elem().Kind() == reflect.String {
+			strs = encodeStrings(v, dtype)
+		}
+	case reflect.String:
+		if C.H5Tis_variable_str(dtype.id) > 0 {
+			strs = encodeStrings(reflect.ValueOf([]string{v.String()}), dtype)
+		}
+	}
+	if strs != nil {
+		defer strs.free()
+		c_data = strs.ptr()
+	} else {
+		b, err := pinBuffer(data, true)
+		if err != nil {
+			return err
+		}
+		defer b.unpin()
+		c_data = b.ptr
+	}
+
+	return h5err(C.H5Awrite(a.id, dtype.id, c_data))
+}
@@ -0,0 +1,252 @@
+package hdf5
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestAttribute(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	scalar, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatalf("CreateDataspace failed: %s", err)
+	}
+	defer scalar.Close()
+	a, err := f.CreateAttribute("version", T_NATIVE_INT32, scalar)
+	if err != nil {
+		t.Fatalf("CreateAttribute failed: %s", err)
+	}
+	defer a.Close()
+	if err := a.Write(int32(3), T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	if name := a.Name(); name != "version" {
+		t.Errorf("Name() = %q, want %q", name, "version")
+	}
+
+	dspace, err := CreateSimpleDataspace([]uint{3}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	b, err := f.CreateAttribute("bounds", T_NATIVE_DOUBLE, dspace)
+	if err != nil {
+		t.Fatalf("CreateAttribute failed: %s", err)
+	}
+	defer b.Close()
+	bounds := []float64{-1.5, 0, 2.25}
+	if err := b.Write(bounds, T_NATIVE_DOUBLE); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+
+	if n, err := f.NumAttributes(); err != nil {
+		t.Fatalf("NumAttributes failed: %s", err)
+	} else if n != 2 {
+		t.Errorf("NumAttributes() = %d, want 2", n)
+	}
+	// attributes are indexed in name order
+	if name, err := f.AttributeNameByIndex(0); err != nil {
+		t.Fatalf("AttributeNameByIndex failed: %s", err)
+	} else if name != "bounds" {
+		t.Errorf("AttributeNameByIndex(0) = %q, want %q", name, "bounds")
+	}
+
+	a2, err := f.OpenAttribute("version")
+	if err != nil {
+		t.Fatalf("OpenAttribute failed: %s", err)
+	}
+	defer a2.Close()
+	var version int32
+	if err := a2.Read(&version, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if version != 3 {
+		t.Errorf("read %d, want 3", version)
+	}
+
+	b2, err := f.OpenAttribute("bounds")
+	if err != nil {
+		t.Fatalf("OpenAttribute failed: %s", err)
+	}
+	defer b2.Close()
+	got := make([]float64, 3)
+	if err := b2.Read(got, T_NATIVE_DOUBLE); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	for i := range bounds {
+		if got[i] != bounds[i] {
+			t.Errorf("bounds[%d] = %v, want %v", i, got[i], bounds[i])
+		}
+	}
+
+	if _, err := f.OpenAttribute("missing"); err == nil {
+		t.Errorf("expected an error opening a missing attribute")
+	}
+}
+
+func TestRenameDeleteAttribute(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	g, err := f.CreateGroup("g")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	defer g.Close()
+	scalar, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatalf("CreateDataspace failed: %s", err)
+	}
+	defer scalar.Close()
+	a, err := g.CreateAttribute("gian", T_NATIVE_DOUBLE, scalar)
+	if err != nil {
+		t.Fatalf("CreateAttribute failed: %s", err)
+	}
+	a.Close()
+
+	if ok, err := g.AttributeExists("gian"); err != nil || !ok {
+		t.Fatalf("AttributeExists(%q) = %v, %v", "gian", ok, err)
+	}
+	if err := g.RenameAttribute("gian", "gain"); err != nil {
+		t.Fatalf("RenameAttribute failed: %s", err)
+	}
+	if ok, err := g.AttributeExists("gian"); err != nil || ok {
+		t.Errorf("AttributeExists(%q) = %v, %v after rename", "gian", ok, err)
+	}
+	if ok, err := g.AttributeExists("gain"); err != nil || !ok {
+		t.Errorf("AttributeExists(%q) = %v, %v after rename", "gain", ok, err)
+	}
+	if err := g.DeleteAttribute("gain"); err != nil {
+		t.Fatalf("DeleteAttribute failed: %s", err)
+	}
+	if n, err := g.NumAttributes(); err != nil || n != 0 {
+		t.Errorf("NumAttributes() = %d, %v after delete", n, err)
+	}
+	if err := g.DeleteAttribute("gain"); err == nil {
+		t.Errorf("expected an error deleting a missing attribute")
+	}
+}
+
+func TestLargeAttribute(t *testing.T) {
+	matrix := make([]float64, 128*128)
+	for i := range matrix {
+		matrix[i] = float64(i) / 3
+	}
+	dspace, err := CreateSimpleDataspace([]uint{128, 128}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	_, err = f.CreateAttribute("calibration", T_NATIVE_DOUBLE, dspace)
+	f.Close()
+	var serr *AttributeSizeError
+	if !errors.As(err, &serr) || serr.Name != "calibration" || serr.Size != 8*128*128 {
+		t.Errorf("CreateAttribute of a large attribute returned %v", err)
+	}
+
+	f, err = Create(FNAME, WithTruncate(), WithLargeAttributes())
+	if err != nil {
+		t.Fatalf("Create failed: %s", err)
+	}
+	defer f.Close()
+	a, err := f.CreateAttribute("calibration", T_NATIVE_DOUBLE, dspace)
+	if err != nil {
+		t.Fatalf("CreateAttribute failed: %s", err)
+	}
+	defer a.Close()
+	if err := a.Write(matrix, T_NATIVE_DOUBLE); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	got := make([]float64, len(matrix))
+	if err := a.Read(got, T_NATIVE_DOUBLE); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, matrix) {
+		t.Errorf("Read returned different values")
+	}
+}
+
+func TestNamedDatatypeAttributes(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	dtype, err := T_NATIVE_DOUBLE.Copy()
+	if err != nil {
+		t.Fatalf("Copy failed: %s", err)
+	}
+	defer dtype.Close()
+	if err := f.CommitDatatype("reading", dtype); err != nil {
+		t.Fatalf("CommitDatatype failed: %s", err)
+	}
+	if !dtype.Committed() {
+		t.Fatalf("datatype not committed")
+	}
+	scalar, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatalf("CreateDataspace failed: %s", err)
+	}
+	defer scalar.Close()
+	a, err := dtype.CreateAttribute("version", T_NATIVE_INT32, scalar)
+	if err != nil {
+		t.Fatalf("CreateAttribute failed: %s", err)
+	}
+	version := int32(3)
+	if err := a.Write(&version, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	a.Close()
+	if names, err := dtype.AttributeNames(INDEX_NAME); err != nil || !reflect.DeepEqual(names, []string{"version"}) {
+		t.Errorf("AttributeNames returned %v, %v", names, err)
+	}
+
+	// through a soft link to the datatype
+	if err := f.CreateSoftLink("/reading", "latest"); err != nil {
+		t.Fatalf("CreateSoftLink failed: %s", err)
+	}
+	if ok, err := f.AttributeExistsByName("latest", "version"); err != nil || !ok {
+		t.Errorf("AttributeExistsByName returned %v, %v", ok, err)
+	}
+	a, err = f.OpenAttributeByName("latest", "version")
+	if err != nil {
+		t.Fatalf("OpenAttributeByName failed: %s", err)
+	}
+	var got int32
+	if err := a.Read(&got, T_NATIVE_INT32); err != nil || got != version {
+		t.Errorf("read version %d, %v", got, err)
+	}
+	a.Close()
+	if err := f.RenameAttributeByName("latest", "version", "schema_version"); err != nil {
+		t.Fatalf("RenameAttributeByName failed: %s", err)
+	}
+	if ok, _ := dtype.AttributeExists("schema_version"); !ok {
+		t.Errorf("renamed attribute not found on the datatype")
+	}
+	if err := f.DeleteAttributeByName("latest", "schema_version"); err != nil {
+		t.Fatalf("DeleteAttributeByName failed: %s", err)
+	}
+	if n, err := dtype.NumAttributes(); err != nil || n != 0 {
+		t.Errorf("NumAttributes returned %d, %v after deleting", n, err)
+	}
+}
@@ -0,0 +1,394 @@
+//go:build arrow
+
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// #include <string.h>
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// ---- Apache Arrow conversion (build with -tags arrow) ----
+
+// ArrowSchema returns the Arrow schema of the records of a one-dimensional
+// compound dataset, such as a packet table. Integer and floating-point
+// members map to the Arrow types of the same size, strings to utf8 and
+// arrays of those to fixed-size lists.
+func (s *Dataset) ArrowSchema() (*arrow.Schema, error) {
+	tid := C.H5Dget_type(s.id)
+	if err := h5err(C.herr_t(int(tid))); err != nil {
+		return nil, err
+	}
+	defer C.H5Tclose(tid)
+	return arrowSchema(tid)
+}
+
+func arrowSchema(tid C.hid_t) (*arrow.Schema, error) {
+	if TypeClass(C.H5Tget_class(tid)) != T_COMPOUND {
+		return nil, fmt.Errorf("only compound datasets convert to Arrow records")
+	}
+	n := int(C.H5Tget_nmembers(tid))
+	fields := make([]arrow.Field, n)
+	for i := range fields {
+		c_name := C.H5Tget_member_name(tid, C.uint(i))
+		fields[i].Name = C.GoString(c_name)
+		C.free(unsafe.Pointer(c_name))
+
+		mtype := C.H5Tget_member_type(tid, C.uint(i))
+		dt, err := arrowType(mtype)
+		C.H5Tclose(mtype)
+		if err != nil {
+			return nil, fmt.Errorf("member %q: %s", fields[i].Name, err)
+		}
+		fields[i].Type = dt
+	}
+	return arrow.NewSchema(fields, nil), nil
+}
+
+func arrowType(t C.hid_t) (arrow.DataType, error) {
+	size := int(C.H5Tget_size(t))
+	switch TypeClass(C.H5Tget_class(t)) {
+	case T_INTEGER:
+		signed := C.H5Tget_sign(t) == C.H5T_SGN_2
+		switch {
+		case size == 1 && signed:
+			return arrow.PrimitiveTypes.Int8, nil
+		case size == 1:
+			return arrow.PrimitiveTypes.Uint8, nil
+		case size == 2 && signed:
+			return arrow.PrimitiveTypes.Int16, nil
+		case size == 2:
+			return arrow.PrimitiveTypes.Uint16, nil
+		case size == 4 && signed:
+			return arrow.PrimitiveTypes.Int32, nil
+		case size == 4:
+			return arrow.PrimitiveTypes.Uint32, nil
+		case size == 8 && signed:
+			return arrow.PrimitiveTypes.Int64, nil
+		case size == 8:
+			return arrow.PrimitiveTypes.Uint64, nil
+		}
+	case T_FLOAT:
+		switch size {
+		case 4:
+			return arrow.PrimitiveTypes.Float32, nil
+		case 8:
+			return arrow.PrimitiveTypes.Float64, nil
+		}
+	case T_STRING:
+		return arrow.BinaryTypes.String, nil
+	case T_ARRAY:
+		super := C.H5Tget_super(t)
+		defer C.H5Tclose(super)
+		elem, err := arrowType(super)
+		if err != nil {
+			return nil, err
+		}
+		return arrow.FixedSizeListOf(int32(arrayLen(t)), elem), nil
+	}
+	return nil, fmt.Errorf("no Arrow type for datatype class %d of size %d", int(C.H5Tget_class(t)), size)
+}
+
+// ReadArrow reads nrecords records of a one-dimensional compound dataset,
+// such as a packet table, starting at record start, into an Arrow record
+// with the schema returned by ArrowSchema. The caller must release the
+// record.
+func (s *Dataset) ReadArrow(mem memory.Allocator, start, nrecords int) (arrow.Record, error) {
+	tid := C.H5Dget_type(s.id)
+	if err := h5err(C.herr_t(int(tid))); err != nil {
+		return nil, err
+	}
+	defer C.H5Tclose(tid)
+	schema, err := arrowSchema(tid)
+	if err != nil {
+		return nil, err
+	}
+	dspace := s.Space()
+	if dspace == nil {
+		return nil, fmt.Errorf("could not get dataspace of dataset %q", s.Name())
+	}
+	defer dspace.Close()
+	dims, err := extentDims(dspace)
+	if err != nil {
+		return nil, err
+	}
+	if len(dims) != 1 {
+		return nil, fmt.Errorf("only one-dimensional datasets convert to Arrow records")
+	}
+	if start < 0 || nrecords < 0 || uint(start+nrecords) > dims[0] {
+		return nil, fmt.Errorf("records [%d, %d) out of range [0, %d)", start, start+nrecords, dims[0])
+	}
+
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	for off := 0; off < nrecords; off += csvBatchSize {
+		n := csvBatchSize
+		if off+n > nrecords {
+			n = nrecords - off
+		}
+		values, err := readRows(s.id, tid, dims, uint(start+off), uint(n))
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			rec, _ := v.(record)
+			for i := range schema.Fields() {
+				var fv interface{}
+				if i < len(rec) {
+					fv = rec[i].Value
+				}
+				if err := appendArrow(b.Field(i), fv); err != nil {
+					return nil, fmt.Errorf("field %q: %s", schema.Field(i).Name, err)
+				}
+			}
+		}
+	}
+	return b.NewRecord(), nil
+}
+
+func appendArrow(b array.Builder, v interface{}) error {
+	if v == nil {
+		b.AppendNull()
+		return nil
+	}
+	switch b := b.(type) {
+	case *array.Int8Builder:
+		i, err := toInt64(v)
+		b.Append(int8(i))
+		return err
+	case *array.Int16Builder:
+		i, err := toInt64(v)
+		b.Append(int16(i))
+		return err
+	case *array.Int32Builder:
+		i, err := toInt64(v)
+		b.Append(int32(i))
+		return err
+	case *array.Int64Builder:
+		i, err := toInt64(v)
+		b.Append(i)
+		return err
+	case *array.Uint8Builder:
+		u, err := toUint64(v)
+		b.Append(uint8(u))
+		return err
+	case *array.Uint16Builder:
+		u, err := toUint64(v)
+		b.Append(uint16(u))
+		return err
+	case *array.Uint32Builder:
+		u, err := toUint64(v)
+		b.Append(uint32(u))
+		return err
+	case *array.Uint64Builder:
+		u, err := toUint64(v)
+		b.Append(u)
+		return err
+	case *array.Float32Builder:
+		f, err := toFloat64(v)
+		b.Append(float32(f))
+		return err
+	case *array.Float64Builder:
+		f, err := toFloat64(v)
+		b.Append(f)
+		return err
+	case *array.StringBuilder:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("cannot append %T to a string column", v)
+		}
+		b.Append(s)
+		return nil
+	case *array.FixedSizeListBuilder:
+		values, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("cannot append %T to a list column", v)
+		}
+		b.Append(true)
+		vb := b.ValueBuilder()
+		for _, e := range flattenNested(values, nil) {
+			if err := appendArrow(vb, e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported Arrow builder %T", b)
+}
+
+// flattenNested appends the elements of the possibly multi-dimensional
+// array value v, as decoded by decodeArray, to values in row-major order.
+func flattenNested(v []interface{}, values []interface{}) []interface{} {
+	for _, e := range v {
+		if sub, ok := e.([]interface{}); ok {
+			values = flattenNested(sub, values)
+		} else {
+			values = append(values, e)
+		}
+	}
+	return values
+}
+
+// WriteArrow creates a one-dimensional compound dataset named name under
+// loc, a file or group, holding the rows of rec. The columns must be of
+// integer, floating-point, utf8 or fixed-size list types; strings are
+// stored as variable-length UTF-8 strings.
+func WriteArrow(loc Object, name string, rec arrow.Record) (*Dataset, error) {
+	schema := rec.Schema()
+	ftypes := make([]C.hid_t, len(schema.Fields()))
+	defer func() {
+		for _, t := range ftypes {
+			if t > 0 {
+				C.H5Tclose(t)
+			}
+		}
+	}()
+	size := 0
+	for i, f := range schema.Fields() {
+		t, err := datatypeFromArrow(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("column %q: %s", f.Name, err)
+		}
+		ftypes[i] = t
+		size += int(C.H5Tget_size(t))
+	}
+	dtype, err := CreateDatatype(T_COMPOUND, size)
+	if err != nil {
+		return nil, err
+	}
+	defer dtype.Close()
+	offset := 0
+	for i, f := range schema.Fields() {
+		c_name := C.CString(f.Name)
+		err := h5err(C.H5Tinsert(dtype.id, c_name, C.size_t(offset), ftypes[i]))
+		C.free(unsafe.Pointer(c_name))
+		if err != nil {
+			return nil, err
+		}
+		offset += int(C.H5Tget_size(ftypes[i]))
+	}
+
+	nrows := int(rec.NumRows())
+	dspace, err := CreateSimpleDataspace([]uint{uint(nrows)}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer dspace.Close()
+	d, err := createDataset(C.hid_t(loc.Id()), name, dtype, dspace, P_DEFAULT)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, nrows)
+	for r := range values {
+		row := make(record, len(schema.Fields()))
+		for i, f := range schema.Fields() {
+			row[i] = field{Name: f.Name, Value: arrowValue(rec.Column(i), r)}
+		}
+		values[r] = row
+	}
+	err = writeValues(dtype.id, values, func(mtype C.hid_t, buf unsafe.Pointer) error {
+		return h5err(C.H5Dwrite(d.id, mtype, C.H5S_ALL, C.H5S_ALL, C.H5P_DEFAULT, buf))
+	})
+	if err != nil {
+		d.Close()
+		return nil, err
+	}
+	return d, nil
+}
+
+// datatypeFromArrow returns a new datatype identifier for the Arrow type dt.
+func datatypeFromArrow(dt arrow.DataType) (C.hid_t, error) {
+	var base *Datatype
+	switch dt.ID() {
+	case arrow.INT8:
+		base = T_NATIVE_INT8
+	case arrow.INT16:
+		base = T_NATIVE_INT16
+	case arrow.INT32:
+		base = T_NATIVE_INT32
+	case arrow.INT64:
+		base = T_NATIVE_INT64
+	case arrow.UINT8:
+		base = T_NATIVE_UINT8
+	case arrow.UINT16:
+		base = T_NATIVE_UINT16
+	case arrow.UINT32:
+		base = T_NATIVE_UINT32
+	case arrow.UINT64:
+		base = T_NATIVE_UINT64
+	case arrow.FLOAT32:
+		base = T_NATIVE_FLOAT
+	case arrow.FLOAT64:
+		base = T_NATIVE_DOUBLE
+	case arrow.STRING:
+		t, err := NewDatatypeFromText("H5T_STRING { STRSIZE H5T_VARIABLE; STRPAD H5T_STR_NULLTERM; CSET H5T_CSET_UTF8; CTYPE H5T_C_S1; }")
+		if err != nil {
+			return 0, err
+		}
+		hid := C.H5Tcopy(t.id)
+		t.Close()
+		return hid, h5err(C.herr_t(int(hid)))
+	case arrow.FIXED_SIZE_LIST:
+		l := dt.(*arrow.FixedSizeListType)
+		elem, err := datatypeFromArrow(l.Elem())
+		if err != nil {
+			return 0, err
+		}
+		defer C.H5Tclose(elem)
+		c_dims := C.hsize_t(l.Len())
+		hid := C.H5Tarray_create2(elem, 1, &c_dims)
+		return hid, h5err(C.herr_t(int(hid)))
+	default:
+		return 0, fmt.Errorf("unsupported Arrow type %s", dt)
+	}
+	hid := C.H5Tcopy(base.id)
+	return hid, h5err(C.herr_t(int(hid)))
+}
+
+// arrowValue returns element i of a, in the form accepted by encodeValue.
+func arrowValue(a arrow.Array, i int) interface{} {
+	if a.IsNull(i) {
+		return nil
+	}
+	switch a := a.(type) {
+	case *array.Int8:
+		return int64(a.Value(i))
+	case *array.Int16:
+		return int64(a.Value(i))
+	case *array.Int32:
+		return int64(a.Value(i))
+	case *array.Int64:
+		return a.Value(i)
+	case *array.Uint8:
+		return uint64(a.Value(i))
+	case *array.Uint16:
+		return uint64(a.Value(i))
+	case *array.Uint32:
+		return uint64(a.Value(i))
+	case *array.Uint64:
+		return a.Value(i)
+	case *array.Float32:
+		return float64(a.Value(i))
+	case *array.Float64:
+		return a.Value(i)
+	case *array.String:
+		return a.Value(i)
+	case *array.FixedSizeList:
+		start, end := a.ValueOffsets(i)
+		values := make([]interface{}, 0, end-start)
+		for j := start; j < end; j++ {
+			values = append(values, arrowValue(a.ListValues(), int(j)))
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+//go:build arrow
+
+package hdf5
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func TestArrowRoundTrip(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "energy", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "name", Type: arrow.BinaryTypes.String},
+		{Name: "pos", Type: arrow.FixedSizeListOf(3, arrow.PrimitiveTypes.Float32)},
+	}, nil)
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	b.Field(0).(*array.Int32Builder).AppendValues([]int32{1, 2, 3}, nil)
+	b.Field(1).(*array.Float64Builder).AppendValues([]float64{0.5, 1.5, 2.5}, nil)
+	b.Field(2).(*array.StringBuilder).AppendValues([]string{"alpha", "beta", ""}, nil)
+	lb := b.Field(3).(*array.FixedSizeListBuilder)
+	pos := []float32{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	for i := 0; i < 3; i++ {
+		lb.Append(true)
+		lb.ValueBuilder().(*array.Float32Builder).AppendValues(pos[3*i:3*i+3], nil)
+	}
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	s, err := WriteArrow(f, "events", rec)
+	if err != nil {
+		t.Fatalf("WriteArrow failed: %s", err)
+	}
+	defer s.Close()
+
+	got, err := s.ArrowSchema()
+	if err != nil {
+		t.Fatalf("ArrowSchema failed: %s", err)
+	}
+	if !got.Equal(schema) {
+		t.Errorf("ArrowSchema = %s, want %s", got, schema)
+	}
+
+	back, err := s.ReadArrow(mem, 1, 2)
+	if err != nil {
+		t.Fatalf("ReadArrow failed: %s", err)
+	}
+	defer back.Release()
+	if back.NumRows() != 2 {
+		t.Fatalf("ReadArrow read %d rows, want 2", back.NumRows())
+	}
+	if ids := back.Column(0).(*array.Int32).Int32Values(); !reflect.DeepEqual(ids, []int32{2, 3}) {
+		t.Errorf("id column = %v", ids)
+	}
+	if es := back.Column(1).(*array.Float64).Float64Values(); !reflect.DeepEqual(es, []float64{1.5, 2.5}) {
+		t.Errorf("energy column = %v", es)
+	}
+	names := back.Column(2).(*array.String)
+	if names.Value(0) != "beta" || names.Value(1) != "" {
+		t.Errorf("name column = %v", names)
+	}
+	values := back.Column(3).(*array.FixedSizeList).ListValues().(*array.Float32).Float32Values()
+	if !reflect.DeepEqual(values, pos[3:]) {
+		t.Errorf("pos column = %v", values)
+	}
+
+	if _, err := s.ReadArrow(mem, 2, 2); err == nil {
+		t.Errorf("ReadArrow past the end succeeded")
+	}
+}
+
+type arrowGrid struct {
+	ID   int32
+	Grid [2][3]float32
+}
+
+func TestArrowMultiDimArray(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	data := []arrowGrid{
+		{1, [2][3]float32{{1, 2, 3}, {4, 5, 6}}},
+		{2, [2][3]float32{{7, 8, 9}, {10, 11, 12}}},
+	}
+	s, err := f.CreateDatasetFromValue("grids", data, nil)
+	if err != nil {
+		t.Fatalf("CreateDatasetFromValue failed: %s", err)
+	}
+	defer s.Close()
+
+	schema, err := s.ArrowSchema()
+	if err != nil {
+		t.Fatalf("ArrowSchema failed: %s", err)
+	}
+	want := arrow.FixedSizeListOf(6, arrow.PrimitiveTypes.Float32)
+	if got := schema.Field(1).Type; !arrow.TypeEqual(got, want) {
+		t.Errorf("Grid maps to %s, want %s", got, want)
+	}
+
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+	rec, err := s.ReadArrow(mem, 0, 2)
+	if err != nil {
+		t.Fatalf("ReadArrow failed: %s", err)
+	}
+	defer rec.Release()
+	values := rec.Column(1).(*array.FixedSizeList).ListValues().(*array.Float32).Float32Values()
+	if want := []float32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}; !reflect.DeepEqual(values, want) {
+		t.Errorf("Grid column = %v, want the elements in row-major order", values)
+	}
+}
@@ -0,0 +1,439 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include "hdf5_hl.h"
+// #include <stdlib.h>
+// #include <string.h>
+import "C"
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"strconv"
+	"unsafe"
+)
+
+// number of values read or appended at once when converting CSV
+const csvBatchSize = 4096
+
+// WriteCSV writes the dataset to w as CSV, with a header line.
+//
+// One-dimensional datasets of compound type, such as packet tables and
+// tables of the H5TB API, get a column per member; nested compounds and
+// arrays are flattened into columns named "a.b" and "a[0]". Other
+// one-dimensional datasets get a single column named after the dataset,
+// and two-dimensional datasets a column per index of their second
+// dimension. The dataset is read in batches of rows.
+func (s *Dataset) WriteCSV(w io.Writer) error {
+	tid := C.H5Dget_type(s.id)
+	if err := h5err(C.herr_t(int(tid))); err != nil {
+		return err
+	}
+	defer C.H5Tclose(tid)
+	mtype := C.H5Tget_native_type(tid, C.H5T_DIR_DEFAULT)
+	if err := h5err(C.herr_t(int(mtype))); err != nil {
+		return err
+	}
+	defer C.H5Tclose(mtype)
+
+	dspace := s.Space()
+	if dspace == nil {
+		return fmt.Errorf("could not get dataspace of dataset %q", s.Name())
+	}
+	defer dspace.Close()
+	dims, err := extentDims(dspace)
+	if err != nil {
+		return err
+	}
+
+	var header []string
+	cols := uint(1)
+	switch len(dims) {
+	case 1:
+		name := ""
+		if TypeClass(C.H5Tget_class(mtype)) != T_COMPOUND {
+			name = path.Base(s.Name())
+		}
+		header = csvColumns(mtype, name)
+	case 2:
+		cols = dims[1]
+		for i := uint(0); i < cols; i++ {
+			header = append(header, csvColumns(mtype, strconv.Itoa(int(i)))...)
+		}
+	default:
+		return fmt.Errorf("cannot write a %d-dimensional dataset as CSV", len(dims))
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	rows := dims[0]
+	batch := uint(csvBatchSize) / cols
+	if batch == 0 {
+		batch = 1
+	}
+	row := make([]string, 0, len(header))
+	for start := uint(0); start < rows; start += batch {
+		n := batch
+		if start+n > rows {
+			n = rows - start
+		}
+		values, err := readRows(s.id, tid, dims, start, n)
+		if err != nil {
+			return err
+		}
+		for r := uint(0); r < n; r++ {
+			row = row[:0]
+			for _, v := range values[r*cols : (r+1)*cols] {
+				row = csvCells(mtype, v, row)
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// readRows reads and decodes n rows, from row start, of the dataset id of
+// datatype tid and dimensions dims.
+func readRows(id, tid C.hid_t, dims []uint, start, n uint) ([]interface{}, error) {
+	c_start := make([]C.hsize_t, len(dims))
+	c_count := make([]C.hsize_t, len(dims))
+	c_start[0] = C.hsize_t(start)
+	c_count[0] = C.hsize_t(n)
+	npoints := n
+	for i := 1; i < len(dims); i++ {
+		c_count[i] = C.hsize_t(dims[i])
+		npoints *= dims[i]
+	}
+
+	fspace := C.H5Dget_space(id)
+	if err := h5err(C.herr_t(int(fspace))); err != nil {
+		return nil, err
+	}
+	defer C.H5Sclose(fspace)
+	err := h5err(C.H5Sselect_hyperslab(fspace, C.H5S_SELECT_SET, &c_start[0], nil, &c_count[0], nil))
+	if err != nil {
+		return nil, err
+	}
+	c_n := C.hsize_t(npoints)
+	mspace := C.H5Screate_simple(1, &c_n, nil)
+	if err := h5err(C.herr_t(int(mspace))); err != nil {
+		return nil, err
+	}
+	defer C.H5Sclose(mspace)
+
+	return readValues(tid, int(npoints), func(mtype C.hid_t, buf unsafe.Pointer) error {
+		return h5err(C.H5Dread(id, mtype, mspace, fspace, C.H5P_DEFAULT, buf))
+	})
+}
+
+func joinColumn(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// csvColumns returns the names of the CSV columns of a value of the memory
+// datatype t named name.
+func csvColumns(t C.hid_t, name string) []string {
+	switch TypeClass(C.H5Tget_class(t)) {
+	case T_COMPOUND:
+		var cols []string
+		n := int(C.H5Tget_nmembers(t))
+		for i := 0; i < n; i++ {
+			c_name := C.H5Tget_member_name(t, C.uint(i))
+			mname := C.GoString(c_name)
+			C.free(unsafe.Pointer(c_name))
+			mtype := C.H5Tget_member_type(t, C.uint(i))
+			cols = append(cols, csvColumns(mtype, joinColumn(name, mname))...)
+			C.H5Tclose(mtype)
+		}
+		return cols
+
+	case T_ARRAY:
+		super := C.H5Tget_super(t)
+		defer C.H5Tclose(super)
+		var cols []string
+		for i := 0; i < arrayLen(t); i++ {
+			cols = append(cols, csvColumns(super, fmt.Sprintf("%s[%d]", name, i))...)
+		}
+		return cols
+	}
+	return []string{name}
+}
+
+// csvCells appends the CSV cells of the value v, decoded from the memory
+// datatype t, to cells.
+func csvCells(t C.hid_t, v interface{}, cells []string) []string {
+	switch TypeClass(C.H5Tget_class(t)) {
+	case T_COMPOUND:
+		rec, _ := v.(record)
+		n := int(C.H5Tget_nmembers(t))
+		for i := 0; i < n; i++ {
+			var mv interface{}
+			if i < len(rec) {
+				mv = rec[i].Value
+			}
+			mtype := C.H5Tget_member_type(t, C.uint(i))
+			cells = csvCells(mtype, mv, cells)
+			C.H5Tclose(mtype)
+		}
+		return cells
+
+	case T_ARRAY:
+		super := C.H5Tget_super(t)
+		defer C.H5Tclose(super)
+		leaves := flattenArray(v, int(C.H5Tget_array_ndims(t)), nil)
+		for i := 0; i < arrayLen(t); i++ {
+			var e interface{}
+			if i < len(leaves) {
+				e = leaves[i]
+			}
+			cells = csvCells(super, e, cells)
+		}
+		return cells
+	}
+	return append(cells, csvCell(v))
+}
+
+// arrayLen returns the number of elements of the array datatype t.
+func arrayLen(t C.hid_t) int {
+	rank := int(C.H5Tget_array_ndims(t))
+	if rank <= 0 {
+		return 0
+	}
+	dims := make([]C.hsize_t, rank)
+	C.H5Tget_array_dims2(t, &dims[0])
+	n := 1
+	for _, d := range dims {
+		n *= int(d)
+	}
+	return n
+}
+
+// flattenArray appends the elements of the rank-dimensional array value v,
+// as decoded by decodeArray, to values in row-major order.
+func flattenArray(v interface{}, rank int, values []interface{}) []interface{} {
+	if rank == 0 {
+		return append(values, v)
+	}
+	for _, e := range v.([]interface{}) {
+		values = flattenArray(e, rank-1, values)
+	}
+	return values
+}
+
+func csvCell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case []byte:
+		return fmt.Sprintf("%x", v)
+	}
+	return formatValue(v)
+}
+
+// csvField is a numeric or string field of a struct, reachable through
+// nested structs and arrays, that is mapped to a CSV column.
+type csvField struct {
+	name string
+	get  func(v reflect.Value) reflect.Value
+}
+
+func csvFields(t reflect.Type, prefix string, get func(reflect.Value) reflect.Value) ([]csvField, error) {
+	switch t.Kind() {
+	case reflect.Struct:
+		var fields []csvField
+		for _, f := range structFields(t) {
+			index := f.index
+			sub, err := csvFields(f.typ, joinColumn(prefix, f.name), func(v reflect.Value) reflect.Value {
+				// go through the address so that unexported fields can be set
+				fv := get(v)
+				for _, i := range index {
+					fv = fv.Field(i)
+				}
+				return reflect.NewAt(fv.Type(), unsafe.Pointer(fv.UnsafeAddr())).Elem()
+			})
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, sub...)
+		}
+		return fields, nil
+
+	case reflect.Array:
+		var fields []csvField
+		for i := 0; i < t.Len(); i++ {
+			i := i
+			sub, err := csvFields(t.Elem(), fmt.Sprintf("%s[%d]", prefix, i), func(v reflect.Value) reflect.Value {
+				return get(v).Index(i)
+			})
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, sub...)
+		}
+		return fields, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String:
+		return []csvField{{name: prefix, get: get}}, nil
+	}
+	return nil, fmt.Errorf("field %q: cannot load CSV into a %s", prefix, t.Kind())
+}
+
+func setCSVValue(v reflect.Value, s string) error {
+	if s == "" {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	}
+	return nil
+}
+
+// createTableFromCSV creates a packet table of the struct type of schema
+// and appends to it the records read from r, whose header line names the
+// columns like WriteCSV does. String fields are stored as variable-length
+// strings.
+func createTableFromCSV(id C.hid_t, name string, r io.Reader, schema interface{}, chunkSize, compression int) (*Table, error) {
+	rt := reflect.TypeOf(schema)
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("CSV schema must be a struct, not %T", schema)
+	}
+	fields, err := csvFields(rt, "", func(v reflect.Value) reflect.Value { return v })
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*csvField, len(fields))
+	probe := reflect.New(rt).Elem()
+	var strs []uintptr
+	for i := range fields {
+		byName[fields[i].name] = &fields[i]
+		if fv := fields[i].get(probe); fv.Kind() == reflect.String {
+			strs = append(strs, fv.UnsafeAddr()-probe.UnsafeAddr())
+		}
+	}
+
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %s", err)
+	}
+	columns := make([]*csvField, len(header))
+	for i, col := range header {
+		columns[i] = byName[col]
+		if columns[i] == nil {
+			return nil, fmt.Errorf("CSV column %q matches no field of %s", col, rt)
+		}
+	}
+
+	table, err := createTable(id, name, newDataTypeFromType(rt), chunkSize, compression)
+	if err != nil {
+		return nil, err
+	}
+	buf := reflect.MakeSlice(reflect.SliceOf(rt), 0, csvBatchSize)
+	line := 1
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			table.Close()
+			return nil, err
+		}
+		buf = reflect.Append(buf, reflect.Zero(rt))
+		v := buf.Index(buf.Len() - 1)
+		for i, cell := range rec {
+			if err := setCSVValue(columns[i].get(v), cell); err != nil {
+				table.Close()
+				return nil, fmt.Errorf("line %d, column %q: %s", line, header[i], err)
+			}
+		}
+		if buf.Len() == buf.Cap() {
+			if err := appendCSVRecords(table, buf, strs); err != nil {
+				table.Close()
+				return nil, err
+			}
+			buf = buf.Slice(0, 0)
+		}
+	}
+	if err := appendCSVRecords(table, buf, strs); err != nil {
+		table.Close()
+		return nil, err
+	}
+	return table, nil
+}
+
+// appendCSVRecords appends the records of the slice buf to table. The
+// records are copied so that their string fields, at the offsets strs, can
+// be replaced by the C strings that the variable-length string members of
+// the table hold.
+func appendCSVRecords(table *Table, buf reflect.Value, strs []uintptr) error {
+	if len(strs) == 0 || buf.Len() == 0 {
+		return table.Append(buf.Interface())
+	}
+	n := buf.Len()
+	size := int(buf.Type().Elem().Size())
+	raw := getScratch(n * size)
+	defer putScratch(raw)
+	c_raw := unsafe.Pointer(&raw[0])
+	var allocs []unsafe.Pointer
+	defer func() {
+		for _, p := range allocs {
+			C.free(p)
+		}
+	}()
+	for i := 0; i < n; i++ {
+		rec := buf.Index(i).Addr().UnsafePointer()
+		dst := unsafe.Add(c_raw, i*size)
+		copy(raw[i*size:(i+1)*size], unsafe.Slice((*byte)(rec), size))
+		for _, off := range strs {
+			c_s := C.CString(*(*string)(unsafe.Add(rec, off)))
+			allocs = append(allocs, unsafe.Pointer(c_s))
+			*(**C.char)(unsafe.Add(dst, off)) = c_s
+		}
+	}
+	return h5err(C.H5PTappend(table.id, C.size_t(n), c_raw))
+}
@@ -0,0 +1,111 @@
+package hdf5
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+type reading_t struct {
+	id    int32
+	pos   [2]float32
+	value float64
+}
+
+func TestCSV(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	const input = "id,value,pos[0],pos[1]\n1,0.5,10,20\n2,-1.25,11,21\n3,,12,22\n"
+	table, err := f.CreateTableFromCSV("readings", strings.NewReader(input), reading_t{}, 16, 0)
+	if err != nil {
+		t.Fatalf("CreateTableFromCSV failed: %s", err)
+	}
+	defer table.Close()
+	if n, err := table.NumPackets(); err != nil {
+		t.Fatalf("NumPackets failed: %s", err)
+	} else if n != 3 {
+		t.Fatalf("table has %d packets, want 3", n)
+	}
+
+	dset, err := f.OpenDataset("readings")
+	if err != nil {
+		t.Fatalf("OpenDataset failed: %s", err)
+	}
+	defer dset.Close()
+	var buf bytes.Buffer
+	if err := dset.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %s", err)
+	}
+	want := "id,pos[0],pos[1],value\n1,10,20,0.5\n2,11,21,-1.25\n3,12,22,0\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteCSV wrote:\n%s\nwant:\n%s", got, want)
+	}
+
+	// two-dimensional dataset
+	dspace, err := CreateSimpleDataspace([]uint{2, 3}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	grid, err := f.CreateDataset("grid", T_NATIVE_INT32, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer grid.Close()
+	if err := grid.Write([]int32{1, 2, 3, 4, 5, 6}, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	buf.Reset()
+	if err := grid.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %s", err)
+	}
+	want = "0,1,2\n1,2,3\n4,5,6\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteCSV wrote:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := f.CreateTableFromCSV("bad", strings.NewReader("id,nope\n1,2\n"), reading_t{}, 16, 0); err == nil {
+		t.Errorf("expected an error for an unknown column")
+	}
+}
+
+type station_t struct {
+	id    int32
+	name  string
+	value float64
+}
+
+func TestCSVStrings(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	const input = "id,name,value\n1,Uppsala,0.5\n2,\"Kiruna, north\",1.5\n3,,2.5\n"
+	table, err := f.CreateTableFromCSV("stations", strings.NewReader(input), station_t{}, 16, 0)
+	if err != nil {
+		t.Fatalf("CreateTableFromCSV failed: %s", err)
+	}
+	defer table.Close()
+
+	dset, err := f.OpenDataset("stations")
+	if err != nil {
+		t.Fatalf("OpenDataset failed: %s", err)
+	}
+	defer dset.Close()
+	var buf bytes.Buffer
+	if err := dset.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %s", err)
+	}
+	if got := buf.String(); got != input {
+		t.Errorf("WriteCSV wrote:\n%s\nwant:\n%s", got, input)
+	}
+}
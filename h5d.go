@@ -6,10 +6,10 @@ package hdf5
 import "C"
 
 import (
-	"bytes"
 	"fmt"
 	"reflect"
 	"runtime"
+	"time"
 	"unsafe"
 )
 
@@ -20,13 +20,29 @@ type Dataset struct {
 func newDataset(id C.hid_t) *Dataset {
 	d := &Dataset{id: id}
 	runtime.SetFinalizer(d, (*Dataset).finalizer)
+	trackID(id)
 	return d
 }
 
 func createDataset(id C.hid_t, name string, dtype *Datatype, dspace *Dataspace, dcpl *PropList) (*Dataset, error) {
+	return createDatasetWith(id, name, dtype, dspace, P_DEFAULT, dcpl)
+}
+
+func createDatasetWith(id C.hid_t, name string, dtype *Datatype, dspace *Dataspace, lcpl, dcpl *PropList) (*Dataset, error) {
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
-	hid := C.H5Dcreate2(id, c_name, dtype.id, dspace.id, P_DEFAULT.id, dcpl.id, P_DEFAULT.id)
+	hid := C.H5Dcreate2(id, c_name, dtype.id, dspace.id, lcpl.id, dcpl.id, P_DEFAULT.id)
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return nil, err
+	}
+	if l := logger(hid); l != nil {
+		l.Debug("create dataset", "path", getName(hid))
+	}
+	return newDataset(hid), nil
+}
+
+func createAnonDataset(id C.hid_t, dtype *Datatype, dspace *Dataspace, dcpl *PropList) (*Dataset, error) {
+	hid := C.H5Dcreate_anon(id, dtype.id, dspace.id, dcpl.id, P_DEFAULT.id)
 	if err := h5err(C.herr_t(int(hid))); err != nil {
 		return nil, err
 	}
@@ -34,17 +50,27 @@ func createDataset(id C.hid_t, name string, dtype *Datatype, dspace *Dataspace,
 }
 
 func openDataset(id C.hid_t, name string) (*Dataset, error) {
+	return openDatasetWith(id, name, P_DEFAULT)
+}
+
+func openDatasetWith(id C.hid_t, name string, dapl *PropList) (*Dataset, error) {
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 
-	hid := C.H5Dopen2(id, c_name, P_DEFAULT.id)
+	hid := C.H5Dopen2(id, c_name, dapl.id)
 	if err := h5err(C.herr_t(int(hid))); err != nil {
 		return nil, err
 	}
+	if l := logger(hid); l != nil {
+		l.Debug("open dataset", "path", getName(hid))
+	}
 	return newDataset(hid), nil
 }
 
 func (s *Dataset) finalizer() {
+	if C.H5Iis_valid(s.id) <= 0 {
+		return // closed by File.CloseWith
+	}
 	err := s.Close()
 	if err != nil {
 		panic(fmt.Sprintf("error closing dset: %s", err))
@@ -63,9 +89,30 @@ func (s *Dataset) File() *File {
 	return getFile(s.id)
 }
 
+// Info returns the object header metadata of the dataset.
+// herr_t H5Oget_info(hid_t object_id, H5O_info_t *object_info)
+func (s *Dataset) Info() (*ObjectInfo, error) {
+	return objectInfo(s.id)
+}
+
+// SetComment sets the comment of the dataset. An empty comment removes it.
+// herr_t H5Oset_comment(hid_t object_id, const char *comment)
+func (s *Dataset) SetComment(comment string) error {
+	return setComment(s.id, comment)
+}
+
+// Comment returns the comment of the dataset, or "" if it has none.
+// ssize_t H5Oget_comment(hid_t object_id, char *comment, size_t bufsize)
+func (s *Dataset) Comment() (string, error) {
+	return getComment(s.id)
+}
+
 // Releases and terminates access to a dataset.
 func (s *Dataset) Close() error {
 	if s.id > 0 {
+		if l := logger(s.id); l != nil {
+			l.Debug("close dataset", "path", getName(s.id))
+		}
 		err := C.H5Dclose(s.id)
 		s.id = 0
 		return h5err(err)
@@ -82,6 +129,17 @@ func (s *Dataset) Space() *Dataspace {
 	return nil
 }
 
+// Shape returns the current dimensions of the dataset, which are empty
+// for a scalar dataset.
+func (s *Dataset) Shape() (Shape, error) {
+	hid := C.H5Dget_space(s.id)
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return nil, err
+	}
+	defer C.H5Sclose(hid)
+	return (&Dataspace{id: hid}).Shape()
+}
+
 // Returns an identifier for a copy of the datatype for a dataset.
 // hid_t H5Dget_type(hid_t dataset_id )
 func (s *Dataset) Type() (*Datatype, error) {
@@ -94,87 +152,246 @@ func (s *Dataset) Type() (*Datatype, error) {
 	return dt, err
 }
 
-// Reads raw data from a dataset into a buffer.
-// herr_t H5Dread(hid_t dataset_id, hid_t mem_type_id, hid_t mem_space_id, hid_t file_space_id, hid_t xfer_plist_id, void * buf )
-func (s *Dataset) Read(data interface{}, dtype *Datatype) error {
-	var addr uintptr
-	var tmp_slice []byte
-	post_process := false
-	v := reflect.ValueOf(data)
+// CreatePropList returns a copy of the creation property list of the dataset.
+// hid_t H5Dget_create_plist(hid_t dataset_id)
+func (s *Dataset) CreatePropList() (*PropList, error) {
+	hid := C.H5Dget_create_plist(s.id)
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return nil, err
+	}
+	return new_proplist(hid), nil
+}
 
-	//fmt.Printf(":: read[%s]...\n", v.Kind())
-	switch v.Kind() {
+// AccessPropList returns a copy of the access property list of the dataset.
+// hid_t H5Dget_access_plist(hid_t dataset_id)
+func (s *Dataset) AccessPropList() (*PropList, error) {
+	hid := C.H5Dget_access_plist(s.id)
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return nil, err
+	}
+	return new_proplist(hid), nil
+}
 
-	case reflect.Array:
-		addr = v.UnsafeAddr()
+// Reads raw data from a dataset into a buffer. If dtype is nil, the memory
+// datatype is inferred from the Go type of the elements of data.
+// herr_t H5Dread(hid_t dataset_id, hid_t mem_type_id, hid_t mem_space_id, hid_t file_space_id, hid_t xfer_plist_id, void * buf )
+func (s *Dataset) Read(data interface{}, dtype *Datatype) error {
+	return s.ReadWith(data, dtype, P_DEFAULT)
+}
 
-	case reflect.Slice:
-		if v.Index(0).Kind() == reflect.String && C.H5Tis_variable_str(dtype.id) == 0 {
-			tmp_slice = make([]byte, v.Len()*int(dtype.Size()))
-			addr = reflect.ValueOf(tmp_slice).Pointer()
-			post_process = true
-		} else {
-			addr = v.Pointer()
+// ReadWith reads raw data from a dataset into a buffer with the transfer
+// properties dxpl, e.g. a larger type conversion buffer.
+func (s *Dataset) ReadWith(data interface{}, dtype *Datatype, dxpl *PropList) error {
+	if dtype == nil {
+		dt, owned, err := inferType(data, s.Type)
+		if err != nil {
+			return err
 		}
-
-	case reflect.String:
-		str := (*reflect.StringHeader)(unsafe.Pointer(v.UnsafeAddr()))
-		addr = str.Data
-
-	case reflect.Ptr:
-		addr = v.Pointer()
-
-	default:
-		addr = v.UnsafeAddr()
+		if owned {
+			defer dt.Close()
+		}
+		dtype = dt
 	}
-
-	rc := C.H5Dread(s.id, dtype.id, 0, 0, 0, unsafe.Pointer(addr))
+	var buf unsafe.Pointer
+	v := reflect.ValueOf(data)
+	var strs *stringBuffer
+	if v.Kind() == reflect.Slice && v.Len() > 0 && v.Index(0).Kind() == reflect.String {
+		strs = newStringBuffer(v.Len(), dtype)
+		defer strs.release()
+		buf = strs.ptr()
+	} else {
+		b, err := pinBuffer(data, false)
+		if err != nil {
+			return err
+		}
+		defer b.unpin()
+		buf = b.ptr
+	}
+	start := time.Now()
+	rc := C.H5Dread(s.id, dtype.id, 0, 0, dxpl.id, buf)
 	err := h5err(rc)
+	if metricsOn() {
+		observeTransfer(s.id, dtype, false, start, err)
+	}
+	if l := logger(s.id); l != nil {
+		l.Debug("read", "path", getName(s.id), "bytes", transferSize(s.id, dtype), "error", err)
+	}
 
-	if err == nil && post_process {
-		str_len := int(dtype.Size())
-		p := 0
-		for i := 0; i < v.Len(); i++ {
-			str := tmp_slice[p : p+str_len]
-			n := bytes.Index(str, []byte{0})
-			if n < 0 {
-				n = str_len
-			}
-			v.Index(i).SetString(string(str[:n]))
-			p += str_len
-		}
+	if err == nil && strs != nil {
+		strs.decode(v)
 	}
 
 	return err
 }
 
-// Writes raw data from a buffer to a dataset.
+// Writes raw data from a buffer to a dataset. If dtype is nil, the memory
+// datatype is inferred from the Go type of the elements of data.
 // herr_t H5Dwrite(hid_t dataset_id, hid_t mem_type_id, hid_t mem_space_id, hid_t file_space_id, hid_t xfer_plist_id, const void * buf )
 func (s *Dataset) Write(data interface{}, dtype *Datatype) error {
-	var addr uintptr
+	return s.WriteWith(data, dtype, P_DEFAULT)
+}
+
+// WriteWith writes raw data from a buffer to a dataset with the transfer
+// properties dxpl.
+func (s *Dataset) WriteWith(data interface{}, dtype *Datatype, dxpl *PropList) error {
+	if dtype == nil {
+		dt, owned, err := inferType(data, s.Type)
+		if err != nil {
+			return err
+		}
+		if owned {
+			defer dt.Close()
+		}
+		dtype = dt
+	}
+	var buf unsafe.Pointer
 	v := reflect.ValueOf(data)
+	var strs *stringBuffer
+	switch {
+	case v.Kind() == reflect.Slice && v.Len() > 0 && v.Type().Elem().Kind() == reflect.String:
+		strs = encodeStrings(v, dtype)
+	case v.Kind() == reflect.String && C.H5Tis_variable_str(dtype.id) > 0:
+		strs = encodeStrings(reflect.ValueOf([]string{v.String()}), dtype)
+	}
+	if strs != nil {
+		defer strs.free()
+		buf = strs.ptr()
+	} else {
+		b, err := pinBuffer(data, true)
+		if err != nil {
+			return err
+		}
+		defer b.unpin()
+		buf = b.ptr
+	}
+	start := time.Now()
+	rc := C.H5Dwrite(s.id, dtype.id, 0, 0, dxpl.id, buf)
+	err := h5err(rc)
+	if metricsOn() {
+		observeTransfer(s.id, dtype, true, start, err)
+	}
+	if l := logger(s.id); l != nil {
+		l.Debug("write", "path", getName(s.id), "bytes", transferSize(s.id, dtype), "error", err)
+	}
+	return err
+}
+
+// Creates an attribute attached to the dataset.
+// hid_t H5Acreate2(hid_t loc_id, const char *attr_name, hid_t type_id, hid_t space_id, hid_t acpl_id, hid_t aapl_id)
+func (s *Dataset) CreateAttribute(name string, dtype *Datatype, dspace *Dataspace) (*Attribute, error) {
+	return createAttribute(s.id, name, dtype, dspace, P_DEFAULT)
+}
+
+// CreateAttributeWith creates an attribute attached to the dataset with the
+// attribute creation properties acpl, e.g. for a UTF-8 name.
+func (s *Dataset) CreateAttributeWith(name string, dtype *Datatype, dspace *Dataspace, acpl *PropList) (*Attribute, error) {
+	return createAttribute(s.id, name, dtype, dspace, acpl)
+}
 
-	//fmt.Printf(":: write[%s]...\n", v.Kind())
-	switch v.Kind() {
+// AttributeNames returns the names of the attributes of the dataset, in
+// increasing order of index.
+func (s *Dataset) AttributeNames(index IndexType) ([]string, error) {
+	return attributeNamesByOrder(s.id, index)
+}
 
-	case reflect.Array:
-		addr = v.UnsafeAddr()
+// Opens an attribute attached to the dataset.
+// hid_t H5Aopen(hid_t obj_id, const char *attr_name, hid_t aapl_id)
+func (s *Dataset) OpenAttribute(name string) (*Attribute, error) {
+	return openAttribute(s.id, name)
+}
 
-	case reflect.Slice:
-		addr = v.Pointer()
+// AttributeExists reports whether the dataset has an attribute called name.
+// htri_t H5Aexists(hid_t obj_id, const char *attr_name)
+func (s *Dataset) AttributeExists(name string) (bool, error) {
+	return attributeExists(s.id, name)
+}
 
-	case reflect.String:
-		str := (*reflect.StringHeader)(unsafe.Pointer(v.UnsafeAddr()))
-		addr = str.Data
+// RenameAttribute renames the attribute oldName of the dataset to newName.
+// herr_t H5Arename(hid_t loc_id, const char *old_attr_name, const char *new_attr_name)
+func (s *Dataset) RenameAttribute(oldName, newName string) error {
+	return renameAttribute(s.id, oldName, newName)
+}
 
-	case reflect.Ptr:
-		addr = v.Pointer()
+// DeleteAttribute removes the attribute name from the dataset.
+// herr_t H5Adelete(hid_t loc_id, const char *attr_name)
+func (s *Dataset) DeleteAttribute(name string) error {
+	return deleteAttribute(s.id, name)
+}
 
-	default:
-		addr = v.Pointer()
+// Returns the number of attributes attached to the dataset.
+func (s *Dataset) NumAttributes() (uint, error) {
+	return numAttributes(s.id)
+}
+
+// Returns the name of the attribute at idx, in name order.
+// ssize_t H5Aget_name_by_idx(hid_t loc_id, const char *obj_name, H5_index_t idx_type, H5_iter_order_t order, hsize_t n, char *name, size_t size, hid_t lapl_id)
+func (s *Dataset) AttributeNameByIndex(idx uint) (string, error) {
+	return attributeNameByIndex(s.id, idx)
+}
+
+// CopyTo copies the dataset, with its attributes, to name under the group or
+// file dst, and returns the new dataset.
+//
+// If dcpl is nil the dataset is copied as-is, keeping its chunking, filters
+// and fill value. Otherwise the copy is created with the given creation
+// property list, which is typically obtained from CreatePropList and
+// adjusted, e.g. to change the chunk size or recompress the data; the whole
+// dataset is then read into memory and rewritten.
+// herr_t H5Ocopy(hid_t src_loc_id, const char *src_name, hid_t dst_loc_id, const char *dst_name, hid_t ocpypl_id, hid_t lcpl_id)
+func (s *Dataset) CopyTo(dst Object, name string, dcpl *PropList) (*Dataset, error) {
+	return copyDataset(s, C.hid_t(dst.Id()), name, dcpl)
+}
+
+func copyDataset(s *Dataset, dst_id C.hid_t, name string, dcpl *PropList) (*Dataset, error) {
+	if dcpl == nil {
+		c_name := C.CString(name)
+		defer C.free(unsafe.Pointer(c_name))
+
+		err := h5err(C.H5Ocopy(s.id, cdot, dst_id, c_name, C.H5P_DEFAULT, C.H5P_DEFAULT))
+		if err != nil {
+			return nil, err
+		}
+		return openDataset(dst_id, name)
 	}
 
-	rc := C.H5Dwrite(s.id, dtype.id, 0, 0, 0, unsafe.Pointer(addr))
-	err := h5err(rc)
+	dtype, err := s.Type()
+	if err != nil {
+		return nil, err
+	}
+	defer dtype.Close()
+	dspace := s.Space()
+	if dspace == nil {
+		return nil, fmt.Errorf("could not get dataspace of dataset %q", s.Name())
+	}
+	defer dspace.Close()
+
+	o, err := createDataset(dst_id, name, dtype, dspace, dcpl)
+	if err != nil {
+		return nil, err
+	}
+	if err := copyDatasetData(s, o, dtype, dspace); err != nil {
+		o.Close()
+		return nil, err
+	}
+	if err := copyAttributes(s.id, o.id); err != nil {
+		o.Close()
+		return nil, err
+	}
+	return o, nil
+}
+
+// copyDatasetData copies the raw data of src, which has the datatype dtype
+// and the dataspace dspace, to dst.
+func copyDatasetData(src, dst *Dataset, dtype *Datatype, dspace *Dataspace) error {
+	buf := make([]byte, dspace.SimpleExtentNPoints()*int(dtype.Size()))
+	if len(buf) == 0 {
+		return nil
+	}
+	c_buf := unsafe.Pointer(&buf[0])
+	if err := h5err(C.H5Dread(src.id, dtype.id, 0, 0, 0, c_buf)); err != nil {
+		return err
+	}
+	err := h5err(C.H5Dwrite(dst.id, dtype.id, 0, 0, 0, c_buf))
+	reclaim(dtype.id, dspace.id, c_buf)
 	return err
 }
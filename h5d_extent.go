@@ -0,0 +1,198 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"fmt"
+)
+
+// Resize sets the dimensions of the dataset, which must be chunked, to
+// dims, within its maximum dimensions. Elements past new smaller
+// dimensions are lost.
+// herr_t H5Dset_extent(hid_t dset_id, const hsize_t size[])
+func (s *Dataset) Resize(dims []uint) error {
+	shape, err := s.Shape()
+	if err != nil {
+		return err
+	}
+	if len(dims) != len(shape) {
+		return fmt.Errorf("hdf5: cannot resize a dataset of shape %v to %v", shape, Shape(dims))
+	}
+	if len(dims) == 0 {
+		return nil
+	}
+	return h5err(C.H5Dset_extent(s.id, hsizes(dims)))
+}
+
+// AppendSlice extends the dataset along the dimension axis and writes data,
+// a slice or pointer of values of datatype dtype, to the new elements. The
+// number of elements of data must be a multiple of the product of the
+// other dimensions, which gives how much the dataset grows, e.g. one row
+// of a table of records.
+func (s *Dataset) AppendSlice(data interface{}, dtype *Datatype, axis int) error {
+	buf, err := pinBuffer(data, true)
+	if err != nil {
+		return err
+	}
+	defer buf.unpin()
+	ptr, size := buf.ptr, buf.size
+	shape, err := s.Shape()
+	if err != nil {
+		return err
+	}
+	if axis < 0 || axis >= len(shape) {
+		return fmt.Errorf("hdf5: axis %d out of range of shape %v", axis, shape)
+	}
+	elemSize := int(dtype.Size())
+	if elemSize == 0 || size%elemSize != 0 {
+		return fmt.Errorf("hdf5: %T does not hold values of %d bytes", data, elemSize)
+	}
+	other := uint(1)
+	for i, d := range shape {
+		if i != axis {
+			other *= d
+		}
+	}
+	n := uint(size / elemSize)
+	if other == 0 || n%other != 0 {
+		return fmt.Errorf("hdf5: %d values cannot extend shape %v along axis %d", n, shape, axis)
+	}
+	if n == 0 {
+		return nil
+	}
+
+	start := make([]uint, len(shape))
+	count := append([]uint(nil), shape...)
+	start[axis] = shape[axis]
+	count[axis] = n / other
+	dims := append([]uint(nil), shape...)
+	dims[axis] += count[axis]
+	if err := s.Resize(dims); err != nil {
+		return err
+	}
+
+	fspace := s.Space()
+	if fspace == nil {
+		return fmt.Errorf("hdf5: could not get the dataspace of the dataset")
+	}
+	defer fspace.Close()
+	if err := fspace.SelectHyperslab(S_SELECT_SET, start, nil, count, nil); err != nil {
+		return err
+	}
+	mspace, err := CreateSimpleDataspace(count, nil)
+	if err != nil {
+		return err
+	}
+	defer mspace.Close()
+	return h5err(C.H5Dwrite(s.id, dtype.id, mspace.id, fspace.id, C.H5P_DEFAULT, ptr))
+}
+
+// WriteAt writes data, a slice or pointer of values of datatype dtype, to
+// the block of the dataset of dimensions count that starts at offset.
+func (s *Dataset) WriteAt(data interface{}, dtype *Datatype, offset, count []uint) error {
+	return s.WriteSubset(data, dtype, offset, nil, count)
+}
+
+// ReadAt reads the block of the dataset of dimensions count that starts
+// at offset into data, a slice or pointer of values of datatype dtype.
+func (s *Dataset) ReadAt(data interface{}, dtype *Datatype, offset, count []uint) error {
+	return s.ReadSubset(data, dtype, offset, nil, count)
+}
+
+// WriteSubset writes data, a slice or pointer of values of datatype dtype,
+// in row-major order to the elements of the dataset that start at offset
+// and follow in steps of stride, count of them in each dimension. stride
+// may be nil for steps of 1. A nil dtype is inferred from data, as for
+// Write.
+func (s *Dataset) WriteSubset(data interface{}, dtype *Datatype, offset, stride, count []uint) error {
+	return s.transferSubset(data, dtype, offset, stride, count, true)
+}
+
+// ReadSubset reads into data, a slice or pointer of values of datatype
+// dtype, the elements of the dataset that start at offset and follow in
+// steps of stride, count of them in each dimension, in row-major order.
+// stride may be nil for steps of 1. A nil dtype is inferred from data, as
+// for Read.
+func (s *Dataset) ReadSubset(data interface{}, dtype *Datatype, offset, stride, count []uint) error {
+	return s.transferSubset(data, dtype, offset, stride, count, false)
+}
+
+// ReadSelection reads into data, a slice or pointer of values of datatype
+// dtype, the elements of the dataset selected in fspace, a dataspace of
+// its extent such as one returned by DereferenceRegion, in the order of
+// the selection. A nil dtype is inferred from data, as for Read.
+// herr_t H5Dread(hid_t dataset_id, hid_t mem_type_id, hid_t mem_space_id, hid_t file_space_id, hid_t xfer_plist_id, void * buf)
+func (s *Dataset) ReadSelection(data interface{}, dtype *Datatype, fspace *Dataspace) error {
+	if dtype == nil {
+		dt, owned, err := inferType(data, s.Type)
+		if err != nil {
+			return err
+		}
+		if owned {
+			defer dt.Close()
+		}
+		dtype = dt
+	}
+	buf, err := pinBuffer(data, false)
+	if err != nil {
+		return err
+	}
+	defer buf.unpin()
+	n := uint(fspace.SelectNPoints())
+	if need := n * dtype.Size(); uint(buf.size) < need {
+		return fmt.Errorf("hdf5: selection of %d elements needs %d bytes, %T has %d", n, need, data, buf.size)
+	}
+	if n == 0 {
+		return nil
+	}
+	mspace, err := CreateSimpleDataspace([]uint{n}, nil)
+	if err != nil {
+		return err
+	}
+	defer mspace.Close()
+	return h5err(C.H5Dread(s.id, dtype.id, mspace.id, fspace.id, C.H5P_DEFAULT, buf.ptr))
+}
+
+func (s *Dataset) transferSubset(data interface{}, dtype *Datatype, offset, stride, count []uint, write bool) error {
+	if dtype == nil {
+		dt, owned, err := inferType(data, s.Type)
+		if err != nil {
+			return err
+		}
+		if owned {
+			defer dt.Close()
+		}
+		dtype = dt
+	}
+	buf, err := pinBuffer(data, write)
+	if err != nil {
+		return err
+	}
+	defer buf.unpin()
+	ptr, size := buf.ptr, buf.size
+	if need := Shape(count).Size() * dtype.Size(); uint(size) < need {
+		return fmt.Errorf("hdf5: selection of %v needs %d bytes, %T has %d", Shape(count), need, data, size)
+	}
+	if Shape(count).Size() == 0 {
+		return nil
+	}
+
+	fspace := s.Space()
+	if fspace == nil {
+		return fmt.Errorf("hdf5: could not get the dataspace of the dataset")
+	}
+	defer fspace.Close()
+	if err := fspace.SelectHyperslab(S_SELECT_SET, offset, stride, count, nil); err != nil {
+		return err
+	}
+	mspace, err := CreateSimpleDataspace(count, nil)
+	if err != nil {
+		return err
+	}
+	defer mspace.Close()
+	if write {
+		return h5err(C.H5Dwrite(s.id, dtype.id, mspace.id, fspace.id, C.H5P_DEFAULT, ptr))
+	}
+	return h5err(C.H5Dread(s.id, dtype.id, mspace.id, fspace.id, C.H5P_DEFAULT, ptr))
+}
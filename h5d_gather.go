@@ -0,0 +1,117 @@
+package hdf5
+
+// #include "hdf5.h"
+// typedef struct { const void *buf; size_t size; } _go_hdf5_scatter_src;
+// static herr_t _go_hdf5_scatter_cb(const void **src_buf, size_t *src_buf_bytes_used, void *op_data) {
+//   _go_hdf5_scatter_src *src = op_data;
+//   *src_buf = src->buf;
+//   *src_buf_bytes_used = src->size;
+//   src->size = 0;
+//   return 0;
+// }
+// static herr_t _go_hdf5_H5Dscatter(const void *src_buf, size_t src_size, hid_t type_id, hid_t dst_space_id, void *dst_buf) {
+//   _go_hdf5_scatter_src src = { src_buf, src_size };
+//   return H5Dscatter(_go_hdf5_scatter_cb, &src, type_id, dst_space_id, dst_buf);
+// }
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// bufferOf returns the address and size in bytes of the memory of data,
+// a slice or a pointer to a value, for access from Go. Memory passed to the
+// library is pinned with pinBuffer instead.
+func bufferOf(data interface{}) (unsafe.Pointer, int, error) {
+	v := reflect.ValueOf(data)
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return nil, 0, nil
+		}
+		return v.UnsafePointer(), v.Len() * int(v.Type().Elem().Size()), nil
+	case reflect.Ptr:
+		return v.UnsafePointer(), int(v.Type().Elem().Size()), nil
+	}
+	return nil, 0, fmt.Errorf("hdf5: cannot use %T as a buffer", data)
+}
+
+// noGoPointers returns an error if the elements of dst, a slice or a
+// pointer, hold Go pointers, which the library would write into Go memory
+// behind the write barriers of the garbage collector, as cgo forbids.
+func noGoPointers(dst interface{}) error {
+	t := reflect.TypeOf(dst)
+	if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Ptr) && hasPointers(t.Elem()) {
+		return fmt.Errorf("hdf5: cannot copy into %s elements, which hold Go pointers", t.Elem())
+	}
+	return nil
+}
+
+// Gather copies the elements of src selected by space, which describes
+// src, to the start of dst, contiguously in selection order. The elements
+// are of datatype dtype. src and dst are slices or pointers; src must hold
+// the whole extent of space and dst the whole selection. The elements of dst may not hold Go pointers,
+// such as strings or slices.
+// herr_t H5Dgather(hid_t src_space_id, const void *src_buf, hid_t type_id, size_t dst_buf_size, void *dst_buf, H5D_gather_func_t op, void *op_data)
+func Gather(space *Dataspace, src interface{}, dtype *Datatype, dst interface{}) error {
+	if err := noGoPointers(dst); err != nil {
+		return err
+	}
+	c_src, err := pinBuffer(src, true)
+	if err != nil {
+		return err
+	}
+	defer c_src.unpin()
+	c_dst, err := pinBuffer(dst, false)
+	if err != nil {
+		return err
+	}
+	defer c_dst.unpin()
+	size := c_dst.size
+	need := space.SelectNPoints() * int(dtype.Size())
+	if size < need {
+		return fmt.Errorf("hdf5: gather needs %d bytes, destination has %d", need, size)
+	}
+	if need == 0 {
+		return nil
+	}
+	if extent := space.SimpleExtentNPoints() * int(dtype.Size()); c_src.size < extent {
+		return fmt.Errorf("hdf5: gather from a dataspace of %d bytes, source has %d", extent, c_src.size)
+	}
+	return h5err(C.H5Dgather(space.id, c_src.ptr, dtype.id, C.size_t(size), c_dst.ptr, nil, nil))
+}
+
+// Scatter copies the elements at the start of src, of datatype dtype, to
+// the elements of dst selected by space, which describes dst, in selection
+// order. src and dst are slices or pointers; src must hold the whole
+// selection and dst the whole extent of space. The elements of dst may not hold Go pointers.
+// herr_t H5Dscatter(H5D_scatter_func_t op, void *op_data, hid_t type_id, hid_t dst_space_id, void *dst_buf)
+func Scatter(src interface{}, dtype *Datatype, space *Dataspace, dst interface{}) error {
+	if err := noGoPointers(dst); err != nil {
+		return err
+	}
+	c_src, err := pinBuffer(src, true)
+	if err != nil {
+		return err
+	}
+	defer c_src.unpin()
+	c_dst, err := pinBuffer(dst, false)
+	if err != nil {
+		return err
+	}
+	defer c_dst.unpin()
+	size := c_src.size
+	need := space.SelectNPoints() * int(dtype.Size())
+	if size < need {
+		return fmt.Errorf("hdf5: scatter needs %d bytes, source has %d", need, size)
+	}
+	if need == 0 {
+		return nil
+	}
+	if extent := space.SimpleExtentNPoints() * int(dtype.Size()); c_dst.size < extent {
+		return fmt.Errorf("hdf5: scatter to a dataspace of %d bytes, destination has %d", extent, c_dst.size)
+	}
+	return h5err(C._go_hdf5_H5Dscatter(c_src.ptr, C.size_t(need), dtype.id, space.id, c_dst.ptr))
+}
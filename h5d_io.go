@@ -0,0 +1,218 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// streamType returns the native memory datatype of the elements of the
+// one-dimensional dataset s, which must be of a fixed size, with its size
+// and the number of elements of s.
+func streamType(s *Dataset) (C.hid_t, int, uint, error) {
+	shape, _, err := s.extent()
+	if err != nil {
+		return -1, 0, 0, err
+	}
+	if len(shape) != 1 {
+		return -1, 0, 0, fmt.Errorf("hdf5: a stream needs a one-dimensional dataset, %s has shape %v", s.Name(), shape)
+	}
+	ftype := C.H5Dget_type(s.id)
+	if err := h5err(C.herr_t(int(ftype))); err != nil {
+		return -1, 0, 0, err
+	}
+	defer C.H5Tclose(ftype)
+	mtype := C.H5Tget_native_type(ftype, C.H5T_DIR_DEFAULT)
+	if err := h5err(C.herr_t(int(mtype))); err != nil {
+		return -1, 0, 0, err
+	}
+	fixedString := C.H5Tget_class(mtype) == C.H5T_STRING && C.H5Tis_variable_str(mtype) == 0
+	if hasVarLen(mtype) && !fixedString {
+		C.H5Tclose(mtype)
+		return -1, 0, 0, fmt.Errorf("hdf5: cannot stream the variable-length elements of %s", s.Name())
+	}
+	return mtype, int(C.H5Tget_size(mtype)), shape[0], nil
+}
+
+// DatasetReader reads a one-dimensional dataset of fixed-size records,
+// such as bytes, as a stream of their bytes in native memory layout.
+type DatasetReader struct {
+	s     *Dataset
+	mtype C.hid_t
+	size  int   // bytes per record
+	len   int64 // bytes of the dataset
+	off   int64
+}
+
+// NewDatasetReader returns a reader of the bytes of the elements of the
+// one-dimensional dataset s, which remains owned by the caller. Elements
+// added to s later are not read.
+func NewDatasetReader(s *Dataset) (*DatasetReader, error) {
+	mtype, size, n, err := streamType(s)
+	if err != nil {
+		return nil, err
+	}
+	return &DatasetReader{s: s, mtype: mtype, size: size, len: int64(n) * int64(size)}, nil
+}
+
+// Size returns the number of bytes of the dataset.
+func (r *DatasetReader) Size() int64 {
+	return r.len
+}
+
+// Read implements io.Reader.
+func (r *DatasetReader) Read(p []byte) (int, error) {
+	n, err := r.ReadAt(p, r.off)
+	r.off += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+// ReadAt implements io.ReaderAt, reading the records which p overlaps.
+func (r *DatasetReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("hdf5: negative offset")
+	}
+	if off >= r.len {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	end := min(off+int64(len(p)), r.len)
+	first := off / int64(r.size)
+	last := (end + int64(r.size) - 1) / int64(r.size)
+	buf := getScratch(int(last-first) * r.size)
+	defer putScratch(buf)
+	if err := transferRecords(r.s, r.mtype, buf, uint(first), uint(last-first), false); err != nil {
+		return 0, err
+	}
+	n := copy(p, buf[off-first*int64(r.size):])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Seek implements io.Seeker.
+func (r *DatasetReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.off
+	case io.SeekEnd:
+		offset += r.len
+	default:
+		return 0, fmt.Errorf("hdf5: invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return 0, errors.New("hdf5: negative position")
+	}
+	r.off = offset
+	return offset, nil
+}
+
+// Close releases the reader, leaving the dataset open.
+func (r *DatasetReader) Close() error {
+	if r.mtype < 0 {
+		return nil
+	}
+	err := h5err(C.H5Tclose(r.mtype))
+	r.mtype = -1
+	return err
+}
+
+// DatasetWriter appends to a one-dimensional extendible dataset of
+// fixed-size records, such as bytes, from a stream of their bytes in
+// native memory layout.
+type DatasetWriter struct {
+	s       *Dataset
+	mtype   C.hid_t
+	size    int    // bytes per record
+	n       uint   // records in the dataset
+	pending []byte // bytes of an incomplete record
+}
+
+// NewDatasetWriter returns a writer appending to the one-dimensional
+// dataset s, which must be chunked with an unlimited maximum dimension and
+// remains owned by the caller. Each Write extends s by the records it
+// completes; the bytes of an incomplete record are kept until the next
+// Write.
+func NewDatasetWriter(s *Dataset) (*DatasetWriter, error) {
+	mtype, size, n, err := streamType(s)
+	if err != nil {
+		return nil, err
+	}
+	return &DatasetWriter{s: s, mtype: mtype, size: size, n: n}, nil
+}
+
+// Write implements io.Writer.
+func (w *DatasetWriter) Write(p []byte) (int, error) {
+	if w.mtype < 0 {
+		return 0, errors.New("hdf5: write to a closed dataset writer")
+	}
+	data := p
+	if len(w.pending) > 0 {
+		data = append(w.pending, p...)
+	}
+	k := len(data) / w.size
+	if k > 0 {
+		if err := w.s.Resize([]uint{w.n + uint(k)}); err != nil {
+			return 0, err
+		}
+		if err := transferRecords(w.s, w.mtype, data[:k*w.size], w.n, uint(k), true); err != nil {
+			return 0, err
+		}
+		w.n += uint(k)
+	}
+	w.pending = append(w.pending[:0], data[k*w.size:]...)
+	return len(p), nil
+}
+
+// Close releases the writer, leaving the dataset open. It returns an
+// error if the bytes written end with an incomplete record, which is
+// dropped.
+func (w *DatasetWriter) Close() error {
+	if w.mtype < 0 {
+		return nil
+	}
+	err := h5err(C.H5Tclose(w.mtype))
+	w.mtype = -1
+	if len(w.pending) > 0 && err == nil {
+		err = fmt.Errorf("hdf5: %d bytes of an incomplete record of %d bytes not written", len(w.pending), w.size)
+	}
+	w.pending = nil
+	return err
+}
+
+// transferRecords reads or writes buf as the n records of the memory
+// datatype mtype of the dataset s that start at first.
+func transferRecords(s *Dataset, mtype C.hid_t, buf []byte, first, n uint, write bool) error {
+	fspace := s.Space()
+	if fspace == nil {
+		return fmt.Errorf("hdf5: could not get the dataspace of the dataset")
+	}
+	defer fspace.Close()
+	if err := fspace.SelectHyperslab(S_SELECT_SET, []uint{first}, nil, []uint{n}, nil); err != nil {
+		return err
+	}
+	mspace, err := CreateSimpleDataspace([]uint{n}, nil)
+	if err != nil {
+		return err
+	}
+	defer mspace.Close()
+	b, err := pinBuffer(buf, write)
+	if err != nil {
+		return err
+	}
+	defer b.unpin()
+	if write {
+		return h5err(C.H5Dwrite(s.id, mtype, mspace.id, fspace.id, C.H5P_DEFAULT, b.ptr))
+	}
+	return h5err(C.H5Dread(s.id, mtype, mspace.id, fspace.id, C.H5P_DEFAULT, b.ptr))
+}
@@ -0,0 +1,105 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// #if H5_VERSION_GE(1,14,0)
+// static herr_t _go_hdf5_H5Dread_multi(size_t count, hid_t *dset_id, hid_t *mem_type_id, hid_t *mem_space_id, hid_t *file_space_id, hid_t dxpl_id, void **buf) {
+//   return H5Dread_multi(count, dset_id, mem_type_id, mem_space_id, file_space_id, dxpl_id, buf);
+// }
+// static herr_t _go_hdf5_H5Dwrite_multi(size_t count, hid_t *dset_id, hid_t *mem_type_id, hid_t *mem_space_id, hid_t *file_space_id, hid_t dxpl_id, void **buf) {
+//   return H5Dwrite_multi(count, dset_id, mem_type_id, mem_space_id, file_space_id, dxpl_id, (const void **)buf);
+// }
+// #else
+// static herr_t _go_hdf5_H5Dread_multi(size_t count, hid_t *dset_id, hid_t *mem_type_id, hid_t *mem_space_id, hid_t *file_space_id, hid_t dxpl_id, void **buf) { return -1; }
+// static herr_t _go_hdf5_H5Dwrite_multi(size_t count, hid_t *dset_id, hid_t *mem_type_id, hid_t *mem_space_id, hid_t *file_space_id, hid_t dxpl_id, void **buf) { return -1; }
+// #endif
+import "C"
+
+import (
+	"unsafe"
+)
+
+// Request is one dataset transfer of ReadMulti or WriteMulti.
+type Request struct {
+	Dataset   *Dataset
+	Data      interface{} // slice or pointer holding the values
+	Type      *Datatype   // datatype of the values in Data
+	MemSpace  *Dataspace  // selection of Data, or nil for all of it
+	FileSpace *Dataspace  // selection of the dataset, or nil for all of it
+}
+
+func spaceOrAll(s *Dataspace) C.hid_t {
+	if s == nil {
+		return C.H5S_ALL
+	}
+	return s.id
+}
+
+// ReadMulti reads the datasets of reqs in a single call, which saves the
+// overhead of many small reads. Libraries before 1.14 read the datasets
+// one by one.
+// herr_t H5Dread_multi(size_t count, hid_t dset_id[], hid_t mem_type_id[], hid_t mem_space_id[], hid_t file_space_id[], hid_t dxpl_id, void *buf[])
+func ReadMulti(reqs []Request) error {
+	return transferMulti(reqs, false)
+}
+
+// WriteMulti writes the datasets of reqs in a single call. Libraries before
+// 1.14 write the datasets one by one.
+// herr_t H5Dwrite_multi(size_t count, hid_t dset_id[], hid_t mem_type_id[], hid_t mem_space_id[], hid_t file_space_id[], hid_t dxpl_id, const void *buf[])
+func WriteMulti(reqs []Request) error {
+	return transferMulti(reqs, true)
+}
+
+func transferMulti(reqs []Request, write bool) error {
+	n := len(reqs)
+	if n == 0 {
+		return nil
+	}
+	ptrs := make([]unsafe.Pointer, n)
+	for i, r := range reqs {
+		b, err := pinBuffer(r.Data, write)
+		if err != nil {
+			return err
+		}
+		defer b.unpin()
+		ptrs[i] = b.ptr
+	}
+
+	if !headerVersion.atLeast(1, 14, 0) {
+		for i, r := range reqs {
+			mspace, fspace := spaceOrAll(r.MemSpace), spaceOrAll(r.FileSpace)
+			var err error
+			if write {
+				err = h5err(C.H5Dwrite(r.Dataset.id, r.Type.id, mspace, fspace, C.H5P_DEFAULT, ptrs[i]))
+			} else {
+				err = h5err(C.H5Dread(r.Dataset.id, r.Type.id, mspace, fspace, C.H5P_DEFAULT, ptrs[i]))
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	dsets := make([]C.hid_t, n)
+	mtypes := make([]C.hid_t, n)
+	mspaces := make([]C.hid_t, n)
+	fspaces := make([]C.hid_t, n)
+	for i, r := range reqs {
+		dsets[i] = r.Dataset.id
+		mtypes[i] = r.Type.id
+		mspaces[i] = spaceOrAll(r.MemSpace)
+		fspaces[i] = spaceOrAll(r.FileSpace)
+	}
+
+	// The array of buffers is passed through C memory, which may hold the
+	// addresses of the buffers since they are pinned.
+	c_bufs := C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof(uintptr(0))))
+	defer C.free(c_bufs)
+	copy(unsafe.Slice((*unsafe.Pointer)(c_bufs), n), ptrs)
+
+	if write {
+		return h5err(C._go_hdf5_H5Dwrite_multi(C.size_t(n), &dsets[0], &mtypes[0], &mspaces[0], &fspaces[0], C.H5P_DEFAULT, (*unsafe.Pointer)(c_bufs)))
+	}
+	return h5err(C._go_hdf5_H5Dread_multi(C.size_t(n), &dsets[0], &mtypes[0], &mspaces[0], &fspaces[0], C.H5P_DEFAULT, (*unsafe.Pointer)(c_bufs)))
+}
@@ -0,0 +1,146 @@
+package hdf5
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// WriteNested writes data, a rectangular nested slice such as a
+// [][]float64 or a [][][]int32 of values of datatype dtype, to a dataset
+// of the same shape. Each level of nesting is a dimension, outermost
+// first; levels past the rank of the dataset are part of the elements,
+// e.g. variable-length sequences.
+func (s *Dataset) WriteNested(data interface{}, dtype *Datatype) error {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("hdf5: cannot write a %T as a nested slice", data)
+	}
+	shape, err := s.Shape()
+	if err != nil {
+		return err
+	}
+	if len(shape) == 0 {
+		return fmt.Errorf("hdf5: cannot write a nested slice to a scalar dataset")
+	}
+	dims, err := nestedDims(v, len(shape))
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual([]uint(shape), dims) {
+		return fmt.Errorf("hdf5: cannot write a slice of shape %v to a dataset of shape %v", Shape(dims), shape)
+	}
+	flat := reflect.MakeSlice(reflect.SliceOf(nestedElem(v.Type(), len(dims))), 0, int(Shape(dims).Size()))
+	flat = flatten(flat, v, len(dims))
+	if flat.Len() == 0 {
+		return nil
+	}
+	return s.Write(flat.Interface(), dtype)
+}
+
+// ReadNested reads the dataset into the nested slice data points to, such
+// as a *[][]float64 for a two-dimensional dataset of values of datatype
+// dtype, allocating slices of the dataset's shape. The nested slices
+// share a single backing array.
+func (s *Dataset) ReadNested(data interface{}, dtype *Datatype) error {
+	ptr := reflect.ValueOf(data)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("hdf5: cannot read into a %T, need a pointer to a slice", data)
+	}
+	shape, err := s.Shape()
+	if err != nil {
+		return err
+	}
+	rt := ptr.Elem().Type()
+	depth := 0
+	for t := rt; t.Kind() == reflect.Slice; t = t.Elem() {
+		depth++
+	}
+	if depth < len(shape) || len(shape) == 0 {
+		return fmt.Errorf("hdf5: cannot read a dataset of shape %v into a %s", shape, rt)
+	}
+	// Levels of nesting past the rank of the dataset are part of the
+	// elements, e.g. variable-length sequences.
+	depth = len(shape)
+
+	n := int(shape.Size())
+	flat := reflect.MakeSlice(reflect.SliceOf(nestedElem(rt, depth)), n, n)
+	if n > 0 {
+		if err := s.Read(flat.Interface(), dtype); err != nil {
+			return err
+		}
+	}
+	ptr.Elem().Set(unflatten(rt, flat, shape))
+	return nil
+}
+
+// nestedDims returns the dimensions of the outer depth levels of the
+// nested slice v, or fewer if it is not as deep, checking that every slice
+// at a level has the same length.
+func nestedDims(v reflect.Value, depth int) ([]uint, error) {
+	var dims []uint
+	for e := v; len(dims) < depth && e.Kind() == reflect.Slice; {
+		dims = append(dims, uint(e.Len()))
+		if e.Len() > 0 {
+			e = e.Index(0)
+		} else {
+			e = reflect.Zero(e.Type().Elem())
+		}
+	}
+	if err := checkRectangular(v, dims, nil); err != nil {
+		return nil, err
+	}
+	return dims, nil
+}
+
+func checkRectangular(v reflect.Value, dims []uint, index []int) error {
+	if len(dims) == 0 {
+		return nil
+	}
+	if uint(v.Len()) != dims[0] {
+		return fmt.Errorf("hdf5: slice at %v has length %d, want %d", index, v.Len(), dims[0])
+	}
+	if len(dims) == 1 {
+		return nil
+	}
+	for i := 0; i < v.Len(); i++ {
+		if err := checkRectangular(v.Index(i), dims[1:], append(index, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nestedElem returns the element type of t after depth levels of slices.
+func nestedElem(t reflect.Type, depth int) reflect.Type {
+	for i := 0; i < depth; i++ {
+		t = t.Elem()
+	}
+	return t
+}
+
+// flatten appends the elements of the nested slice v, depth levels deep,
+// to flat in row-major order.
+func flatten(flat, v reflect.Value, depth int) reflect.Value {
+	if depth == 1 {
+		return reflect.AppendSlice(flat, v)
+	}
+	for i := 0; i < v.Len(); i++ {
+		flat = flatten(flat, v.Index(i), depth-1)
+	}
+	return flat
+}
+
+// unflatten returns a nested slice of type t and dimensions shape whose
+// innermost slices are consecutive parts of flat.
+func unflatten(t reflect.Type, flat reflect.Value, shape Shape) reflect.Value {
+	if len(shape) == 1 {
+		return flat
+	}
+	n := int(shape[0])
+	v := reflect.MakeSlice(t, n, n)
+	step := int(shape[1:].Size())
+	for i := 0; i < n; i++ {
+		v.Index(i).Set(unflatten(t.Elem(), flat.Slice3(i*step, (i+1)*step, (i+1)*step), shape[1:]))
+	}
+	return v
+}
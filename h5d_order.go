@@ -0,0 +1,119 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// ReadColumnMajor reads the whole dataset like Read, but into data in
+// column-major order, with the first index varying fastest, as Fortran
+// and Julia arrays are laid out. data is a slice or pointer of values of
+// datatype dtype, which must not hold strings or variable-length data,
+// even as members of compounds. A nil dtype is inferred as for Read.
+func (s *Dataset) ReadColumnMajor(data interface{}, dtype *Datatype) error {
+	if dtype == nil {
+		dt, owned, err := inferType(data, s.Type)
+		if err != nil {
+			return err
+		}
+		if owned {
+			defer dt.Close()
+		}
+		dtype = dt
+	}
+	dst, shape, err := s.columnMajorBuffer(data, dtype)
+	if err != nil || dst == nil {
+		return err
+	}
+	tmp := make([]byte, len(dst))
+	if err := s.Read(tmp, dtype); err != nil {
+		return err
+	}
+	transpose(dst, tmp, shape, int(dtype.Size()), true)
+	return nil
+}
+
+// WriteColumnMajor writes the whole dataset like Write, but from data in
+// column-major order, with the first index varying fastest. data is a
+// slice or pointer of values of datatype dtype, which must not hold
+// strings or variable-length data, even as members of compounds. A nil
+// dtype is inferred as for Write.
+func (s *Dataset) WriteColumnMajor(data interface{}, dtype *Datatype) error {
+	if dtype == nil {
+		dt, owned, err := inferType(data, s.Type)
+		if err != nil {
+			return err
+		}
+		if owned {
+			defer dt.Close()
+		}
+		dtype = dt
+	}
+	src, shape, err := s.columnMajorBuffer(data, dtype)
+	if err != nil || src == nil {
+		return err
+	}
+	tmp := make([]byte, len(src))
+	transpose(tmp, src, shape, int(dtype.Size()), false)
+	return s.Write(tmp, dtype)
+}
+
+// columnMajorBuffer returns the memory of data holding the whole dataset,
+// or nil if the dataset is empty, and the shape of the dataset.
+func (s *Dataset) columnMajorBuffer(data interface{}, dtype *Datatype) ([]byte, Shape, error) {
+	if hasVarLen(dtype.id) {
+		return nil, nil, fmt.Errorf("hdf5: cannot reorder strings or variable-length data")
+	}
+	ptr, size, err := bufferOf(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	shape, err := s.Shape()
+	if err != nil {
+		return nil, nil, err
+	}
+	n := int(shape.Size() * dtype.Size())
+	if size < n {
+		return nil, nil, fmt.Errorf("hdf5: dataset of shape %v needs %d bytes, %T has %d", shape, n, data, size)
+	}
+	if n == 0 {
+		return nil, shape, nil
+	}
+	return unsafe.Slice((*byte)(ptr), n), shape, nil
+}
+
+// transpose copies the elements of size bytes of an array of dimensions
+// shape from src to dst, from row-major to column-major order if
+// toColumnMajor is true and back if not.
+func transpose(dst, src []byte, shape Shape, size int, toColumnMajor bool) {
+	colStrides := make([]uint, len(shape))
+	n := uint(1)
+	for i, d := range shape {
+		colStrides[i] = n
+		n *= d
+	}
+	index := make([]uint, len(shape))
+	for r := uint(0); r < n; r++ {
+		c := uint(0)
+		for i := range index {
+			c += index[i] * colStrides[i]
+		}
+		from, to := r, c
+		if !toColumnMajor {
+			from, to = c, r
+		}
+		copy(dst[int(to)*size:int(to+1)*size], src[int(from)*size:int(from+1)*size])
+
+		// Advance index in row-major order.
+		for i := len(index) - 1; i >= 0; i-- {
+			index[i]++
+			if index[i] < shape[i] {
+				break
+			}
+			index[i] = 0
+		}
+	}
+}
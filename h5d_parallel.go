@@ -0,0 +1,379 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdint.h>
+// #if H5_VERSION_GE(1,10,5)
+// static herr_t _go_hdf5_H5Dget_num_chunks(hid_t dset_id, hsize_t *nchunks) {
+//   return H5Dget_num_chunks(dset_id, H5S_ALL, nchunks);
+// }
+// static herr_t _go_hdf5_H5Dget_chunk_info(hid_t dset_id, hsize_t index, hsize_t *offset, unsigned *filter_mask, hsize_t *size) {
+//   haddr_t addr;
+//   return H5Dget_chunk_info(dset_id, H5S_ALL, index, offset, filter_mask, &addr, size);
+// }
+// static herr_t _go_hdf5_H5Dread_chunk(hid_t dset_id, const hsize_t *offset, unsigned *filters, void *buf) {
+//   uint32_t mask = 0;
+//   herr_t err = H5Dread_chunk(dset_id, H5P_DEFAULT, offset, &mask, buf);
+//   *filters = mask;
+//   return err;
+// }
+// #else
+// static herr_t _go_hdf5_H5Dget_num_chunks(hid_t dset_id, hsize_t *nchunks) { return -1; }
+// static herr_t _go_hdf5_H5Dget_chunk_info(hid_t dset_id, hsize_t index, hsize_t *offset, unsigned *filter_mask, hsize_t *size) { return -1; }
+// static herr_t _go_hdf5_H5Dread_chunk(hid_t dset_id, const hsize_t *offset, unsigned *filters, void *buf) { return -1; }
+// #endif
+import "C"
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// ChunkDecoder reverses a filter of the filter pipeline on the stored data
+// of a chunk, given the client data values of the filter and the size of
+// the elements of the dataset, and returns the decoded data. Decoders are
+// called from several goroutines at once.
+type ChunkDecoder func(data []byte, cd []uint, elemSize int) ([]byte, error)
+
+var (
+	chunkDecodersMu sync.RWMutex
+	chunkDecoders   = map[Filter]ChunkDecoder{
+		Z_FILTER_DEFLATE: inflateChunk,
+		Z_FILTER_SHUFFLE: unshuffleChunk,
+	}
+)
+
+// RegisterChunkDecoder sets the decoder ReadParallel uses for the filter f,
+// such as a zstd or lz4 plugin filter. Deflate and shuffle are decoded
+// without registration.
+func RegisterChunkDecoder(f Filter, dec ChunkDecoder) {
+	chunkDecodersMu.Lock()
+	defer chunkDecodersMu.Unlock()
+	chunkDecoders[f] = dec
+}
+
+func chunkDecoder(f Filter) ChunkDecoder {
+	chunkDecodersMu.RLock()
+	defer chunkDecodersMu.RUnlock()
+	return chunkDecoders[f]
+}
+
+func inflateChunk(data []byte, cd []uint, elemSize int) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func unshuffleChunk(data []byte, cd []uint, elemSize int) ([]byte, error) {
+	if elemSize <= 1 {
+		return data, nil
+	}
+	n := len(data) / elemSize
+	out := make([]byte, len(data))
+	for j := 0; j < elemSize; j++ {
+		for i := 0; i < n; i++ {
+			out[i*elemSize+j] = data[j*n+i]
+		}
+	}
+	// Bytes past the last whole element are not shuffled.
+	copy(out[n*elemSize:], data[n*elemSize:])
+	return out, nil
+}
+
+// pipelineFilter is a filter of the filter pipeline of a dataset.
+type pipelineFilter struct {
+	id  Filter
+	cd  []uint
+	dec ChunkDecoder
+}
+
+// chunkPipeline returns the filters of the pipeline of dcpl, in the order
+// they are applied when writing, or false if one of them has no decoder.
+func chunkPipeline(dcpl *PropList) ([]pipelineFilter, bool) {
+	n := int(C.H5Pget_nfilters(dcpl.id))
+	if n < 0 {
+		return nil, false
+	}
+	filters := make([]pipelineFilter, n)
+	for i := range filters {
+		var flags, config C.uint
+		c_cd := make([]C.uint, 32)
+		nelmts := C.size_t(len(c_cd))
+		id := C.H5Pget_filter2(dcpl.id, C.uint(i), &flags, &nelmts, &c_cd[0], 0, nil, &config)
+		if id < 0 {
+			return nil, false
+		}
+		filters[i].id = Filter(id)
+		filters[i].dec = chunkDecoder(filters[i].id)
+		if filters[i].dec == nil {
+			return nil, false
+		}
+		for _, v := range c_cd[:min(int(nelmts), len(c_cd))] {
+			filters[i].cd = append(filters[i].cd, uint(v))
+		}
+	}
+	return filters, true
+}
+
+// rawChunk is the stored data of a chunk.
+type rawChunk struct {
+	offset []uint
+	mask   uint
+	data   []byte
+}
+
+// ReadParallel reads the whole dataset into data, a slice or pointer
+// holding all its elements in the memory datatype dtype, decoding its
+// chunks on workers goroutines, or one per CPU if workers is not
+// positive. The chunks are read from the file in turn, as the library may
+// not be called concurrently, while they are decompressed and copied into
+// place in parallel. Only chunked datasets whose filters all have a
+// ChunkDecoder, read in their own datatype, take this path; others are
+// read with Read. It needs HDF5 1.10.5 for the parallel path.
+// herr_t H5Dread_chunk(hid_t dset_id, hid_t dxpl_id, const hsize_t *offset, uint32_t *filters, void *buf)
+func (s *Dataset) ReadParallel(data interface{}, dtype *Datatype, workers int) error {
+	if dtype == nil {
+		dt, owned, err := inferType(data, s.Type)
+		if err != nil {
+			return err
+		}
+		if owned {
+			defer dt.Close()
+		}
+		dtype = dt
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	r, ok, err := s.chunkReader(data, dtype)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return s.Read(data, dtype)
+	}
+	return r.run(workers)
+}
+
+// chunkReader holds the layout of a parallel chunked read.
+type chunkReader struct {
+	s        *Dataset
+	dims     []uint
+	chunk    []uint
+	elemSize int
+	filters  []pipelineFilter
+	dst      []byte
+}
+
+// chunkReader returns the reader of the chunks of the dataset into data,
+// or false if the dataset cannot be read chunk by chunk in the memory
+// datatype dtype.
+func (s *Dataset) chunkReader(data interface{}, dtype *Datatype) (*chunkReader, bool, error) {
+	if !headerVersion.atLeast(1, 10, 5) || hasVarLen(dtype.id) {
+		return nil, false, nil
+	}
+	ftype, err := s.Type()
+	if err != nil {
+		return nil, false, err
+	}
+	defer ftype.Close()
+	if !ftype.Equal(dtype) {
+		return nil, false, nil
+	}
+	dcpl, err := s.CreatePropList()
+	if err != nil {
+		return nil, false, err
+	}
+	defer dcpl.Close()
+	chunk, err := dcpl.Chunk()
+	if err != nil || chunk == nil {
+		return nil, false, err
+	}
+	filters, ok := chunkPipeline(dcpl)
+	if !ok {
+		return nil, false, nil
+	}
+	dims, err := s.Shape()
+	if err != nil {
+		return nil, false, err
+	}
+
+	buf, size, err := bufferOf(data)
+	if err != nil {
+		return nil, false, err
+	}
+	r := &chunkReader{
+		s:        s,
+		dims:     dims,
+		chunk:    chunk,
+		elemSize: int(dtype.Size()),
+		filters:  filters,
+	}
+	npoints := 1
+	for _, d := range dims {
+		npoints *= int(d)
+	}
+	if size < npoints*r.elemSize {
+		return nil, false, fmt.Errorf("hdf5: buffer of %d bytes cannot hold %d elements of %d bytes", size, npoints, r.elemSize)
+	}
+	if npoints == 0 {
+		return nil, false, nil
+	}
+	r.dst = unsafe.Slice((*byte)(buf), size)
+
+	// Unallocated chunks hold the fill value, which the chunks read
+	// overwrite.
+	var nchunks C.hsize_t
+	if err := h5err(C._go_hdf5_H5Dget_num_chunks(s.id, &nchunks)); err != nil {
+		return nil, false, err
+	}
+	total := 1
+	for i, d := range dims {
+		total *= (int(d) + int(chunk[i]) - 1) / int(chunk[i])
+	}
+	if int(nchunks) < total {
+		if err := fillBuffer(dcpl, dtype, r.dst[:npoints*r.elemSize]); err != nil {
+			return nil, false, err
+		}
+	}
+	return r, true, nil
+}
+
+// fillBuffer sets the elements of buf, of the datatype dtype, to the fill
+// value of dcpl.
+// herr_t H5Pget_fill_value(hid_t plist_id, hid_t type_id, void *value)
+func fillBuffer(dcpl *PropList, dtype *Datatype, buf []byte) error {
+	size := int(dtype.Size())
+	fill := make([]byte, size)
+	if err := h5err(C.H5Pget_fill_value(dcpl.id, dtype.id, unsafe.Pointer(&fill[0]))); err != nil {
+		return err
+	}
+	for i := 0; i < len(buf); i += size {
+		copy(buf[i:i+size], fill)
+	}
+	return nil
+}
+
+// run reads the chunks in turn and decodes them on workers goroutines.
+func (r *chunkReader) run(workers int) error {
+	var nchunks C.hsize_t
+	if err := h5err(C._go_hdf5_H5Dget_num_chunks(r.s.id, &nchunks)); err != nil {
+		return err
+	}
+	jobs := make(chan rawChunk, workers)
+	done := make(chan struct{})
+	var once sync.Once
+	var firstErr error
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			close(done)
+		})
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range jobs {
+				if err := r.decode(c); err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+	rank := len(r.dims)
+	c_offset := make([]C.hsize_t, rank)
+read:
+	for i := C.hsize_t(0); i < nchunks; i++ {
+		var c_mask C.uint
+		var c_size C.hsize_t
+		if err := h5err(C._go_hdf5_H5Dget_chunk_info(r.s.id, i, &c_offset[0], &c_mask, &c_size)); err != nil {
+			fail(err)
+			break
+		}
+		c := rawChunk{offset: make([]uint, rank), data: make([]byte, int(c_size))}
+		for j := range c.offset {
+			c.offset[j] = uint(c_offset[j])
+		}
+		if len(c.data) > 0 {
+			var filters C.uint
+			if err := h5err(C._go_hdf5_H5Dread_chunk(r.s.id, &c_offset[0], &filters, unsafe.Pointer(&c.data[0]))); err != nil {
+				fail(err)
+				break
+			}
+			c.mask = uint(filters)
+		}
+		select {
+		case jobs <- c:
+		case <-done:
+			break read
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return firstErr
+}
+
+// decode reverses the filters of the chunk c and copies its elements
+// within the dataset into place.
+func (r *chunkReader) decode(c rawChunk) error {
+	data := c.data
+	for i := len(r.filters) - 1; i >= 0; i-- {
+		if c.mask&(1<<uint(i)) != 0 {
+			continue
+		}
+		f := &r.filters[i]
+		out, err := f.dec(data, f.cd, r.elemSize)
+		if err != nil {
+			return fmt.Errorf("hdf5: decoding chunk %v with filter %d: %s", c.offset, f.id, err)
+		}
+		data = out
+	}
+	chunkBytes := r.elemSize
+	for _, d := range r.chunk {
+		chunkBytes *= int(d)
+	}
+	if len(data) < chunkBytes {
+		return fmt.Errorf("hdf5: chunk %v decodes to %d bytes, not %d", c.offset, len(data), chunkBytes)
+	}
+
+	// Copy the rows along the last dimension that lie within the dataset.
+	rank := len(r.dims)
+	last := rank - 1
+	rowLen := min(r.chunk[last], r.dims[last]-c.offset[last])
+	rowBytes := int(rowLen) * r.elemSize
+	idx := make([]uint, rank)
+	for {
+		src, dst := 0, 0
+		for j := 0; j < rank; j++ {
+			src = src*int(r.chunk[j]) + int(idx[j])
+			dst = dst*int(r.dims[j]) + int(c.offset[j]+idx[j])
+		}
+		src *= r.elemSize
+		dst *= r.elemSize
+		copy(r.dst[dst:dst+rowBytes], data[src:src+rowBytes])
+
+		// Advance to the next row of the chunk within the dataset.
+		j := last - 1
+		for ; j >= 0; j-- {
+			idx[j]++
+			if idx[j] < r.chunk[j] && c.offset[j]+idx[j] < r.dims[j] {
+				break
+			}
+			idx[j] = 0
+		}
+		if j < 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// ReadAll reads the whole dataset into a newly allocated slice, whose
+// element type follows the datatype of the file: int8 to uint64 for
+// integers, uint8 to uint64 for bitfields, Float16, float32 or float64 for floats, string for strings
+// and interface{} for other values, decoded as by ExportJSON. It also
+// returns the dimensions of the dataset; the slice holds its elements in
+// row-major order.
+func (s *Dataset) ReadAll() (interface{}, Shape, error) {
+	shape, n, err := s.extent()
+	if err != nil {
+		return nil, nil, err
+	}
+	ftype := C.H5Dget_type(s.id)
+	if err := h5err(C.herr_t(int(ftype))); err != nil {
+		return nil, nil, err
+	}
+	defer C.H5Tclose(ftype)
+
+	if elem := goTypeOf(ftype); elem != nil {
+		buf := reflect.MakeSlice(reflect.SliceOf(elem), n, n)
+		if n == 0 {
+			return buf.Interface(), shape, nil
+		}
+		dtype := T_NATIVE_FLOAT16
+		if elem != float16Type {
+			mtype := C.H5Tget_native_type(ftype, C.H5T_DIR_DEFAULT)
+			if err := h5err(C.herr_t(int(mtype))); err != nil {
+				return nil, nil, err
+			}
+			dtype = NewDatatype(mtype, elem)
+			defer dtype.Close()
+		}
+		if err := s.Read(buf.Interface(), dtype); err != nil {
+			return nil, nil, err
+		}
+		return buf.Interface(), shape, nil
+	}
+
+	values, err := s.readValues(ftype, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	if TypeClass(C.H5Tget_class(ftype)) != T_STRING {
+		return values, shape, nil
+	}
+	strs := make([]string, n)
+	for i, v := range values {
+		strs[i] = v.(string)
+	}
+	return strs, shape, nil
+}
+
+// ReadAllAs reads the whole dataset into a newly allocated slice of T,
+// converting from the datatype of the file to that of T as given by
+// NewDatatypeFromValue, or to a native bitfield for unsigned integer T
+// and bitfield datasets. It also returns the dimensions of the dataset.
+func ReadAllAs[T any](s *Dataset) ([]T, Shape, error) {
+	var zero T
+	rt := reflect.TypeOf(zero)
+	if rt == nil {
+		return nil, nil, fmt.Errorf("hdf5: cannot read into a slice of %T", zero)
+	}
+	if rt.Kind() == reflect.String {
+		data, shape, err := s.ReadAll()
+		if err != nil {
+			return nil, nil, err
+		}
+		strs, ok := data.([]string)
+		if !ok {
+			return nil, nil, fmt.Errorf("hdf5: cannot read %T into a slice of %s", data, rt)
+		}
+		buf := make([]T, len(strs))
+		for i, str := range strs {
+			reflect.ValueOf(&buf[i]).Elem().SetString(str)
+		}
+		return buf, shape, nil
+	}
+
+	shape, n, err := s.extent()
+	if err != nil {
+		return nil, nil, err
+	}
+	buf := make([]T, n)
+	if n > 0 {
+		dtype := NewDatatypeFromValue(zero)
+		if bt := bitfieldMemType(rt); bt != nil && isBitfield(s) {
+			dtype = bt
+		}
+		if err := s.Read(buf, dtype); err != nil {
+			return nil, nil, err
+		}
+	}
+	return buf, shape, nil
+}
+
+// extent returns the dimensions of the dataset and its number of
+// elements, which is 0 for a null dataspace.
+func (s *Dataset) extent() (Shape, int, error) {
+	dspace := s.Space()
+	if dspace == nil {
+		return nil, 0, fmt.Errorf("hdf5: could not get the dataspace of the dataset")
+	}
+	defer dspace.Close()
+	if dspace.SimpleExtentType() == S_NULL {
+		return Shape{}, 0, nil
+	}
+	shape, err := dspace.Shape()
+	if err != nil {
+		return nil, 0, err
+	}
+	return shape, dspace.SimpleExtentNPoints(), nil
+}
+
+func (s *Dataset) readValues(ftype C.hid_t, n int) ([]interface{}, error) {
+	return readValues(ftype, n, func(mtype C.hid_t, buf unsafe.Pointer) error {
+		return h5err(C.H5Dread(s.id, mtype, C.H5S_ALL, C.H5S_ALL, C.H5P_DEFAULT, buf))
+	})
+}
+
+// isBitfield reports whether the datatype of the dataset s is a bitfield.
+func isBitfield(s *Dataset) bool {
+	ftype := C.H5Dget_type(s.id)
+	if ftype < 0 {
+		return false
+	}
+	defer C.H5Tclose(ftype)
+	return C.H5Tget_class(ftype) == C.H5T_BITFIELD
+}
+
+// goTypeOf returns the Go type of the native integers, bitfields and
+// floats of the file datatype ftype, or nil for other datatypes.
+func goTypeOf(ftype C.hid_t) reflect.Type {
+	size := C.H5Tget_size(ftype)
+	switch TypeClass(C.H5Tget_class(ftype)) {
+	case T_INTEGER:
+		signed := C.H5Tget_sign(ftype) == C.H5T_SGN_2
+		switch size {
+		case 1:
+			if signed {
+				return reflect.TypeOf(int8(0))
+			}
+			return reflect.TypeOf(uint8(0))
+		case 2:
+			if signed {
+				return reflect.TypeOf(int16(0))
+			}
+			return reflect.TypeOf(uint16(0))
+		case 4:
+			if signed {
+				return reflect.TypeOf(int32(0))
+			}
+			return reflect.TypeOf(uint32(0))
+		case 8:
+			if signed {
+				return reflect.TypeOf(int64(0))
+			}
+			return reflect.TypeOf(uint64(0))
+		}
+	case T_BITFIELD:
+		switch size {
+		case 1:
+			return reflect.TypeOf(uint8(0))
+		case 2:
+			return reflect.TypeOf(uint16(0))
+		case 4:
+			return reflect.TypeOf(uint32(0))
+		case 8:
+			return reflect.TypeOf(uint64(0))
+		}
+	case T_FLOAT:
+		switch size {
+		case 2:
+			return float16Type
+		case 4:
+			return reflect.TypeOf(float32(0))
+		case 8:
+			return reflect.TypeOf(float64(0))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// Column is a member of the compound datatype of a dataset with its
+// values, as read by ReadColumns.
+type Column struct {
+	Name   string
+	Values []interface{}
+}
+
+// ReadRecords reads a dataset of a compound datatype unknown at compile
+// time as one map per element, in row-major order, from member names to
+// values decoded as by ExportJSON: an int64 or uint64 for integers, a
+// float64 for floats, a string for strings and enumeration values, a map
+// for nested compounds and a []interface{} for arrays and variable-length
+// sequences.
+func (s *Dataset) ReadRecords() ([]map[string]interface{}, error) {
+	values, err := s.readCompound()
+	if err != nil {
+		return nil, err
+	}
+	records := make([]map[string]interface{}, len(values))
+	for i, v := range values {
+		records[i] = dynamicValue(v).(map[string]interface{})
+	}
+	return records, nil
+}
+
+// ReadColumns reads a dataset of a compound datatype unknown at compile
+// time as one column per member, in member order, with values decoded as
+// by ReadRecords.
+func (s *Dataset) ReadColumns() ([]Column, error) {
+	ftype := C.H5Dget_type(s.id)
+	if err := h5err(C.herr_t(int(ftype))); err != nil {
+		return nil, err
+	}
+	defer C.H5Tclose(ftype)
+	values, err := s.readCompound()
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]Column, int(C.H5Tget_nmembers(ftype)))
+	for i := range columns {
+		c_name := C.H5Tget_member_name(ftype, C.uint(i))
+		columns[i].Name = C.GoString(c_name)
+		C.free(unsafe.Pointer(c_name))
+		columns[i].Values = make([]interface{}, len(values))
+	}
+	for j, v := range values {
+		for i, f := range v.(record) {
+			columns[i].Values[j] = dynamicValue(f.Value)
+		}
+	}
+	return columns, nil
+}
+
+// readCompound reads the elements of a dataset of a compound datatype as
+// records.
+func (s *Dataset) readCompound() ([]interface{}, error) {
+	_, n, err := s.extent()
+	if err != nil {
+		return nil, err
+	}
+	ftype := C.H5Dget_type(s.id)
+	if err := h5err(C.herr_t(int(ftype))); err != nil {
+		return nil, err
+	}
+	defer C.H5Tclose(ftype)
+	if TypeClass(C.H5Tget_class(ftype)) != T_COMPOUND {
+		return nil, fmt.Errorf("hdf5: dataset %q is not of a compound datatype", getName(s.id))
+	}
+	return s.readValues(ftype, n)
+}
+
+// dynamicValue converts the records in a value returned by decodeValue to
+// maps.
+func dynamicValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case record:
+		m := make(map[string]interface{}, len(v))
+		for _, f := range v {
+			m[f.Name] = dynamicValue(f.Value)
+		}
+		return m
+	case []interface{}:
+		for i := range v {
+			v[i] = dynamicValue(v[i])
+		}
+	}
+	return v
+}
+
+// ReadFields reads only the compound members named fields into data, a
+// slice of structs or a pointer to one whose fields are mapped to members
+// as by NewDatatypeFromValue. The other fields of data are left
+// untouched, and the other members of the file are not converted, so wide
+// records can be scanned cheaply for a few columns.
+func (s *Dataset) ReadFields(data interface{}, fields ...string) error {
+	t := reflect.TypeOf(data)
+	if t == nil || (t.Kind() != reflect.Slice && t.Kind() != reflect.Ptr) || t.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("hdf5: cannot read fields into a %T, need a slice of structs or a pointer to one", data)
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("hdf5: no fields to read")
+	}
+	ftype := C.H5Dget_type(s.id)
+	if err := h5err(C.herr_t(int(ftype))); err != nil {
+		return err
+	}
+	defer C.H5Tclose(ftype)
+	if TypeClass(C.H5Tget_class(ftype)) != T_COMPOUND {
+		return fmt.Errorf("hdf5: dataset %q is not of a compound datatype", getName(s.id))
+	}
+
+	mtype, err := projectStruct(t.Elem(), ftype, fields)
+	if err != nil {
+		return err
+	}
+	defer mtype.Close()
+	return s.Read(data, mtype)
+}
+
+// projectStruct returns a memory compound of the size of the struct type
+// t holding only the members of its fields named fields, which must be
+// members of ftype too.
+func projectStruct(t reflect.Type, ftype C.hid_t, fields []string) (dt *Datatype, err error) {
+	wanted := make(map[string]bool, len(fields))
+	for _, name := range fields {
+		c_name := C.CString(name)
+		idx := C.H5Tget_member_index(ftype, c_name)
+		C.free(unsafe.Pointer(c_name))
+		if idx < 0 {
+			return nil, fmt.Errorf("hdf5: the datatype of the dataset has no member %q", name)
+		}
+		wanted[name] = true
+	}
+
+	hid := C.H5Tcreate(C.H5T_COMPOUND, C.size_t(t.Size()))
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return nil, err
+	}
+	cdt := &CompoundType{*NewDatatype(hid, t)}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("hdf5: cannot map %s to a compound: %v", t, r)
+		}
+		if err != nil {
+			cdt.Close()
+			dt = nil
+		}
+	}()
+	for _, f := range structFields(t) {
+		if !wanted[f.name] {
+			continue
+		}
+		delete(wanted, f.name)
+		if err := cdt.Insert(f.name, int(f.offset), newDataTypeFromType(f.typ)); err != nil {
+			return nil, err
+		}
+	}
+	for name := range wanted {
+		return nil, fmt.Errorf("hdf5: %s has no field for member %q", t, name)
+	}
+	return &cdt.Datatype, nil
+}
@@ -0,0 +1,176 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// BadChunk is a block of a dataset, the elements of dimensions Count that
+// start at Offset, that could not be read, e.g. because its checksum did
+// not match or a filter failed to decode it.
+type BadChunk struct {
+	Offset []uint
+	Count  []uint
+	Err    error
+}
+
+// ReadReport is the outcome of ReadTolerant: the number of chunks read and
+// those that could not be read, whose elements were set to zero.
+type ReadReport struct {
+	Chunks int
+	Bad    []BadChunk
+}
+
+// OK reports whether every chunk was read.
+func (r *ReadReport) OK() bool {
+	return len(r.Bad) == 0
+}
+
+func (r *ReadReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d of %d chunks unreadable", len(r.Bad), r.Chunks)
+	for _, c := range r.Bad {
+		fmt.Fprintf(&b, "\n\t%v+%v: %s", Shape(c.Offset), Shape(c.Count), c.Err)
+	}
+	return b.String()
+}
+
+// ReadTolerant reads the whole dataset into data like Read, but chunk by
+// chunk, so that the chunks that fail to be read, e.g. for a Fletcher32
+// checksum mismatch, only leave their own elements unread. Those are set
+// to zero and listed in the report, which is returned without error
+// whenever the dataset could be read at all. A dataset that is not chunked
+// is read as a single chunk. Elements must be of a fixed size.
+func (s *Dataset) ReadTolerant(data interface{}, dtype *Datatype) (*ReadReport, error) {
+	if dtype == nil {
+		dt, owned, err := inferType(data, s.Type)
+		if err != nil {
+			return nil, err
+		}
+		if owned {
+			defer dt.Close()
+		}
+		dtype = dt
+	}
+	if hasVarLen(dtype.id) {
+		return nil, fmt.Errorf("hdf5: cannot read variable-length elements chunk by chunk, use Read")
+	}
+	shape, n, err := s.extent()
+	if err != nil {
+		return nil, err
+	}
+	buf, err := pinBuffer(data, false)
+	if err != nil {
+		return nil, err
+	}
+	defer buf.unpin()
+	elemSize := int(dtype.Size())
+	if buf.size < n*elemSize {
+		return nil, fmt.Errorf("hdf5: buffer of %d bytes cannot hold %d elements of %d bytes", buf.size, n, elemSize)
+	}
+	report := &ReadReport{}
+	if n == 0 {
+		return report, nil
+	}
+	dst := unsafe.Slice((*byte)(buf.ptr), n*elemSize)
+
+	if len(shape) == 0 {
+		report.Chunks = 1
+		var err error
+		quietly(func() {
+			err = h5err(C.H5Dread(s.id, dtype.id, C.H5S_ALL, C.H5S_ALL, C.H5P_DEFAULT, buf.ptr))
+		})
+		if err != nil {
+			clear(dst)
+			report.Bad = append(report.Bad, BadChunk{Err: err})
+		}
+		return report, nil
+	}
+
+	step := []uint(shape)
+	dcpl, err := s.CreatePropList()
+	if err != nil {
+		return nil, err
+	}
+	chunk, err := dcpl.Chunk()
+	dcpl.Close()
+	if err != nil {
+		return nil, err
+	}
+	if chunk != nil {
+		step = chunk
+	}
+	fspace := s.Space()
+	if fspace == nil {
+		return nil, fmt.Errorf("hdf5: could not get the dataspace of the dataset")
+	}
+	defer fspace.Close()
+	mspace, err := CreateSimpleDataspace(shape, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer mspace.Close()
+
+	tile(shape, step, func(offset, count []uint) bool {
+		report.Chunks++
+		if err = fspace.SelectHyperslab(S_SELECT_SET, offset, nil, count, nil); err != nil {
+			return false
+		}
+		if err = mspace.SelectHyperslab(S_SELECT_SET, offset, nil, count, nil); err != nil {
+			return false
+		}
+		var rerr error
+		quietly(func() {
+			rerr = h5err(C.H5Dread(s.id, dtype.id, mspace.id, fspace.id, C.H5P_DEFAULT, buf.ptr))
+		})
+		if rerr != nil {
+			zeroBlock(dst, shape, offset, count, elemSize)
+			report.Bad = append(report.Bad, BadChunk{
+				Offset: append([]uint{}, offset...),
+				Count:  count,
+				Err:    rerr,
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// zeroBlock sets to zero the elements of elemSize bytes of the block of
+// dimensions count at offset of buf, which holds an array of dimensions
+// shape in row-major order.
+func zeroBlock(buf []byte, shape Shape, offset, count []uint, elemSize int) {
+	rank := len(shape)
+	row := int(count[rank-1]) * elemSize
+	// idx walks the rows of the block, the indices of its outer dimensions
+	idx := make([]uint, rank-1)
+	for {
+		pos := 0
+		for i := 0; i < rank; i++ {
+			k := offset[i]
+			if i < rank-1 {
+				k += idx[i]
+			}
+			pos = pos*int(shape[i]) + int(k)
+		}
+		clear(buf[pos*elemSize : pos*elemSize+row])
+		i := rank - 2
+		for ; i >= 0; i-- {
+			idx[i]++
+			if idx[i] < count[i] {
+				break
+			}
+			idx[i] = 0
+		}
+		if i < 0 {
+			return
+		}
+	}
+}
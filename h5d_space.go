@@ -0,0 +1,39 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+// SpaceStatus is the allocation state of the storage of a dataset.
+type SpaceStatus C.H5D_space_status_t
+
+const (
+	D_SPACE_STATUS_ERROR          SpaceStatus = -1 // Error
+	D_SPACE_STATUS_NOT_ALLOCATED  SpaceStatus = 0  // no storage is allocated
+	D_SPACE_STATUS_PART_ALLOCATED SpaceStatus = 1  // some chunks are allocated
+	D_SPACE_STATUS_ALLOCATED      SpaceStatus = 2  // all storage is allocated
+)
+
+func (s SpaceStatus) String() string {
+	switch s {
+	case D_SPACE_STATUS_NOT_ALLOCATED:
+		return "not allocated"
+	case D_SPACE_STATUS_PART_ALLOCATED:
+		return "partly allocated"
+	case D_SPACE_STATUS_ALLOCATED:
+		return "allocated"
+	}
+	return "unknown"
+}
+
+// SpaceStatus returns whether the storage of the dataset is allocated. A
+// dataset whose storage was never allocated holds only its fill value, so
+// readers can skip it, or fill their buffers themselves, without reading
+// it.
+// herr_t H5Dget_space_status(hid_t dset_id, H5D_space_status_t *allocation)
+func (s *Dataset) SpaceStatus() (SpaceStatus, error) {
+	var c_status C.H5D_space_status_t
+	if err := h5err(C.H5Dget_space_status(s.id, &c_status)); err != nil {
+		return D_SPACE_STATUS_ERROR, err
+	}
+	return SpaceStatus(c_status), nil
+}
@@ -0,0 +1,374 @@
+package hdf5
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"math"
+)
+
+// Stats are summary statistics of the numeric elements of a dataset, as
+// computed by Dataset.Stats.
+type Stats struct {
+	Count     int     // elements counted, those that are not NaN
+	NaN       int     // NaN elements, which are left out of the other statistics
+	Min       float64 // NaN if no element is counted, as are Max, Mean and StdDev
+	Max       float64
+	Mean      float64
+	StdDev    float64         // population standard deviation
+	Histogram *StatsHistogram // if requested
+}
+
+// StatsHistogram counts the elements of a dataset in bins of equal width over
+// the range [Min, Max], the last bin including Max. Infinite elements are
+// counted in Under or Over.
+type StatsHistogram struct {
+	Min    float64
+	Max    float64
+	Counts []int
+	Under  int // elements below Min
+	Over   int // elements above Max
+}
+
+// StatsOptions configures Dataset.Stats. The zero value computes no
+// histogram and caches nothing.
+type StatsOptions struct {
+	Bins    int     // bins of the histogram, none if zero
+	HistMin float64 // range of the histogram; that of the finite elements,
+	HistMax float64 // which takes a second pass over the dataset, if equal
+
+	// Cache stores the statistics as attributes of the dataset, and
+	// returns those stored by an earlier call with the same options
+	// instead of reading the dataset again, provided its number of
+	// elements is unchanged. Elements overwritten in place are not
+	// detected; Refresh computes and stores the statistics regardless.
+	Cache   bool
+	Refresh bool
+}
+
+// Stats computes the statistics of the elements of a numeric dataset by
+// reading it chunk by chunk, if it is chunked, or in blocks of rows of
+// about a million elements, so that a dataset of any size is summarized in
+// bounded memory. ctx is checked between blocks. A nil opts takes the
+// zero StatsOptions.
+func (s *Dataset) Stats(ctx context.Context, opts *StatsOptions) (*Stats, error) {
+	if opts == nil {
+		opts = &StatsOptions{}
+	}
+	if opts.Bins < 0 {
+		return nil, fmt.Errorf("hdf5: cannot count a histogram of %d bins", opts.Bins)
+	}
+	_, n, err := s.extent()
+	if err != nil {
+		return nil, err
+	}
+	if opts.Cache && !opts.Refresh {
+		if st, err := s.cachedStats(opts, n); err != nil || st != nil {
+			return st, err
+		}
+	}
+
+	st := &Stats{}
+	var mean, m2 float64
+	st.Min, st.Max = math.Inf(1), math.Inf(-1)
+	finMin, finMax := math.Inf(1), math.Inf(-1)
+	err = s.eachValue(ctx, func(v float64) {
+		if math.IsNaN(v) {
+			st.NaN++
+			return
+		}
+		// Welford's update of the mean and the sum of squared deviations
+		st.Count++
+		d := v - mean
+		mean += d / float64(st.Count)
+		m2 += d * (v - mean)
+		st.Min = min(st.Min, v)
+		st.Max = max(st.Max, v)
+		if !math.IsInf(v, 0) {
+			finMin = min(finMin, v)
+			finMax = max(finMax, v)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if st.Count == 0 {
+		st.Min, st.Max, st.Mean, st.StdDev = math.NaN(), math.NaN(), math.NaN(), math.NaN()
+	} else {
+		st.Mean, st.StdDev = mean, math.Sqrt(m2/float64(st.Count))
+	}
+
+	if opts.Bins > 0 {
+		h := &StatsHistogram{Min: opts.HistMin, Max: opts.HistMax, Counts: make([]int, opts.Bins)}
+		if h.Min == h.Max && finMin <= finMax {
+			h.Min, h.Max = finMin, finMax
+		}
+		err := s.eachValue(ctx, func(v float64) {
+			switch {
+			case math.IsNaN(v):
+			case v < h.Min || math.IsInf(v, -1):
+				h.Under++
+			case v > h.Max || math.IsInf(v, 1):
+				h.Over++
+			default:
+				h.Counts[h.bin(v)]++
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		st.Histogram = h
+	}
+
+	if opts.Cache {
+		if err := s.cacheStats(st, opts, n); err != nil {
+			return nil, err
+		}
+	}
+	return st, nil
+}
+
+// bin returns the index of the bin of v, within [h.Min, h.Max].
+func (h *StatsHistogram) bin(v float64) int {
+	last := len(h.Counts) - 1
+	// halved so that the widths of ranges such as -MaxFloat64..MaxFloat64
+	// do not overflow
+	f := (v/2 - h.Min/2) / (h.Max/2 - h.Min/2)
+	switch {
+	case h.Max <= h.Min || f >= 1:
+		return last
+	case !(f > 0): // NaN of an infinite range, or the first bin
+		return 0
+	}
+	return min(int(f*float64(len(h.Counts))), last)
+}
+
+// eachValue calls fn with each element of the dataset, converted to
+// float64, reading it in blocks.
+func (s *Dataset) eachValue(ctx context.Context, fn func(float64)) error {
+	dcpl, err := s.CreatePropList()
+	if err != nil {
+		return err
+	}
+	layout, err := dcpl.Layout()
+	dcpl.Close()
+	if err != nil {
+		return err
+	}
+	var blocks iter.Seq2[Block, error]
+	if layout == D_CHUNKED {
+		blocks = s.Chunks()
+	} else {
+		shape, _, err := s.extent()
+		if err != nil {
+			return err
+		}
+		row := 1
+		for _, d := range shape[min(1, len(shape)):] {
+			row *= int(d)
+		}
+		blocks = s.Stream(max(1<<20/max(row, 1), 1))
+	}
+	for b, err := range blocks {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := eachFloat(b.Data, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eachFloat calls fn with each element of data, a slice of numbers as
+// returned by ReadAll, converted to float64.
+func eachFloat(data interface{}, fn func(float64)) error {
+	switch d := data.(type) {
+	case []float64:
+		for _, v := range d {
+			fn(v)
+		}
+	case []float32:
+		for _, v := range d {
+			fn(float64(v))
+		}
+	case []Float16:
+		for _, v := range d {
+			fn(float64(v.Float32()))
+		}
+	case []int8:
+		for _, v := range d {
+			fn(float64(v))
+		}
+	case []int16:
+		for _, v := range d {
+			fn(float64(v))
+		}
+	case []int32:
+		for _, v := range d {
+			fn(float64(v))
+		}
+	case []int64:
+		for _, v := range d {
+			fn(float64(v))
+		}
+	case []uint8:
+		for _, v := range d {
+			fn(float64(v))
+		}
+	case []uint16:
+		for _, v := range d {
+			fn(float64(v))
+		}
+	case []uint32:
+		for _, v := range d {
+			fn(float64(v))
+		}
+	case []uint64:
+		for _, v := range d {
+			fn(float64(v))
+		}
+	default:
+		return fmt.Errorf("hdf5: cannot compute statistics of %T elements", data)
+	}
+	return nil
+}
+
+const (
+	statsAttr     = "_stats"
+	histogramAttr = "_stats_histogram"
+)
+
+// statsRecord is the compound attribute caching the statistics of a
+// dataset.
+type statsRecord struct {
+	Elements int64   `hdf5:"elements"`
+	Count    int64   `hdf5:"count"`
+	NaN      int64   `hdf5:"nan"`
+	Min      float64 `hdf5:"min"`
+	Max      float64 `hdf5:"max"`
+	Mean     float64 `hdf5:"mean"`
+	StdDev   float64 `hdf5:"stddev"`
+	Bins     int64   `hdf5:"bins"`
+	HistMin  float64 `hdf5:"hist_min"`
+	HistMax  float64 `hdf5:"hist_max"`
+	Under    int64   `hdf5:"under"`
+	Over     int64   `hdf5:"over"`
+}
+
+// cachedStats returns the statistics cached for opts on the dataset of n
+// elements, or nil if there are none.
+func (s *Dataset) cachedStats(opts *StatsOptions, n int) (*Stats, error) {
+	ok, err := attributeExists(s.id, statsAttr)
+	if err != nil || !ok {
+		return nil, err
+	}
+	a, err := openAttribute(s.id, statsAttr)
+	if err != nil {
+		return nil, err
+	}
+	var rec statsRecord
+	err = a.Read(&rec, nil)
+	a.Close()
+	if err != nil {
+		return nil, err
+	}
+	if rec.Elements != int64(n) || rec.Bins != int64(opts.Bins) {
+		return nil, nil
+	}
+	if opts.HistMin != opts.HistMax && (rec.HistMin != opts.HistMin || rec.HistMax != opts.HistMax) {
+		return nil, nil
+	}
+	st := &Stats{
+		Count:  int(rec.Count),
+		NaN:    int(rec.NaN),
+		Min:    rec.Min,
+		Max:    rec.Max,
+		Mean:   rec.Mean,
+		StdDev: rec.StdDev,
+	}
+	if rec.Bins > 0 {
+		a, err := openAttribute(s.id, histogramAttr)
+		if err != nil {
+			return nil, err
+		}
+		defer a.Close()
+		counts := make([]int64, rec.Bins)
+		if err := a.Read(counts, nil); err != nil {
+			return nil, err
+		}
+		h := &StatsHistogram{Min: rec.HistMin, Max: rec.HistMax, Counts: make([]int, rec.Bins), Under: int(rec.Under), Over: int(rec.Over)}
+		for i, c := range counts {
+			h.Counts[i] = int(c)
+		}
+		st.Histogram = h
+	}
+	return st, nil
+}
+
+// cacheStats stores st, computed for opts on the dataset of n elements,
+// as attributes of the dataset, replacing those of an earlier call.
+func (s *Dataset) cacheStats(st *Stats, opts *StatsOptions, n int) error {
+	for _, name := range []string{statsAttr, histogramAttr} {
+		if ok, err := attributeExists(s.id, name); err != nil {
+			return err
+		} else if ok {
+			if err := deleteAttribute(s.id, name); err != nil {
+				return err
+			}
+		}
+	}
+	rec := statsRecord{
+		Elements: int64(n),
+		Count:    int64(st.Count),
+		NaN:      int64(st.NaN),
+		Min:      st.Min,
+		Max:      st.Max,
+		Mean:     st.Mean,
+		StdDev:   st.StdDev,
+		Bins:     int64(opts.Bins),
+	}
+	if h := st.Histogram; h != nil {
+		rec.HistMin, rec.HistMax = h.Min, h.Max
+		rec.Under, rec.Over = int64(h.Under), int64(h.Over)
+		counts := make([]int64, len(h.Counts))
+		for i, c := range h.Counts {
+			counts[i] = int64(c)
+		}
+		if err := s.writeStatsAttr(histogramAttr, counts, []uint{uint(len(counts))}); err != nil {
+			return err
+		}
+	}
+	return s.writeStatsAttr(statsAttr, &rec, nil)
+}
+
+// writeStatsAttr creates the attribute name of the dataset, of dimensions
+// dims or scalar if nil, holding data.
+func (s *Dataset) writeStatsAttr(name string, data interface{}, dims []uint) error {
+	dtype, owned, err := inferType(data, nil)
+	if err != nil {
+		return err
+	}
+	if owned {
+		defer dtype.Close()
+	}
+	var dspace *Dataspace
+	if dims == nil {
+		dspace, err = CreateDataspace(S_SCALAR)
+	} else {
+		dspace, err = CreateSimpleDataspace(dims, nil)
+	}
+	if err != nil {
+		return err
+	}
+	defer dspace.Close()
+	a, err := createAttribute(s.id, name, dtype, dspace, P_DEFAULT)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+	return a.Write(data, dtype)
+}
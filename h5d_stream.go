@@ -0,0 +1,200 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"unsafe"
+)
+
+// Block is a hyperslab of a dataset, the elements of dimensions Count that
+// start at Offset, as yielded by Dataset.Stream and Dataset.Chunks.
+type Block struct {
+	Offset []uint
+	Count  []uint
+	Data   interface{} // the elements in row-major order, a slice as returned by ReadAll
+}
+
+// Stream returns an iterator over the dataset in blocks of batchRows
+// consecutive rows, indices of the first dimension, read one at a time so
+// that a dataset of any size is processed in bounded memory. The last
+// block may have fewer rows. A scalar dataset is a single block with no
+// offset. Iteration stops after the first error, which is yielded with a
+// zero Block.
+func (s *Dataset) Stream(batchRows int) iter.Seq2[Block, error] {
+	return func(yield func(Block, error) bool) {
+		if batchRows <= 0 {
+			yield(Block{}, fmt.Errorf("hdf5: cannot stream batches of %d rows", batchRows))
+			return
+		}
+		shape, _, err := s.extent()
+		if err != nil {
+			yield(Block{}, err)
+			return
+		}
+		step := append([]uint{}, shape...)
+		if len(step) > 0 {
+			step[0] = uint(batchRows)
+		}
+		s.blocks(shape, step, yield)
+	}
+}
+
+// Chunks returns an iterator over the chunks of a chunked dataset, in
+// row-major order of the chunk grid, each read as a block clipped to the
+// extent of the dataset. Reading whole chunks decompresses each of them
+// once. Iteration stops after the first error, which is yielded with a
+// zero Block.
+func (s *Dataset) Chunks() iter.Seq2[Block, error] {
+	return func(yield func(Block, error) bool) {
+		dcpl, err := s.CreatePropList()
+		if err != nil {
+			yield(Block{}, err)
+			return
+		}
+		defer dcpl.Close()
+		if layout, err := dcpl.Layout(); err != nil || layout != D_CHUNKED {
+			if err == nil {
+				err = fmt.Errorf("hdf5: dataset %s is not chunked, use Stream", s.Name())
+			}
+			yield(Block{}, err)
+			return
+		}
+		chunk, err := dcpl.Chunk()
+		if err != nil {
+			yield(Block{}, err)
+			return
+		}
+		shape, _, err := s.extent()
+		if err != nil {
+			yield(Block{}, err)
+			return
+		}
+		s.blocks(shape, chunk, yield)
+	}
+}
+
+// blocks yields the blocks of steps of step elements that tile the
+// extent shape.
+func (s *Dataset) blocks(shape Shape, step []uint, yield func(Block, error) bool) {
+	ftype := C.H5Dget_type(s.id)
+	if err := h5err(C.herr_t(int(ftype))); err != nil {
+		yield(Block{}, err)
+		return
+	}
+	defer C.H5Tclose(ftype)
+
+	if len(shape) == 0 {
+		data, err := s.readBlock(ftype, nil, nil)
+		if err != nil {
+			yield(Block{}, err)
+			return
+		}
+		yield(Block{Data: data}, nil)
+		return
+	}
+	if shape.Size() == 0 {
+		return
+	}
+	tile(shape, step, func(offset, count []uint) bool {
+		data, err := s.readBlock(ftype, offset, count)
+		if err != nil {
+			yield(Block{}, err)
+			return false
+		}
+		return yield(Block{Offset: append([]uint{}, offset...), Count: count, Data: data}, nil)
+	})
+}
+
+// tile calls fn with the offset and dimensions of each block of step
+// elements, clipped to the extent shape, that tile a non-empty extent, in
+// row-major order, until fn returns false. fn must not keep offset.
+func tile(shape Shape, step []uint, fn func(offset, count []uint) bool) {
+	offset := make([]uint, len(shape))
+	for {
+		count := make([]uint, len(shape))
+		for i := range count {
+			count[i] = min(step[i], shape[i]-offset[i])
+		}
+		if !fn(offset, count) {
+			return
+		}
+		// advance the offset over the grid of blocks, last dimension first
+		i := len(shape) - 1
+		for ; i >= 0; i-- {
+			offset[i] += step[i]
+			if offset[i] < shape[i] {
+				break
+			}
+			offset[i] = 0
+		}
+		if i < 0 {
+			return
+		}
+	}
+}
+
+// readBlock reads the block of the dataset of dimensions count that
+// starts at offset, or the whole dataset for a nil count, into a new
+// slice as ReadAll does.
+func (s *Dataset) readBlock(ftype C.hid_t, offset, count []uint) (interface{}, error) {
+	mspace, fspace := C.hid_t(C.H5S_ALL), C.hid_t(C.H5S_ALL)
+	n := 1
+	if count != nil {
+		fs := s.Space()
+		if fs == nil {
+			return nil, fmt.Errorf("hdf5: could not get the dataspace of the dataset")
+		}
+		defer fs.Close()
+		if err := fs.SelectHyperslab(S_SELECT_SET, offset, nil, count, nil); err != nil {
+			return nil, err
+		}
+		ms, err := CreateSimpleDataspace(count, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer ms.Close()
+		mspace, fspace = ms.id, fs.id
+		n = int(Shape(count).Size())
+	}
+	read := func(mtype C.hid_t, buf unsafe.Pointer) error {
+		return h5err(C.H5Dread(s.id, mtype, mspace, fspace, C.H5P_DEFAULT, buf))
+	}
+
+	if elem := goTypeOf(ftype); elem != nil {
+		slice := reflect.MakeSlice(reflect.SliceOf(elem), n, n)
+		mtype := T_NATIVE_FLOAT16.id
+		if elem != float16Type {
+			mtype = C.H5Tget_native_type(ftype, C.H5T_DIR_DEFAULT)
+			if err := h5err(C.herr_t(int(mtype))); err != nil {
+				return nil, err
+			}
+			defer C.H5Tclose(mtype)
+		}
+		buf, err := pinBuffer(slice.Interface(), false)
+		if err != nil {
+			return nil, err
+		}
+		defer buf.unpin()
+		if err := read(mtype, buf.ptr); err != nil {
+			return nil, err
+		}
+		return slice.Interface(), nil
+	}
+
+	values, err := readValues(ftype, n, read)
+	if err != nil {
+		return nil, err
+	}
+	if TypeClass(C.H5Tget_class(ftype)) != T_STRING {
+		return values, nil
+	}
+	strs := make([]string, n)
+	for i, v := range values {
+		strs[i] = v.(string)
+	}
+	return strs, nil
+}
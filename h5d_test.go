@@ -0,0 +1,1770 @@
+package hdf5
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestDatasetCopyTo(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	dcpl, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer dcpl.Close()
+	if err := dcpl.SetChunk([]uint{25}); err != nil {
+		t.Fatalf("SetChunk failed: %s", err)
+	}
+	if err := dcpl.SetDeflate(6); err != nil {
+		t.Fatalf("SetDeflate failed: %s", err)
+	}
+
+	data := make([]float64, 100)
+	for i := range data {
+		data[i] = float64(i) * 0.5
+	}
+	dspace, err := CreateSimpleDataspace([]uint{uint(len(data))}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("src", T_NATIVE_DOUBLE, dspace, dcpl)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	if err := dset.Write(data, T_NATIVE_DOUBLE); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	scalar, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatalf("CreateDataspace failed: %s", err)
+	}
+	defer scalar.Close()
+	attr, err := dset.CreateAttribute("scale", T_NATIVE_INT32, scalar)
+	if err != nil {
+		t.Fatalf("CreateAttribute failed: %s", err)
+	}
+	defer attr.Close()
+	if err := attr.Write(int32(7), T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+
+	g, err := f.CreateGroup("archive")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	defer g.Close()
+
+	check := func(d *Dataset, nfilters int) {
+		plist, err := d.CreatePropList()
+		if err != nil {
+			t.Fatalf("CreatePropList failed: %s", err)
+		}
+		defer plist.Close()
+		if chunk, err := plist.Chunk(); err != nil {
+			t.Fatalf("Chunk failed: %s", err)
+		} else if len(chunk) != 1 || chunk[0] != 25 {
+			t.Errorf("%s: Chunk() = %v, want [25]", d.Name(), chunk)
+		}
+		if n, err := plist.NumFilters(); err != nil {
+			t.Fatalf("NumFilters failed: %s", err)
+		} else if n != nfilters {
+			t.Errorf("%s: NumFilters() = %d, want %d", d.Name(), n, nfilters)
+		}
+
+		got := make([]float64, len(data))
+		if err := d.Read(got, T_NATIVE_DOUBLE); err != nil {
+			t.Fatalf("Read failed: %s", err)
+		}
+		for i := range data {
+			if got[i] != data[i] {
+				t.Fatalf("%s: data[%d] = %v, want %v", d.Name(), i, got[i], data[i])
+			}
+		}
+
+		a, err := d.OpenAttribute("scale")
+		if err != nil {
+			t.Fatalf("%s: OpenAttribute failed: %s", d.Name(), err)
+		}
+		defer a.Close()
+		var scale int32
+		if err := a.Read(&scale, T_NATIVE_INT32); err != nil {
+			t.Fatalf("Read failed: %s", err)
+		}
+		if scale != 7 {
+			t.Errorf("%s: attribute = %d, want 7", d.Name(), scale)
+		}
+	}
+
+	// as-is copy
+	cp, err := dset.CopyTo(g, "copy", nil)
+	if err != nil {
+		t.Fatalf("CopyTo failed: %s", err)
+	}
+	defer cp.Close()
+	check(cp, 1)
+
+	// copy without compression
+	plist, err := dset.CreatePropList()
+	if err != nil {
+		t.Fatalf("CreatePropList failed: %s", err)
+	}
+	defer plist.Close()
+	if err := plist.RemoveFilters(); err != nil {
+		t.Fatalf("RemoveFilters failed: %s", err)
+	}
+	raw, err := dset.CopyTo(f, "raw", plist)
+	if err != nil {
+		t.Fatalf("CopyTo failed: %s", err)
+	}
+	defer raw.Close()
+	check(raw, 0)
+}
+
+func TestCreateDatasetWith(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	dspace, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatalf("CreateDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	if _, err := f.CreateDataset("/a/b/c/data", T_NATIVE_INT32, dspace, P_DEFAULT); err == nil {
+		t.Fatalf("expected an error creating a dataset in missing groups")
+	}
+
+	lcpl, err := NewPropList(P_LINK_CREATE)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer lcpl.Close()
+	if err := lcpl.SetCreateIntermediateGroup(true); err != nil {
+		t.Fatalf("SetCreateIntermediateGroup failed: %s", err)
+	}
+	if create, err := lcpl.CreateIntermediateGroup(); err != nil || !create {
+		t.Errorf("CreateIntermediateGroup returned %v, %v", create, err)
+	}
+	dset, err := f.CreateDatasetWith("/a/b/c/data", T_NATIVE_INT32, dspace, lcpl, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDatasetWith failed: %s", err)
+	}
+	dset.Close()
+
+	dset, err = f.OpenDataset("/a/b/c/data")
+	if err != nil {
+		t.Fatalf("OpenDataset failed: %s", err)
+	}
+	dset.Close()
+	g, err := f.OpenGroup("a/b")
+	if err != nil {
+		t.Fatalf("OpenGroup failed: %s", err)
+	}
+	defer g.Close()
+	dset, err = g.OpenDataset("c/data")
+	if err != nil {
+		t.Fatalf("OpenDataset failed: %s", err)
+	}
+	dset.Close()
+}
+
+func TestCreateAnon(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	dspace, err := CreateSimpleDataspace([]uint{3}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateAnonDataset(T_NATIVE_INT32, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateAnonDataset failed: %s", err)
+	}
+	defer dset.Close()
+	if err := dset.Write([]int32{1, 2, 3}, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	if n, err := f.NumObjects(); err != nil || n != 0 {
+		t.Errorf("NumObjects returned %d, %v before linking", n, err)
+	}
+
+	g, err := f.CreateAnonGroup(P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateAnonGroup failed: %s", err)
+	}
+	defer g.Close()
+	if err := g.Link(dset, "data"); err != nil {
+		t.Fatalf("Link failed: %s", err)
+	}
+	if err := f.Link(g, "results"); err != nil {
+		t.Fatalf("Link failed: %s", err)
+	}
+
+	d2, err := f.OpenDataset("results/data")
+	if err != nil {
+		t.Fatalf("OpenDataset failed: %s", err)
+	}
+	defer d2.Close()
+	got := make([]int32, 3)
+	if err := d2.Read(got, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, []int32{1, 2, 3}) {
+		t.Errorf("Read returned %v", got)
+	}
+}
+
+func TestGatherScatter(t *testing.T) {
+	// Two frames of 3 pixels, interleaved pixel by pixel.
+	space, err := CreateSimpleDataspace([]uint{3, 2}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer space.Close()
+	if err := space.SelectHyperslab(S_SELECT_SET, []uint{0, 1}, nil, []uint{3, 1}, nil); err != nil {
+		t.Fatalf("SelectHyperslab failed: %s", err)
+	}
+
+	interleaved := []int32{10, 11, 20, 21, 30, 31}
+	frame := make([]int32, 3)
+	if err := Gather(space, interleaved, T_NATIVE_INT32, frame); err != nil {
+		t.Fatalf("Gather failed: %s", err)
+	}
+	if !reflect.DeepEqual(frame, []int32{11, 21, 31}) {
+		t.Errorf("Gather returned %v", frame)
+	}
+	if err := Gather(space, interleaved, T_NATIVE_INT32, frame[:2]); err == nil {
+		t.Errorf("expected an error for a short destination")
+	}
+
+	if err := Scatter([]int32{-1, -2, -3}, T_NATIVE_INT32, space, interleaved); err != nil {
+		t.Fatalf("Scatter failed: %s", err)
+	}
+	if !reflect.DeepEqual(interleaved, []int32{10, -1, 20, -2, 30, -3}) {
+		t.Errorf("Scatter returned %v", interleaved)
+	}
+
+	// the library accesses the whole extent, not only the selection
+	long, err := CreateSimpleDataspace([]uint{100}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer long.Close()
+	if err := long.SelectHyperslab(S_SELECT_SET, []uint{90}, nil, []uint{1}, nil); err != nil {
+		t.Fatalf("SelectHyperslab failed: %s", err)
+	}
+	if err := Gather(long, make([]int32, 10), T_NATIVE_INT32, frame); err == nil {
+		t.Errorf("expected an error gathering from a source shorter than the extent")
+	}
+	if err := Scatter([]int32{1}, T_NATIVE_INT32, long, make([]int32, 10)); err == nil {
+		t.Errorf("expected an error scattering to a destination shorter than the extent")
+	}
+}
+
+func TestReadWriteMulti(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{4}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+
+	var writes, reads []Request
+	var got [][]int32
+	for i := 0; i < 3; i++ {
+		dset, err := f.CreateDataset(fmt.Sprintf("ch%d", i), T_STD_I32LE, dspace, P_DEFAULT)
+		if err != nil {
+			t.Fatalf("CreateDataset failed: %s", err)
+		}
+		defer dset.Close()
+		data := []int32{int32(i), int32(i) + 10, int32(i) + 20, int32(i) + 30}
+		writes = append(writes, Request{Dataset: dset, Data: data, Type: T_NATIVE_INT32})
+		got = append(got, make([]int32, 4))
+		reads = append(reads, Request{Dataset: dset, Data: got[i], Type: T_NATIVE_INT32})
+	}
+	if err := WriteMulti(writes); err != nil {
+		t.Fatalf("WriteMulti failed: %s", err)
+	}
+	if err := ReadMulti(reads); err != nil {
+		t.Fatalf("ReadMulti failed: %s", err)
+	}
+	for i := range got {
+		if !reflect.DeepEqual(got[i], writes[i].Data) {
+			t.Errorf("ReadMulti read %v from ch%d, want %v", got[i], i, writes[i].Data)
+		}
+	}
+}
+
+func TestDatasetShape(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{5, 2}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("d", T_NATIVE_INT8, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	if shape, err := dset.Shape(); err != nil || !reflect.DeepEqual(shape, Shape{5, 2}) {
+		t.Errorf("Shape returned %v, %v", shape, err)
+	}
+}
+
+func TestAppendSlice(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{0, 3}, []uint{S_UNLIMITED, 3})
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dcpl, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer dcpl.Close()
+	if err := dcpl.SetChunk([]uint{16, 3}); err != nil {
+		t.Fatalf("SetChunk failed: %s", err)
+	}
+	dset, err := f.CreateDataset("series", T_NATIVE_INT32, dspace, dcpl)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+
+	if err := dset.AppendSlice([]int32{1, 2, 3}, T_NATIVE_INT32, 0); err != nil {
+		t.Fatalf("AppendSlice failed: %s", err)
+	}
+	row := [3]int32{7, 8, 9}
+	if err := dset.AppendSlice([]int32{4, 5, 6}, T_NATIVE_INT32, 0); err != nil {
+		t.Fatalf("AppendSlice failed: %s", err)
+	}
+	if err := dset.AppendSlice(&row, T_NATIVE_INT32, 0); err != nil {
+		t.Fatalf("AppendSlice of a pointer failed: %s", err)
+	}
+	if shape, err := dset.Shape(); err != nil || !reflect.DeepEqual(shape, Shape{3, 3}) {
+		t.Errorf("Shape returned %v, %v", shape, err)
+	}
+	got := make([]int32, 9)
+	if err := dset.Read(got, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if want := []int32{1, 2, 3, 4, 5, 6, 7, 8, 9}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Read returned %v, want %v", got, want)
+	}
+
+	if err := dset.AppendSlice([]int32{1, 2}, T_NATIVE_INT32, 0); err == nil {
+		t.Errorf("expected an error appending a partial row")
+	}
+	if err := dset.AppendSlice([]int32{1, 2, 3}, T_NATIVE_INT32, 1); err == nil {
+		t.Errorf("expected an error appending along a fixed dimension")
+	}
+}
+
+func TestReadWriteSubset(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{4, 5}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("grid", T_NATIVE_INT32, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	if err := dset.Write(make([]int32, 20), T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+
+	if err := dset.WriteAt([]int32{1, 2, 3, 4}, T_NATIVE_INT32, []uint{1, 2}, []uint{2, 2}); err != nil {
+		t.Fatalf("WriteAt failed: %s", err)
+	}
+	if err := dset.WriteSubset([]int32{9, 9, 9}, T_NATIVE_INT32, []uint{3, 0}, []uint{1, 2}, []uint{1, 3}); err != nil {
+		t.Fatalf("WriteSubset failed: %s", err)
+	}
+	all := make([]int32, 20)
+	if err := dset.Read(all, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	want := []int32{
+		0, 0, 0, 0, 0,
+		0, 0, 1, 2, 0,
+		0, 0, 3, 4, 0,
+		9, 0, 9, 0, 9,
+	}
+	if !reflect.DeepEqual(all, want) {
+		t.Errorf("Read returned %v, want %v", all, want)
+	}
+
+	block := make([]int32, 4)
+	if err := dset.ReadAt(block, T_NATIVE_INT32, []uint{1, 2}, []uint{2, 2}); err != nil {
+		t.Fatalf("ReadAt failed: %s", err)
+	}
+	if !reflect.DeepEqual(block, []int32{1, 2, 3, 4}) {
+		t.Errorf("ReadAt returned %v", block)
+	}
+	if err := dset.ReadAt(block, nil, []uint{1, 2}, []uint{2, 2}); err != nil || !reflect.DeepEqual(block, []int32{1, 2, 3, 4}) {
+		t.Errorf("ReadAt with an inferred datatype returned %v, %v", block, err)
+	}
+	var v int32
+	if err := dset.ReadSubset(&v, T_NATIVE_INT32, []uint{3, 4}, nil, []uint{1, 1}); err != nil || v != 9 {
+		t.Errorf("ReadSubset returned %d, %v", v, err)
+	}
+	if err := dset.ReadAt(block[:2], T_NATIVE_INT32, []uint{0, 0}, []uint{2, 2}); err == nil {
+		t.Errorf("expected an error reading into a short buffer")
+	}
+
+	// the subset of a scalar dataset is its element
+	sspace, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatalf("CreateDataspace failed: %s", err)
+	}
+	defer sspace.Close()
+	scalar, err := f.CreateDataset("scalar", T_NATIVE_INT32, sspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer scalar.Close()
+	if err := scalar.WriteAt([]int32{42}, T_NATIVE_INT32, []uint{}, []uint{}); err != nil {
+		t.Fatalf("WriteAt of a scalar failed: %s", err)
+	}
+	if err := scalar.ReadAt(&v, T_NATIVE_INT32, []uint{}, []uint{}); err != nil || v != 42 {
+		t.Errorf("ReadAt of a scalar returned %d, %v", v, err)
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{2, 3}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("grid", T_STD_U16BE, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	if err := dset.Write([]int32{1, 2, 3, 4, 5, 6}, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+
+	data, shape, err := dset.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll failed: %s", err)
+	}
+	if !reflect.DeepEqual(data, []uint16{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("ReadAll returned %#v", data)
+	}
+	if !reflect.DeepEqual(shape, Shape{2, 3}) {
+		t.Errorf("ReadAll returned shape %v", shape)
+	}
+	floats, shape, err := ReadAllAs[float64](dset)
+	if err != nil {
+		t.Fatalf("ReadAllAs failed: %s", err)
+	}
+	if !reflect.DeepEqual(floats, []float64{1, 2, 3, 4, 5, 6}) || shape.Size() != 6 {
+		t.Errorf("ReadAllAs returned %v, %v", floats, shape)
+	}
+
+	sspace, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatalf("CreateDataspace failed: %s", err)
+	}
+	defer sspace.Close()
+	name, err := f.CreateDataset("name", T_GO_STRING, sspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer name.Close()
+	if err := name.WriteScalar("probe"); err != nil {
+		t.Fatalf("WriteScalar failed: %s", err)
+	}
+	data, shape, err = name.ReadAll()
+	if err != nil || !reflect.DeepEqual(data, []string{"probe"}) || len(shape) != 0 {
+		t.Errorf("ReadAll returned %#v, %v, %v", data, shape, err)
+	}
+	strs, _, err := ReadAllAs[string](name)
+	if err != nil || !reflect.DeepEqual(strs, []string{"probe"}) {
+		t.Errorf("ReadAllAs returned %v, %v", strs, err)
+	}
+}
+
+func TestNested(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{2, 3, 2}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("cube", T_NATIVE_INT32, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+
+	cube := [][][]int32{
+		{{1, 2}, {3, 4}, {5, 6}},
+		{{7, 8}, {9, 10}, {11, 12}},
+	}
+	if err := dset.WriteNested(cube, T_NATIVE_INT32); err != nil {
+		t.Fatalf("WriteNested failed: %s", err)
+	}
+	flat := make([]int32, 12)
+	if err := dset.Read(flat, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if !reflect.DeepEqual(flat, []int32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}) {
+		t.Errorf("Read returned %v", flat)
+	}
+	var got [][][]int32
+	if err := dset.ReadNested(&got, T_NATIVE_INT32); err != nil {
+		t.Fatalf("ReadNested failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, cube) {
+		t.Errorf("ReadNested returned %v, want %v", got, cube)
+	}
+
+	ragged := [][][]int32{
+		{{1, 2}, {3, 4}, {5, 6}},
+		{{7, 8}, {9}, {11, 12}},
+	}
+	if err := dset.WriteNested(ragged, T_NATIVE_INT32); err == nil {
+		t.Errorf("expected an error writing a ragged slice")
+	}
+	if err := dset.WriteNested([][]int32{{1, 2}, {3, 4}}, T_NATIVE_INT32); err == nil {
+		t.Errorf("expected an error writing a slice of the wrong shape")
+	}
+	var flatOnly []int32
+	if err := dset.ReadNested(&flatOnly, T_NATIVE_INT32); err == nil {
+		t.Errorf("expected an error reading into too few levels of slices")
+	}
+}
+
+func TestColumnMajor(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{2, 3}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("matrix", T_NATIVE_DOUBLE, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+
+	// The column-major layout of [[1 2 3] [4 5 6]].
+	fortran := []float64{1, 4, 2, 5, 3, 6}
+	if err := dset.WriteColumnMajor(fortran, T_NATIVE_DOUBLE); err != nil {
+		t.Fatalf("WriteColumnMajor failed: %s", err)
+	}
+	rows := make([]float64, 6)
+	if err := dset.Read(rows, T_NATIVE_DOUBLE); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if !reflect.DeepEqual(rows, []float64{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("Read returned %v", rows)
+	}
+	cols := make([]float64, 6)
+	if err := dset.ReadColumnMajor(cols, T_NATIVE_DOUBLE); err != nil {
+		t.Fatalf("ReadColumnMajor failed: %s", err)
+	}
+	if !reflect.DeepEqual(cols, fortran) {
+		t.Errorf("ReadColumnMajor returned %v, want %v", cols, fortran)
+	}
+	if err := dset.ReadColumnMajor(cols, nil); err != nil || !reflect.DeepEqual(cols, fortran) {
+		t.Errorf("ReadColumnMajor with an inferred datatype returned %v, %v", cols, err)
+	}
+	if err := dset.ReadColumnMajor(cols[:5], T_NATIVE_DOUBLE); err == nil {
+		t.Errorf("expected an error reading into a short buffer")
+	}
+
+	type named struct {
+		X    float64
+		Name string
+	}
+	dtype := NewDatatypeFromValue(named{})
+	defer dtype.Close()
+	if err := dset.WriteColumnMajor(make([]named, 6), dtype); err == nil {
+		t.Errorf("expected an error reordering a compound with a string member")
+	}
+}
+
+func TestInferType(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{3}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+
+	type point struct {
+		X, Y float64
+	}
+	dtype, err := NewDatatypeFromValue(point{}).Copy()
+	if err != nil {
+		t.Fatalf("Copy failed: %s", err)
+	}
+	defer dtype.Close()
+	dset, err := f.CreateDataset("points", dtype, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	points := []point{{1, 2}, {3, 4}, {5, 6}}
+	if err := dset.Write(points, nil); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	got := make([]point, 3)
+	if err := dset.Read(got, nil); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, points) {
+		t.Errorf("Read returned %v, want %v", got, points)
+	}
+
+	// Integers of the file are converted to the inferred float32.
+	ints, err := f.CreateDataset("ints", T_STD_I64BE, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer ints.Close()
+	if err := ints.Write([]int16{-1, 0, 1}, nil); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	var floats [3]float32
+	if err := ints.Read(&floats, nil); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if floats != [3]float32{-1, 0, 1} {
+		t.Errorf("Read returned %v", floats)
+	}
+
+	a, err := ints.CreateAttribute("scale", T_NATIVE_DOUBLE, dspace)
+	if err != nil {
+		t.Fatalf("CreateAttribute failed: %s", err)
+	}
+	defer a.Close()
+	if err := a.Write([]float64{0.5, 1, 2}, nil); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	scale := make([]float64, 3)
+	if err := a.Read(scale, nil); err != nil || !reflect.DeepEqual(scale, []float64{0.5, 1, 2}) {
+		t.Errorf("Read returned %v, %v", scale, err)
+	}
+	if err := a.Write([]string{"a", "b", "c"}, nil); err == nil {
+		t.Errorf("expected an error inferring the datatype of strings")
+	}
+	if err := ints.Write([]complex64{1, 2, 3}, nil); err == nil {
+		t.Errorf("expected an error inferring the datatype of complex64")
+	}
+}
+
+func TestReadRecords(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{2}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+
+	type position struct {
+		X, Y float32
+	}
+	type hit struct {
+		Channel int32
+		Energy  float64
+		Pos     position
+	}
+	dset, err := f.CreateDataset("hits", NewDatatypeFromValue(hit{}), dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	if err := dset.Write([]hit{{1, 2.5, position{1, 2}}, {7, 0.25, position{3, 4}}}, nil); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+
+	records, err := dset.ReadRecords()
+	if err != nil {
+		t.Fatalf("ReadRecords failed: %s", err)
+	}
+	want := []map[string]interface{}{
+		{"Channel": int64(1), "Energy": 2.5, "Pos": map[string]interface{}{"X": 1.0, "Y": 2.0}},
+		{"Channel": int64(7), "Energy": 0.25, "Pos": map[string]interface{}{"X": 3.0, "Y": 4.0}},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("ReadRecords returned %v, want %v", records, want)
+	}
+
+	columns, err := dset.ReadColumns()
+	if err != nil {
+		t.Fatalf("ReadColumns failed: %s", err)
+	}
+	if len(columns) != 3 || columns[0].Name != "Channel" || columns[2].Name != "Pos" {
+		t.Fatalf("ReadColumns returned %v", columns)
+	}
+	if !reflect.DeepEqual(columns[1].Values, []interface{}{2.5, 0.25}) {
+		t.Errorf("ReadColumns returned energies %v", columns[1].Values)
+	}
+
+	ints, err := f.CreateDataset("ints", T_NATIVE_INT32, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer ints.Close()
+	if _, err := ints.ReadRecords(); err == nil {
+		t.Errorf("expected an error reading records of integers")
+	}
+}
+
+func TestReadFields(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{3}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+
+	type wide struct {
+		ID     int64
+		A, B   float64
+		C      int32
+		Energy float32
+	}
+	dset, err := f.CreateDataset("wide", NewDatatypeFromValue(wide{}), dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	rows := []wide{{1, 2, 3, 4, 0.5}, {2, 3, 4, 5, 1.5}, {3, 4, 5, 6, 2.5}}
+	if err := dset.Write(rows, nil); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+
+	type slim struct {
+		Energy float32
+		ID     int64
+		Tag    string
+	}
+	got := make([]slim, 3)
+	got[0].Tag = "kept"
+	if err := dset.ReadFields(got, "ID", "Energy"); err != nil {
+		t.Fatalf("ReadFields failed: %s", err)
+	}
+	want := []slim{{0.5, 1, "kept"}, {1.5, 2, ""}, {2.5, 3, ""}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadFields returned %v, want %v", got, want)
+	}
+	if err := dset.ReadFields(got, "Missing"); err == nil {
+		t.Errorf("expected an error reading a missing member")
+	}
+	if err := dset.ReadFields(got, "A"); err == nil {
+		t.Errorf("expected an error reading a member with no field")
+	}
+}
+
+func TestVarLenStrings(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{3}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+
+	names := []string{"alpha", "", "a much longer name"}
+	dset, err := f.CreateDataset("names", T_GO_STRING, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	if err := dset.Write(names, T_GO_STRING); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	got := make([]string, 3)
+	if err := dset.Read(got, T_GO_STRING); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, names) {
+		t.Errorf("Read returned %q, want %q", got, names)
+	}
+
+	fixed, err := T_C_S1.Copy()
+	if err != nil {
+		t.Fatalf("Copy failed: %s", err)
+	}
+	defer fixed.Close()
+	if err := fixed.SetSize(8); err != nil {
+		t.Fatalf("SetSize failed: %s", err)
+	}
+	codes, err := f.CreateDataset("codes", fixed, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer codes.Close()
+	if err := codes.Write([]string{"AB", "CDEFGH", "I"}, nil); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	if err := codes.Read(got, nil); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if want := []string{"AB", "CDEFGH", "I"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Read returned %q, want %q", got, want)
+	}
+
+	a, err := dset.CreateAttribute("aliases", T_GO_STRING, dspace)
+	if err != nil {
+		t.Fatalf("CreateAttribute failed: %s", err)
+	}
+	defer a.Close()
+	if err := a.Write(names, nil); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	aliases := make([]string, 3)
+	if err := a.Read(aliases, nil); err != nil || !reflect.DeepEqual(aliases, names) {
+		t.Errorf("Read returned %q, %v", aliases, err)
+	}
+}
+
+func TestVarLenBufSize(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	names := []string{"alpha", "beta", "gamma"}
+	dspace, err := CreateSimpleDataspace([]uint{uint(len(names))}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("names", T_GO_STRING, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	if err := dset.Write(names, T_GO_STRING); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+
+	all, err := dset.VarLenBufSize(T_GO_STRING, nil)
+	if err != nil {
+		t.Fatalf("VarLenBufSize failed: %s", err)
+	}
+	if all < 5+4+5 {
+		t.Errorf("VarLenBufSize returned %d for %v", all, names)
+	}
+	if err := dspace.SelectHyperslab(S_SELECT_SET, []uint{1}, nil, []uint{1}, nil); err != nil {
+		t.Fatalf("SelectHyperslab failed: %s", err)
+	}
+	one, err := dset.VarLenBufSize(T_GO_STRING, dspace)
+	if err != nil {
+		t.Fatalf("VarLenBufSize failed: %s", err)
+	}
+	if one < 4 || one >= all {
+		t.Errorf("VarLenBufSize returned %d for %q, %d for all", one, names[1], all)
+	}
+}
+
+func TestSpaceStatus(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	dspace, err := CreateSimpleDataspace([]uint{100}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dcpl, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer dcpl.Close()
+	if err := dcpl.SetChunk([]uint{10}); err != nil {
+		t.Fatalf("SetChunk failed: %s", err)
+	}
+	dset, err := f.CreateDataset("dset", T_NATIVE_INT32, dspace, dcpl)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+
+	check := func(want SpaceStatus) {
+		t.Helper()
+		if status, err := dset.SpaceStatus(); err != nil || status != want {
+			t.Errorf("SpaceStatus returned %v, %v, want %v", status, err, want)
+		}
+	}
+	check(D_SPACE_STATUS_NOT_ALLOCATED)
+	if err := dset.WriteSubset(make([]int32, 10), T_NATIVE_INT32, []uint{0}, nil, []uint{10}); err != nil {
+		t.Fatalf("WriteSubset failed: %s", err)
+	}
+	check(D_SPACE_STATUS_PART_ALLOCATED)
+	if err := dset.Write(make([]int32, 100), T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	check(D_SPACE_STATUS_ALLOCATED)
+}
+
+func TestScratch(t *testing.T) {
+	b := getScratch(16)
+	for i := range b {
+		b[i] = 0xff
+	}
+	putScratch(b)
+	for _, n := range []int{8, 16, 32} {
+		b := getScratch(n)
+		if len(b) != n {
+			t.Errorf("getScratch(%d) returned %d bytes", n, len(b))
+		}
+		for i, c := range b {
+			if c != 0 {
+				t.Fatalf("getScratch(%d) returned byte %d set", n, i)
+			}
+		}
+		putScratch(b)
+	}
+}
+
+// createFixedStrings creates a dataset of n fixed-length strings of 16
+// bytes for the string benchmarks.
+func createFixedStrings(b *testing.B, f *File, n int) (*Dataset, *Datatype) {
+	dtype, err := NewStringType(16, T_CSET_ASCII, T_STR_NULLPAD)
+	if err != nil {
+		b.Fatalf("NewStringType failed: %s", err)
+	}
+	dspace, err := CreateSimpleDataspace([]uint{uint(n)}, nil)
+	if err != nil {
+		b.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("strings", dtype, dspace, P_DEFAULT)
+	if err != nil {
+		b.Fatalf("CreateDataset failed: %s", err)
+	}
+	return dset, dtype
+}
+
+func BenchmarkReadFixedStrings(b *testing.B) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		b.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	const n = 10000
+	dset, dtype := createFixedStrings(b, f, n)
+	defer dset.Close()
+	defer dtype.Close()
+	strs := make([]string, n)
+	for i := range strs {
+		strs[i] = fmt.Sprintf("record %d", i)
+	}
+	if err := dset.Write(strs, dtype); err != nil {
+		b.Fatalf("Write failed: %s", err)
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(n * 16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := dset.Read(strs, dtype); err != nil {
+			b.Fatalf("Read failed: %s", err)
+		}
+	}
+}
+
+func BenchmarkWriteFixedStrings(b *testing.B) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		b.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	const n = 10000
+	dset, dtype := createFixedStrings(b, f, n)
+	defer dset.Close()
+	defer dtype.Close()
+	strs := make([]string, n)
+	for i := range strs {
+		strs[i] = fmt.Sprintf("record %d", i)
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(n * 16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := dset.Write(strs, dtype); err != nil {
+			b.Fatalf("Write failed: %s", err)
+		}
+	}
+}
+
+func TestReadParallel(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	dims := []uint{100, 70}
+	dspace, err := CreateSimpleDataspace(dims, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dcpl, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer dcpl.Close()
+	// chunks that do not divide the dataset
+	if err := dcpl.SetChunk([]uint{16, 16}); err != nil {
+		t.Fatalf("SetChunk failed: %s", err)
+	}
+	if err := dcpl.SetDeflate(6); err != nil {
+		t.Fatalf("SetDeflate failed: %s", err)
+	}
+
+	for _, full := range []bool{true, false} {
+		name := fmt.Sprintf("full=%v", full)
+		dset, err := f.CreateDataset(name, T_NATIVE_DOUBLE, dspace, dcpl)
+		if err != nil {
+			t.Fatalf("CreateDataset failed: %s", err)
+		}
+		defer dset.Close()
+		data := make([]float64, 100*70)
+		for i := range data {
+			data[i] = float64(i) / 3
+		}
+		if full {
+			err = dset.Write(data, T_NATIVE_DOUBLE)
+		} else {
+			// leave most chunks unallocated
+			err = dset.WriteSubset(data[:20*30], T_NATIVE_DOUBLE, []uint{10, 20}, nil, []uint{20, 30})
+		}
+		if err != nil {
+			t.Fatalf("Write failed: %s", err)
+		}
+
+		want := make([]float64, len(data))
+		if err := dset.Read(want, T_NATIVE_DOUBLE); err != nil {
+			t.Fatalf("Read failed: %s", err)
+		}
+		for _, workers := range []int{0, 1, 3} {
+			got := make([]float64, len(data))
+			for i := range got {
+				got[i] = -1
+			}
+			if err := dset.ReadParallel(got, T_NATIVE_DOUBLE, workers); err != nil {
+				t.Fatalf("ReadParallel failed: %s", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: ReadParallel with %d workers differs from Read", name, workers)
+			}
+		}
+		if err := dset.ReadParallel(make([]float64, 10), T_NATIVE_DOUBLE, 2); err == nil {
+			t.Errorf("expected an error reading into a short buffer")
+		}
+	}
+
+	// a contiguous dataset is read with Read
+	dset, err := f.CreateDataset("contiguous", T_NATIVE_INT32, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	data := make([]int32, 100*70)
+	for i := range data {
+		data[i] = int32(i)
+	}
+	if err := dset.Write(data, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	got := make([]int32, len(data))
+	if err := dset.ReadParallel(got, nil, 2); err != nil {
+		t.Fatalf("ReadParallel failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, data) {
+		t.Errorf("ReadParallel of a contiguous dataset differs from the data written")
+	}
+}
+
+func TestUnshuffleChunk(t *testing.T) {
+	// 3 elements of 4 bytes and a trailing byte
+	data := []byte{0, 1, 2, 3, 10, 11, 12, 13, 20, 21, 22, 23, 99}
+	shuffled := []byte{0, 10, 20, 1, 11, 21, 2, 12, 22, 3, 13, 23, 99}
+	got, err := unshuffleChunk(shuffled, nil, 4)
+	if err != nil {
+		t.Fatalf("unshuffleChunk failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, data) {
+		t.Errorf("unshuffleChunk returned %v, want %v", got, data)
+	}
+}
+
+func TestCreateDatasetFromValue(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	shape := func(s *Dataset) []uint {
+		space := s.Space()
+		defer space.Close()
+		dims, _, err := space.SimpleExtentDims()
+		if err != nil {
+			t.Fatalf("SimpleExtentDims failed: %s", err)
+		}
+		return dims
+	}
+
+	grid := [][]float64{{1, 2, 3}, {4, 5, 6}}
+	s, err := f.CreateDatasetFromValue("grid", grid, &DatasetOptions{Chunk: []uint{1, 3}, Deflate: 6})
+	if err != nil {
+		t.Fatalf("CreateDatasetFromValue failed: %s", err)
+	}
+	if dims := shape(s); !reflect.DeepEqual(dims, []uint{2, 3}) {
+		t.Errorf("grid has dimensions %v", dims)
+	}
+	got := make([]float64, 6)
+	if err := s.Read(got, nil); err != nil || !reflect.DeepEqual(got, []float64{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("Read returned %v, %v", got, err)
+	}
+	dcpl, err := s.CreatePropList()
+	if err != nil {
+		t.Fatalf("CreatePropList failed: %s", err)
+	}
+	if chunk, _ := dcpl.Chunk(); !reflect.DeepEqual(chunk, []uint{1, 3}) {
+		t.Errorf("grid has chunks %v", chunk)
+	}
+	if n, _ := dcpl.NumFilters(); n != 1 {
+		t.Errorf("grid has %d filters", n)
+	}
+	dcpl.Close()
+	s.Close()
+
+	type point struct {
+		X, Y int32
+	}
+	points := [][2]point{{{1, 2}, {3, 4}}, {{5, 6}, {7, 8}}, {{9, 10}, {11, 12}}}
+	s, err = f.CreateDatasetFromValue("points", points, &DatasetOptions{Unlimited: true})
+	if err != nil {
+		t.Fatalf("CreateDatasetFromValue failed: %s", err)
+	}
+	if dims := shape(s); !reflect.DeepEqual(dims, []uint{3, 2}) {
+		t.Errorf("points has dimensions %v", dims)
+	}
+	if err := s.Resize([]uint{4, 2}); err != nil {
+		t.Errorf("Resize failed: %s", err)
+	}
+	gotPoints := make([][2]point, 4)
+	if err := s.Read(gotPoints, nil); err != nil || !reflect.DeepEqual(gotPoints[:3], points) {
+		t.Errorf("Read returned %v, %v", gotPoints, err)
+	}
+	s.Close()
+
+	s, err = f.CreateDatasetFromValue("origin", point{-1, 1}, nil)
+	if err != nil {
+		t.Fatalf("CreateDatasetFromValue failed: %s", err)
+	}
+	if dims := shape(s); len(dims) != 0 {
+		t.Errorf("origin has dimensions %v", dims)
+	}
+	var origin point
+	if err := s.Read(&origin, nil); err != nil || origin != (point{-1, 1}) {
+		t.Errorf("Read returned %v, %v", origin, err)
+	}
+	s.Close()
+
+	s, err = f.CreateDatasetFromValue("names", [][]string{{"a", "b"}, {"c", "d"}}, nil)
+	if err != nil {
+		t.Fatalf("CreateDatasetFromValue failed: %s", err)
+	}
+	names := make([]string, 4)
+	if err := s.Read(names, nil); err != nil || !reflect.DeepEqual(names, []string{"a", "b", "c", "d"}) {
+		t.Errorf("Read returned %v, %v", names, err)
+	}
+	s.Close()
+
+	if _, err := f.CreateDatasetFromValue("ragged", [][]int{{1, 2}, {3}}, nil); err == nil {
+		t.Errorf("expected an error for ragged slices")
+	}
+	if _, err := f.CreateDatasetFromValue("nil", nil, nil); err == nil {
+		t.Errorf("expected an error for nil")
+	}
+}
+
+// TestPinnedBuffers passes buffers on growing stacks and buffers holding Go
+// pointers to the library while the garbage collector runs, and again in a
+// child process that collects as often as it can.
+func TestPinnedBuffers(t *testing.T) {
+	const name = "pinned.h5"
+	f, err := CreateFile(name, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(name)
+	defer f.Close()
+	s, err := f.CreateDatasetFromValue("values", make([]int64, 64), nil)
+	if err != nil {
+		t.Fatalf("CreateDatasetFromValue failed: %s", err)
+	}
+	defer s.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				runtime.GC()
+			}
+		}
+	}()
+
+	// arrays on the stack, which grows between the calls
+	var roundTrip func(depth int) error
+	roundTrip = func(depth int) error {
+		var in, out [64]int64
+		for i := range in {
+			in[i] = int64(depth*100 + i)
+		}
+		if err := s.Write(&in, nil); err != nil {
+			return err
+		}
+		if depth > 0 {
+			if err := roundTrip(depth - 1); err != nil {
+				return err
+			}
+			if err := s.Write(&in, nil); err != nil {
+				return err
+			}
+		}
+		if err := s.Read(&out, nil); err != nil {
+			return err
+		}
+		if out != in {
+			return fmt.Errorf("read %v at depth %d, want %v", out[:3], depth, in[:3])
+		}
+		return nil
+	}
+	for i := 0; i < 20; i++ {
+		if err := roundTrip(50); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Go pointers may not be written by the library into Go memory
+	type named struct {
+		Name  string
+		Limbs []int
+	}
+	src := []named{{"octopus", []int{8}}, {"starfish", []int{5, 6}}, {"snail", nil}}
+	otype, err := CreateDatatype(T_OPAQUE, int(reflect.TypeOf(named{}).Size()))
+	if err != nil {
+		t.Fatalf("CreateDatatype failed: %s", err)
+	}
+	defer otype.Close()
+	space, err := CreateSimpleDataspace([]uint{3}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer space.Close()
+	dst := make([]named, 3)
+	if err := Gather(space, src, otype, dst); err == nil {
+		t.Errorf("Gather copied Go pointers into Go memory")
+	}
+	if err := Scatter(src, otype, space, dst); err == nil {
+		t.Errorf("Scatter copied Go pointers into Go memory")
+	}
+
+	var v [4]int64
+	if err := s.Read(v, nil); err == nil {
+		t.Errorf("expected an error reading into an array value")
+	}
+
+	if os.Getenv("GO_HDF5_PIN_CHILD") != "" || testing.Short() {
+		return
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestPinnedBuffers$", "-test.count=1")
+	cmd.Env = append(os.Environ(), "GO_HDF5_PIN_CHILD=1", "GOGC=1", "GODEBUG=cgocheck=1,gcstoptheworld=1,invalidptr=1")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("child process failed: %s\n%s", err, out)
+	}
+}
+
+func TestStream(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	data := make([][]int32, 10)
+	for i := range data {
+		data[i] = []int32{int32(3 * i), int32(3*i + 1), int32(3*i + 2)}
+	}
+	s, err := f.CreateDatasetFromValue("grid", data, &DatasetOptions{Chunk: []uint{4, 2}})
+	if err != nil {
+		t.Fatalf("CreateDatasetFromValue failed: %s", err)
+	}
+	defer s.Close()
+
+	var rows []int32
+	var counts [][]uint
+	for b, err := range s.Stream(4) {
+		if err != nil {
+			t.Fatalf("Stream failed: %s", err)
+		}
+		if len(rows) != int(3*b.Offset[0]) {
+			t.Errorf("block at offset %v after %d elements", b.Offset, len(rows))
+		}
+		rows = append(rows, b.Data.([]int32)...)
+		counts = append(counts, b.Count)
+	}
+	for i, v := range rows {
+		if v != int32(i) {
+			t.Fatalf("Stream read %v", rows)
+		}
+	}
+	if want := [][]uint{{4, 3}, {4, 3}, {2, 3}}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("Stream read blocks of %v, want %v", counts, want)
+	}
+
+	n := 0
+	for b, err := range s.Chunks() {
+		if err != nil {
+			t.Fatalf("Chunks failed: %s", err)
+		}
+		vals := b.Data.([]int32)
+		if len(vals) != int(b.Count[0]*b.Count[1]) || vals[0] != int32(3*b.Offset[0]+b.Offset[1]) {
+			t.Errorf("chunk at %v of %v read %v", b.Offset, b.Count, vals)
+		}
+		n++
+	}
+	if n != 6 {
+		t.Errorf("Chunks yielded %d chunks, want 6", n)
+	}
+
+	c, err := f.CreateDatasetFromValue("contiguous", []float64{1, 2}, nil)
+	if err != nil {
+		t.Fatalf("CreateDatasetFromValue failed: %s", err)
+	}
+	defer c.Close()
+	for _, err := range c.Chunks() {
+		if err == nil {
+			t.Errorf("Chunks of a contiguous dataset succeeded")
+		}
+	}
+}
+
+func TestDatasetStreams(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	s, err := f.CreateDatasetFromValue("log", []byte{}, &DatasetOptions{Chunk: []uint{16}, Unlimited: true})
+	if err != nil {
+		t.Fatalf("CreateDatasetFromValue failed: %s", err)
+	}
+	defer s.Close()
+
+	w, err := NewDatasetWriter(s)
+	if err != nil {
+		t.Fatalf("NewDatasetWriter failed: %s", err)
+	}
+	text := "first line\nsecond line\n"
+	for _, line := range []string{"first line\n", "second line\n"} {
+		if _, err := io.WriteString(w, line); err != nil {
+			t.Fatalf("Write failed: %s", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %s", err)
+	}
+
+	r, err := NewDatasetReader(s)
+	if err != nil {
+		t.Fatalf("NewDatasetReader failed: %s", err)
+	}
+	defer r.Close()
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %s", err)
+	}
+	if string(got) != text {
+		t.Errorf("read %q, want %q", got, text)
+	}
+	part := make([]byte, 6)
+	if _, err := r.ReadAt(part, 11); err != nil || string(part) != "second" {
+		t.Errorf("ReadAt read %q, %v", part, err)
+	}
+
+	// records of several bytes, written in pieces
+	recs, err := f.CreateDatasetFromValue("records", []int32{}, &DatasetOptions{Chunk: []uint{4}, Unlimited: true})
+	if err != nil {
+		t.Fatalf("CreateDatasetFromValue failed: %s", err)
+	}
+	defer recs.Close()
+	w, err = NewDatasetWriter(recs)
+	if err != nil {
+		t.Fatalf("NewDatasetWriter failed: %s", err)
+	}
+	w.Write([]byte{1, 0, 0})
+	w.Write([]byte{0, 2, 0, 0, 0, 3})
+	if err := w.Close(); err == nil {
+		t.Errorf("Close with an incomplete record succeeded")
+	}
+	vals, _, err := ReadAllAs[int32](recs)
+	if err != nil {
+		t.Fatalf("ReadAllAs failed: %s", err)
+	}
+	if !reflect.DeepEqual(vals, []int32{1, 2}) {
+		t.Errorf("wrote records %v, want [1 2]", vals)
+	}
+}
+
+func TestReadTolerant(t *testing.T) {
+	const name = "corrupt.h5"
+	f, err := CreateFile(name, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(name)
+	dcpl, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer dcpl.Close()
+	if err := dcpl.SetChunk([]uint{10}); err != nil {
+		t.Fatalf("SetChunk failed: %s", err)
+	}
+	if err := dcpl.SetFletcher32(); err != nil {
+		t.Fatalf("SetFletcher32 failed: %s", err)
+	}
+	dspace, err := CreateSimpleDataspace([]uint{100}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	s, err := f.CreateDataset("samples", T_NATIVE_INT32, dspace, dcpl)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	values := make([]int32, 100)
+	for i := range values {
+		values[i] = int32(1000 + i)
+	}
+	if err := s.Write(&values, nil); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	s.Close()
+	f.Close()
+
+	// corrupt the fourth chunk
+	raw, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chunk bytes.Buffer
+	binary.Write(&chunk, binary.LittleEndian, values[30:40])
+	pos := bytes.Index(raw, chunk.Bytes())
+	if pos < 0 {
+		t.Skip("chunk not found in the file")
+	}
+	raw[pos+5] ^= 0xff
+	if err := os.WriteFile(name, raw, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err = OpenFile(name, F_ACC_RDONLY)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %s", err)
+	}
+	defer f.Close()
+	s, err = f.OpenDataset("samples")
+	if err != nil {
+		t.Fatalf("OpenDataset failed: %s", err)
+	}
+	defer s.Close()
+	got := make([]int32, 100)
+	if err := s.Read(&got, nil); err == nil {
+		t.Errorf("Read of a corrupted chunk succeeded")
+	}
+	report, err := s.ReadTolerant(&got, nil)
+	if err != nil {
+		t.Fatalf("ReadTolerant failed: %s", err)
+	}
+	if report.Chunks != 10 || len(report.Bad) != 1 || !reflect.DeepEqual(report.Bad[0].Offset, []uint{30}) {
+		t.Fatalf("ReadTolerant reported %s", report)
+	}
+	for i, v := range got {
+		want := values[i]
+		if i >= 30 && i < 40 {
+			want = 0
+		}
+		if v != want {
+			t.Fatalf("element %d read as %d, want %d", i, v, want)
+		}
+	}
+}
+
+func TestRegionRef(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	image := make([][]uint16, 8)
+	for i := range image {
+		image[i] = make([]uint16, 8)
+		for j := range image[i] {
+			image[i][j] = uint16(10*i + j)
+		}
+	}
+	s, err := f.CreateDatasetFromValue("image", image, nil)
+	if err != nil {
+		t.Fatalf("CreateDatasetFromValue failed: %s", err)
+	}
+	defer s.Close()
+
+	// a feature of 2x3 pixels at row 4, column 2
+	space := s.Space()
+	defer space.Close()
+	if err := space.SelectHyperslab(S_SELECT_SET, []uint{4, 2}, nil, []uint{2, 3}, nil); err != nil {
+		t.Fatalf("SelectHyperslab failed: %s", err)
+	}
+	ref, err := s.CreateRegionRef(space)
+	if err != nil {
+		t.Fatalf("CreateRegionRef failed: %s", err)
+	}
+	rspace, err := CreateSimpleDataspace([]uint{1}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer rspace.Close()
+	features, err := f.CreateDataset("features", T_STD_REF_DSETREG, rspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer features.Close()
+	if err := features.Write([]RegionRef{ref}, T_STD_REF_DSETREG); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+
+	refs := make([]RegionRef, 1)
+	if err := features.Read(refs, T_STD_REF_DSETREG); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	d, sel, err := f.DereferenceRegion(refs[0])
+	if err != nil {
+		t.Fatalf("DereferenceRegion failed: %s", err)
+	}
+	defer d.Close()
+	defer sel.Close()
+	if d.Name() != "/image" || sel.SelectNPoints() != 6 {
+		t.Fatalf("DereferenceRegion returned %s with %d points", d.Name(), sel.SelectNPoints())
+	}
+	pixels := make([]uint16, 6)
+	if err := d.ReadSelection(pixels, T_NATIVE_UINT16, sel); err != nil {
+		t.Fatalf("ReadSelection failed: %s", err)
+	}
+	if want := []uint16{42, 43, 44, 52, 53, 54}; !reflect.DeepEqual(pixels, want) {
+		t.Errorf("ReadSelection read %v, want %v", pixels, want)
+	}
+}
+
+func TestStats(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	data := make([]float64, 100)
+	for i := range data {
+		data[i] = float64(i)
+	}
+	data[99] = math.NaN()
+	s, err := f.CreateDatasetFromValue("values", data, &DatasetOptions{Chunk: []uint{16}})
+	if err != nil {
+		t.Fatalf("CreateDatasetFromValue failed: %s", err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+	st, err := s.Stats(ctx, &StatsOptions{Bins: 2, Cache: true})
+	if err != nil {
+		t.Fatalf("Stats failed: %s", err)
+	}
+	want := &Stats{
+		Count:     99,
+		NaN:       1,
+		Min:       0,
+		Max:       98,
+		Mean:      49,
+		StdDev:    math.Sqrt(816.6666666666666),
+		Histogram: &StatsHistogram{Min: 0, Max: 98, Counts: []int{49, 50}},
+	}
+	if math.Abs(st.StdDev-want.StdDev) < 1e-9 {
+		st.StdDev = want.StdDev
+	}
+	if !reflect.DeepEqual(st, want) {
+		t.Errorf("Stats = %+v %+v, want %+v %+v", st, st.Histogram, want, want.Histogram)
+	}
+
+	// the cached statistics are returned until refreshed
+	for i := range data {
+		data[i] = 1
+	}
+	if err := s.Write(data, nil); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	if cached, err := s.Stats(ctx, &StatsOptions{Bins: 2, Cache: true}); err != nil || !reflect.DeepEqual(cached, st) {
+		t.Errorf("cached Stats = %+v, %v, want %+v", cached, err, st)
+	}
+	st, err = s.Stats(ctx, &StatsOptions{Cache: true, Refresh: true})
+	if err != nil {
+		t.Fatalf("Stats failed: %s", err)
+	}
+	if st.Count != 100 || st.Mean != 1 || st.StdDev != 0 || st.Histogram != nil {
+		t.Errorf("refreshed Stats = %+v", st)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := s.Stats(ctx, nil); err != context.Canceled {
+		t.Errorf("Stats after cancellation returned %v", err)
+	}
+
+	// infinities are left out of the range of the histogram
+	inf, err := f.CreateDatasetFromValue("inf", []float64{math.Inf(-1), 1, 2, 3, math.Inf(1)}, nil)
+	if err != nil {
+		t.Fatalf("CreateDatasetFromValue failed: %s", err)
+	}
+	defer inf.Close()
+	st, err = inf.Stats(context.Background(), &StatsOptions{Bins: 2})
+	if err != nil {
+		t.Fatalf("Stats with infinities failed: %s", err)
+	}
+	if h := st.Histogram; h.Min != 1 || h.Max != 3 || !reflect.DeepEqual(h.Counts, []int{1, 2}) || h.Under != 1 || h.Over != 1 {
+		t.Errorf("histogram with infinities = %+v", h)
+	}
+	if st.Min != math.Inf(-1) || st.Max != math.Inf(1) {
+		t.Errorf("Stats with infinities = %+v", st)
+	}
+	st, err = inf.Stats(context.Background(), &StatsOptions{Bins: 4, HistMin: -math.MaxFloat64, HistMax: math.MaxFloat64})
+	if err != nil {
+		t.Fatalf("Stats over the whole range failed: %s", err)
+	}
+	if h := st.Histogram; !reflect.DeepEqual(h.Counts, []int{0, 0, 3, 0}) || h.Under != 1 || h.Over != 1 {
+		t.Errorf("histogram over the whole range = %+v", h)
+	}
+
+	strs, err := f.CreateDatasetFromValue("names", []string{"a", "b"}, nil)
+	if err != nil {
+		t.Fatalf("CreateDatasetFromValue failed: %s", err)
+	}
+	defer strs.Close()
+	if _, err := strs.Stats(context.Background(), nil); err == nil {
+		t.Errorf("Stats of strings succeeded")
+	}
+}
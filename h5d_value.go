@@ -0,0 +1,192 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DatasetOptions sets the storage of a dataset created by
+// CreateDatasetFromValue.
+type DatasetOptions struct {
+	// Chunk, if not nil, is the chunk size. Datasets that are compressed
+	// or extendible without a chunk size are stored as a single chunk.
+	Chunk []uint
+	// Deflate, if not 0, compresses the dataset with deflate at this
+	// level, from 1 to 9.
+	Deflate uint
+	// Unlimited makes every dimension extendible with Resize.
+	Unlimited bool
+}
+
+// CreateDatasetFromValue creates a dataset in the file holding v, and
+// writes v to it, as CreateDatasetFromValue of Group does.
+func (f *File) CreateDatasetFromValue(name string, v interface{}, opts *DatasetOptions) (*Dataset, error) {
+	return createDatasetFromValue(f.id, name, v, opts)
+}
+
+// CreateDatasetFromValue creates a dataset in the group holding v, and
+// writes v to it. The datatype is inferred from the elements of v, as for
+// reads and writes given a nil datatype, and the dimensions from its
+// shape: a slice, array or pointer to one has a dimension for each level
+// of nesting, e.g. a [][3]float64 of 10 elements gives a 10 x 3 dataset;
+// nested slices must be rectangular. Any other value, such as a struct,
+// gives a scalar dataset. opts may be nil.
+func (g *Group) CreateDatasetFromValue(name string, v interface{}, opts *DatasetOptions) (*Dataset, error) {
+	return createDatasetFromValue(g.id, name, v, opts)
+}
+
+func createDatasetFromValue(id C.hid_t, name string, v interface{}, opts *DatasetOptions) (*Dataset, error) {
+	data, dims, err := valueShape(v)
+	if err != nil {
+		return nil, err
+	}
+	dtype, owned, err := inferType(data, nil)
+	if err != nil {
+		return nil, err
+	}
+	if owned {
+		defer dtype.Close()
+	}
+
+	var dspace *Dataspace
+	if dims == nil {
+		dspace, err = CreateDataspace(S_SCALAR)
+	} else {
+		var maxDims []uint
+		if opts != nil && opts.Unlimited {
+			maxDims = make([]uint, len(dims))
+			for i := range maxDims {
+				maxDims[i] = S_UNLIMITED
+			}
+		}
+		dspace, err = CreateSimpleDataspace(dims, maxDims)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer dspace.Close()
+
+	dcpl := P_DEFAULT
+	if opts != nil && dims != nil && (opts.Chunk != nil || opts.Deflate > 0 || opts.Unlimited) {
+		dcpl, err = NewPropList(P_DATASET_CREATE)
+		if err != nil {
+			return nil, err
+		}
+		defer dcpl.Close()
+		if err := opts.apply(dcpl, dims); err != nil {
+			return nil, err
+		}
+	}
+
+	s, err := createDataset(id, name, dtype, dspace, dcpl)
+	if err != nil {
+		return nil, err
+	}
+	if dims == nil || dspace.SimpleExtentNPoints() > 0 {
+		if err := s.Write(data, dtype); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// apply sets the storage of the options in dcpl, for a dataset of the
+// dimensions dims.
+func (o *DatasetOptions) apply(dcpl *PropList, dims []uint) error {
+	chunk := o.Chunk
+	if chunk == nil {
+		chunk = make([]uint, len(dims))
+		for i, d := range dims {
+			chunk[i] = d
+			if d == 0 {
+				chunk[i] = 1
+			}
+		}
+	}
+	if err := dcpl.SetChunk(chunk); err != nil {
+		return err
+	}
+	if o.Deflate > 0 {
+		return dcpl.SetDeflate(o.Deflate)
+	}
+	return nil
+}
+
+// valueShape returns the dimensions of v, or nil if v is a scalar, and the
+// data to write them from: v itself, a pointer to a copy of it, or the
+// elements of nested slices copied to a single slice.
+func valueShape(v interface{}) (interface{}, []uint, error) {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return nil, nil, fmt.Errorf("hdf5: cannot create a dataset from nil")
+	}
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil, fmt.Errorf("hdf5: cannot create a dataset from a nil %T", v)
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice:
+		var dims []uint
+		t := rv.Type()
+		for w := rv; t.Kind() == reflect.Slice; t = t.Elem() {
+			dims = append(dims, uint(w.Len()))
+			if w.IsValid() && w.Len() > 0 {
+				w = w.Index(0)
+			} else {
+				w = reflect.Value{}
+			}
+		}
+		data := rv
+		if len(dims) > 1 {
+			data = reflect.MakeSlice(reflect.SliceOf(t), 0, int(Shape(dims).Size()))
+			var err error
+			if data, err = flattenSlices(data, rv, dims); err != nil {
+				return nil, nil, err
+			}
+		}
+		return data.Interface(), append(dims, arrayDims(t)...), nil
+
+	case reflect.Array:
+		p := reflect.New(rv.Type())
+		p.Elem().Set(rv)
+		return p.Interface(), arrayDims(rv.Type()), nil
+
+	case reflect.String:
+		return rv.String(), nil, nil
+	}
+	p := reflect.New(rv.Type())
+	p.Elem().Set(rv)
+	return p.Interface(), nil, nil
+}
+
+// arrayDims returns the lengths of the nested array types of t.
+func arrayDims(t reflect.Type) []uint {
+	var dims []uint
+	for ; t.Kind() == reflect.Array; t = t.Elem() {
+		dims = append(dims, uint(t.Len()))
+	}
+	return dims
+}
+
+// flattenSlices appends the elements of the nested slices v, of the
+// dimensions dims, to flat, and returns an error if they are ragged.
+func flattenSlices(flat, v reflect.Value, dims []uint) (reflect.Value, error) {
+	if v.Len() != int(dims[0]) {
+		return flat, fmt.Errorf("hdf5: cannot create a dataset from ragged slices: a length of %d instead of %d", v.Len(), dims[0])
+	}
+	if len(dims) == 1 {
+		return reflect.AppendSlice(flat, v), nil
+	}
+	var err error
+	for i := 0; i < v.Len() && err == nil; i++ {
+		flat, err = flattenSlices(flat, v.Index(i), dims[1:])
+	}
+	return flat, err
+}
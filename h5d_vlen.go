@@ -0,0 +1,29 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"fmt"
+)
+
+// VarLenBufSize returns the number of bytes the library allocates for the
+// variable-length elements selected by space, or all elements if space is
+// nil, when they are read in the memory datatype dtype. The buffer that
+// holds the elements themselves is not counted. It lets ragged reads be
+// checked against a memory limit before they are made.
+// herr_t H5Dvlen_get_buf_size(hid_t dataset_id, hid_t type_id, hid_t space_id, hsize_t *size)
+func (s *Dataset) VarLenBufSize(dtype *Datatype, space *Dataspace) (uint, error) {
+	if space == nil {
+		space = s.Space()
+		if space == nil {
+			return 0, fmt.Errorf("hdf5: could not get the dataspace of the dataset")
+		}
+		defer space.Close()
+	}
+	var c_size C.hsize_t
+	if err := h5err(C.H5Dvlen_get_buf_size(s.id, dtype.id, space.id, &c_size)); err != nil {
+		return 0, err
+	}
+	return uint(c_size), nil
+}
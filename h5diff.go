@@ -0,0 +1,494 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// #include <string.h>
+import "C"
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+// DiffKind is the kind of a difference found by Diff.
+type DiffKind int
+
+const (
+	DiffOnlyInA    DiffKind = iota // the object or attribute only exists in the first file
+	DiffOnlyInB                    // the object or attribute only exists in the second file
+	DiffObjectType                 // the objects are of different kinds, e.g. a group and a dataset
+	DiffDatatype                   // the datatypes differ
+	DiffShape                      // the dataspace dimensions differ
+	DiffData                       // the values differ
+)
+
+func (k DiffKind) String() string {
+	switch k {
+	case DiffOnlyInA:
+		return "only in A"
+	case DiffOnlyInB:
+		return "only in B"
+	case DiffObjectType:
+		return "object type"
+	case DiffDatatype:
+		return "datatype"
+	case DiffShape:
+		return "shape"
+	case DiffData:
+		return "data"
+	}
+	return fmt.Sprintf("DiffKind(%d)", int(k))
+}
+
+// DiffOptions controls the comparison made by Diff.
+type DiffOptions struct {
+	// AbsTol is the largest absolute difference at which two floating-point
+	// values are still considered equal (h5diff -d).
+	AbsTol float64
+	// RelTol is the largest difference, relative to the value in the first
+	// file, at which two floating-point values are still considered equal
+	// (h5diff -p).
+	RelTol float64
+	// IgnoreAttributes skips the comparison of attributes.
+	IgnoreAttributes bool
+	// StructureOnly compares the hierarchy, datatypes and shapes but not
+	// the values of datasets and attributes.
+	StructureOnly bool
+}
+
+// Difference describes one difference between two files.
+type Difference struct {
+	Kind      DiffKind
+	Path      string // path of the object
+	Attribute string // name of the attribute, or "" for the object itself
+	Count     int    // number of differing elements, for DiffData
+	Detail    string // human-readable description
+}
+
+func (d Difference) String() string {
+	where := d.Path
+	if d.Attribute != "" {
+		where += " @" + d.Attribute
+	}
+	if d.Detail == "" {
+		return fmt.Sprintf("%s: %s", where, d.Kind)
+	}
+	return fmt.Sprintf("%s: %s: %s", where, d.Kind, d.Detail)
+}
+
+// DiffReport is the result of Diff.
+type DiffReport struct {
+	Differences []Difference
+	// Objects is the number of objects present in both files.
+	Objects int
+	// NotCompared lists the datasets and attributes, as "path" or
+	// "path @name", whose values could not be compared because their
+	// datatype holds variable-length data of a class that cannot be
+	// decoded, such as references.
+	NotCompared []string
+}
+
+// Equal returns whether no differences were found.
+func (r *DiffReport) Equal() bool {
+	return len(r.Differences) == 0
+}
+
+func (r *DiffReport) String() string {
+	var b strings.Builder
+	for _, d := range r.Differences {
+		b.WriteString(d.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Diff compares the hierarchy, attributes and data of two files, like the
+// h5diff tool. Floating-point values are compared within the tolerances of
+// opts; all other values, including those inside compound types, must be
+// identical. A nil opts compares everything exactly.
+func Diff(a, b *File, opts *DiffOptions) (*DiffReport, error) {
+	if opts == nil {
+		opts = &DiffOptions{}
+	}
+	d := &differ{opts: opts, report: &DiffReport{}}
+
+	pa, ta, err := listObjects(a.id)
+	if err != nil {
+		return nil, err
+	}
+	pb, tb, err := listObjects(b.id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.compare(a.id, b.id, "/", O_TYPE_GROUP); err != nil {
+		return nil, err
+	}
+	for _, path := range pa {
+		if _, ok := tb[path]; !ok {
+			d.add(Difference{Kind: DiffOnlyInA, Path: "/" + path})
+		}
+	}
+	for _, path := range pb {
+		if _, ok := ta[path]; !ok {
+			d.add(Difference{Kind: DiffOnlyInB, Path: "/" + path})
+		}
+	}
+	for _, path := range pa {
+		typ, ok := tb[path]
+		if !ok {
+			continue
+		}
+		if typ != ta[path] {
+			d.add(Difference{
+				Kind:   DiffObjectType,
+				Path:   "/" + path,
+				Detail: fmt.Sprintf("%s vs %s", ta[path], typ),
+			})
+			continue
+		}
+		if err := d.compareByName(a.id, b.id, path, typ); err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(d.report.Differences, func(i, j int) bool {
+		return d.report.Differences[i].Path < d.report.Differences[j].Path
+	})
+	return d.report, nil
+}
+
+// listObjects returns the paths of all objects in the file, relative to
+// the root group, and their types.
+func listObjects(id C.hid_t) ([]string, map[string]ObjectType, error) {
+	var paths []string
+	types := make(map[string]ObjectType)
+	err := walkObjects(id, func(path string, info *ObjectInfo) error {
+		paths = append(paths, path)
+		types[path] = info.Type
+		return nil
+	})
+	return paths, types, err
+}
+
+type differ struct {
+	opts   *DiffOptions
+	report *DiffReport
+}
+
+func (d *differ) add(diff Difference) {
+	d.report.Differences = append(d.report.Differences, diff)
+}
+
+func (d *differ) compareByName(a, b C.hid_t, path string, typ ObjectType) error {
+	oa, err := openObject(a, path)
+	if err != nil {
+		return err
+	}
+	defer C.H5Oclose(oa)
+	ob, err := openObject(b, path)
+	if err != nil {
+		return err
+	}
+	defer C.H5Oclose(ob)
+
+	return d.compare(oa, ob, "/"+path, typ)
+}
+
+// compare compares the attributes and, for datasets, the data of the
+// objects a and b.
+func (d *differ) compare(a, b C.hid_t, path string, typ ObjectType) error {
+	d.report.Objects++
+	if !d.opts.IgnoreAttributes {
+		if err := d.compareAttributes(a, b, path); err != nil {
+			return err
+		}
+	}
+	if typ != O_TYPE_DATASET {
+		return nil
+	}
+	return d.compareValues(path, "", a, b, diffSource{
+		typ:   func(id C.hid_t) C.hid_t { return C.H5Dget_type(id) },
+		space: func(id C.hid_t) C.hid_t { return C.H5Dget_space(id) },
+		read: func(id, mtype C.hid_t, buf unsafe.Pointer) error {
+			return h5err(C.H5Dread(id, mtype, 0, 0, 0, buf))
+		},
+	})
+}
+
+func (d *differ) compareAttributes(a, b C.hid_t, path string) error {
+	na, err := attributeNames(a)
+	if err != nil {
+		return err
+	}
+	nb, err := attributeNames(b)
+	if err != nil {
+		return err
+	}
+	inB := make(map[string]bool, len(nb))
+	for _, name := range nb {
+		inB[name] = true
+	}
+	inA := make(map[string]bool, len(na))
+	for _, name := range na {
+		inA[name] = true
+		if !inB[name] {
+			d.add(Difference{Kind: DiffOnlyInA, Path: path, Attribute: name})
+		}
+	}
+	for _, name := range nb {
+		if !inA[name] {
+			d.add(Difference{Kind: DiffOnlyInB, Path: path, Attribute: name})
+		}
+	}
+
+	for _, name := range na {
+		if !inB[name] {
+			continue
+		}
+		aa, err := openAttribute(a, name)
+		if err != nil {
+			return err
+		}
+		ab, err := openAttribute(b, name)
+		if err != nil {
+			aa.Close()
+			return err
+		}
+		err = d.compareValues(path, name, aa.id, ab.id, diffSource{
+			typ:   func(id C.hid_t) C.hid_t { return C.H5Aget_type(id) },
+			space: func(id C.hid_t) C.hid_t { return C.H5Aget_space(id) },
+			read: func(id, mtype C.hid_t, buf unsafe.Pointer) error {
+				return h5err(C.H5Aread(id, mtype, buf))
+			},
+		})
+		aa.Close()
+		ab.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func attributeNames(id C.hid_t) ([]string, error) {
+	return attributeNamesByOrder(id, INDEX_NAME)
+}
+
+// diffSource abstracts the reading of datasets and attributes.
+type diffSource struct {
+	typ   func(id C.hid_t) C.hid_t
+	space func(id C.hid_t) C.hid_t
+	read  func(id, mtype C.hid_t, buf unsafe.Pointer) error
+}
+
+func (d *differ) compareValues(path, attr string, a, b C.hid_t, src diffSource) error {
+	ta, err := newDatatypeFromId(src.typ(a))
+	if err != nil {
+		return err
+	}
+	defer ta.Close()
+	tb, err := newDatatypeFromId(src.typ(b))
+	if err != nil {
+		return err
+	}
+	defer tb.Close()
+	if !ta.Equal(tb) {
+		detail := ""
+		if sa, err := ta.Text(); err == nil {
+			if sb, err := tb.Text(); err == nil {
+				detail = fmt.Sprintf("%s vs %s", sa, sb)
+			}
+		}
+		d.add(Difference{Kind: DiffDatatype, Path: path, Attribute: attr, Detail: detail})
+		return nil
+	}
+
+	sa, err := newDataspaceFromId(src.space(a))
+	if err != nil {
+		return err
+	}
+	defer sa.Close()
+	sb, err := newDataspaceFromId(src.space(b))
+	if err != nil {
+		return err
+	}
+	defer sb.Close()
+	da, err := extentDims(sa)
+	if err != nil {
+		return err
+	}
+	db, err := extentDims(sb)
+	if err != nil {
+		return err
+	}
+	if !equalDims(da, db) {
+		d.add(Difference{
+			Kind:      DiffShape,
+			Path:      path,
+			Attribute: attr,
+			Detail:    fmt.Sprintf("%v vs %v", da, db),
+		})
+		return nil
+	}
+
+	if d.opts.StructureOnly {
+		return nil
+	}
+	npoints := sa.SimpleExtentNPoints()
+	if npoints == 0 {
+		return nil
+	}
+
+	var count, first int
+	var detail string
+	if hasVarLen(ta.id) {
+		// the memory of variable-length data holds pointers, so such
+		// values are compared decoded
+		read := func(id C.hid_t) func(mtype C.hid_t, buf unsafe.Pointer) error {
+			return func(mtype C.hid_t, buf unsafe.Pointer) error {
+				return src.read(id, mtype, buf)
+			}
+		}
+		va, err := readValues(ta.id, npoints, read(a))
+		if err != nil {
+			return err
+		}
+		vb, err := readValues(tb.id, npoints, read(b))
+		if err != nil {
+			return err
+		}
+		for i := range va {
+			if va[i] == nil {
+				where := path
+				if attr != "" {
+					where += " @" + attr
+				}
+				d.report.NotCompared = append(d.report.NotCompared, where)
+				return nil
+			}
+			if reflect.DeepEqual(va[i], vb[i]) {
+				continue
+			}
+			if count == 0 {
+				first = i
+			}
+			count++
+		}
+		if count > 0 {
+			detail = fmt.Sprintf("%d of %d elements differ, first at index %d (%v vs %v)",
+				count, npoints, first, va[first], vb[first])
+		}
+	} else if ta.Class() == T_FLOAT {
+		va := make([]float64, npoints)
+		vb := make([]float64, npoints)
+		if err := src.read(a, T_NATIVE_DOUBLE.id, unsafe.Pointer(&va[0])); err != nil {
+			return err
+		}
+		if err := src.read(b, T_NATIVE_DOUBLE.id, unsafe.Pointer(&vb[0])); err != nil {
+			return err
+		}
+		var max float64
+		for i := range va {
+			if d.floatEqual(va[i], vb[i]) {
+				continue
+			}
+			if count == 0 {
+				first = i
+			}
+			count++
+			if diff := math.Abs(va[i] - vb[i]); diff > max || math.IsNaN(diff) {
+				max = diff
+			}
+		}
+		if count > 0 {
+			detail = fmt.Sprintf("%d of %d elements differ, first at index %d (%v vs %v), largest difference %g",
+				count, npoints, first, va[first], vb[first], max)
+		}
+	} else {
+		mtype := C.H5Tget_native_type(ta.id, C.H5T_DIR_DEFAULT)
+		if err := h5err(C.herr_t(int(mtype))); err != nil {
+			return err
+		}
+		defer C.H5Tclose(mtype)
+		size := int(C.H5Tget_size(mtype))
+		va := make([]byte, npoints*size)
+		vb := make([]byte, npoints*size)
+		if err := src.read(a, mtype, unsafe.Pointer(&va[0])); err != nil {
+			return err
+		}
+		if err := src.read(b, mtype, unsafe.Pointer(&vb[0])); err != nil {
+			return err
+		}
+		for i := 0; i < npoints; i++ {
+			if bytes.Equal(va[i*size:(i+1)*size], vb[i*size:(i+1)*size]) {
+				continue
+			}
+			if count == 0 {
+				first = i
+			}
+			count++
+		}
+		if count > 0 {
+			detail = fmt.Sprintf("%d of %d elements differ, first at index %d", count, npoints, first)
+		}
+	}
+	if count > 0 {
+		d.add(Difference{Kind: DiffData, Path: path, Attribute: attr, Count: count, Detail: detail})
+	}
+	return nil
+}
+
+func (d *differ) floatEqual(a, b float64) bool {
+	if a == b || (math.IsNaN(a) && math.IsNaN(b)) {
+		return true
+	}
+	diff := math.Abs(a - b)
+	if d.opts.AbsTol > 0 && diff <= d.opts.AbsTol {
+		return true
+	}
+	if d.opts.RelTol > 0 && diff <= d.opts.RelTol*math.Abs(a) {
+		return true
+	}
+	return false
+}
+
+func newDatatypeFromId(hid C.hid_t) (*Datatype, error) {
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return nil, err
+	}
+	return NewDatatype(hid, nil), nil
+}
+
+func newDataspaceFromId(hid C.hid_t) (*Dataspace, error) {
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return nil, err
+	}
+	return newDataspace(hid), nil
+}
+
+// extentDims returns the dimensions of a dataspace, which are empty for a
+// scalar or null dataspace.
+func extentDims(s *Dataspace) ([]uint, error) {
+	if s.SimpleExtentNDims() == 0 {
+		return []uint{}, nil
+	}
+	dims, _, err := s.SimpleExtentDims()
+	return dims, err
+}
+
+func equalDims(a, b []uint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,170 @@
+package hdf5
+
+import (
+	"os"
+	"testing"
+)
+
+func writeDiffFile(t *testing.T, name string, data []float64, units int32, extra bool) *File {
+	f, err := CreateFile(name, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	g, err := f.CreateGroup("g")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	defer g.Close()
+
+	dspace, err := CreateSimpleDataspace([]uint{uint(len(data))}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := g.CreateDataset("x", T_NATIVE_DOUBLE, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	if err := dset.Write(data, T_NATIVE_DOUBLE); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+
+	scalar, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatalf("CreateDataspace failed: %s", err)
+	}
+	defer scalar.Close()
+	attr, err := dset.CreateAttribute("units", T_NATIVE_INT32, scalar)
+	if err != nil {
+		t.Fatalf("CreateAttribute failed: %s", err)
+	}
+	defer attr.Close()
+	if err := attr.Write(units, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+
+	if extra {
+		e, err := f.CreateDataset("extra", T_NATIVE_INT32, scalar, P_DEFAULT)
+		if err != nil {
+			t.Fatalf("CreateDataset failed: %s", err)
+		}
+		e.Close()
+	}
+	return f
+}
+
+func TestDiff(t *testing.T) {
+	const nameA, nameB = "ex_diff_a.h5", "ex_diff_b.h5"
+	data := []float64{0, 1, 2, 3, 4, 5, 6, 7}
+
+	a := writeDiffFile(t, nameA, data, 1, false)
+	defer os.Remove(nameA)
+	defer a.Close()
+
+	same := writeDiffFile(t, nameB, data, 1, false)
+	r, err := Diff(a, same, nil)
+	if err != nil {
+		t.Fatalf("Diff failed: %s", err)
+	}
+	if !r.Equal() {
+		t.Errorf("identical files differ:\n%s", r)
+	}
+	if r.Objects != 3 {
+		t.Errorf("compared %d objects, want 3", r.Objects)
+	}
+	same.Close()
+
+	perturbed := append([]float64(nil), data...)
+	perturbed[3] += 1e-9
+	b := writeDiffFile(t, nameB, perturbed, 2, true)
+	defer os.Remove(nameB)
+	defer b.Close()
+
+	r, err = Diff(a, b, nil)
+	if err != nil {
+		t.Fatalf("Diff failed: %s", err)
+	}
+	want := []Difference{
+		{Kind: DiffOnlyInB, Path: "/extra"},
+		{Kind: DiffData, Path: "/g/x", Attribute: "units", Count: 1},
+		{Kind: DiffData, Path: "/g/x", Count: 1},
+	}
+	if len(r.Differences) != len(want) {
+		t.Fatalf("got %d differences, want %d:\n%s", len(r.Differences), len(want), r)
+	}
+	for i, d := range r.Differences {
+		w := want[i]
+		if d.Kind != w.Kind || d.Path != w.Path || d.Attribute != w.Attribute || d.Count != w.Count {
+			t.Errorf("difference %d: got %v, want %v", i, d, w)
+		}
+	}
+
+	r, err = Diff(a, b, &DiffOptions{AbsTol: 1e-6, IgnoreAttributes: true})
+	if err != nil {
+		t.Fatalf("Diff failed: %s", err)
+	}
+	if len(r.Differences) != 1 || r.Differences[0].Kind != DiffOnlyInB {
+		t.Errorf("with tolerance, got differences:\n%s", r)
+	}
+}
+
+type diffLabel struct {
+	ID   int32
+	Name string
+}
+
+func writeDiffStrings(t *testing.T, name string, names []string, labels []diffLabel) *File {
+	f, err := CreateFile(name, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	s, err := f.CreateDatasetFromValue("names", names, nil)
+	if err != nil {
+		t.Fatalf("CreateDatasetFromValue failed: %s", err)
+	}
+	s.Close()
+	s, err = f.CreateDatasetFromValue("labels", labels, nil)
+	if err != nil {
+		t.Fatalf("CreateDatasetFromValue failed: %s", err)
+	}
+	s.Close()
+	return f
+}
+
+func TestDiffVarLenStrings(t *testing.T) {
+	const nameA, nameB = "ex_diff_a.h5", "ex_diff_b.h5"
+	names := []string{"alpha", "beta", "gamma"}
+	labels := []diffLabel{{1, "one"}, {2, "two"}}
+
+	a := writeDiffStrings(t, nameA, names, labels)
+	defer os.Remove(nameA)
+	defer a.Close()
+	b := writeDiffStrings(t, nameB, append([]string{}, names...), append([]diffLabel{}, labels...))
+	defer os.Remove(nameB)
+	defer b.Close()
+
+	r, err := Diff(a, b, nil)
+	if err != nil {
+		t.Fatalf("Diff failed: %s", err)
+	}
+	if !r.Equal() || len(r.NotCompared) != 0 {
+		t.Errorf("identical files differ:\n%s\nnot compared: %v", r, r.NotCompared)
+	}
+	b.Close()
+
+	c := writeDiffStrings(t, nameB, []string{"alpha", "BETA", "gamma"}, []diffLabel{{1, "one"}, {2, "deux"}})
+	defer c.Close()
+	r, err = Diff(a, c, nil)
+	if err != nil {
+		t.Fatalf("Diff failed: %s", err)
+	}
+	if len(r.Differences) != 2 {
+		t.Fatalf("got differences:\n%s", r)
+	}
+	for _, d := range r.Differences {
+		if d.Kind != DiffData || d.Count != 1 {
+			t.Errorf("got difference %v, want one differing element", d)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// #include <string.h>
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"unsafe"
+)
+
+// DumpOptions controls the output of Dump.
+type DumpOptions struct {
+	// JSON writes a JSON document instead of the h5dump-like text.
+	JSON bool
+	// Data includes the values of datasets and attributes.
+	Data bool
+	// MaxElements, if not 0, limits the number of values shown for each
+	// dataset and attribute. Only the shown values of a dataset are read.
+	MaxElements int
+}
+
+// dumpNode is the description of an object or attribute written by Dump.
+type dumpNode struct {
+	Name       string        `json:"name"`
+	Kind       string        `json:"kind"`
+	Type       string        `json:"type,omitempty"`
+	Space      string        `json:"space,omitempty"`
+	Shape      []uint        `json:"shape,omitempty"`
+	Attributes []*dumpNode   `json:"attributes,omitempty"`
+	Data       []interface{} `json:"data,omitempty"`
+	Truncated  bool          `json:"truncated,omitempty"`
+	Children   []*dumpNode   `json:"children,omitempty"`
+}
+
+// Dump writes a description of the hierarchy of the file, with the
+// datatypes, shapes and attributes of its objects, like the h5dump tool.
+// A nil opts writes text without data.
+func (f *File) Dump(w io.Writer, opts *DumpOptions) error {
+	return dump(f.id, "/", w, opts)
+}
+
+// Dump writes a description of the hierarchy of the group, with the
+// datatypes, shapes and attributes of its objects, like the h5dump tool.
+// A nil opts writes text without data.
+func (g *Group) Dump(w io.Writer, opts *DumpOptions) error {
+	return dump(g.id, g.Name(), w, opts)
+}
+
+func dump(id C.hid_t, name string, w io.Writer, opts *DumpOptions) error {
+	if opts == nil {
+		opts = &DumpOptions{}
+	}
+	root, err := newDumpTree(id, name, opts)
+	if err != nil {
+		return err
+	}
+	if opts.JSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(root)
+	}
+	d := &textDumper{w: w}
+	d.node(root, 0)
+	return d.err
+}
+
+func newDumpTree(loc C.hid_t, name string, opts *DumpOptions) (*dumpNode, error) {
+	root := &dumpNode{Name: name, Kind: "group"}
+	if err := root.addAttributes(loc, opts); err != nil {
+		return nil, err
+	}
+
+	nodes := map[string]*dumpNode{"": root}
+	err := walkObjects(loc, func(name string, info *ObjectInfo) error {
+		id, err := openObject(loc, name)
+		if err != nil {
+			return err
+		}
+		defer C.H5Oclose(id)
+
+		dir, base := path.Split(name)
+		n := &dumpNode{Name: base}
+		switch info.Type {
+		case O_TYPE_GROUP:
+			n.Kind = "group"
+		case O_TYPE_DATASET:
+			n.Kind = "dataset"
+			err = n.describeDataset(id, opts)
+		case O_TYPE_NAMED_DATATYPE:
+			n.Kind = "datatype"
+			n.Type, err = datatypeText(id)
+		default:
+			n.Kind = info.Type.String()
+		}
+		if err != nil {
+			return err
+		}
+		if err := n.addAttributes(id, opts); err != nil {
+			return err
+		}
+
+		parent := nodes[strings.TrimSuffix(dir, "/")]
+		parent.Children = append(parent.Children, n)
+		nodes[name] = n
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+func datatypeText(id C.hid_t) (string, error) {
+	t := Datatype{id: id}
+	return t.Text()
+}
+
+// describeSpace sets the space and shape of the node from the dataspace id,
+// and returns its number of elements.
+func (n *dumpNode) describeSpace(id C.hid_t) (int, error) {
+	s, err := newDataspaceFromId(id)
+	if err != nil {
+		return 0, err
+	}
+	defer s.Close()
+
+	switch s.SimpleExtentType() {
+	case S_SCALAR:
+		n.Space = "scalar"
+	case S_SIMPLE:
+		n.Space = "simple"
+		n.Shape, _, err = s.SimpleExtentDims()
+		if err != nil {
+			return 0, err
+		}
+	case S_NULL:
+		n.Space = "null"
+	}
+	return s.SimpleExtentNPoints(), nil
+}
+
+func (n *dumpNode) describeDataset(id C.hid_t, opts *DumpOptions) error {
+	tid := C.H5Dget_type(id)
+	if err := h5err(C.herr_t(int(tid))); err != nil {
+		return err
+	}
+	defer C.H5Tclose(tid)
+	var err error
+	if n.Type, err = datatypeText(tid); err != nil {
+		return err
+	}
+	npoints, err := n.describeSpace(C.H5Dget_space(id))
+	if err != nil || !opts.Data {
+		return err
+	}
+	n.Data, n.Truncated, err = readDatasetPreview(id, tid, n.Shape, npoints, opts.MaxElements)
+	return err
+}
+
+func (n *dumpNode) addAttributes(id C.hid_t, opts *DumpOptions) error {
+	names, err := attributeNames(id)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		a, err := openAttribute(id, name)
+		if err != nil {
+			return err
+		}
+		err = n.addAttribute(a, opts)
+		a.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *dumpNode) addAttribute(a *Attribute, opts *DumpOptions) error {
+	attr := &dumpNode{Name: a.Name(), Kind: "attribute"}
+	tid := C.H5Aget_type(a.id)
+	if err := h5err(C.herr_t(int(tid))); err != nil {
+		return err
+	}
+	defer C.H5Tclose(tid)
+	var err error
+	if attr.Type, err = datatypeText(tid); err != nil {
+		return err
+	}
+	npoints, err := attr.describeSpace(C.H5Aget_space(a.id))
+	if err != nil {
+		return err
+	}
+	if opts.Data {
+		attr.Data, err = readValues(tid, npoints, func(mtype C.hid_t, buf unsafe.Pointer) error {
+			return h5err(C.H5Aread(a.id, mtype, buf))
+		})
+		if err != nil {
+			return err
+		}
+		if opts.MaxElements > 0 && len(attr.Data) > opts.MaxElements {
+			attr.Data = attr.Data[:opts.MaxElements]
+			attr.Truncated = true
+		}
+	}
+	n.Attributes = append(n.Attributes, attr)
+	return nil
+}
+
+// readDatasetPreview reads the first max values, in row-major order, of
+// the dataset id, of datatype tid and dimensions dims, or all its npoints
+// values if max is 0.
+func readDatasetPreview(id, tid C.hid_t, dims []uint, npoints, max int) ([]interface{}, bool, error) {
+	if max <= 0 || npoints <= max {
+		values, err := readValues(tid, npoints, func(mtype C.hid_t, buf unsafe.Pointer) error {
+			return h5err(C.H5Dread(id, mtype, C.H5S_ALL, C.H5S_ALL, C.H5P_DEFAULT, buf))
+		})
+		return values, false, err
+	}
+
+	// The first max values lie in the hyperslab that is one element wide
+	// along the leading dimensions, up to the first dimension j whose
+	// trailing dimensions hold at most max values, and that spans the whole
+	// of the dimensions after j.
+	rank := len(dims)
+	count := make([]C.hsize_t, rank)
+	start := make([]C.hsize_t, rank)
+	inner := 1
+	j := rank - 1
+	for ; j > 0; j-- {
+		if inner*int(dims[j]) > max {
+			break
+		}
+		inner *= int(dims[j])
+	}
+	for i := range count {
+		switch {
+		case i < j:
+			count[i] = 1
+		case i == j:
+			rows := (max + inner - 1) / inner
+			if rows > int(dims[j]) {
+				rows = int(dims[j])
+			}
+			count[i] = C.hsize_t(rows)
+		default:
+			count[i] = C.hsize_t(dims[i])
+		}
+	}
+	n := 1
+	for _, c := range count {
+		n *= int(c)
+	}
+
+	fspace := C.H5Dget_space(id)
+	if err := h5err(C.herr_t(int(fspace))); err != nil {
+		return nil, false, err
+	}
+	defer C.H5Sclose(fspace)
+	if err := h5err(C.H5Sselect_hyperslab(fspace, C.H5S_SELECT_SET, &start[0], nil, &count[0], nil)); err != nil {
+		return nil, false, err
+	}
+	c_n := C.hsize_t(n)
+	mspace := C.H5Screate_simple(1, &c_n, nil)
+	if err := h5err(C.herr_t(int(mspace))); err != nil {
+		return nil, false, err
+	}
+	defer C.H5Sclose(mspace)
+
+	values, err := readValues(tid, n, func(mtype C.hid_t, buf unsafe.Pointer) error {
+		return h5err(C.H5Dread(id, mtype, mspace, fspace, C.H5P_DEFAULT, buf))
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return values[:max], true, nil
+}
+
+// textDumper writes dump trees in a format close to that of h5dump.
+type textDumper struct {
+	w   io.Writer
+	err error
+}
+
+func (d *textDumper) printf(depth int, format string, args ...interface{}) {
+	if d.err != nil {
+		return
+	}
+	indent := strings.Repeat("   ", depth)
+	s := fmt.Sprintf(format, args...)
+	s = indent + strings.Replace(s, "\n", "\n"+indent, -1) + "\n"
+	_, d.err = io.WriteString(d.w, s)
+}
+
+func (d *textDumper) node(n *dumpNode, depth int) {
+	switch n.Kind {
+	case "datatype":
+		d.printf(depth, "DATATYPE %q %s", n.Name, n.Type)
+		if len(n.Attributes) == 0 {
+			return
+		}
+		d.printf(depth, "{")
+	default:
+		d.printf(depth, "%s %q {", strings.ToUpper(n.Kind), n.Name)
+	}
+	if n.Kind == "dataset" || n.Kind == "attribute" {
+		d.printf(depth+1, "DATATYPE %s", n.Type)
+		d.space(n, depth+1)
+		d.data(n, depth+1)
+	}
+	for _, a := range n.Attributes {
+		d.node(a, depth+1)
+	}
+	for _, c := range n.Children {
+		d.node(c, depth+1)
+	}
+	d.printf(depth, "}")
+}
+
+func (d *textDumper) space(n *dumpNode, depth int) {
+	switch n.Space {
+	case "scalar":
+		d.printf(depth, "DATASPACE SCALAR")
+	case "null":
+		d.printf(depth, "DATASPACE NULL")
+	default:
+		dims := make([]string, len(n.Shape))
+		for i, v := range n.Shape {
+			dims[i] = fmt.Sprint(v)
+		}
+		d.printf(depth, "DATASPACE SIMPLE { ( %s ) }", strings.Join(dims, ", "))
+	}
+}
+
+func (d *textDumper) data(n *dumpNode, depth int) {
+	if n.Data == nil {
+		return
+	}
+	values := make([]string, len(n.Data))
+	for i, v := range n.Data {
+		values[i] = formatValue(v)
+	}
+	if n.Truncated {
+		values = append(values, "...")
+	}
+	d.printf(depth, "DATA { %s }", strings.Join(values, ", "))
+}
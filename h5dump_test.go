@@ -0,0 +1,114 @@
+package hdf5
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	scalar, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatalf("CreateDataspace failed: %s", err)
+	}
+	defer scalar.Close()
+	attr, err := f.CreateAttribute("version", T_NATIVE_INT32, scalar)
+	if err != nil {
+		t.Fatalf("CreateAttribute failed: %s", err)
+	}
+	defer attr.Close()
+	if err := attr.Write(int32(2), T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+
+	g, err := f.CreateGroup("g")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	defer g.Close()
+	dspace, err := CreateSimpleDataspace([]uint{3, 4}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := g.CreateDataset("x", T_NATIVE_INT32, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	data := make([]int32, 12)
+	for i := range data {
+		data[i] = int32(i)
+	}
+	if err := dset.Write(data, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+
+	var buf bytes.Buffer
+	if err := f.Dump(&buf, &DumpOptions{Data: true, MaxElements: 5}); err != nil {
+		t.Fatalf("Dump failed: %s", err)
+	}
+	text := buf.String()
+	for _, want := range []string{
+		`GROUP "/" {`,
+		`ATTRIBUTE "version" {`,
+		`DATASPACE SCALAR`,
+		`DATA { 2 }`,
+		`GROUP "g" {`,
+		`DATASET "x" {`,
+		`DATATYPE H5T_STD_I32`,
+		`DATASPACE SIMPLE { ( 3, 4 ) }`,
+		`DATA { 0, 1, 2, 3, 4, ... }`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("dump does not contain %q:\n%s", want, text)
+		}
+	}
+
+	buf.Reset()
+	if err := f.Dump(&buf, &DumpOptions{JSON: true, Data: true}); err != nil {
+		t.Fatalf("Dump failed: %s", err)
+	}
+	var root struct {
+		Name     string
+		Children []struct {
+			Name     string
+			Children []struct {
+				Name  string
+				Kind  string
+				Shape []uint
+				Data  []int
+			}
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &root); err != nil {
+		t.Fatalf("invalid JSON: %s\n%s", err, buf.String())
+	}
+	if len(root.Children) != 1 || len(root.Children[0].Children) != 1 {
+		t.Fatalf("wrong hierarchy:\n%s", buf.String())
+	}
+	x := root.Children[0].Children[0]
+	if x.Name != "x" || x.Kind != "dataset" {
+		t.Errorf("got %s %q, want dataset %q", x.Kind, x.Name, "x")
+	}
+	if len(x.Shape) != 2 || x.Shape[0] != 3 || x.Shape[1] != 4 {
+		t.Errorf("shape = %v, want [3 4]", x.Shape)
+	}
+	if len(x.Data) != len(data) {
+		t.Fatalf("got %d values, want %d", len(x.Data), len(data))
+	}
+	for i := range data {
+		if x.Data[i] != int(data[i]) {
+			t.Errorf("data[%d] = %d, want %d", i, x.Data[i], data[i])
+		}
+	}
+}
@@ -1,8 +1,138 @@
 package hdf5
 
 // #include "hdf5.h"
+// #include <string.h>
+// enum {
+//   _GO_HDF5_ERR_NOT_FOUND = 1 << 0,
+//   _GO_HDF5_ERR_EXISTS = 1 << 1,
+//   _GO_HDF5_ERR_TYPE_MISMATCH = 1 << 2,
+//   _GO_HDF5_ERR_FILE_LOCKED = 1 << 3,
+//   _GO_HDF5_ERR_READ_ONLY = 1 << 4,
+// };
+// typedef struct {
+//   unsigned kinds;
+//   char desc[256];
+// } _go_hdf5_error_t;
+// static herr_t _go_hdf5_classify_walk(unsigned n, const H5E_error2_t *err_desc, void *client_data) {
+//   _go_hdf5_error_t *e = client_data;
+//   hid_t min = err_desc->min_num;
+//   const char *desc = err_desc->desc;
+//   if (min == H5E_NOTFOUND) {
+//     // a conversion path not found between datatypes is a mismatch
+//     e->kinds |= err_desc->maj_num == H5E_DATATYPE ? _GO_HDF5_ERR_TYPE_MISMATCH : _GO_HDF5_ERR_NOT_FOUND;
+//   }
+//   if (min == H5E_EXISTS || min == H5E_FILEEXISTS) e->kinds |= _GO_HDF5_ERR_EXISTS;
+//   if (min == H5E_BADTYPE || min == H5E_CANTCONVERT) e->kinds |= _GO_HDF5_ERR_TYPE_MISMATCH;
+// #if H5_VERSION_GE(1,10,0)
+//   if (min == H5E_CANTLOCKFILE) e->kinds |= _GO_HDF5_ERR_FILE_LOCKED;
+// #endif
+//   if (desc != NULL) {
+//     // the file drivers report the errno of a failed open(2)
+//     if (strstr(desc, "errno = 2,") != NULL) e->kinds |= _GO_HDF5_ERR_NOT_FOUND;
+//     if (strstr(desc, "errno = 17,") != NULL) e->kinds |= _GO_HDF5_ERR_EXISTS;
+//     if (strstr(desc, "no write intent") != NULL) e->kinds |= _GO_HDF5_ERR_READ_ONLY;
+//     if (n == 0) {
+//       strncpy(e->desc, desc, sizeof(e->desc) - 1);
+//     }
+//   }
+//   return 0;
+// }
+// static void _go_hdf5_classify_error(_go_hdf5_error_t *e) {
+//   H5Ewalk2(H5E_DEFAULT, H5E_WALK_UPWARD, _go_hdf5_classify_walk, e);
+// }
 import "C"
 
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"runtime"
+	"unsafe"
+)
+
+// Errors of the kinds of failures the library reports, which the errors
+// returned by the functions of this package match with errors.Is. They are
+// derived from the error stack of the library when a call fails.
+var (
+	ErrNotFound     = errors.New("hdf5: not found")             // missing object, attribute, link or file
+	ErrExists       = errors.New("hdf5: already exists")        // object, attribute, link or file to create present
+	ErrTypeMismatch = errors.New("hdf5: datatype mismatch")     // inappropriate or inconvertible datatype
+	ErrFileLocked   = errors.New("hdf5: file locked")           // file locked by another process, from HDF5 1.10
+	ErrReadOnly     = errors.New("hdf5: file opened read-only") // write to a file without write intent
+)
+
+// hdferror is the error of a failed library call, with the kinds of
+// failure and the most specific description of its error stack.
+type hdferror struct {
+	code  int
+	kinds C.uint
+	desc  string
+}
+
+func (h *hdferror) Error() string {
+	if h.desc != "" {
+		return fmt.Sprintf("**hdf5 error** code=%d: %s", h.code, h.desc)
+	}
+	return fmt.Sprintf("**hdf5 error** code=%d", h.code)
+}
+
+// Is reports whether the failure is of the kind of target, one of the
+// exported errors of this package, fs.ErrNotExist or fs.ErrExist.
+func (h *hdferror) Is(target error) bool {
+	var kind C.uint
+	switch target {
+	case ErrNotFound, fs.ErrNotExist:
+		kind = C._GO_HDF5_ERR_NOT_FOUND
+	case ErrExists, fs.ErrExist:
+		kind = C._GO_HDF5_ERR_EXISTS
+	case ErrTypeMismatch:
+		kind = C._GO_HDF5_ERR_TYPE_MISMATCH
+	case ErrFileLocked:
+		kind = C._GO_HDF5_ERR_FILE_LOCKED
+	case ErrReadOnly:
+		kind = C._GO_HDF5_ERR_READ_ONLY
+	}
+	return h.kinds&kind != 0
+}
+
+// stackError returns the error of a failed call with the return value
+// code, classified from the error stack of the library, which must not
+// have been cleared by another call since. The stack is per thread in
+// thread-safe builds, so callers whose errors are matched with errors.Is
+// make the call and the walk on one thread with lockedErr or quietly.
+func stackError(code int) error {
+	var e C._go_hdf5_error_t
+	C._go_hdf5_classify_error(&e)
+	return &hdferror{code: code, kinds: e.kinds, desc: C.GoString(&e.desc[0])}
+}
+
+// lockedErr calls fn with the goroutine locked to its thread and returns
+// the error of the result of fn, classified from the error stack of the
+// thread of the call.
+func lockedErr(fn func() C.herr_t) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	return h5err(fn())
+}
+
 func disableErrorPrinting() error {
 	return h5err(C.H5Eset_auto(C.H5E_DEFAULT, nil, nil))
 }
+
+// quietly calls fn with the printing of library errors turned off, for
+// calls whose failures are expected and handled. The goroutine is locked
+// to its thread, on which the printing of errors is set and the errors of
+// the calls of fn are classified.
+func quietly(fn func()) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	var c_func C.H5E_auto2_t
+	var c_data unsafe.Pointer
+	if C.H5Eget_auto2(C.H5E_DEFAULT, &c_func, &c_data) < 0 {
+		fn()
+		return
+	}
+	C.H5Eset_auto2(C.H5E_DEFAULT, nil, nil)
+	defer C.H5Eset_auto2(C.H5E_DEFAULT, c_func, c_data)
+	fn()
+}
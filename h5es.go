@@ -0,0 +1,263 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// #include <stdint.h>
+// #if H5_VERSION_GE(1,14,0)
+// static hid_t _go_hdf5_H5EScreate(void) { return H5EScreate(); }
+// static herr_t _go_hdf5_H5ESwait(hid_t es_id, uint64_t timeout, size_t *num_in_progress, unsigned *err_occurred) {
+//   hbool_t failed = 0;
+//   herr_t err = H5ESwait(es_id, timeout, num_in_progress, &failed);
+//   *err_occurred = failed;
+//   return err;
+// }
+// static herr_t _go_hdf5_H5ESclose(hid_t es_id) { return H5ESclose(es_id); }
+// static herr_t _go_hdf5_H5Dread_async(hid_t dset_id, hid_t mem_type_id, hid_t mem_space_id, hid_t file_space_id, hid_t dxpl_id, void *buf, hid_t es_id) {
+//   return H5Dread_async(dset_id, mem_type_id, mem_space_id, file_space_id, dxpl_id, buf, es_id);
+// }
+// static herr_t _go_hdf5_H5Dwrite_async(hid_t dset_id, hid_t mem_type_id, hid_t mem_space_id, hid_t file_space_id, hid_t dxpl_id, const void *buf, hid_t es_id) {
+//   return H5Dwrite_async(dset_id, mem_type_id, mem_space_id, file_space_id, dxpl_id, buf, es_id);
+// }
+// static herr_t _go_hdf5_H5Fclose_async(hid_t file_id, hid_t es_id) { return H5Fclose_async(file_id, es_id); }
+// #else
+// static hid_t _go_hdf5_H5EScreate(void) { return -1; }
+// static herr_t _go_hdf5_H5ESwait(hid_t es_id, uint64_t timeout, size_t *num_in_progress, unsigned *err_occurred) { return -1; }
+// static herr_t _go_hdf5_H5ESclose(hid_t es_id) { return -1; }
+// static herr_t _go_hdf5_H5Dread_async(hid_t dset_id, hid_t mem_type_id, hid_t mem_space_id, hid_t file_space_id, hid_t dxpl_id, void *buf, hid_t es_id) { return -1; }
+// static herr_t _go_hdf5_H5Dwrite_async(hid_t dset_id, hid_t mem_type_id, hid_t mem_space_id, hid_t file_space_id, hid_t dxpl_id, const void *buf, hid_t es_id) { return -1; }
+// static herr_t _go_hdf5_H5Fclose_async(hid_t file_id, hid_t es_id) { return -1; }
+// #endif
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// EventSet tracks asynchronous operations, which run in the background
+// with VOL connectors that support them and complete before returning
+// with the others. The buffers of the operations must not be used until
+// Wait reports that they have completed.
+type EventSet struct {
+	mu      sync.Mutex
+	id      C.hid_t
+	pending []asyncBuffer
+}
+
+// asyncBuffer is the C memory an operation transfers data through, since C
+// may not keep Go pointers, and the Go buffer it is copied to for reads.
+type asyncBuffer struct {
+	c     unsafe.Pointer
+	dst   unsafe.Pointer
+	size  int
+	dtype *Datatype // inferred memory datatype, closed with the buffer
+}
+
+// NewEventSet creates an event set. It needs HDF5 1.14.0.
+// hid_t H5EScreate(void)
+func NewEventSet() (*EventSet, error) {
+	if err := requireVersion("H5EScreate", 1, 14, 0); err != nil {
+		return nil, err
+	}
+	hid := C._go_hdf5_H5EScreate()
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return nil, err
+	}
+	es := &EventSet{id: hid}
+	runtime.SetFinalizer(es, (*EventSet).finalizer)
+	return es, nil
+}
+
+func (es *EventSet) finalizer() {
+	if err := es.Close(); err != nil {
+		panic(fmt.Sprintf("error closing event set: %s", err))
+	}
+}
+
+// Wait waits up to timeout, or without limit if timeout is negative, for
+// the operations of the event set to complete, and returns the number
+// still in progress. It returns an error if an operation failed.
+// herr_t H5ESwait(hid_t es_id, uint64_t timeout, size_t *num_in_progress, hbool_t *err_occurred)
+func (es *EventSet) Wait(timeout time.Duration) (int, error) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	c_timeout := C.uint64_t(math.MaxUint64)
+	if timeout >= 0 {
+		c_timeout = C.uint64_t(timeout.Nanoseconds())
+	}
+	var n C.size_t
+	var failed C.uint
+	if err := h5err(C._go_hdf5_H5ESwait(es.id, c_timeout, &n, &failed)); err != nil {
+		return int(n), err
+	}
+	if n == 0 {
+		es.release()
+	}
+	if failed != 0 {
+		return int(n), errors.New("hdf5: asynchronous operation failed")
+	}
+	return int(n), nil
+}
+
+// release copies the data of completed reads to their Go buffers and
+// frees the C buffers.
+func (es *EventSet) release() {
+	for _, b := range es.pending {
+		if b.dst != nil && b.size > 0 {
+			copy(unsafe.Slice((*byte)(b.dst), b.size), unsafe.Slice((*byte)(b.c), b.size))
+		}
+		b.free()
+	}
+	es.pending = nil
+}
+
+func (b *asyncBuffer) free() {
+	C.free(b.c)
+	if b.dtype != nil {
+		b.dtype.Close()
+	}
+}
+
+// Done waits in the background for the operations of the event set to
+// complete, and then sends the result of Wait. Calls into the library
+// from other goroutines meanwhile need a thread-safe library.
+func (es *EventSet) Done() <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		_, err := es.Wait(-1)
+		done <- err
+	}()
+	return done
+}
+
+// Close waits for the operations of the event set to complete and
+// releases it.
+// herr_t H5ESclose(hid_t es_id)
+func (es *EventSet) Close() error {
+	if es.id <= 0 {
+		return nil
+	}
+	_, werr := es.Wait(-1)
+	err := h5err(C._go_hdf5_H5ESclose(es.id))
+	es.id = 0
+	if err == nil {
+		err = werr
+	}
+	return err
+}
+
+// add allocates the C buffer of an operation on size bytes, copied to dst
+// once complete if not nil, of the datatype dtype, which is closed with the
+// buffer if owned.
+func (es *EventSet) add(size int, dst unsafe.Pointer, dtype *Datatype, owned bool) unsafe.Pointer {
+	c := C.malloc(C.size_t(size) + 1)
+	b := asyncBuffer{c: c, dst: dst, size: size}
+	if owned {
+		b.dtype = dtype
+	}
+	es.pending = append(es.pending, b)
+	return c
+}
+
+// drop frees the C buffer of the last operation added, which failed to
+// start and so is not pending.
+func (es *EventSet) drop() {
+	b := es.pending[len(es.pending)-1]
+	es.pending = es.pending[:len(es.pending)-1]
+	b.free()
+}
+
+// asyncArgs returns the memory of data, a slice or pointer that must hold
+// the whole dataset in elements without Go pointers, and its datatype:
+// dtype, or inferred from data if nil, in which case owned reports whether
+// it must be closed.
+func (s *Dataset) asyncArgs(data interface{}, dtype *Datatype) (ptr unsafe.Pointer, size int, dt *Datatype, owned bool, err error) {
+	if err := noGoPointers(data); err != nil {
+		return nil, 0, nil, false, err
+	}
+	ptr, size, err = bufferOf(data)
+	if err != nil {
+		return nil, 0, nil, false, err
+	}
+	dt = dtype
+	if dt == nil {
+		if dt, owned, err = inferType(data, s.Type); err != nil {
+			return nil, 0, nil, false, err
+		}
+	}
+	_, n, err := s.extent()
+	if err == nil {
+		if need := n * int(dt.Size()); size < need {
+			err = fmt.Errorf("hdf5: dataset needs %d bytes, %T has %d", need, data, size)
+		}
+	}
+	if err != nil {
+		if owned {
+			dt.Close()
+		}
+		return nil, 0, nil, false, err
+	}
+	return ptr, size, dt, owned, nil
+}
+
+// ReadAsync starts reading the dataset into data, a slice or pointer,
+// which is filled once es reports the read complete. data must hold the
+// whole dataset, and its elements may not hold Go pointers, such as
+// strings or slices. A nil dtype is inferred from data, as for Read.
+// herr_t H5Dread_async(hid_t dset_id, hid_t mem_type_id, hid_t mem_space_id, hid_t file_space_id, hid_t dxpl_id, void *buf, hid_t es_id)
+func (s *Dataset) ReadAsync(data interface{}, dtype *Datatype, es *EventSet) error {
+	ptr, size, dtype, owned, err := s.asyncArgs(data, dtype)
+	if err != nil {
+		return err
+	}
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	c := es.add(size, ptr, dtype, owned)
+	err = h5err(C._go_hdf5_H5Dread_async(s.id, dtype.id, C.H5S_ALL, C.H5S_ALL, C.H5P_DEFAULT, c, es.id))
+	if err != nil {
+		es.drop()
+	}
+	return err
+}
+
+// WriteAsync starts writing data, a slice or pointer, to the dataset. data
+// is copied, so it may be reused at once. As for ReadAsync, it must hold
+// the whole dataset in elements without Go pointers, and a nil dtype is
+// inferred from it.
+// herr_t H5Dwrite_async(hid_t dset_id, hid_t mem_type_id, hid_t mem_space_id, hid_t file_space_id, hid_t dxpl_id, const void *buf, hid_t es_id)
+func (s *Dataset) WriteAsync(data interface{}, dtype *Datatype, es *EventSet) error {
+	ptr, size, dtype, owned, err := s.asyncArgs(data, dtype)
+	if err != nil {
+		return err
+	}
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	c := es.add(size, nil, dtype, owned)
+	if size > 0 {
+		copy(unsafe.Slice((*byte)(c), size), unsafe.Slice((*byte)(ptr), size))
+	}
+	err = h5err(C._go_hdf5_H5Dwrite_async(s.id, dtype.id, C.H5S_ALL, C.H5S_ALL, C.H5P_DEFAULT, c, es.id))
+	if err != nil {
+		es.drop()
+	}
+	return err
+}
+
+// CloseAsync starts closing the file, after the operations of es on it.
+// herr_t H5Fclose_async(hid_t file_id, hid_t es_id)
+func (f *File) CloseAsync(es *EventSet) error {
+	if f.id <= 0 {
+		return nil
+	}
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	err := h5err(C._go_hdf5_H5Fclose_async(f.id, es.id))
+	f.id = 0
+	return err
+}
@@ -0,0 +1,83 @@
+package hdf5
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestEventSet(t *testing.T) {
+	if err := requireVersion("", 1, 14, 0); err != nil {
+		if _, err := NewEventSet(); err == nil {
+			t.Errorf("expected an error from libraries before 1.14.0")
+		}
+		t.Skip(err)
+	}
+	es, err := NewEventSet()
+	if err != nil {
+		t.Fatalf("NewEventSet failed: %s", err)
+	}
+	defer es.Close()
+
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	dspace, err := CreateSimpleDataspace([]uint{3}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("d", T_NATIVE_DOUBLE, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+
+	data := []float64{1.5, 2.5, 3.5}
+	if err := dset.WriteAsync(data, T_NATIVE_DOUBLE, es); err != nil {
+		t.Fatalf("WriteAsync failed: %s", err)
+	}
+	data[0] = 0
+	got := make([]float64, 3)
+	if err := dset.ReadAsync(got, T_NATIVE_DOUBLE, es); err != nil {
+		t.Fatalf("ReadAsync failed: %s", err)
+	}
+	if err := <-es.Done(); err != nil {
+		t.Fatalf("Done reported %s", err)
+	}
+	if !reflect.DeepEqual(got, []float64{1.5, 2.5, 3.5}) {
+		t.Errorf("ReadAsync read %v", got)
+	}
+
+	if err := dset.ReadAsync(got[:2], T_NATIVE_DOUBLE, es); err == nil {
+		t.Errorf("expected an error reading into a short buffer")
+	}
+	if err := dset.WriteAsync([]string{"a", "b", "c"}, T_GO_STRING, es); err == nil {
+		t.Errorf("expected an error writing Go strings")
+	}
+	inferred := make([]float64, 3)
+	if err := dset.ReadAsync(inferred, nil, es); err != nil {
+		t.Fatalf("ReadAsync with an inferred datatype failed: %s", err)
+	}
+	if _, err := es.Wait(-1); err != nil {
+		t.Fatalf("Wait failed: %s", err)
+	}
+	if !reflect.DeepEqual(inferred, got) {
+		t.Errorf("ReadAsync with an inferred datatype read %v", inferred)
+	}
+
+	dset.Close()
+	if err := dset.ReadAsync(got, T_NATIVE_DOUBLE, es); err == nil {
+		t.Errorf("expected an error reading a closed dataset")
+	}
+	if n := len(es.pending); n != 0 {
+		t.Errorf("%d buffers pending after a failed read", n)
+	}
+	if err := f.CloseAsync(es); err != nil {
+		t.Fatalf("CloseAsync failed: %s", err)
+	}
+	if n, err := es.Wait(-1); err != nil || n != 0 {
+		t.Errorf("Wait returned %d, %v", n, err)
+	}
+}
@@ -7,20 +7,25 @@ package hdf5
 import "C"
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"os"
 	"runtime"
 	"unsafe"
 )
 
 // File constants
 const (
-	F_ACC_RDONLY  int = 0x0000 // absence of rdwr => rd-only
-	F_ACC_RDWR    int = 0x0001 // open for read and write
-	F_ACC_TRUNC   int = 0x0002 // Truncate file, if it already exists, erasing all data previously stored in the file.
-	F_ACC_EXCL    int = 0x0004 // Fail if file already exists.
-	F_ACC_DEBUG   int = 0x0008 // print debug info
-	F_ACC_CREAT   int = 0x0010 // create non-existing files
-	F_ACC_DEFAULT int = 0xffff // value passed to set_elink_acc_flags to cause flags to be taken from the parent file
+	F_ACC_RDONLY     int = 0x0000 // absence of rdwr => rd-only
+	F_ACC_RDWR       int = 0x0001 // open for read and write
+	F_ACC_TRUNC      int = 0x0002 // Truncate file, if it already exists, erasing all data previously stored in the file.
+	F_ACC_EXCL       int = 0x0004 // Fail if file already exists.
+	F_ACC_DEBUG      int = 0x0008 // print debug info
+	F_ACC_CREAT      int = 0x0010 // create non-existing files
+	F_ACC_SWMR_WRITE int = 0x0020 // open for single-writer/multiple-reader writing (HDF5 1.10)
+	F_ACC_SWMR_READ  int = 0x0040 // open for single-writer/multiple-reader reading (HDF5 1.10)
+	F_ACC_DEFAULT    int = 0xffff // value passed to set_elink_acc_flags to cause flags to be taken from the parent file
 )
 
 // The difference between a single file and a set of mounted files.
@@ -46,34 +51,59 @@ func (f *File) finalizer() {
 func newFile(id C.hid_t) *File {
 	f := &File{id: id}
 	runtime.SetFinalizer(f, (*File).finalizer)
+	trackID(id)
 	return f
 }
 
 // Creates an HDF5 file.
 func CreateFile(name string, flags int) (*File, error) {
+	return CreateFileWith(name, flags, P_DEFAULT, P_DEFAULT)
+}
+
+// CreateFileWith creates an HDF5 file with the file creation properties
+// fcpl and the file access properties fapl.
+// hid_t H5Fcreate(const char *name, unsigned flags, hid_t fcpl_id, hid_t fapl_id)
+func CreateFileWith(name string, flags int, fcpl, fapl *PropList) (*File, error) {
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 
-	// FIXME: file props
-	hid := C.H5Fcreate(c_name, C.uint(flags), P_DEFAULT.id, P_DEFAULT.id)
-	err := h5err(C.herr_t(int(hid)))
+	var hid C.hid_t
+	err := lockedErr(func() C.herr_t {
+		hid = C.H5Fcreate(c_name, C.uint(flags), fcpl.id, fapl.id)
+		return C.herr_t(int(hid))
+	})
 	if err != nil {
 		return nil, err
 	}
+	if l := logger(hid); l != nil {
+		l.Debug("create file", "file", name, "flags", flags)
+	}
 	return newFile(hid), nil
 }
 
 // Opens an existing HDF5 file.
 func OpenFile(name string, flags int) (*File, error) {
+	return OpenFileWith(name, flags, P_DEFAULT)
+}
+
+// OpenFileWith opens an existing HDF5 file with the file access properties
+// fapl.
+// hid_t H5Fopen(const char *name, unsigned flags, hid_t fapl_id)
+func OpenFileWith(name string, flags int, fapl *PropList) (*File, error) {
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 
-	// FIXME: file props
-	hid := C.H5Fopen(c_name, C.uint(flags), P_DEFAULT.id)
-	err := h5err(C.herr_t(int(hid)))
+	var hid C.hid_t
+	err := lockedErr(func() C.herr_t {
+		hid = C.H5Fopen(c_name, C.uint(flags), fapl.id)
+		return C.herr_t(int(hid))
+	})
 	if err != nil {
 		return nil, err
 	}
+	if l := logger(hid); l != nil {
+		l.Debug("open file", "file", name, "flags", flags)
+	}
 	return newFile(hid), nil
 }
 
@@ -87,28 +117,145 @@ func (self *File) ReOpen() (*File, error) {
 	return newFile(hid), nil
 }
 
-// IsHDF5 Determines whether a file is in the HDF5 format.
+// IsHDF5 Determines whether a file is in the HDF5 format, from the
+// signature of its superblock. Unlike H5Fis_hdf5 it does not print an
+// error stack for files that are missing or not HDF5.
 func IsHDF5(name string) bool {
-	c_name := C.CString(name)
-	defer C.free(unsafe.Pointer(c_name))
+	f, err := os.Open(name)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	off, err := signatureOffset(f)
+	return err == nil && off >= 0
+}
+
+// signatureOffset returns the offset of the superblock of the file f, which
+// is also the size of its user block, or -1 if f is not an HDF5 file.
+func signatureOffset(f *os.File) (int64, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	buf := make([]byte, len(hdf5Signature))
+	for off := int64(0); off+int64(len(buf)) <= fi.Size(); off = nextSignatureOffset(off) {
+		if _, err := f.ReadAt(buf, off); err != nil {
+			return 0, err
+		}
+		if bytes.Equal(buf, hdf5Signature) {
+			return off, nil
+		}
+	}
+	return -1, nil
+}
 
-	o := int(C.H5Fis_hdf5(c_name))
-	if o > 0 {
-		return true
+// hdf5Signature is the format signature that starts the superblock, which
+// is at offset 0 or, after a user block, at 512 or a power of two above.
+var hdf5Signature = []byte("\x89HDF\r\n\x1a\n")
+
+// HasHDF5Signature returns whether prefix, the start of a file, holds an
+// HDF5 superblock signature at one of the offsets it covers. A file with a
+// user block needs a prefix longer than the user block.
+func HasHDF5Signature(prefix []byte) bool {
+	for off := int64(0); off+int64(len(hdf5Signature)) <= int64(len(prefix)); off = nextSignatureOffset(off) {
+		if bytes.HasPrefix(prefix[off:], hdf5Signature) {
+			return true
+		}
 	}
 	return false
 }
 
+func nextSignatureOffset(off int64) int64 {
+	if off == 0 {
+		return 512
+	}
+	return off * 2
+}
+
+// ReadUserblock returns the contents of the user block of the HDF5 file
+// name, which is empty if the file has none.
+func ReadUserblock(name string) ([]byte, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	off, err := signatureOffset(f)
+	if err != nil {
+		return nil, err
+	}
+	if off < 0 {
+		return nil, fmt.Errorf("%s is not an HDF5 file", name)
+	}
+	data := make([]byte, off)
+	if _, err := f.ReadAt(data, 0); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// WriteUserblock writes data to the start of the user block of the HDF5
+// file name, padding the rest of the block with zeros. The file must have
+// been created with a user block at least as large as data, see
+// SetUserblock, and must not be open.
+func WriteUserblock(name string, data []byte) error {
+	f, err := os.OpenFile(name, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	off, err := signatureOffset(f)
+	if err == nil && off < 0 {
+		err = fmt.Errorf("%s is not an HDF5 file", name)
+	}
+	if err == nil && int64(len(data)) > off {
+		err = fmt.Errorf("%d bytes do not fit in the %d byte user block", len(data), off)
+	}
+	if err == nil {
+		block := make([]byte, off)
+		copy(block, data)
+		_, err = f.WriteAt(block, 0)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
 // Terminates access to an HDF5 file.
 func (f *File) Close() error {
 	var err error = nil
 	if f.id > 0 {
+		warnOpenObjects(f.id)
+		if l := logger(f.id); l != nil {
+			l.Debug("close file", "file", f.FileName())
+		}
+		forgetFileLogger(f.id)
 		err = h5err(C.H5Fclose(f.id))
 		f.id = 0
 	}
 	return err
 }
 
+// CreatePropList returns a copy of the creation property list of the file.
+// hid_t H5Fget_create_plist(hid_t file_id)
+func (f *File) CreatePropList() (*PropList, error) {
+	hid := C.H5Fget_create_plist(f.id)
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return nil, err
+	}
+	return new_proplist(hid), nil
+}
+
+// AccessPropList returns a copy of the access property list of the file.
+// hid_t H5Fget_access_plist(hid_t file_id)
+func (f *File) AccessPropList() (*PropList, error) {
+	hid := C.H5Fget_access_plist(f.id)
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return nil, err
+	}
+	return new_proplist(hid), nil
+}
+
 // Flushes all buffers associated with a file to disk.
 // herr_t H5Fflush(hid_t object_id, H5F_scope_t scope )
 func (f *File) Flush(scope Scope) error {
@@ -143,6 +290,21 @@ func (f *File) CreateGroup(name string) (*Group, error) {
 	return createGroup(f.id, name, C.H5P_DEFAULT, C.H5P_DEFAULT, C.H5P_DEFAULT)
 }
 
+// CreateGroupWith creates a new empty group with the link creation
+// properties lcpl and the group creation properties gcpl.
+// hid_t H5Gcreate2(hid_t loc_id, const char *name, hid_t lcpl_id, hid_t gcpl_id, hid_t gapl_id)
+func (f *File) CreateGroupWith(name string, lcpl, gcpl *PropList) (*Group, error) {
+	return createGroup(f.id, name, int(lcpl.id), int(gcpl.id), C.H5P_DEFAULT)
+}
+
+// CreateAnonGroup creates a new empty group in the file of this location
+// without linking it into the hierarchy. It is freed when closed unless
+// it has been linked with Link.
+// hid_t H5Gcreate_anon(hid_t loc_id, hid_t gcpl_id, hid_t gapl_id)
+func (f *File) CreateAnonGroup(gcpl *PropList) (*Group, error) {
+	return createAnonGroup(f.id, gcpl)
+}
+
 func (f *File) Id() int {
 	return int(f.id)
 }
@@ -156,11 +318,27 @@ func (f *File) OpenGroup(name string) (*Group, error) {
 	return openGroup(f.id, name, P_DEFAULT.id)
 }
 
+// OpenGroupWith opens an existing group with the group access properties
+// gapl, e.g. to resolve external links on its path.
+// hid_t H5Gopen2(hid_t loc_id, const char *name, hid_t gapl_id)
+func (f *File) OpenGroupWith(name string, gapl *PropList) (*Group, error) {
+	return openGroup(f.id, name, gapl.id)
+}
+
 // Opens a named datatype.
 func (f *File) OpenDatatype(name string, tapl_id int) (*Datatype, error) {
 	return openDatatype(f.id, name, tapl_id)
 }
 
+// CommitDatatype links the transient datatype dtype into the file as name,
+// making it a named datatype that datasets and attributes can share, and
+// that can have attributes itself. Predefined datatypes must be copied
+// first.
+// herr_t H5Tcommit2(hid_t loc_id, const char *name, hid_t dtype_id, hid_t lcpl_id, hid_t tcpl_id, hid_t tapl_id)
+func (f *File) CommitDatatype(name string, dtype *Datatype) error {
+	return commitDatatype(f.id, name, dtype)
+}
+
 // NumObjects returns the number of objects in the root of the File.
 func (f *File) NumObjects() (uint, error) {
 	return numObjects(f.id)
@@ -173,16 +351,55 @@ func (f *File) ObjectNameByIndex(idx uint) (string, error) {
 	return objectNameByIndex(f.id, idx)
 }
 
+// LinkNames returns the names of the links in the root of the File, in
+// increasing order of index. Ordering by INDEX_CRT_ORDER requires the file
+// to track link creation order.
+func (f *File) LinkNames(index IndexType) ([]string, error) {
+	return linkNames(f.id, index)
+}
+
 // Creates a new dataset at this location.
 func (f *File) CreateDataset(name string, dtype *Datatype, dspace *Dataspace, dcpl *PropList) (*Dataset, error) {
 	return createDataset(f.id, name, dtype, dspace, dcpl)
 }
 
-// Opens an existing dataset.
+// CreateDatasetWith creates a new dataset at this location with the link
+// creation properties lcpl, e.g. to create the missing intermediate groups
+// of a path like "/a/b/c/data".
+// hid_t H5Dcreate2(hid_t loc_id, const char *name, hid_t dtype_id, hid_t space_id, hid_t lcpl_id, hid_t dcpl_id, hid_t dapl_id)
+func (f *File) CreateDatasetWith(name string, dtype *Datatype, dspace *Dataspace, lcpl, dcpl *PropList) (*Dataset, error) {
+	return createDatasetWith(f.id, name, dtype, dspace, lcpl, dcpl)
+}
+
+// CreateAnonDataset creates a new dataset in the file of this location
+// without linking it into the hierarchy, so that it can be written in full
+// before being linked with Link. It is freed when closed unless it has been
+// linked.
+// hid_t H5Dcreate_anon(hid_t loc_id, hid_t type_id, hid_t space_id, hid_t dcpl_id, hid_t dapl_id)
+func (f *File) CreateAnonDataset(dtype *Datatype, dspace *Dataspace, dcpl *PropList) (*Dataset, error) {
+	return createAnonDataset(f.id, dtype, dspace, dcpl)
+}
+
+// Link creates a hard link name at this location to the object obj, such
+// as a dataset created with CreateAnonDataset.
+// herr_t H5Olink(hid_t obj_id, hid_t new_loc_id, const char *new_name, hid_t lcpl_id, hid_t lapl_id)
+func (f *File) Link(obj Object, name string) error {
+	return linkObject(f.id, obj, name)
+}
+
+// Opens an existing dataset, given its name or its path from this
+// location, e.g. "/a/b/c/data".
 func (f *File) OpenDataset(name string) (*Dataset, error) {
 	return openDataset(f.id, name)
 }
 
+// OpenDatasetWith opens an existing dataset with the dataset access
+// properties dapl, e.g. to size its chunk cache.
+// hid_t H5Dopen2(hid_t loc_id, const char *name, hid_t dapl_id)
+func (f *File) OpenDatasetWith(name string, dapl *PropList) (*Dataset, error) {
+	return openDatasetWith(f.id, name, dapl)
+}
+
 // Creates a packet table to store fixed-length packets.
 // hid_t H5PTcreate_fl( hid_t loc_id, const char * dset_name, hid_t dtype_id, hsize_t chunk_size, int compression )
 func (f *File) CreateTable(name string, dtype *Datatype, chunkSize, compression int) (*Table, error) {
@@ -195,8 +412,264 @@ func (f *File) CreateTableFrom(name string, dtype interface{}, chunkSize, compre
 	return createTableFrom(f.id, name, dtype, chunkSize, compression)
 }
 
+// Creates a packet table to store variable-length packets of elem.
+func (f *File) CreateVarLenTable(name string, elem *Datatype, chunkSize, compression int) (*Table, error) {
+	return createVarLenTable(f.id, name, elem, chunkSize, compression)
+}
+
 // Opens an existing packet table.
 // hid_t H5PTopen( hid_t loc_id, const char *dset_name )
 func (f *File) OpenTable(name string) (*Table, error) {
 	return openTable(f.id, name)
 }
+
+// ReadTableFields reads nrecords records of the named table, starting at
+// start, into data, which must be a slice of structs. Only the table fields
+// whose names match the struct's fields are read.
+func (f *File) ReadTableFields(name string, start, nrecords int, data interface{}) error {
+	return readTableFields(f.id, name, start, nrecords, data)
+}
+
+// ReadTableFieldsByIndex reads the table fields at the given indices of
+// nrecords records of the named table, starting at start, into the fields
+// of data's struct type, in order.
+func (f *File) ReadTableFieldsByIndex(name string, fields []int, start, nrecords int, data interface{}) error {
+	return readTableFieldsByIndex(f.id, name, fields, start, nrecords, data)
+}
+
+// InsertTableRecords inserts the records in data, a slice of structs holding
+// every field of the named table, before record start.
+func (f *File) InsertTableRecords(name string, start int, data interface{}) error {
+	return insertTableRecords(f.id, name, start, data)
+}
+
+// DeleteTableRecords removes nrecords records of the named table, starting
+// at record start.
+func (f *File) DeleteTableRecords(name string, start, nrecords int) error {
+	return deleteTableRecords(f.id, name, start, nrecords)
+}
+
+// LinkExists returns whether a link with the given name exists in this
+// group. Every intermediate group of name must exist.
+// htri_t H5Lexists(hid_t loc_id, const char *name, hid_t lapl_id)
+func (f *File) LinkExists(name string) (bool, error) {
+	return linkExists(f.id, name)
+}
+
+// CreateSoftLink creates a soft link name in this file to the path target,
+// which is resolved when the link is followed and need not exist.
+// herr_t H5Lcreate_soft(const char *link_target, hid_t link_loc_id, const char *link_name, hid_t lcpl_id, hid_t lapl_id)
+func (f *File) CreateSoftLink(target, name string) error {
+	return createSoftLink(f.id, target, name)
+}
+
+// CreateExternalLink creates a link name in this file to the object at
+// the path obj of the file named file.
+// herr_t H5Lcreate_external(const char *file_name, const char *obj_name, hid_t link_loc_id, const char *link_name, hid_t lcpl_id, hid_t lapl_id)
+func (f *File) CreateExternalLink(file, obj, name string) error {
+	return createExternalLink(f.id, file, obj, name)
+}
+
+// LinkInfo returns the metadata of the link name in this file, such as
+// whether it is a hard, soft or external link.
+// herr_t H5Lget_info(hid_t link_loc_id, const char *link_name, H5L_info_t *link_buff, hid_t lapl_id)
+func (f *File) LinkInfo(name string) (*LinkInfo, error) {
+	return linkInfo(f.id, name)
+}
+
+// SoftLinkTarget returns the path the soft link name of this file points to.
+// herr_t H5Lget_val(hid_t link_loc_id, const char *link_name, void *linkval_buff, size_t size, hid_t lapl_id)
+func (f *File) SoftLinkTarget(name string) (string, error) {
+	return softLinkTarget(f.id, name)
+}
+
+// ExternalLinkTarget returns the file name and object path the external
+// link name of this file points to.
+// herr_t H5Lunpack_elink_val(const void *ext_linkval, size_t link_size, unsigned *flags, const char **filename, const char **obj_path)
+func (f *File) ExternalLinkTarget(name string) (file, obj string, err error) {
+	return externalLinkTarget(f.id, name)
+}
+
+// Delete removes the link name from this file. The object it points to
+// is freed once no other link refers to it and it is closed, though the
+// space it used in the file is only reclaimed by repacking.
+// herr_t H5Ldelete(hid_t loc_id, const char *name, hid_t lapl_id)
+func (f *File) Delete(name string) error {
+	return deleteLink(f.id, name)
+}
+
+// Move renames the link src of this file to dst, which may be in another
+// group of the file. Open handles to the object stay valid.
+// herr_t H5Lmove(hid_t src_loc, const char *src_name, hid_t dst_loc, const char *dst_name, hid_t lcpl_id, hid_t lapl_id)
+func (f *File) Move(src, dst string) error {
+	return moveLink(f.id, src, dst)
+}
+
+// ObjectExists reports whether the path resolves to an object from this file,
+// telling OBJECT_MISSING paths, on which a link does not exist, from
+// OBJECT_DANGLING ones, whose last link exists but resolves to no object,
+// such as a soft link whose target was deleted. Missing links are not
+// errors and print no library errors.
+// htri_t H5Oexists_by_name(hid_t loc_id, const char *name, hid_t lapl_id)
+func (f *File) ObjectExists(path string) (ObjectState, error) {
+	return objectExists(f.id, path)
+}
+
+// PathExists returns whether every link along path exists, without
+// reporting errors for missing intermediate groups. If followLinks is true
+// the final link must also resolve to an object, so dangling soft and
+// external links are reported as missing.
+// htri_t H5LTpath_valid(hid_t loc_id, const char *path, hbool_t check_object_valid)
+func (f *File) PathExists(path string, followLinks bool) (bool, error) {
+	return pathExists(f.id, path, followLinks)
+}
+
+// Info returns the object header metadata of the file's root group.
+// herr_t H5Oget_info(hid_t object_id, H5O_info_t *object_info)
+func (f *File) Info() (*ObjectInfo, error) {
+	return objectInfo(f.id)
+}
+
+// InfoByName returns the object header metadata of the named object,
+// without opening it.
+// herr_t H5Oget_info_by_name(hid_t loc_id, const char *object_name, H5O_info_t *object_info, hid_t lapl_id)
+func (f *File) InfoByName(name string) (*ObjectInfo, error) {
+	return objectInfoByName(f.id, name)
+}
+
+// GroupInfo returns the metadata of the links of the file's root group,
+// such as how many there are and how they are stored.
+// herr_t H5Gget_info(hid_t group_id, H5G_info_t *group_info)
+func (f *File) GroupInfo() (*GroupInfo, error) {
+	return groupInfo(f.id)
+}
+
+// GroupInfoByName returns the metadata of the links of the named group,
+// without opening it.
+// herr_t H5Gget_info_by_name(hid_t loc_id, const char *group_name, H5G_info_t *group_info, hid_t lapl_id)
+func (f *File) GroupInfoByName(name string) (*GroupInfo, error) {
+	return groupInfoByName(f.id, name)
+}
+
+// SetComment sets the comment of the file's root group. An empty comment removes it.
+// herr_t H5Oset_comment(hid_t object_id, const char *comment)
+func (f *File) SetComment(comment string) error {
+	return setComment(f.id, comment)
+}
+
+// Comment returns the comment of the file's root group, or "" if it has none.
+// ssize_t H5Oget_comment(hid_t object_id, char *comment, size_t bufsize)
+func (f *File) Comment() (string, error) {
+	return getComment(f.id)
+}
+
+// SetCommentByName sets the comment of the named object, without opening it.
+// herr_t H5Oset_comment_by_name(hid_t loc_id, const char *name, const char *comment, hid_t lapl_id)
+func (f *File) SetCommentByName(name, comment string) error {
+	return setCommentByName(f.id, name, comment)
+}
+
+// CommentByName returns the comment of the named object, or "" if it has
+// none.
+// ssize_t H5Oget_comment_by_name(hid_t loc_id, const char *name, char *comment, size_t bufsize, hid_t lapl_id)
+func (f *File) CommentByName(name string) (string, error) {
+	return getCommentByName(f.id, name)
+}
+
+// Creates an attribute attached to the file's root group.
+// hid_t H5Acreate2(hid_t loc_id, const char *attr_name, hid_t type_id, hid_t space_id, hid_t acpl_id, hid_t aapl_id)
+func (f *File) CreateAttribute(name string, dtype *Datatype, dspace *Dataspace) (*Attribute, error) {
+	return createAttribute(f.id, name, dtype, dspace, P_DEFAULT)
+}
+
+// CreateAttributeWith creates an attribute attached to the file's root
+// group with the attribute creation properties acpl, e.g. for a UTF-8 name.
+func (f *File) CreateAttributeWith(name string, dtype *Datatype, dspace *Dataspace, acpl *PropList) (*Attribute, error) {
+	return createAttribute(f.id, name, dtype, dspace, acpl)
+}
+
+// AttributeNames returns the names of the attributes of the file's root
+// group, in increasing order of index.
+func (f *File) AttributeNames(index IndexType) ([]string, error) {
+	return attributeNamesByOrder(f.id, index)
+}
+
+// Opens an attribute attached to the file's root group.
+// hid_t H5Aopen(hid_t obj_id, const char *attr_name, hid_t aapl_id)
+func (f *File) OpenAttribute(name string) (*Attribute, error) {
+	return openAttribute(f.id, name)
+}
+
+// AttributeExists reports whether the file's root group has an attribute called name.
+// htri_t H5Aexists(hid_t obj_id, const char *attr_name)
+func (f *File) AttributeExists(name string) (bool, error) {
+	return attributeExists(f.id, name)
+}
+
+// RenameAttribute renames the attribute oldName of the file's root group to newName.
+// herr_t H5Arename(hid_t loc_id, const char *old_attr_name, const char *new_attr_name)
+func (f *File) RenameAttribute(oldName, newName string) error {
+	return renameAttribute(f.id, oldName, newName)
+}
+
+// DeleteAttribute removes the attribute name from the file's root group.
+// herr_t H5Adelete(hid_t loc_id, const char *attr_name)
+func (f *File) DeleteAttribute(name string) error {
+	return deleteAttribute(f.id, name)
+}
+
+// Returns the number of attributes attached to the file's root group.
+func (f *File) NumAttributes() (uint, error) {
+	return numAttributes(f.id)
+}
+
+// Returns the name of the attribute at idx, in name order.
+// ssize_t H5Aget_name_by_idx(hid_t loc_id, const char *obj_name, H5_index_t idx_type, H5_iter_order_t order, hsize_t n, char *name, size_t size, hid_t lapl_id)
+func (f *File) AttributeNameByIndex(idx uint) (string, error) {
+	return attributeNameByIndex(f.id, idx)
+}
+
+// CreateAttributeByName creates the attribute name of the object objName,
+// a path relative to the root group that may go through soft links.
+// hid_t H5Acreate_by_name(hid_t loc_id, const char *obj_name, const char *attr_name, hid_t type_id, hid_t space_id, hid_t acpl_id, hid_t aapl_id, hid_t lapl_id)
+func (f *File) CreateAttributeByName(objName, name string, dtype *Datatype, dspace *Dataspace) (*Attribute, error) {
+	return createAttributeByName(f.id, objName, name, dtype, dspace, P_DEFAULT)
+}
+
+// OpenAttributeByName opens the attribute name of the object objName,
+// relative to the root group.
+// hid_t H5Aopen_by_name(hid_t loc_id, const char *obj_name, const char *attr_name, hid_t aapl_id, hid_t lapl_id)
+func (f *File) OpenAttributeByName(objName, name string) (*Attribute, error) {
+	return openAttributeByName(f.id, objName, name)
+}
+
+// AttributeExistsByName reports whether the object objName, relative to
+// the root group, has an attribute called name.
+// htri_t H5Aexists_by_name(hid_t loc_id, const char *obj_name, const char *attr_name, hid_t lapl_id)
+func (f *File) AttributeExistsByName(objName, name string) (bool, error) {
+	return attributeExistsByName(f.id, objName, name)
+}
+
+// RenameAttributeByName renames the attribute oldName of the object
+// objName, relative to the root group, to newName.
+// herr_t H5Arename_by_name(hid_t loc_id, const char *obj_name, const char *old_attr_name, const char *new_attr_name, hid_t lapl_id)
+func (f *File) RenameAttributeByName(objName, oldName, newName string) error {
+	return renameAttributeByName(f.id, objName, oldName, newName)
+}
+
+// DeleteAttributeByName removes the attribute name from the object
+// objName, relative to the root group.
+// herr_t H5Adelete_by_name(hid_t loc_id, const char *obj_name, const char *attr_name, hid_t lapl_id)
+func (f *File) DeleteAttributeByName(objName, name string) error {
+	return deleteAttributeByName(f.id, objName, name)
+}
+
+// CreateTableFromCSV creates a packet table of the struct type of schema,
+// whose fields must be numbers or strings, and loads the CSV records read
+// from r into it. String fields become variable-length string members,
+// holding the cells as they are. The header line of r names the fields of
+// each column, with nested structs and arrays flattened like
+// Dataset.WriteCSV does.
+func (f *File) CreateTableFromCSV(name string, r io.Reader, schema interface{}, chunkSize, compression int) (*Table, error) {
+	return createTableFromCSV(f.id, name, r, schema, chunkSize, compression)
+}
@@ -0,0 +1,166 @@
+package hdf5
+
+// #include "hdf5.h"
+// #if H5_VERSION_GE(1,10,0)
+// static herr_t _go_hdf5_H5Fstart_mdc_logging(hid_t file_id) {
+//   return H5Fstart_mdc_logging(file_id);
+// }
+// static herr_t _go_hdf5_H5Fstop_mdc_logging(hid_t file_id) {
+//   return H5Fstop_mdc_logging(file_id);
+// }
+// static herr_t _go_hdf5_H5Fget_mdc_logging_status(hid_t file_id, unsigned *is_enabled, unsigned *is_currently_logging) {
+//   hbool_t enabled, logging;
+//   herr_t err = H5Fget_mdc_logging_status(file_id, &enabled, &logging);
+//   *is_enabled = enabled;
+//   *is_currently_logging = logging;
+//   return err;
+// }
+// #else
+// static herr_t _go_hdf5_H5Fstart_mdc_logging(hid_t file_id) { return -1; }
+// static herr_t _go_hdf5_H5Fstop_mdc_logging(hid_t file_id) { return -1; }
+// static herr_t _go_hdf5_H5Fget_mdc_logging_status(hid_t file_id, unsigned *is_enabled, unsigned *is_currently_logging) { return -1; }
+// #endif
+import "C"
+
+// --- Metadata cache ---
+
+// MDCConfig holds the commonly tuned fields of the configuration of the
+// metadata cache. The other fields keep the values of the configuration
+// they are applied to.
+type MDCConfig struct {
+	EvictionsEnabled bool    // whether entries may be evicted, which must be true to resize
+	SetInitialSize   bool    // whether InitialSize is applied
+	InitialSize      uint64  // initial size of the cache in bytes
+	MinCleanFraction float64 // fraction of the cache kept clean
+	MaxSize          uint64  // maximum size of the cache in bytes
+	MinSize          uint64  // minimum size of the cache in bytes
+	EpochLength      int64   // number of accesses between resizes
+}
+
+// MDCSize is the current size of the metadata cache of a file.
+type MDCSize struct {
+	MaxSize      uint64 // maximum size in bytes
+	MinCleanSize uint64 // minimum clean size in bytes
+	CurSize      uint64 // current size in bytes
+	CurEntries   int    // number of entries
+}
+
+func newMDCConfig(c *C.H5AC_cache_config_t) *MDCConfig {
+	return &MDCConfig{
+		EvictionsEnabled: c.evictions_enabled != 0,
+		SetInitialSize:   c.set_initial_size != 0,
+		InitialSize:      uint64(c.initial_size),
+		MinCleanFraction: float64(c.min_clean_fraction),
+		MaxSize:          uint64(c.max_size),
+		MinSize:          uint64(c.min_size),
+		EpochLength:      int64(c.epoch_length),
+	}
+}
+
+func (cfg *MDCConfig) apply(c *C.H5AC_cache_config_t) {
+	c.evictions_enabled = cbool(cfg.EvictionsEnabled)
+	c.set_initial_size = cbool(cfg.SetInitialSize)
+	c.initial_size = C.size_t(cfg.InitialSize)
+	c.min_clean_fraction = C.double(cfg.MinCleanFraction)
+	c.max_size = C.size_t(cfg.MaxSize)
+	c.min_size = C.size_t(cfg.MinSize)
+	c.epoch_length = C.long(cfg.EpochLength)
+}
+
+func cbool(b bool) C.hbool_t {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// MDCConfig returns the configuration of the metadata cache of the file.
+// herr_t H5Fget_mdc_config(hid_t file_id, H5AC_cache_config_t *config_ptr)
+func (f *File) MDCConfig() (*MDCConfig, error) {
+	var c C.H5AC_cache_config_t
+	c.version = C.H5AC__CURR_CACHE_CONFIG_VERSION
+	if err := h5err(C.H5Fget_mdc_config(f.id, &c)); err != nil {
+		return nil, err
+	}
+	return newMDCConfig(&c), nil
+}
+
+// SetMDCConfig changes the configuration of the metadata cache of the open
+// file.
+// herr_t H5Fset_mdc_config(hid_t file_id, H5AC_cache_config_t *config_ptr)
+func (f *File) SetMDCConfig(cfg *MDCConfig) error {
+	var c C.H5AC_cache_config_t
+	c.version = C.H5AC__CURR_CACHE_CONFIG_VERSION
+	if err := h5err(C.H5Fget_mdc_config(f.id, &c)); err != nil {
+		return err
+	}
+	cfg.apply(&c)
+	return h5err(C.H5Fset_mdc_config(f.id, &c))
+}
+
+// MDCHitRate returns the hit rate of the metadata cache of the file since
+// the statistics were last reset.
+// herr_t H5Fget_mdc_hit_rate(hid_t file_id, double *hit_rate_ptr)
+func (f *File) MDCHitRate() (float64, error) {
+	var rate C.double
+	err := h5err(C.H5Fget_mdc_hit_rate(f.id, &rate))
+	return float64(rate), err
+}
+
+// ResetMDCHitRateStats resets the hit rate statistics of the metadata
+// cache of the file.
+// herr_t H5Freset_mdc_hit_rate_stats(hid_t file_id)
+func (f *File) ResetMDCHitRateStats() error {
+	return h5err(C.H5Freset_mdc_hit_rate_stats(f.id))
+}
+
+// MDCSize returns the current size of the metadata cache of the file.
+// herr_t H5Fget_mdc_size(hid_t file_id, size_t *max_size_ptr, size_t *min_clean_size_ptr, size_t *cur_size_ptr, int *cur_num_entries_ptr)
+func (f *File) MDCSize() (*MDCSize, error) {
+	var max_size, min_clean_size, cur_size C.size_t
+	var cur_num_entries C.int
+	if err := h5err(C.H5Fget_mdc_size(f.id, &max_size, &min_clean_size, &cur_size, &cur_num_entries)); err != nil {
+		return nil, err
+	}
+	return &MDCSize{
+		MaxSize:      uint64(max_size),
+		MinCleanSize: uint64(min_clean_size),
+		CurSize:      uint64(cur_size),
+		CurEntries:   int(cur_num_entries),
+	}, nil
+}
+
+// StartMDCLogging starts logging the accesses to the metadata cache of the
+// file to the log file set with SetMDCLogOptions on the file access
+// property list it was opened with, which must have enabled logging. It
+// needs HDF5 1.10.0.
+// herr_t H5Fstart_mdc_logging(hid_t file_id)
+func (f *File) StartMDCLogging() error {
+	if err := requireVersion("H5Fstart_mdc_logging", 1, 10, 0); err != nil {
+		return err
+	}
+	return h5err(C._go_hdf5_H5Fstart_mdc_logging(f.id))
+}
+
+// StopMDCLogging stops logging the accesses to the metadata cache of the
+// file. It needs HDF5 1.10.0.
+// herr_t H5Fstop_mdc_logging(hid_t file_id)
+func (f *File) StopMDCLogging() error {
+	if err := requireVersion("H5Fstop_mdc_logging", 1, 10, 0); err != nil {
+		return err
+	}
+	return h5err(C._go_hdf5_H5Fstop_mdc_logging(f.id))
+}
+
+// MDCLoggingStatus returns whether logging of the metadata cache of the
+// file is enabled by its file access property list, and whether it is
+// currently logging. It needs HDF5 1.10.0.
+// herr_t H5Fget_mdc_logging_status(hid_t file_id, hbool_t *is_enabled, hbool_t *is_currently_logging)
+func (f *File) MDCLoggingStatus() (enabled, logging bool, err error) {
+	if err := requireVersion("H5Fget_mdc_logging_status", 1, 10, 0); err != nil {
+		return false, false, err
+	}
+	var c_enabled, c_logging C.uint
+	err = h5err(C._go_hdf5_H5Fget_mdc_logging_status(f.id, &c_enabled, &c_logging))
+	return c_enabled != 0, c_logging != 0, err
+}
@@ -0,0 +1,113 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FileCloseMode is what File.CloseWith does about the objects of a file
+// that are still open, which keep the file open after its identifier is
+// closed.
+type FileCloseMode int
+
+const (
+	F_CLOSE_DEFAULT  FileCloseMode = iota // leave them open, as Close does
+	F_CLOSE_REPORT                        // leave them open, and return an *OpenObjectsError
+	F_CLOSE_CHILDREN                      // close them first, so the file closes
+)
+
+// childObjects are the kinds of objects in a file that keep it open.
+const childObjects = C.H5F_OBJ_DATASET | C.H5F_OBJ_GROUP | C.H5F_OBJ_DATATYPE | C.H5F_OBJ_ATTR
+
+// OpenObjectsError reports the objects still open as the file File was
+// closed, which keep it open until they are closed.
+type OpenObjectsError struct {
+	File    string
+	Objects []OpenObject
+}
+
+func (e *OpenObjectsError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "hdf5: file %q kept open by %d objects", e.File, len(e.Objects))
+	for _, o := range e.Objects {
+		b.WriteString("\n\t")
+		b.WriteString(o.String())
+	}
+	return b.String()
+}
+
+// OpenObjects returns the datasets, groups, named datatypes and attributes
+// of the file that are open, through any identifier of the file and
+// whether opened by this package or not. Their Stack is set only for the
+// objects recorded while TrackHandles is on.
+// ssize_t H5Fget_obj_count(hid_t file_id, unsigned int types)
+// ssize_t H5Fget_obj_ids(hid_t file_id, unsigned int types, size_t max_objs, hid_t *obj_id_list)
+func (f *File) OpenObjects() ([]OpenObject, error) {
+	ids, err := fileObjectIDs(f.id, childObjects)
+	if err != nil {
+		return nil, err
+	}
+	handles.Lock()
+	defer handles.Unlock()
+	objs := make([]OpenObject, len(ids))
+	for i, id := range ids {
+		objs[i] = describeID(id, handles.stacks[id])
+	}
+	return objs, nil
+}
+
+// CloseWith closes the file as Close does, and deals with the objects
+// still open in it as mode says. Objects closed by F_CLOSE_CHILDREN,
+// including those opened by other code, are closed whatever their
+// reference count, and closing their Go values again returns an error.
+// herr_t H5Fclose(hid_t file_id)
+func (f *File) CloseWith(mode FileCloseMode) error {
+	if f.id <= 0 || mode == F_CLOSE_DEFAULT {
+		return f.Close()
+	}
+	name := f.FileName()
+	objs, err := f.OpenObjects()
+	if err != nil {
+		return err
+	}
+	if mode == F_CLOSE_CHILDREN {
+		for _, o := range objs {
+			closeID(C.hid_t(o.ID))
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if mode == F_CLOSE_REPORT && len(objs) > 0 {
+		return &OpenObjectsError{File: name, Objects: objs}
+	}
+	return nil
+}
+
+// closeID releases every reference to the identifier id, closing it.
+// int H5Idec_ref(hid_t obj_id)
+func closeID(id C.hid_t) {
+	for C.H5Iis_valid(id) > 0 {
+		if C.H5Idec_ref(id) < 0 {
+			return
+		}
+	}
+}
+
+// fileObjectIDs returns the identifiers of the objects of types open in
+// the file fid.
+func fileObjectIDs(fid C.hid_t, types C.uint) ([]C.hid_t, error) {
+	n := C.H5Fget_obj_count(fid, types)
+	if err := h5err(C.herr_t(n)); err != nil || n == 0 {
+		return nil, err
+	}
+	ids := make([]C.hid_t, n)
+	n = C.H5Fget_obj_ids(fid, types, C.size_t(n), &ids[0])
+	if err := h5err(C.herr_t(n)); err != nil {
+		return nil, err
+	}
+	return ids[:n], nil
+}
@@ -0,0 +1,152 @@
+package hdf5
+
+// A FileOption configures how Open opens or Create creates a file. Options
+// are applied in order, so a later option overrides an earlier one.
+type FileOption func(*fileOptions)
+
+type fileOptions struct {
+	flags int
+	fcpl  *PropList
+	fapl  *PropList
+	setup []func(fapl *PropList) error
+	retry *RetryPolicy
+}
+
+// WithReadOnly opens the file for reading only.
+func WithReadOnly() FileOption {
+	return func(o *fileOptions) { o.flags &^= F_ACC_RDWR }
+}
+
+// WithTruncate lets Create replace an existing file, erasing its contents,
+// instead of failing.
+func WithTruncate() FileOption {
+	return func(o *fileOptions) { o.flags = o.flags&^F_ACC_EXCL | F_ACC_TRUNC }
+}
+
+// WithSWMRRead opens the file for reading only while a single writer,
+// which opened it with WithSWMRWrite, may be appending to it. It needs
+// HDF5 1.10.
+func WithSWMRRead() FileOption {
+	return func(o *fileOptions) { o.flags = o.flags&^F_ACC_RDWR | F_ACC_SWMR_READ }
+}
+
+// WithSWMRWrite opens the file for writing by a single writer, while
+// readers may open it with WithSWMRRead. It selects the latest file format,
+// which SWMR needs. It needs HDF5 1.10.
+func WithSWMRWrite() FileOption {
+	return func(o *fileOptions) {
+		o.flags |= F_ACC_RDWR | F_ACC_SWMR_WRITE
+		WithLibverLatest()(o)
+	}
+}
+
+// WithCoreDriver holds the file in memory, as PropList.SetCore does.
+func WithCoreDriver(increment uint, backingStore bool) FileOption {
+	return withFAPLSetting(func(fapl *PropList) error { return fapl.SetCore(increment, backingStore) })
+}
+
+// WithLibverLatest writes objects with the latest file formats of the
+// linked library, which older libraries may be unable to read.
+func WithLibverLatest() FileOption {
+	return withFAPLSetting(func(fapl *PropList) error { return fapl.SetLibverBounds(F_LIBVER_LATEST, F_LIBVER_LATEST) })
+}
+
+// WithLargeAttributes writes objects with the 1.8 or later file formats,
+// whose headers switch to dense attribute storage for attributes larger
+// than 64KB, which the earliest format cannot store. Such files can be
+// read by libraries from 1.8 on.
+func WithLargeAttributes() FileOption {
+	return withFAPLSetting(func(fapl *PropList) error { return fapl.SetLibverBounds(F_LIBVER_V18, F_LIBVER_LATEST) })
+}
+
+// WithFAPL starts from the file access properties of fapl, to which later
+// options add. fapl itself is not modified.
+func WithFAPL(fapl *PropList) FileOption {
+	return func(o *fileOptions) { o.fapl = fapl }
+}
+
+// WithFCPL creates the file with the file creation properties of fcpl.
+// Open ignores it.
+func WithFCPL(fcpl *PropList) FileOption {
+	return func(o *fileOptions) { o.fcpl = fcpl }
+}
+
+// WithRetry makes Open retry opening a file that another process has
+// locked, as OpenFileRetry does. Create ignores it.
+func WithRetry(policy RetryPolicy) FileOption {
+	return func(o *fileOptions) { o.retry = &policy }
+}
+
+func withFAPLSetting(set func(fapl *PropList) error) FileOption {
+	return func(o *fileOptions) { o.setup = append(o.setup, set) }
+}
+
+func newFileOptions(flags int, opts []FileOption) *fileOptions {
+	o := &fileOptions{flags: flags, fcpl: P_DEFAULT, fapl: P_DEFAULT}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// accessList returns the file access property list of the options, and a
+// function releasing it.
+func (o *fileOptions) accessList() (*PropList, func(), error) {
+	if len(o.setup) == 0 {
+		return o.fapl, func() {}, nil
+	}
+	var fapl *PropList
+	var err error
+	if o.fapl == P_DEFAULT {
+		fapl, err = NewPropList(P_FILE_ACCESS)
+	} else {
+		fapl, err = o.fapl.Copy()
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, set := range o.setup {
+		if err := set(fapl); err != nil {
+			fapl.Close()
+			return nil, nil, err
+		}
+	}
+	return fapl, func() { fapl.Close() }, nil
+}
+
+// Open opens an existing HDF5 file, for reading and writing unless an
+// option says otherwise, e.g.
+//
+//	f, err := hdf5.Open("data.h5", hdf5.WithSWMRRead())
+//
+// OpenFileWith remains for flags and property lists set up by hand.
+func Open(name string, opts ...FileOption) (*File, error) {
+	o := newFileOptions(F_ACC_RDWR, opts)
+	fapl, release, err := o.accessList()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if o.retry != nil {
+		return OpenFileRetry(name, o.flags, fapl, *o.retry)
+	}
+	return OpenFileWith(name, o.flags, fapl)
+}
+
+// Create creates an HDF5 file, failing if it exists unless WithTruncate is
+// given, e.g.
+//
+//	f, err := hdf5.Create("scratch.h5", hdf5.WithTruncate(), hdf5.WithCoreDriver(1<<20, false))
+//
+// CreateFileWith remains for flags and property lists set up by hand.
+func Create(name string, opts ...FileOption) (*File, error) {
+	o := newFileOptions(F_ACC_EXCL, opts)
+	fapl, release, err := o.accessList()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	// H5Fcreate implies read-write access and rejects the access flags.
+	flags := o.flags & (F_ACC_TRUNC | F_ACC_EXCL | F_ACC_DEBUG | F_ACC_SWMR_WRITE)
+	return CreateFileWith(name, flags, o.fcpl, fapl)
+}
@@ -0,0 +1,74 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+import "C"
+
+import (
+	"errors"
+	"time"
+	"unsafe"
+)
+
+// RetryPolicy controls how OpenFileRetry retries opens that fail because
+// another process holds a lock on the file.
+type RetryPolicy struct {
+	Backoff    time.Duration // delay before the first retry, 1ms if 0; doubled after each retry
+	MaxBackoff time.Duration // longest delay between retries, unlimited if 0
+	Timeout    time.Duration // time after the first attempt at which to give up, no retries if 0
+}
+
+// OpenFileRetry opens an existing HDF5 file like OpenFileWith, retrying
+// with exponential backoff while the open fails because the file is
+// locked by another process, such as a concurrent job opening it for
+// writing, until the timeout of policy. Other errors are returned at once.
+// Library errors are not printed for the failed attempts. File locking
+// errors are detected from HDF5 1.10.0.
+// hid_t H5Fopen(const char *name, unsigned flags, hid_t fapl_id)
+func OpenFileRetry(name string, flags int, fapl *PropList, policy RetryPolicy) (*File, error) {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	var hid C.hid_t
+	err := retry(policy, func() (bool, error) {
+		var err error
+		quietly(func() {
+			hid = C.H5Fopen(c_name, C.uint(flags), fapl.id)
+			err = h5err(C.herr_t(int(hid)))
+		})
+		return errors.Is(err, ErrFileLocked), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if l := logger(hid); l != nil {
+		l.Debug("open file", "file", name, "flags", flags)
+	}
+	return newFile(hid), nil
+}
+
+// retry calls attempt until it succeeds, fails without asking for a retry,
+// or the timeout of policy passes, waiting with exponential backoff
+// between the attempts. It returns the error of the last attempt.
+func retry(policy RetryPolicy, attempt func() (again bool, err error)) error {
+	backoff := policy.Backoff
+	if backoff <= 0 {
+		backoff = time.Millisecond
+	}
+	deadline := time.Now().Add(policy.Timeout)
+	for {
+		again, err := attempt()
+		if err == nil || !again {
+			return err
+		}
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return err
+		}
+		time.Sleep(min(backoff, wait))
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
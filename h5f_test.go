@@ -1,8 +1,12 @@
 package hdf5
 
 import (
+	"bytes"
+	"fmt"
 	"os"
+	"runtime"
 	"testing"
+	"time"
 )
 
 func TestFile(t *testing.T) {
@@ -131,3 +135,801 @@ func TestClosedFile(t *testing.T) {
 	}
 
 }
+
+func TestPathExists(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	a, err := f.CreateGroup("a")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	defer a.Close()
+	b, err := a.CreateGroup("b", 0, 0, 0)
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	defer b.Close()
+
+	for _, tt := range []struct {
+		path string
+		want bool
+	}{
+		{"/", true},
+		{"/a", true},
+		{"/a/b", true},
+		{"a/b", true},
+		{"/a/c", false},
+		{"/x/y/z", false},
+	} {
+		ok, err := f.PathExists(tt.path, true)
+		if err != nil {
+			t.Errorf("PathExists(%q) failed: %s", tt.path, err)
+		} else if ok != tt.want {
+			t.Errorf("PathExists(%q) = %v, want %v", tt.path, ok, tt.want)
+		}
+	}
+
+	if ok, err := a.LinkExists("b"); err != nil {
+		t.Errorf("LinkExists failed: %s", err)
+	} else if !ok {
+		t.Errorf("LinkExists(%q) = false, want true", "b")
+	}
+	if ok, err := a.LinkExists("c"); err != nil {
+		t.Errorf("LinkExists failed: %s", err)
+	} else if ok {
+		t.Errorf("LinkExists(%q) = true, want false", "c")
+	}
+}
+
+func TestLinkInfo(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	g, err := f.CreateGroup("data")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	defer g.Close()
+	if err := f.CreateSoftLink("/data", "latest"); err != nil {
+		t.Fatalf("CreateSoftLink failed: %s", err)
+	}
+	if err := g.CreateSoftLink("/missing", "dangling"); err != nil {
+		t.Fatalf("CreateSoftLink failed: %s", err)
+	}
+	if err := f.CreateExternalLink("calib.h5", "/constants", "calib"); err != nil {
+		t.Fatalf("CreateExternalLink failed: %s", err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		want LinkType
+	}{
+		{"data", L_TYPE_HARD},
+		{"latest", L_TYPE_SOFT},
+		{"data/dangling", L_TYPE_SOFT},
+		{"calib", L_TYPE_EXTERNAL},
+	} {
+		info, err := f.LinkInfo(tt.name)
+		if err != nil {
+			t.Errorf("LinkInfo(%q) failed: %s", tt.name, err)
+		} else if info.Type != tt.want {
+			t.Errorf("LinkInfo(%q) returned a %s link, want %s", tt.name, info.Type, tt.want)
+		}
+	}
+	if info, err := f.LinkInfo("data"); err == nil && info.Addr == 0 {
+		t.Errorf("LinkInfo returned no address for a hard link")
+	}
+
+	if target, err := f.SoftLinkTarget("latest"); err != nil || target != "/data" {
+		t.Errorf("SoftLinkTarget returned %q, %v", target, err)
+	}
+	if target, err := g.SoftLinkTarget("dangling"); err != nil || target != "/missing" {
+		t.Errorf("SoftLinkTarget returned %q, %v", target, err)
+	}
+	if file, obj, err := f.ExternalLinkTarget("calib"); err != nil || file != "calib.h5" || obj != "/constants" {
+		t.Errorf("ExternalLinkTarget returned %q, %q, %v", file, obj, err)
+	}
+	if _, err := f.SoftLinkTarget("data"); err == nil {
+		t.Errorf("expected an error for the target of a hard link")
+	}
+	if _, err := f.LinkInfo("nothing"); err == nil {
+		t.Errorf("expected an error for a missing link")
+	}
+}
+
+func TestObjectExists(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	g, err := f.CreateGroup("a")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	defer g.Close()
+	dspace, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatalf("CreateDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	d, err := g.CreateDataset("d", T_NATIVE_INT32, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	d.Close()
+	if err := g.CreateSoftLink("/a/d", "alias"); err != nil {
+		t.Fatalf("CreateSoftLink failed: %s", err)
+	}
+	if err := g.CreateSoftLink("/a/gone", "stale"); err != nil {
+		t.Fatalf("CreateSoftLink failed: %s", err)
+	}
+	if err := f.CreateExternalLink("no-such-file.h5", "/x", "ext"); err != nil {
+		t.Fatalf("CreateExternalLink failed: %s", err)
+	}
+
+	for _, tt := range []struct {
+		path string
+		want ObjectState
+	}{
+		{"/", OBJECT_EXISTS},
+		{"/a", OBJECT_EXISTS},
+		{"a/d", OBJECT_EXISTS},
+		{"/a/alias", OBJECT_EXISTS},
+		{"/a/stale", OBJECT_DANGLING},
+		{"/ext", OBJECT_DANGLING},
+		{"/a/missing", OBJECT_MISSING},
+		{"/x/y/z", OBJECT_MISSING},
+		{"/a/stale/z", OBJECT_MISSING},
+		{"/a/d/z", OBJECT_MISSING},
+	} {
+		state, err := f.ObjectExists(tt.path)
+		if err != nil {
+			t.Errorf("ObjectExists(%q) failed: %s", tt.path, err)
+		} else if state != tt.want {
+			t.Errorf("ObjectExists(%q) = %s, want %s", tt.path, state, tt.want)
+		}
+	}
+	if state, err := g.ObjectExists("stale"); err != nil || state != OBJECT_DANGLING {
+		t.Errorf("ObjectExists(%q) = %s, %v", "stale", state, err)
+	}
+}
+
+func TestDeleteMove(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	for _, name := range []string{"a", "b", "c"} {
+		g, err := f.CreateGroup(name)
+		if err != nil {
+			t.Fatalf("CreateGroup failed: %s", err)
+		}
+		g.Close()
+	}
+	if err := f.Delete("a"); err != nil {
+		t.Fatalf("Delete failed: %s", err)
+	}
+	if err := f.Move("b", "c/bb"); err != nil {
+		t.Fatalf("Move failed: %s", err)
+	}
+	for _, tt := range []struct {
+		path string
+		want bool
+	}{
+		{"a", false},
+		{"b", false},
+		{"c/bb", true},
+	} {
+		if ok, err := f.PathExists(tt.path, true); err != nil || ok != tt.want {
+			t.Errorf("PathExists(%q) = %v, %v, want %v", tt.path, ok, err, tt.want)
+		}
+	}
+
+	g, err := f.OpenGroup("c")
+	if err != nil {
+		t.Fatalf("OpenGroup failed: %s", err)
+	}
+	defer g.Close()
+	if err := g.Move("bb", "b2"); err != nil {
+		t.Fatalf("Move failed: %s", err)
+	}
+	if err := g.Delete("b2"); err != nil {
+		t.Fatalf("Delete failed: %s", err)
+	}
+	if n, err := g.NumObjects(); err != nil || n != 0 {
+		t.Errorf("NumObjects returned %d, %v", n, err)
+	}
+	if err := g.Delete("missing"); err == nil {
+		t.Errorf("expected an error deleting a missing link")
+	}
+}
+
+func TestIsHDF5(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	f.Close()
+
+	if !IsHDF5(FNAME) {
+		t.Errorf("IsHDF5 returned false for an HDF5 file")
+	}
+	data, err := os.ReadFile(FNAME)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %s", err)
+	}
+	if !HasHDF5Signature(data[:8]) {
+		t.Errorf("HasHDF5Signature returned false for an HDF5 file")
+	}
+
+	const other = "not_hdf5.txt"
+	if err := os.WriteFile(other, []byte("a,b,c\n1,2,3\n"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %s", err)
+	}
+	defer os.Remove(other)
+	if IsHDF5(other) {
+		t.Errorf("IsHDF5 returned true for a text file")
+	}
+
+	block := make([]byte, 1024)
+	copy(block[512:], hdf5Signature)
+	if !HasHDF5Signature(block) {
+		t.Errorf("HasHDF5Signature missed a signature after a user block")
+	}
+	if HasHDF5Signature(block[:519]) {
+		t.Errorf("HasHDF5Signature matched a truncated signature")
+	}
+	if IsHDF5("missing.h5") {
+		t.Errorf("IsHDF5 returned true for a missing file")
+	}
+}
+
+func TestUserblock(t *testing.T) {
+	fcpl, err := NewPropList(P_FILE_CREATE)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer fcpl.Close()
+	if err := fcpl.SetUserblock(512); err != nil {
+		t.Fatalf("SetUserblock failed: %s", err)
+	}
+	f, err := CreateFileWith(FNAME, F_ACC_TRUNC, fcpl, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateFileWith failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	p, err := f.CreatePropList()
+	if err != nil {
+		t.Fatalf("CreatePropList failed: %s", err)
+	}
+	if size, err := p.Userblock(); err != nil || size != 512 {
+		t.Errorf("Userblock returned %d, %v", size, err)
+	}
+	p.Close()
+	f.Close()
+
+	header := []byte("#!/bin/sh\necho not a script\nexit 0\n")
+	if err := WriteUserblock(FNAME, header); err != nil {
+		t.Fatalf("WriteUserblock failed: %s", err)
+	}
+	if err := WriteUserblock(FNAME, make([]byte, 513)); err == nil {
+		t.Errorf("expected an error writing past the user block")
+	}
+	data, err := ReadUserblock(FNAME)
+	if err != nil {
+		t.Fatalf("ReadUserblock failed: %s", err)
+	}
+	if len(data) != 512 || !bytes.HasPrefix(data, header) {
+		t.Errorf("ReadUserblock returned %q", data)
+	}
+
+	if !IsHDF5(FNAME) {
+		t.Errorf("IsHDF5 returned false for a file with a user block")
+	}
+	f, err = OpenFile(FNAME, F_ACC_RDONLY)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %s", err)
+	}
+	f.Close()
+}
+
+func TestMDC(t *testing.T) {
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer fapl.Close()
+	cfg, err := fapl.MDCConfig()
+	if err != nil {
+		t.Fatalf("MDCConfig failed: %s", err)
+	}
+	cfg.SetInitialSize = true
+	cfg.InitialSize = 4 << 20
+	cfg.MaxSize = 32 << 20
+	if err := fapl.SetMDCConfig(cfg); err != nil {
+		t.Fatalf("SetMDCConfig failed: %s", err)
+	}
+
+	f, err := CreateFileWith(FNAME, F_ACC_TRUNC, P_DEFAULT, fapl)
+	if err != nil {
+		t.Fatalf("CreateFileWith failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	got, err := f.MDCConfig()
+	if err != nil {
+		t.Fatalf("MDCConfig failed: %s", err)
+	}
+	if got.MaxSize != 32<<20 {
+		t.Errorf("MDCConfig has a maximum size of %d", got.MaxSize)
+	}
+	size, err := f.MDCSize()
+	if err != nil {
+		t.Fatalf("MDCSize failed: %s", err)
+	}
+	if size.MaxSize != 4<<20 {
+		t.Errorf("MDCSize returned a maximum size of %d", size.MaxSize)
+	}
+	if err := f.ResetMDCHitRateStats(); err != nil {
+		t.Errorf("ResetMDCHitRateStats failed: %s", err)
+	}
+	if _, err := f.MDCHitRate(); err != nil {
+		t.Errorf("MDCHitRate failed: %s", err)
+	}
+
+	got.MinSize = 1 << 20
+	if err := f.SetMDCConfig(got); err != nil {
+		t.Errorf("SetMDCConfig failed: %s", err)
+	}
+}
+
+func TestMDCLogging(t *testing.T) {
+	logname := FNAME + ".mdclog"
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer fapl.Close()
+	if err := fapl.SetMDCLogOptions(true, logname, false); err != nil {
+		t.Fatalf("SetMDCLogOptions failed: %s", err)
+	}
+	enabled, location, start, err := fapl.MDCLogOptions()
+	if err != nil {
+		t.Fatalf("MDCLogOptions failed: %s", err)
+	}
+	if !enabled || location != logname || start {
+		t.Errorf("MDCLogOptions returned %v, %q, %v", enabled, location, start)
+	}
+
+	f, err := CreateFileWith(FNAME, F_ACC_TRUNC, P_DEFAULT, fapl)
+	if err != nil {
+		t.Fatalf("CreateFileWith failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer os.Remove(logname)
+	defer f.Close()
+
+	if enabled, logging, err := f.MDCLoggingStatus(); err != nil || !enabled || logging {
+		t.Errorf("MDCLoggingStatus returned %v, %v, %v before logging", enabled, logging, err)
+	}
+	if err := f.StartMDCLogging(); err != nil {
+		t.Fatalf("StartMDCLogging failed: %s", err)
+	}
+	if _, logging, err := f.MDCLoggingStatus(); err != nil || !logging {
+		t.Errorf("MDCLoggingStatus returned %v, %v while logging", logging, err)
+	}
+	g, err := f.CreateGroup("logged")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	g.Close()
+	if err := f.StopMDCLogging(); err != nil {
+		t.Fatalf("StopMDCLogging failed: %s", err)
+	}
+	if _, logging, err := f.MDCLoggingStatus(); err != nil || logging {
+		t.Errorf("MDCLoggingStatus returned %v, %v after logging", logging, err)
+	}
+	if _, err := os.Stat(logname); err != nil {
+		t.Errorf("no metadata cache log: %s", err)
+	}
+}
+
+func TestOnion(t *testing.T) {
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer fapl.Close()
+	cfg := &OnionConfig{Revision: FD_ONION_REVISION_LATEST, PageSize: 4096, Comment: "second value"}
+	if err := requireVersion("", 1, 14, 0); err != nil {
+		if fapl.SetOnion(cfg) == nil {
+			t.Errorf("expected an error from libraries before 1.14.0")
+		}
+		t.Skip(err)
+	}
+	if err := fapl.SetOnion(cfg); err != nil {
+		t.Fatalf("SetOnion failed: %s", err)
+	}
+	if got, err := fapl.Onion(); err != nil || *got != *cfg {
+		t.Errorf("Onion returned %+v, %v", got, err)
+	}
+
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer os.Remove(FNAME + ".onion")
+	dspace, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatalf("CreateDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	d, err := f.CreateDataset("v", T_NATIVE_INT32, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	v := int32(1)
+	if err := d.Write(&v, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	d.Close()
+	f.Close()
+
+	f, err = OpenFileWith(FNAME, F_ACC_RDWR, fapl)
+	if err != nil {
+		t.Fatalf("OpenFileWith failed: %s", err)
+	}
+	d, err = f.OpenDataset("v")
+	if err != nil {
+		t.Fatalf("OpenDataset failed: %s", err)
+	}
+	v = 2
+	if err := d.Write(&v, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	d.Close()
+	f.Close()
+
+	if n, err := OnionRevisionCount(FNAME, fapl); err != nil || n == 0 {
+		t.Errorf("OnionRevisionCount returned %d, %v", n, err)
+	}
+	// The original file is left as it was.
+	f, err = OpenFile(FNAME, F_ACC_RDONLY)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %s", err)
+	}
+	defer f.Close()
+	d, err = f.OpenDataset("v")
+	if err != nil {
+		t.Fatalf("OpenDataset failed: %s", err)
+	}
+	defer d.Close()
+	if err := d.Read(&v, T_NATIVE_INT32); err != nil || v != 1 {
+		t.Errorf("Read of the original returned %d, %v", v, err)
+	}
+}
+
+func TestSplitter(t *testing.T) {
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer fapl.Close()
+	const copyName = "test_splitter_copy.h5"
+	cfg := &SplitterConfig{WriteOnlyPath: copyName}
+	if err := requireVersion("", 1, 12, 0); err != nil {
+		if fapl.SetSplitter(cfg) == nil {
+			t.Errorf("expected an error from libraries before 1.12.0")
+		}
+		t.Skip(err)
+	}
+	if err := fapl.SetSplitter(cfg); err != nil {
+		t.Fatalf("SetSplitter failed: %s", err)
+	}
+	got, err := fapl.Splitter()
+	if err != nil {
+		t.Fatalf("Splitter failed: %s", err)
+	}
+	got.ReadWrite.Close()
+	got.WriteOnly.Close()
+	if got.WriteOnlyPath != copyName || got.LogPath != "" || got.IgnoreWriteOnlyErrors {
+		t.Errorf("Splitter returned %+v", got)
+	}
+
+	f, err := CreateFileWith(FNAME, F_ACC_TRUNC, P_DEFAULT, fapl)
+	if err != nil {
+		t.Fatalf("CreateFileWith failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer os.Remove(copyName)
+	g, err := f.CreateGroup("g")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	g.Close()
+	f.Close()
+
+	f, err = OpenFile(copyName, F_ACC_RDONLY)
+	if err != nil {
+		t.Fatalf("OpenFile of the copy failed: %s", err)
+	}
+	defer f.Close()
+	if ok, err := f.PathExists("g", true); err != nil || !ok {
+		t.Errorf("PathExists in the copy returned %v, %v", ok, err)
+	}
+}
+
+func TestMirror(t *testing.T) {
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer fapl.Close()
+	if err := haveMirror(); err != nil {
+		if fapl.SetMirror("127.0.0.1", 3000) == nil {
+			t.Errorf("expected an error from a library without the mirror driver")
+		}
+		t.Skip(err)
+	}
+	if err := fapl.SetMirror("127.0.0.1", 3000); err != nil {
+		t.Fatalf("SetMirror failed: %s", err)
+	}
+	if ip, port, err := fapl.Mirror(); err != nil || ip != "127.0.0.1" || port != 3000 {
+		t.Errorf("Mirror returned %q, %d, %v", ip, port, err)
+	}
+}
+
+func TestGroupInfo(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	gcpl, err := NewPropList(P_GROUP_CREATE)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer gcpl.Close()
+	if err := gcpl.SetLinkCreationOrder(P_CRT_ORDER_TRACKED); err != nil {
+		t.Fatalf("SetLinkCreationOrder failed: %s", err)
+	}
+	g, err := f.CreateGroupWith("runs", P_DEFAULT, gcpl)
+	if err != nil {
+		t.Fatalf("CreateGroupWith failed: %s", err)
+	}
+	defer g.Close()
+
+	info, err := f.GroupInfo()
+	if err != nil {
+		t.Fatalf("GroupInfo failed: %s", err)
+	}
+	if info.StorageType != G_STORAGE_TYPE_SYMBOL_TABLE || info.NumLinks != 1 || info.Mounted {
+		t.Errorf("GroupInfo of the root group returned %+v", info)
+	}
+	if info, err := g.GroupInfo(); err != nil || info.StorageType != G_STORAGE_TYPE_COMPACT || info.NumLinks != 0 {
+		t.Errorf("GroupInfo of an empty group returned %+v, %v", info, err)
+	}
+
+	const n = 50
+	for i := 0; i < n; i++ {
+		sub, err := g.CreateGroup(fmt.Sprintf("run%02d", i), 0, 0, 0)
+		if err != nil {
+			t.Fatalf("CreateGroup failed: %s", err)
+		}
+		sub.Close()
+	}
+	info, err = f.GroupInfoByName("runs")
+	if err != nil {
+		t.Fatalf("GroupInfoByName failed: %s", err)
+	}
+	if info.StorageType != G_STORAGE_TYPE_DENSE || info.NumLinks != n || info.MaxCrtOrder != n {
+		t.Errorf("GroupInfoByName returned %+v", info)
+	}
+	if info, err := g.GroupInfoByName("run07"); err != nil || info.NumLinks != 0 {
+		t.Errorf("GroupInfoByName of a subgroup returned %+v, %v", info, err)
+	}
+	if _, err := f.GroupInfoByName("missing"); err == nil {
+		t.Errorf("expected an error for a missing group")
+	}
+}
+
+func TestOpenFileRetry(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	f.Close()
+
+	policy := RetryPolicy{Backoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond, Timeout: 50 * time.Millisecond}
+	f, err = OpenFileRetry(FNAME, F_ACC_RDONLY, P_DEFAULT, policy)
+	if err != nil {
+		t.Fatalf("OpenFileRetry failed: %s", err)
+	}
+	f.Close()
+
+	// errors other than locking are not retried
+	start := time.Now()
+	if _, err := OpenFileRetry("no-such-file.h5", F_ACC_RDONLY, P_DEFAULT, RetryPolicy{Timeout: time.Hour}); err == nil {
+		t.Errorf("expected an error opening a missing file")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("opening a missing file was retried for %s", d)
+	}
+
+	// a lock that is released after a few attempts
+	attempts := 0
+	err = retry(policy, func() (bool, error) {
+		attempts++
+		if attempts < 4 {
+			return true, fmt.Errorf("locked")
+		}
+		return false, nil
+	})
+	if err != nil || attempts != 4 {
+		t.Errorf("retry returned %v after %d attempts", err, attempts)
+	}
+	// a lock that outlasts the timeout
+	start = time.Now()
+	err = retry(policy, func() (bool, error) { return true, fmt.Errorf("locked") })
+	if err == nil {
+		t.Errorf("expected an error after the timeout")
+	}
+	if d := time.Since(start); d < policy.Timeout || d > 10*policy.Timeout {
+		t.Errorf("retry gave up after %s, with a timeout of %s", d, policy.Timeout)
+	}
+}
+
+func TestFileOptions(t *testing.T) {
+	f, err := Create(FNAME, WithTruncate())
+	if err != nil {
+		t.Fatalf("Create failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	f.Close()
+
+	if _, err := Create(FNAME); err == nil {
+		t.Errorf("expected an error creating an existing file without WithTruncate")
+	}
+
+	f, err = Open(FNAME, WithReadOnly(), WithLibverLatest())
+	if err != nil {
+		t.Fatalf("Open failed: %s", err)
+	}
+	if _, err := f.CreateGroup("g"); err == nil {
+		t.Errorf("expected an error creating a group in a read-only file")
+	}
+	fapl, err := f.AccessPropList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, high, err := fapl.LibverBounds(); err != nil || high != F_LIBVER_LATEST {
+		t.Errorf("LibverBounds: high=%v, %v", high, err)
+	}
+	fapl.Close()
+	f.Close()
+
+	// an in-memory file that is never written to disk
+	const memName = "in-memory.h5"
+	f, err = Create(memName, WithCoreDriver(1<<16, false))
+	if err != nil {
+		t.Fatalf("Create with the core driver failed: %s", err)
+	}
+	fapl, err = f.AccessPropList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if increment, backing, err := fapl.Core(); err != nil || increment != 1<<16 || backing {
+		t.Errorf("Core: %d %v %v", increment, backing, err)
+	}
+	fapl.Close()
+	f.Close()
+	if _, err := os.Stat(memName); err == nil {
+		os.Remove(memName)
+		t.Errorf("the core driver without a backing store wrote %s", memName)
+	}
+}
+
+func TestFileOpenObjects(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	g, err := f.CreateGroup("lingering")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	objs, err := f.OpenObjects()
+	if err != nil {
+		t.Fatalf("OpenObjects failed: %s", err)
+	}
+	if len(objs) != 1 || objs[0].Type != "group" || objs[0].Name != "/lingering" {
+		t.Fatalf("OpenObjects returned %v", objs)
+	}
+	err = f.CloseWith(F_CLOSE_REPORT)
+	if e, ok := err.(*OpenObjectsError); !ok || len(e.Objects) != 1 {
+		t.Fatalf("CloseWith(F_CLOSE_REPORT) returned %v", err)
+	}
+	g.Close()
+
+	f, err = OpenFile(FNAME, F_ACC_RDWR)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %s", err)
+	}
+	g, err = f.OpenGroup("lingering")
+	if err != nil {
+		t.Fatalf("OpenGroup failed: %s", err)
+	}
+	if err := f.CloseWith(F_CLOSE_CHILDREN); err != nil {
+		t.Fatalf("CloseWith(F_CLOSE_CHILDREN) failed: %s", err)
+	}
+	if g.Name() != "" {
+		t.Errorf("group still open after CloseWith(F_CLOSE_CHILDREN)")
+	}
+	// the file is closed, so it can be opened without conflicting access
+	f, err = OpenFile(FNAME, F_ACC_RDONLY)
+	if err != nil {
+		t.Fatalf("OpenFile failed after CloseWith: %s", err)
+	}
+	f.Close()
+}
+
+func TestFileCloseChildrenFinalizers(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	dtype, err := T_NATIVE_INT32.Copy()
+	if err != nil {
+		t.Fatalf("Copy failed: %s", err)
+	}
+	if err := f.CommitDatatype("counter", dtype); err != nil {
+		t.Fatalf("CommitDatatype failed: %s", err)
+	}
+	dtype.Close()
+
+	func() {
+		named, err := f.OpenDatatype("counter", int(P_DEFAULT.id))
+		if err != nil {
+			t.Fatalf("OpenDatatype failed: %s", err)
+		}
+		table, err := CreateTable[event_t](f, "events", 16, -1)
+		if err != nil {
+			t.Fatalf("CreateTable failed: %s", err)
+		}
+		if err := table.Append([]event_t{{id: 1}}); err != nil {
+			t.Fatalf("Append failed: %s", err)
+		}
+		if err := f.CloseWith(F_CLOSE_CHILDREN); err != nil {
+			t.Fatalf("CloseWith(F_CLOSE_CHILDREN) failed: %s", err)
+		}
+		_, _ = named, table
+	}()
+	// the finalizers of the values whose identifiers were closed must not
+	// panic
+	for i := 0; i < 3; i++ {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,256 @@
+package hdf5
+
+// #include "hdf5.h"
+// #if H5_VERSION_GE(1,13,0)
+// #include "H5FDdevelop.h"
+// #endif
+// #include <stdint.h>
+// #include <stdlib.h>
+//
+// extern uintptr_t _go_hdf5_fd_open(char *name, unsigned flags, uintptr_t opener);
+// extern herr_t _go_hdf5_fd_close(uintptr_t file);
+// extern haddr_t _go_hdf5_fd_size(uintptr_t file);
+// extern herr_t _go_hdf5_fd_read(uintptr_t file, haddr_t addr, size_t size, void *buf);
+// extern herr_t _go_hdf5_fd_write(uintptr_t file, haddr_t addr, size_t size, void *buf);
+// extern herr_t _go_hdf5_fd_flush(uintptr_t file);
+// extern haddr_t _go_hdf5_fd_truncate(uintptr_t file, haddr_t size);
+// extern void _go_hdf5_fd_retain(uintptr_t opener);
+// extern void _go_hdf5_fd_release(uintptr_t opener);
+//
+// typedef struct {
+//   H5FD_t pub;
+//   uintptr_t opener;
+//   uintptr_t file;
+//   haddr_t eoa;
+//   haddr_t eof;
+// } _go_hdf5_fd_t;
+//
+// static void *_go_hdf5_fd_fapl_copy(const void *info) {
+//   uintptr_t *copy = malloc(sizeof(uintptr_t));
+//   if (copy == NULL) return NULL;
+//   *copy = *(const uintptr_t *)info;
+//   _go_hdf5_fd_retain(*copy);
+//   return copy;
+// }
+// static herr_t _go_hdf5_fd_fapl_free(void *info) {
+//   _go_hdf5_fd_release(*(uintptr_t *)info);
+//   free(info);
+//   return 0;
+// }
+// static void *_go_hdf5_fd_fapl_get(H5FD_t *_file) {
+//   return _go_hdf5_fd_fapl_copy(&((_go_hdf5_fd_t *)_file)->opener);
+// }
+// static H5FD_t *_go_hdf5_fd_open_cb(const char *name, unsigned flags, hid_t fapl_id, haddr_t maxaddr) {
+//   const uintptr_t *opener = H5Pget_driver_info(fapl_id);
+//   if (opener == NULL) return NULL;
+//   _go_hdf5_fd_t *f = calloc(1, sizeof(*f));
+//   if (f == NULL) return NULL;
+//   f->file = _go_hdf5_fd_open((char *)name, flags, *opener);
+//   if (f->file == 0) {
+//     free(f);
+//     return NULL;
+//   }
+//   f->eof = _go_hdf5_fd_size(f->file);
+//   if (f->eof == HADDR_UNDEF) {
+//     _go_hdf5_fd_close(f->file);
+//     free(f);
+//     return NULL;
+//   }
+//   f->opener = *opener;
+//   _go_hdf5_fd_retain(f->opener);
+//   return &f->pub;
+// }
+// static herr_t _go_hdf5_fd_close_cb(H5FD_t *_file) {
+//   _go_hdf5_fd_t *f = (_go_hdf5_fd_t *)_file;
+//   herr_t err = _go_hdf5_fd_close(f->file);
+//   _go_hdf5_fd_release(f->opener);
+//   free(f);
+//   return err;
+// }
+// static herr_t _go_hdf5_fd_query_cb(const H5FD_t *_file, unsigned long *flags) {
+//   *flags = H5FD_FEAT_AGGREGATE_METADATA | H5FD_FEAT_ACCUMULATE_METADATA | H5FD_FEAT_DATA_SIEVE | H5FD_FEAT_AGGREGATE_SMALLDATA;
+//   return 0;
+// }
+// static haddr_t _go_hdf5_fd_get_eoa_cb(const H5FD_t *_file, H5FD_mem_t type) {
+//   return ((const _go_hdf5_fd_t *)_file)->eoa;
+// }
+// static herr_t _go_hdf5_fd_set_eoa_cb(H5FD_t *_file, H5FD_mem_t type, haddr_t addr) {
+//   ((_go_hdf5_fd_t *)_file)->eoa = addr;
+//   return 0;
+// }
+// #if H5_VERSION_GE(1,10,0)
+// static haddr_t _go_hdf5_fd_get_eof_cb(const H5FD_t *_file, H5FD_mem_t type) {
+// #else
+// static haddr_t _go_hdf5_fd_get_eof_cb(const H5FD_t *_file) {
+// #endif
+//   return ((const _go_hdf5_fd_t *)_file)->eof;
+// }
+// static herr_t _go_hdf5_fd_read_cb(H5FD_t *_file, H5FD_mem_t type, hid_t dxpl_id, haddr_t addr, size_t size, void *buf) {
+//   return _go_hdf5_fd_read(((_go_hdf5_fd_t *)_file)->file, addr, size, buf);
+// }
+// static herr_t _go_hdf5_fd_write_cb(H5FD_t *_file, H5FD_mem_t type, hid_t dxpl_id, haddr_t addr, size_t size, const void *buf) {
+//   _go_hdf5_fd_t *f = (_go_hdf5_fd_t *)_file;
+//   if (_go_hdf5_fd_write(f->file, addr, size, (void *)buf) < 0) return -1;
+//   if (addr + size > f->eof) f->eof = addr + size;
+//   return 0;
+// }
+// #if H5_VERSION_GE(1,10,0)
+// static herr_t _go_hdf5_fd_flush_cb(H5FD_t *_file, hid_t dxpl_id, hbool_t closing) {
+// #else
+// static herr_t _go_hdf5_fd_flush_cb(H5FD_t *_file, hid_t dxpl_id, unsigned closing) {
+// #endif
+//   return _go_hdf5_fd_flush(((_go_hdf5_fd_t *)_file)->file);
+// }
+// static herr_t _go_hdf5_fd_truncate_cb(H5FD_t *_file, hid_t dxpl_id, hbool_t closing) {
+//   _go_hdf5_fd_t *f = (_go_hdf5_fd_t *)_file;
+//   if (f->eoa == f->eof) return 0;
+//   haddr_t eof = _go_hdf5_fd_truncate(f->file, f->eoa);
+//   if (eof == HADDR_UNDEF) return -1;
+//   f->eof = eof;
+//   return 0;
+// }
+//
+// static const H5FD_class_t _go_hdf5_fd_class = {
+// #if H5_VERSION_GE(1,13,0)
+//   .version = H5FD_CLASS_VERSION,
+//   .value = 300,
+// #endif
+//   .name = "go",
+//   .maxaddr = (haddr_t)INT64_MAX,
+//   .fc_degree = H5F_CLOSE_WEAK,
+//   .fapl_size = sizeof(uintptr_t),
+//   .fapl_get = _go_hdf5_fd_fapl_get,
+//   .fapl_copy = _go_hdf5_fd_fapl_copy,
+//   .fapl_free = _go_hdf5_fd_fapl_free,
+//   .open = _go_hdf5_fd_open_cb,
+//   .close = _go_hdf5_fd_close_cb,
+//   .query = _go_hdf5_fd_query_cb,
+//   .get_eoa = _go_hdf5_fd_get_eoa_cb,
+//   .set_eoa = _go_hdf5_fd_set_eoa_cb,
+//   .get_eof = _go_hdf5_fd_get_eof_cb,
+//   .read = _go_hdf5_fd_read_cb,
+//   .write = _go_hdf5_fd_write_cb,
+//   .flush = _go_hdf5_fd_flush_cb,
+//   .truncate = _go_hdf5_fd_truncate_cb,
+//   .fl_map = H5FD_FLMAP_DICHOTOMY,
+// };
+// static hid_t _go_hdf5_fd_register(void) { return H5FDregister(&_go_hdf5_fd_class); }
+//
+// static herr_t _go_hdf5_fd_set_driver(hid_t fapl_id, hid_t driver_id, uintptr_t opener) {
+//   return H5Pset_driver(fapl_id, driver_id, &opener);
+// }
+import "C"
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// --- Go file drivers ---
+
+// DriverFile is the storage of a file opened by a Go file driver, from
+// which the library reads at byte offsets. Files opened for writing must
+// also implement io.WriterAt, and may implement Truncate(size int64) error
+// and Sync() error, as *os.File does.
+type DriverFile interface {
+	io.ReaderAt
+	io.Closer
+	// Size returns the size of the file in bytes.
+	Size() (int64, error)
+}
+
+// DriverOpener opens the storage of the file name, with flags a
+// combination of F_ACC_RDWR, F_ACC_TRUNC, F_ACC_EXCL and F_ACC_CREAT.
+type DriverOpener func(name string, flags int) (DriverFile, error)
+
+// goDrivers holds the openers set on file access property lists and the
+// files they opened, under the handles the C side refers to them by.
+var goDrivers = struct {
+	sync.Mutex
+	once    sync.Once
+	id      C.hid_t
+	next    uintptr
+	openers map[uintptr]*goOpener
+	files   map[uintptr]DriverFile
+}{
+	openers: make(map[uintptr]*goOpener),
+	files:   make(map[uintptr]DriverFile),
+}
+
+// haddrUndef is HADDR_UNDEF, the address of nothing.
+const haddrUndef = ^C.haddr_t(0)
+
+type goOpener struct {
+	open DriverOpener
+	refs int
+}
+
+// SetGoDriver sets the file access property list to use a file driver
+// implemented in Go, which opens files with open. The library calls into
+// the returned DriverFile to read and write them, so files can be kept in
+// any storage.
+// herr_t H5Pset_driver(hid_t plist_id, hid_t new_driver_id, const void *new_driver_info)
+func (p *PropList) SetGoDriver(open DriverOpener) error {
+	goDrivers.once.Do(func() {
+		goDrivers.id = C._go_hdf5_fd_register()
+	})
+	if err := h5err(C.herr_t(int(goDrivers.id))); err != nil {
+		return err
+	}
+
+	goDrivers.Lock()
+	goDrivers.next++
+	h := goDrivers.next
+	goDrivers.openers[h] = &goOpener{open: open, refs: 1}
+	goDrivers.Unlock()
+	// The property list holds its own reference, taken as it copies h.
+	defer releaseOpener(h)
+	return h5err(C._go_hdf5_fd_set_driver(p.id, goDrivers.id, C.uintptr_t(h)))
+}
+
+func releaseOpener(h uintptr) {
+	goDrivers.Lock()
+	defer goDrivers.Unlock()
+	if o := goDrivers.openers[h]; o != nil {
+		if o.refs--; o.refs == 0 {
+			delete(goDrivers.openers, h)
+		}
+	}
+}
+
+func driverFile(h C.uintptr_t) DriverFile {
+	goDrivers.Lock()
+	defer goDrivers.Unlock()
+	return goDrivers.files[uintptr(h)]
+}
+
+// OpenReaderAt opens read-only the file of size bytes that r reads, e.g.
+// from a zip archive, a memory-mapped region or a blob store, without
+// copying it to disk first.
+func OpenReaderAt(r io.ReaderAt, size int64) (*File, error) {
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		return nil, err
+	}
+	defer fapl.Close()
+	err = fapl.SetGoDriver(func(name string, flags int) (DriverFile, error) {
+		if flags&F_ACC_RDWR != 0 {
+			return nil, fmt.Errorf("files opened from an io.ReaderAt are read-only")
+		}
+		return readerAtFile{r, size}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return OpenFileWith(fmt.Sprintf("readerat:%p", r), F_ACC_RDONLY, fapl)
+}
+
+// readerAtFile is a DriverFile read from an io.ReaderAt.
+type readerAtFile struct {
+	io.ReaderAt
+	size int64
+}
+
+func (f readerAtFile) Size() (int64, error) { return f.size, nil }
+func (f readerAtFile) Close() error         { return nil }
@@ -0,0 +1,137 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdint.h>
+import "C"
+
+import (
+	"io"
+	"unsafe"
+)
+
+// The callbacks of Go file drivers, called by the C driver in h5fd.go.
+// They return a negative value, HADDR_UNDEF or 0 for a handle on failure,
+// which the library reports as a failure of the file operation.
+
+//export _go_hdf5_fd_open
+func _go_hdf5_fd_open(name *C.char, flags C.uint, opener C.uintptr_t) C.uintptr_t {
+	goDrivers.Lock()
+	o := goDrivers.openers[uintptr(opener)]
+	goDrivers.Unlock()
+	if o == nil {
+		return 0
+	}
+	f, err := o.open(C.GoString(name), int(flags))
+	if err != nil || f == nil {
+		return 0
+	}
+	goDrivers.Lock()
+	defer goDrivers.Unlock()
+	goDrivers.next++
+	goDrivers.files[goDrivers.next] = f
+	return C.uintptr_t(goDrivers.next)
+}
+
+//export _go_hdf5_fd_close
+func _go_hdf5_fd_close(file C.uintptr_t) C.herr_t {
+	goDrivers.Lock()
+	f := goDrivers.files[uintptr(file)]
+	delete(goDrivers.files, uintptr(file))
+	goDrivers.Unlock()
+	if f == nil || f.Close() != nil {
+		return -1
+	}
+	return 0
+}
+
+//export _go_hdf5_fd_size
+func _go_hdf5_fd_size(file C.uintptr_t) C.haddr_t {
+	size, err := driverFile(file).Size()
+	if err != nil || size < 0 {
+		return haddrUndef
+	}
+	return C.haddr_t(size)
+}
+
+//export _go_hdf5_fd_read
+func _go_hdf5_fd_read(file C.uintptr_t, addr C.haddr_t, size C.size_t, buf unsafe.Pointer) C.herr_t {
+	if size == 0 {
+		return 0
+	}
+	p := unsafe.Slice((*byte)(buf), int(size))
+	n, err := driverFile(file).ReadAt(p, int64(addr))
+	if err != nil && err != io.EOF {
+		return -1
+	}
+	// The library expects zeros past the end of the file.
+	for i := n; i < len(p); i++ {
+		p[i] = 0
+	}
+	return 0
+}
+
+//export _go_hdf5_fd_write
+func _go_hdf5_fd_write(file C.uintptr_t, addr C.haddr_t, size C.size_t, buf unsafe.Pointer) C.herr_t {
+	w, ok := driverFile(file).(io.WriterAt)
+	if !ok {
+		return -1
+	}
+	if size == 0 {
+		return 0
+	}
+	if _, err := w.WriteAt(unsafe.Slice((*byte)(buf), int(size)), int64(addr)); err != nil {
+		return -1
+	}
+	return 0
+}
+
+//export _go_hdf5_fd_flush
+func _go_hdf5_fd_flush(file C.uintptr_t) C.herr_t {
+	if s, ok := driverFile(file).(interface{ Sync() error }); ok && s.Sync() != nil {
+		return -1
+	}
+	return 0
+}
+
+// _go_hdf5_fd_truncate sets the size of the file to size, if it supports
+// truncation, or else only extends it, and returns its new size.
+//
+//export _go_hdf5_fd_truncate
+func _go_hdf5_fd_truncate(file C.uintptr_t, size C.haddr_t) C.haddr_t {
+	f := driverFile(file)
+	if t, ok := f.(interface{ Truncate(int64) error }); ok {
+		if t.Truncate(int64(size)) != nil {
+			return haddrUndef
+		}
+		return size
+	}
+	cur, err := f.Size()
+	if err != nil {
+		return haddrUndef
+	}
+	if int64(size) <= cur {
+		return C.haddr_t(cur)
+	}
+	w, ok := f.(io.WriterAt)
+	if !ok {
+		return haddrUndef
+	}
+	if _, err := w.WriteAt([]byte{0}, int64(size)-1); err != nil {
+		return haddrUndef
+	}
+	return size
+}
+
+//export _go_hdf5_fd_retain
+func _go_hdf5_fd_retain(opener C.uintptr_t) {
+	goDrivers.Lock()
+	defer goDrivers.Unlock()
+	if o := goDrivers.openers[uintptr(opener)]; o != nil {
+		o.refs++
+	}
+}
+
+//export _go_hdf5_fd_release
+func _go_hdf5_fd_release(opener C.uintptr_t) {
+	releaseOpener(uintptr(opener))
+}
@@ -0,0 +1,158 @@
+package hdf5
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// URLOptions configures how OpenURL reads a file.
+type URLOptions struct {
+	Client      *http.Client // client making the requests, or http.DefaultClient
+	Header      http.Header  // headers added to each request, e.g. Authorization
+	BlockSize   int          // bytes fetched per range request, 1 MiB if 0
+	CacheBlocks int          // blocks kept in memory, 64 if 0
+}
+
+// OpenURL opens the file at an HTTP or HTTPS URL read-only, reading it with
+// range requests, which the server must support. Recently read blocks
+// are kept in memory, so that the metadata the library reads over and over
+// is fetched once. opts may be nil.
+func OpenURL(url string, opts *URLOptions) (*File, error) {
+	if opts == nil {
+		opts = &URLOptions{}
+	}
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		return nil, err
+	}
+	defer fapl.Close()
+	err = fapl.SetGoDriver(func(name string, flags int) (DriverFile, error) {
+		if flags&F_ACC_RDWR != 0 {
+			return nil, fmt.Errorf("%s: files opened by URL are read-only", name)
+		}
+		return openHTTPFile(name, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return OpenFileWith(url, F_ACC_RDONLY, fapl)
+}
+
+// httpFile is a DriverFile read with HTTP range requests through an LRU
+// cache of blocks.
+type httpFile struct {
+	url    string
+	opts   URLOptions
+	size   int64
+	blocks map[int64]*list.Element
+	lru    *list.List // of *httpBlock, most recently used first
+}
+
+type httpBlock struct {
+	index int64
+	data  []byte
+}
+
+func openHTTPFile(url string, opts *URLOptions) (*httpFile, error) {
+	f := &httpFile{url: url, opts: *opts, blocks: make(map[int64]*list.Element), lru: list.New()}
+	if f.opts.Client == nil {
+		f.opts.Client = http.DefaultClient
+	}
+	if f.opts.BlockSize <= 0 {
+		f.opts.BlockSize = 1 << 20
+	}
+	if f.opts.CacheBlocks <= 0 {
+		f.opts.CacheBlocks = 64
+	}
+
+	resp, err := f.do("HEAD", "")
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	if resp.ContentLength < 0 {
+		return nil, fmt.Errorf("%s: server did not report the size of the file", url)
+	}
+	f.size = resp.ContentLength
+	return f, nil
+}
+
+func (f *httpFile) do(method, byteRange string) (*http.Response, error) {
+	req, err := http.NewRequest(method, f.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range f.opts.Header {
+		req.Header[k] = v
+	}
+	if byteRange != "" {
+		req.Header.Set("Range", byteRange)
+	}
+	return f.opts.Client.Do(req)
+}
+
+func (f *httpFile) Size() (int64, error) { return f.size, nil }
+func (f *httpFile) Close() error         { return nil }
+
+func (f *httpFile) ReadAt(p []byte, off int64) (int, error) {
+	bs := int64(f.opts.BlockSize)
+	n := 0
+	for n < len(p) && off < f.size {
+		b, err := f.block(off / bs)
+		if err != nil {
+			return n, err
+		}
+		c := copy(p[n:], b[off%bs:])
+		n += c
+		off += int64(c)
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// block returns the block at index, fetching it if it is not cached.
+func (f *httpFile) block(index int64) ([]byte, error) {
+	if e, ok := f.blocks[index]; ok {
+		f.lru.MoveToFront(e)
+		return e.Value.(*httpBlock).data, nil
+	}
+
+	bs := int64(f.opts.BlockSize)
+	start, end := index*bs, (index+1)*bs
+	if end > f.size {
+		end = f.size
+	}
+	resp, err := f.do("GET", fmt.Sprintf("bytes=%d-%d", start, end-1))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusPartialContent:
+	case resp.StatusCode == http.StatusOK && start == 0 && end == f.size:
+		// The server sent the whole file, which is this block.
+	case resp.StatusCode == http.StatusOK:
+		return nil, fmt.Errorf("%s: server does not support range requests", f.url)
+	default:
+		return nil, fmt.Errorf("%s: %s", f.url, resp.Status)
+	}
+	data := make([]byte, end-start)
+	if _, err := io.ReadFull(resp.Body, data); err != nil {
+		return nil, fmt.Errorf("%s: %s", f.url, err)
+	}
+
+	f.blocks[index] = f.lru.PushFront(&httpBlock{index: index, data: data})
+	if f.lru.Len() > f.opts.CacheBlocks {
+		e := f.lru.Back()
+		f.lru.Remove(e)
+		delete(f.blocks, e.Value.(*httpBlock).index)
+	}
+	return data, nil
+}
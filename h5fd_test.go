@@ -0,0 +1,214 @@
+package hdf5
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// memFile is a DriverFile kept in memory.
+type memFile struct {
+	data *[]byte
+}
+
+func (f memFile) ReadAt(p []byte, off int64) (int, error) {
+	if off >= int64(len(*f.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, (*f.data)[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (f memFile) WriteAt(p []byte, off int64) (int, error) {
+	if end := off + int64(len(p)); end > int64(len(*f.data)) {
+		f.Truncate(end)
+	}
+	return copy((*f.data)[off:], p), nil
+}
+
+func (f memFile) Truncate(size int64) error {
+	data := make([]byte, size)
+	copy(data, *f.data)
+	*f.data = data
+	return nil
+}
+
+func (f memFile) Size() (int64, error) { return int64(len(*f.data)), nil }
+func (f memFile) Close() error         { return nil }
+
+func TestGoDriver(t *testing.T) {
+	files := make(map[string]*[]byte)
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer fapl.Close()
+	err = fapl.SetGoDriver(func(name string, flags int) (DriverFile, error) {
+		data, ok := files[name]
+		if !ok || flags&F_ACC_TRUNC != 0 {
+			if !ok && flags&F_ACC_CREAT == 0 {
+				return nil, os.ErrNotExist
+			}
+			data = new([]byte)
+			files[name] = data
+		}
+		return memFile{data}, nil
+	})
+	if err != nil {
+		t.Fatalf("SetGoDriver failed: %s", err)
+	}
+
+	f, err := CreateFileWith(FNAME, F_ACC_TRUNC, P_DEFAULT, fapl)
+	if err != nil {
+		t.Fatalf("CreateFileWith failed: %s", err)
+	}
+	dspace, err := CreateSimpleDataspace([]uint{4}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("d", T_NATIVE_INT32, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	want := []int32{4, 3, 2, 1}
+	if err := dset.Write(want, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	dset.Close()
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close failed: %s", err)
+	}
+	if _, err := os.Stat(FNAME); !os.IsNotExist(err) {
+		t.Errorf("expected no file on disk, got %v", err)
+	}
+	if data := files[FNAME]; data == nil || !HasHDF5Signature(*data) {
+		t.Fatalf("expected an HDF5 file in memory")
+	}
+
+	f, err = OpenFileWith(FNAME, F_ACC_RDONLY, fapl)
+	if err != nil {
+		t.Fatalf("OpenFileWith failed: %s", err)
+	}
+	defer f.Close()
+	dset, err = f.OpenDataset("d")
+	if err != nil {
+		t.Fatalf("OpenDataset failed: %s", err)
+	}
+	defer dset.Close()
+	got := make([]int32, 4)
+	if err := dset.Read(got, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read returned %v, want %v", got, want)
+	}
+	if _, err := OpenFileWith("missing.h5", F_ACC_RDONLY, fapl); err == nil {
+		t.Errorf("expected an error opening a missing file")
+	}
+}
+
+func TestOpenURL(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	dspace, err := CreateSimpleDataspace([]uint{1000}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("d", T_NATIVE_INT32, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	want := make([]int32, 1000)
+	for i := range want {
+		want[i] = int32(i * i)
+	}
+	if err := dset.Write(want, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	dset.Close()
+	f.Close()
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") != "secret" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		requests++
+		http.ServeFile(w, r, FNAME)
+	}))
+	defer srv.Close()
+
+	if _, err := OpenURL(srv.URL+"/data.h5", nil); err == nil {
+		t.Errorf("expected an error without the token")
+	}
+	opts := &URLOptions{Header: http.Header{"X-Token": {"secret"}}, BlockSize: 512, CacheBlocks: 64}
+	uf, err := OpenURL(srv.URL+"/data.h5", opts)
+	if err != nil {
+		t.Fatalf("OpenURL failed: %s", err)
+	}
+	defer uf.Close()
+	dset, err = uf.OpenDataset("d")
+	if err != nil {
+		t.Fatalf("OpenDataset failed: %s", err)
+	}
+	defer dset.Close()
+	got := make([]int32, 1000)
+	if err := dset.Read(got, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read returned different values")
+	}
+	fi, err := os.Stat(FNAME)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limit := 2 + int(fi.Size()/512) + 1; requests > limit {
+		t.Errorf("made %d requests for a %d byte file, want at most %d", requests, fi.Size(), limit)
+	}
+}
+
+func TestOpenReaderAt(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	g, err := f.CreateGroup("g")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	g.Close()
+	f.Close()
+	data, err := os.ReadFile(FNAME)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The file embedded in a larger blob.
+	blob := append(append([]byte("header"), data...), "trailer"...)
+	rf, err := OpenReaderAt(io.NewSectionReader(bytes.NewReader(blob), 6, int64(len(data))), int64(len(data)))
+	if err != nil {
+		t.Fatalf("OpenReaderAt failed: %s", err)
+	}
+	defer rf.Close()
+	if ok, err := rf.PathExists("g", true); err != nil || !ok {
+		t.Errorf("PathExists returned %v, %v", ok, err)
+	}
+	if _, err := OpenReaderAt(bytes.NewReader([]byte("not hdf5")), 8); err == nil {
+		t.Errorf("expected an error opening a non-HDF5 reader")
+	}
+}
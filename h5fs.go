@@ -0,0 +1,267 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// FS returns a read-only view of the file as an io/fs.FS, in which groups
+// are directories and datasets are files. The contents of a dataset are
+// written by enc, e.g. (*Dataset).WriteNPY, or are its raw bytes in the
+// datatype of the file if enc is nil. Other objects are not listed. The
+// file must stay open while the view is in use.
+func (f *File) FS(enc func(*Dataset, io.Writer) error) fs.FS {
+	return &fileFS{f: f, enc: enc}
+}
+
+type fileFS struct {
+	// mu serializes calls into the library, which is not thread-safe,
+	// for users such as http.FileServer.
+	mu  sync.Mutex
+	f   *File
+	enc func(*Dataset, io.Writer) error
+}
+
+func (fsys *fileFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	info, err := fsys.stat(name, false)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	if info.IsDir() {
+		entries, err := fsys.readDir(name)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		return &fsDir{info: info, entries: entries}, nil
+	}
+	data, err := fsys.contents(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	info.size = int64(len(data))
+	return &fsFile{Reader: bytes.NewReader(data), info: info}, nil
+}
+
+// stat describes the object at name, computing the size of datasets if
+// withSize is true.
+func (fsys *fileFS) stat(name string, withSize bool) (*fsFileInfo, error) {
+	var oi *ObjectInfo
+	var err error
+	if name == "." {
+		oi, err = objectInfo(fsys.f.id)
+	} else {
+		var ok bool
+		if ok, err = pathExists(fsys.f.id, name, true); err == nil && !ok {
+			return nil, fs.ErrNotExist
+		}
+		if err == nil {
+			oi, err = objectInfoByName(fsys.f.id, name)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info := &fsFileInfo{name: path.Base(name), modTime: oi.ModTime}
+	switch oi.Type {
+	case O_TYPE_GROUP:
+		info.mode = fs.ModeDir | 0555
+	case O_TYPE_DATASET:
+		info.mode = 0444
+		if withSize {
+			if info.size, err = fsys.size(name); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fs.ErrNotExist
+	}
+	return info, nil
+}
+
+func (fsys *fileFS) readDir(name string) ([]fs.DirEntry, error) {
+	id, err := openObject(fsys.f.id, name)
+	if err != nil {
+		return nil, err
+	}
+	defer C.H5Oclose(id)
+	n, err := numObjects(id)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]fs.DirEntry, 0, n)
+	for i := uint(0); i < n; i++ {
+		child, err := objectNameByIndex(id, i)
+		if err != nil {
+			return nil, err
+		}
+		if name != "." {
+			child = name + "/" + child
+		}
+		info, err := fsys.stat(child, false)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &fsDirEntry{fsys: fsys, path: child, info: info})
+	}
+	return entries, nil
+}
+
+func (fsys *fileFS) contents(name string) ([]byte, error) {
+	d, err := openDataset(fsys.f.id, name)
+	if err != nil {
+		return nil, err
+	}
+	defer d.Close()
+	if fsys.enc != nil {
+		var buf bytes.Buffer
+		err := fsys.enc(d, &buf)
+		return buf.Bytes(), err
+	}
+
+	tid, n, err := rawSize(d)
+	if err != nil {
+		return nil, err
+	}
+	defer C.H5Tclose(tid)
+	data := make([]byte, n)
+	if n > 0 {
+		err := h5err(C.H5Dread(d.id, tid, C.H5S_ALL, C.H5S_ALL, C.H5P_DEFAULT, unsafe.Pointer(&data[0])))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+func (fsys *fileFS) size(name string) (int64, error) {
+	if fsys.enc != nil {
+		data, err := fsys.contents(name)
+		return int64(len(data)), err
+	}
+	d, err := openDataset(fsys.f.id, name)
+	if err != nil {
+		return 0, err
+	}
+	defer d.Close()
+	tid, n, err := rawSize(d)
+	if err != nil {
+		return 0, err
+	}
+	C.H5Tclose(tid)
+	return int64(n), nil
+}
+
+// rawSize returns the datatype of the dataset d, which the caller must
+// close, and the size of its values in that datatype.
+func rawSize(d *Dataset) (C.hid_t, int, error) {
+	tid := C.H5Dget_type(d.id)
+	if err := h5err(C.herr_t(int(tid))); err != nil {
+		return 0, 0, err
+	}
+	if C.H5Tdetect_class(tid, C.H5T_VLEN) > 0 || C.H5Tis_variable_str(tid) > 0 {
+		C.H5Tclose(tid)
+		return 0, 0, fmt.Errorf("variable-length data has no raw representation")
+	}
+	_, npoints, err := jsonShape(C.H5Dget_space(d.id))
+	if err != nil {
+		C.H5Tclose(tid)
+		return 0, 0, err
+	}
+	return tid, npoints * int(C.H5Tget_size(tid)), nil
+}
+
+// fsFileInfo implements fs.FileInfo for groups and datasets.
+type fsFileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (fi *fsFileInfo) Name() string       { return fi.name }
+func (fi *fsFileInfo) Size() int64        { return fi.size }
+func (fi *fsFileInfo) Mode() fs.FileMode  { return fi.mode }
+func (fi *fsFileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *fsFileInfo) IsDir() bool        { return fi.mode.IsDir() }
+func (fi *fsFileInfo) Sys() interface{}   { return nil }
+
+// fsDirEntry implements fs.DirEntry, computing the size of datasets only
+// when Info is called.
+type fsDirEntry struct {
+	fsys *fileFS
+	path string
+	info *fsFileInfo
+}
+
+func (e *fsDirEntry) Name() string      { return e.info.name }
+func (e *fsDirEntry) IsDir() bool       { return e.info.IsDir() }
+func (e *fsDirEntry) Type() fs.FileMode { return e.info.mode.Type() }
+
+func (e *fsDirEntry) Info() (fs.FileInfo, error) {
+	if e.info.IsDir() {
+		return e.info, nil
+	}
+	e.fsys.mu.Lock()
+	defer e.fsys.mu.Unlock()
+	return e.fsys.stat(e.path, true)
+}
+
+// fsFile is an open dataset, whose contents are read into memory.
+type fsFile struct {
+	*bytes.Reader
+	info *fsFileInfo
+}
+
+func (f *fsFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *fsFile) Close() error               { return nil }
+
+// fsDir is an open group.
+type fsDir struct {
+	info    *fsFileInfo
+	entries []fs.DirEntry
+	off     int
+}
+
+func (d *fsDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *fsDir) Close() error               { return nil }
+
+func (d *fsDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+
+func (d *fsDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.off:]
+	if n <= 0 {
+		d.off = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(rest) {
+		n = len(rest)
+	}
+	d.off += n
+	return rest[:n], nil
+}
@@ -0,0 +1,66 @@
+package hdf5
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFS(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	g, err := f.CreateGroup("g")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	defer g.Close()
+	dspace, err := CreateSimpleDataspace([]uint{3}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	for _, loc := range []interface {
+		CreateDataset(string, *Datatype, *Dataspace, *PropList) (*Dataset, error)
+	}{f, g} {
+		dset, err := loc.CreateDataset("d", T_STD_I32LE, dspace, P_DEFAULT)
+		if err != nil {
+			t.Fatalf("CreateDataset failed: %s", err)
+		}
+		err = dset.Write([]int32{1, -2, 3}, T_NATIVE_INT32)
+		dset.Close()
+		if err != nil {
+			t.Fatalf("Write failed: %s", err)
+		}
+	}
+
+	fsys := f.FS(nil)
+	if err := fstest.TestFS(fsys, "d", "g/d"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := fs.ReadFile(fsys, "g/d")
+	if err != nil {
+		t.Fatalf("ReadFile failed: %s", err)
+	}
+	want := []byte{1, 0, 0, 0, 0xfe, 0xff, 0xff, 0xff, 3, 0, 0, 0}
+	if !bytes.Equal(data, want) {
+		t.Errorf("ReadFile returned %v, want %v", data, want)
+	}
+
+	csv, err := fs.ReadFile(f.FS((*Dataset).WriteCSV), "d")
+	if err != nil {
+		t.Fatalf("ReadFile failed: %s", err)
+	}
+	if got := string(csv); got != "d\n1\n-2\n3\n" {
+		t.Errorf("ReadFile returned %q", got)
+	}
+	if _, err := fsys.Open("missing"); err == nil {
+		t.Errorf("expected an error opening a missing dataset")
+	}
+}
@@ -8,6 +8,7 @@ import "C"
 
 import (
 	"fmt"
+	"io"
 	"runtime"
 	"unsafe"
 )
@@ -16,6 +17,64 @@ type Group struct {
 	id C.hid_t
 }
 
+// GroupStorageType is how the links of a group are stored.
+type GroupStorageType C.H5G_storage_type_t
+
+const (
+	G_STORAGE_TYPE_UNKNOWN      GroupStorageType = -1 // unknown storage type
+	G_STORAGE_TYPE_SYMBOL_TABLE GroupStorageType = 0  // symbol table of the earliest file format
+	G_STORAGE_TYPE_COMPACT      GroupStorageType = 1  // link messages in the object header
+	G_STORAGE_TYPE_DENSE        GroupStorageType = 2  // fractal heap indexed by a B-tree
+)
+
+func (t GroupStorageType) String() string {
+	switch t {
+	case G_STORAGE_TYPE_SYMBOL_TABLE:
+		return "symbol table"
+	case G_STORAGE_TYPE_COMPACT:
+		return "compact"
+	case G_STORAGE_TYPE_DENSE:
+		return "dense"
+	}
+	return "unknown"
+}
+
+// GroupInfo holds the metadata of the links of a group.
+type GroupInfo struct {
+	StorageType GroupStorageType // how the links are stored
+	NumLinks    uint             // number of links in the group
+	MaxCrtOrder int64            // current maximum creation order value
+	Mounted     bool             // whether a file is mounted on the group
+}
+
+func newGroupInfo(c *C.H5G_info_t) *GroupInfo {
+	return &GroupInfo{
+		StorageType: GroupStorageType(c.storage_type),
+		NumLinks:    uint(c.nlinks),
+		MaxCrtOrder: int64(c.max_corder),
+		Mounted:     c.mounted > 0,
+	}
+}
+
+func groupInfo(id C.hid_t) (*GroupInfo, error) {
+	var info C.H5G_info_t
+	if err := h5err(C.H5Gget_info(id, &info)); err != nil {
+		return nil, err
+	}
+	return newGroupInfo(&info), nil
+}
+
+func groupInfoByName(id C.hid_t, name string) (*GroupInfo, error) {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	var info C.H5G_info_t
+	if err := h5err(C.H5Gget_info_by_name(id, c_name, &info, C.H5P_DEFAULT)); err != nil {
+		return nil, err
+	}
+	return newGroupInfo(&info), nil
+}
+
 func numObjects(id C.hid_t) (uint, error) {
 	var info C.H5G_info_t
 	err := h5err(C.H5Gget_info(id, &info))
@@ -23,19 +82,7 @@ func numObjects(id C.hid_t) (uint, error) {
 }
 
 func objectNameByIndex(id C.hid_t, idx uint) (string, error) {
-	cidx := C.hsize_t(idx)
-	size := C.H5Lget_name_by_idx(id, cdot, C.H5_INDEX_NAME, C.H5_ITER_INC, cidx, nil, 0, C.H5P_DEFAULT)
-	if size < 0 {
-		return "", fmt.Errorf("could not get name")
-	}
-
-	name := make([]C.char, size+1)
-	size = C.H5Lget_name_by_idx(id, cdot, C.H5_INDEX_NAME, C.H5_ITER_INC, cidx, &name[0], C.size_t(size)+1, C.H5P_DEFAULT)
-
-	if size < 0 {
-		return "", fmt.Errorf("could not get name")
-	}
-	return C.GoString(&name[0]), nil
+	return linkNameByIndex(id, INDEX_NAME, idx)
 }
 
 func createGroup(id C.hid_t, name string, link_flags, grp_c_flags, grp_a_flags int) (*Group, error) {
@@ -48,6 +95,18 @@ func createGroup(id C.hid_t, name string, link_flags, grp_c_flags, grp_a_flags i
 	}
 	g := &Group{id: hid}
 	runtime.SetFinalizer(g, (*Group).finalizer)
+	trackID(hid)
+	return g, nil
+}
+
+func createAnonGroup(id C.hid_t, gcpl *PropList) (*Group, error) {
+	hid := C.H5Gcreate_anon(id, gcpl.id, C.H5P_DEFAULT)
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return nil, err
+	}
+	g := &Group{id: hid}
+	runtime.SetFinalizer(g, (*Group).finalizer)
+	trackID(hid)
 	return g, nil
 }
 
@@ -61,6 +120,7 @@ func openGroup(id C.hid_t, name string, gapl_flag C.hid_t) (*Group, error) {
 	}
 	g := &Group{id: hid}
 	runtime.SetFinalizer(g, (*Group).finalizer)
+	trackID(hid)
 	return g, nil
 }
 
@@ -70,11 +130,51 @@ func (g *Group) CreateGroup(name string, link_flags, grp_c_flags, grp_a_flags in
 	return createGroup(g.id, name, C.H5P_DEFAULT, C.H5P_DEFAULT, C.H5P_DEFAULT)
 }
 
+// CreateGroupWith creates a new empty group with the link creation
+// properties lcpl and the group creation properties gcpl.
+func (g *Group) CreateGroupWith(name string, lcpl, gcpl *PropList) (*Group, error) {
+	return createGroup(g.id, name, int(lcpl.id), int(gcpl.id), C.H5P_DEFAULT)
+}
+
+// CreateAnonGroup creates a new empty group in the file of this location
+// without linking it into the hierarchy. It is freed when closed unless
+// it has been linked with Link.
+// hid_t H5Gcreate_anon(hid_t loc_id, hid_t gcpl_id, hid_t gapl_id)
+func (g *Group) CreateAnonGroup(gcpl *PropList) (*Group, error) {
+	return createAnonGroup(g.id, gcpl)
+}
+
 func (g *Group) CreateDataset(name string, dtype *Datatype, dspace *Dataspace, dcpl *PropList) (*Dataset, error) {
 	return createDataset(g.id, name, dtype, dspace, dcpl)
 }
 
+// CreateDatasetWith creates a new dataset in the group with the link
+// creation properties lcpl, e.g. to create the missing intermediate groups
+// of a path like "a/b/c/data".
+func (g *Group) CreateDatasetWith(name string, dtype *Datatype, dspace *Dataspace, lcpl, dcpl *PropList) (*Dataset, error) {
+	return createDatasetWith(g.id, name, dtype, dspace, lcpl, dcpl)
+}
+
+// CreateAnonDataset creates a new dataset in the file of this location
+// without linking it into the hierarchy, so that it can be written in full
+// before being linked with Link. It is freed when closed unless it has been
+// linked.
+// hid_t H5Dcreate_anon(hid_t loc_id, hid_t type_id, hid_t space_id, hid_t dcpl_id, hid_t dapl_id)
+func (g *Group) CreateAnonDataset(dtype *Datatype, dspace *Dataspace, dcpl *PropList) (*Dataset, error) {
+	return createAnonDataset(g.id, dtype, dspace, dcpl)
+}
+
+// Link creates a hard link name at this location to the object obj, such
+// as a dataset created with CreateAnonDataset.
+// herr_t H5Olink(hid_t obj_id, hid_t new_loc_id, const char *new_name, hid_t lcpl_id, hid_t lapl_id)
+func (g *Group) Link(obj Object, name string) error {
+	return linkObject(g.id, obj, name)
+}
+
 func (g *Group) finalizer() {
+	if C.H5Iis_valid(g.id) <= 0 {
+		return // closed by File.CloseWith
+	}
 	err := g.Close()
 	if err != nil {
 		panic(fmt.Sprintf("error closing group: %s", err))
@@ -84,7 +184,12 @@ func (g *Group) finalizer() {
 // Closes the specified group.
 // herr_t H5Gclose(hid_t group_id)
 func (g *Group) Close() error {
-	return h5err(C.H5Gclose(g.id))
+	if g.id > 0 {
+		err := C.H5Gclose(g.id)
+		g.id = 0
+		return h5err(err)
+	}
+	return nil
 }
 
 func (g *Group) Name() string {
@@ -104,16 +209,40 @@ func (g *Group) OpenGroup(name string) (*Group, error) {
 	return openGroup(g.id, name, P_DEFAULT.id)
 }
 
+// OpenGroupWith opens an existing group with the group access properties
+// gapl, e.g. to resolve external links on its path.
+// hid_t H5Gopen2(hid_t loc_id, const char *name, hid_t gapl_id)
+func (g *Group) OpenGroupWith(name string, gapl *PropList) (*Group, error) {
+	return openGroup(g.id, name, gapl.id)
+}
+
+// Opens an existing dataset, given its name or its path from the group.
 func (g *Group) OpenDataset(name string) (*Dataset, error) {
 	return openDataset(g.id, name)
 }
 
+// OpenDatasetWith opens an existing dataset with the dataset access
+// properties dapl, e.g. to size its chunk cache.
+// hid_t H5Dopen2(hid_t loc_id, const char *name, hid_t dapl_id)
+func (g *Group) OpenDatasetWith(name string, dapl *PropList) (*Dataset, error) {
+	return openDatasetWith(g.id, name, dapl)
+}
+
 // Opens a named datatype.
 // hid_t H5Topen2( hid_t loc_id, const char * name, hid_t tapl_id )
 func (g *Group) OpenDatatype(name string, tapl_id int) (*Datatype, error) {
 	return openDatatype(g.id, name, tapl_id)
 }
 
+// CommitDatatype links the transient datatype dtype into the group as name,
+// making it a named datatype that datasets and attributes can share, and
+// that can have attributes itself. Predefined datatypes must be copied
+// first.
+// herr_t H5Tcommit2(hid_t loc_id, const char *name, hid_t dtype_id, hid_t lcpl_id, hid_t tcpl_id, hid_t tapl_id)
+func (g *Group) CommitDatatype(name string, dtype *Datatype) error {
+	return commitDatatype(g.id, name, dtype)
+}
+
 func (g *Group) NumObjects() (uint, error) {
 	return numObjects(g.id)
 }
@@ -122,6 +251,13 @@ func (g *Group) ObjectNameByIndex(idx uint) (string, error) {
 	return objectNameByIndex(g.id, idx)
 }
 
+// LinkNames returns the names of the links in the group, in increasing
+// order of index. Ordering by INDEX_CRT_ORDER requires the group to track
+// link creation order.
+func (g *Group) LinkNames(index IndexType) ([]string, error) {
+	return linkNames(g.id, index)
+}
+
 // Creates a packet table to store fixed-length packets.
 func (g *Group) CreateTable(name string, dtype *Datatype, chunkSize, compression int) (*Table, error) {
 	return createTable(g.id, name, dtype, chunkSize, compression)
@@ -132,7 +268,263 @@ func (g *Group) CreateTableFrom(name string, dtype interface{}, chunkSize, compr
 	return createTableFrom(g.id, name, dtype, chunkSize, compression)
 }
 
+// Creates a packet table to store variable-length packets of elem.
+func (g *Group) CreateVarLenTable(name string, elem *Datatype, chunkSize, compression int) (*Table, error) {
+	return createVarLenTable(g.id, name, elem, chunkSize, compression)
+}
+
 // Opens an existing packet table.
 func (g *Group) OpenTable(name string) (*Table, error) {
 	return openTable(g.id, name)
 }
+
+// ReadTableFields reads nrecords records of the named table, starting at
+// start, into data, which must be a slice of structs. Only the table fields
+// whose names match the struct's fields are read.
+func (g *Group) ReadTableFields(name string, start, nrecords int, data interface{}) error {
+	return readTableFields(g.id, name, start, nrecords, data)
+}
+
+// ReadTableFieldsByIndex reads the table fields at the given indices of
+// nrecords records of the named table, starting at start, into the fields
+// of data's struct type, in order.
+func (g *Group) ReadTableFieldsByIndex(name string, fields []int, start, nrecords int, data interface{}) error {
+	return readTableFieldsByIndex(g.id, name, fields, start, nrecords, data)
+}
+
+// InsertTableRecords inserts the records in data, a slice of structs holding
+// every field of the named table, before record start.
+func (g *Group) InsertTableRecords(name string, start int, data interface{}) error {
+	return insertTableRecords(g.id, name, start, data)
+}
+
+// DeleteTableRecords removes nrecords records of the named table, starting
+// at record start.
+func (g *Group) DeleteTableRecords(name string, start, nrecords int) error {
+	return deleteTableRecords(g.id, name, start, nrecords)
+}
+
+// LinkExists returns whether a link with the given name exists in this
+// group. Every intermediate group of name must exist.
+// htri_t H5Lexists(hid_t loc_id, const char *name, hid_t lapl_id)
+func (g *Group) LinkExists(name string) (bool, error) {
+	return linkExists(g.id, name)
+}
+
+// CreateSoftLink creates a soft link name in this group to the path target,
+// which is resolved when the link is followed and need not exist.
+// herr_t H5Lcreate_soft(const char *link_target, hid_t link_loc_id, const char *link_name, hid_t lcpl_id, hid_t lapl_id)
+func (g *Group) CreateSoftLink(target, name string) error {
+	return createSoftLink(g.id, target, name)
+}
+
+// CreateExternalLink creates a link name in this group to the object at
+// the path obj of the file named file.
+// herr_t H5Lcreate_external(const char *file_name, const char *obj_name, hid_t link_loc_id, const char *link_name, hid_t lcpl_id, hid_t lapl_id)
+func (g *Group) CreateExternalLink(file, obj, name string) error {
+	return createExternalLink(g.id, file, obj, name)
+}
+
+// LinkInfo returns the metadata of the link name in this group, such as
+// whether it is a hard, soft or external link.
+// herr_t H5Lget_info(hid_t link_loc_id, const char *link_name, H5L_info_t *link_buff, hid_t lapl_id)
+func (g *Group) LinkInfo(name string) (*LinkInfo, error) {
+	return linkInfo(g.id, name)
+}
+
+// SoftLinkTarget returns the path the soft link name of this group points to.
+// herr_t H5Lget_val(hid_t link_loc_id, const char *link_name, void *linkval_buff, size_t size, hid_t lapl_id)
+func (g *Group) SoftLinkTarget(name string) (string, error) {
+	return softLinkTarget(g.id, name)
+}
+
+// ExternalLinkTarget returns the file name and object path the external
+// link name of this group points to.
+// herr_t H5Lunpack_elink_val(const void *ext_linkval, size_t link_size, unsigned *flags, const char **filename, const char **obj_path)
+func (g *Group) ExternalLinkTarget(name string) (file, obj string, err error) {
+	return externalLinkTarget(g.id, name)
+}
+
+// Delete removes the link name from this group. The object it points to
+// is freed once no other link refers to it and it is closed, though the
+// space it used in the file is only reclaimed by repacking.
+// herr_t H5Ldelete(hid_t loc_id, const char *name, hid_t lapl_id)
+func (g *Group) Delete(name string) error {
+	return deleteLink(g.id, name)
+}
+
+// Move renames the link src of this group to dst, which may be in another
+// group of the file. Open handles to the object stay valid.
+// herr_t H5Lmove(hid_t src_loc, const char *src_name, hid_t dst_loc, const char *dst_name, hid_t lcpl_id, hid_t lapl_id)
+func (g *Group) Move(src, dst string) error {
+	return moveLink(g.id, src, dst)
+}
+
+// ObjectExists reports whether the path resolves to an object from this group,
+// telling OBJECT_MISSING paths, on which a link does not exist, from
+// OBJECT_DANGLING ones, whose last link exists but resolves to no object,
+// such as a soft link whose target was deleted. Missing links are not
+// errors and print no library errors.
+// htri_t H5Oexists_by_name(hid_t loc_id, const char *name, hid_t lapl_id)
+func (g *Group) ObjectExists(path string) (ObjectState, error) {
+	return objectExists(g.id, path)
+}
+
+// PathExists returns whether every link along path exists, without
+// reporting errors for missing intermediate groups. If followLinks is true
+// the final link must also resolve to an object, so dangling soft and
+// external links are reported as missing.
+// htri_t H5LTpath_valid(hid_t loc_id, const char *path, hbool_t check_object_valid)
+func (g *Group) PathExists(path string, followLinks bool) (bool, error) {
+	return pathExists(g.id, path, followLinks)
+}
+
+// Info returns the object header metadata of the group.
+// herr_t H5Oget_info(hid_t object_id, H5O_info_t *object_info)
+func (g *Group) Info() (*ObjectInfo, error) {
+	return objectInfo(g.id)
+}
+
+// InfoByName returns the object header metadata of the named object,
+// without opening it.
+// herr_t H5Oget_info_by_name(hid_t loc_id, const char *object_name, H5O_info_t *object_info, hid_t lapl_id)
+func (g *Group) InfoByName(name string) (*ObjectInfo, error) {
+	return objectInfoByName(g.id, name)
+}
+
+// GroupInfo returns the metadata of the links of the group, such as how
+// many there are and how they are stored.
+// herr_t H5Gget_info(hid_t group_id, H5G_info_t *group_info)
+func (g *Group) GroupInfo() (*GroupInfo, error) {
+	return groupInfo(g.id)
+}
+
+// GroupInfoByName returns the metadata of the links of the named group,
+// without opening it.
+// herr_t H5Gget_info_by_name(hid_t loc_id, const char *group_name, H5G_info_t *group_info, hid_t lapl_id)
+func (g *Group) GroupInfoByName(name string) (*GroupInfo, error) {
+	return groupInfoByName(g.id, name)
+}
+
+// SetComment sets the comment of the group. An empty comment removes it.
+// herr_t H5Oset_comment(hid_t object_id, const char *comment)
+func (g *Group) SetComment(comment string) error {
+	return setComment(g.id, comment)
+}
+
+// Comment returns the comment of the group, or "" if it has none.
+// ssize_t H5Oget_comment(hid_t object_id, char *comment, size_t bufsize)
+func (g *Group) Comment() (string, error) {
+	return getComment(g.id)
+}
+
+// SetCommentByName sets the comment of the named object, without opening it.
+// herr_t H5Oset_comment_by_name(hid_t loc_id, const char *name, const char *comment, hid_t lapl_id)
+func (g *Group) SetCommentByName(name, comment string) error {
+	return setCommentByName(g.id, name, comment)
+}
+
+// CommentByName returns the comment of the named object, or "" if it has
+// none.
+// ssize_t H5Oget_comment_by_name(hid_t loc_id, const char *name, char *comment, size_t bufsize, hid_t lapl_id)
+func (g *Group) CommentByName(name string) (string, error) {
+	return getCommentByName(g.id, name)
+}
+
+// Creates an attribute attached to the group.
+// hid_t H5Acreate2(hid_t loc_id, const char *attr_name, hid_t type_id, hid_t space_id, hid_t acpl_id, hid_t aapl_id)
+func (g *Group) CreateAttribute(name string, dtype *Datatype, dspace *Dataspace) (*Attribute, error) {
+	return createAttribute(g.id, name, dtype, dspace, P_DEFAULT)
+}
+
+// CreateAttributeWith creates an attribute attached to the group with the
+// attribute creation properties acpl, e.g. for a UTF-8 name.
+func (g *Group) CreateAttributeWith(name string, dtype *Datatype, dspace *Dataspace, acpl *PropList) (*Attribute, error) {
+	return createAttribute(g.id, name, dtype, dspace, acpl)
+}
+
+// AttributeNames returns the names of the attributes of the group, in
+// increasing order of index.
+func (g *Group) AttributeNames(index IndexType) ([]string, error) {
+	return attributeNamesByOrder(g.id, index)
+}
+
+// Opens an attribute attached to the group.
+// hid_t H5Aopen(hid_t obj_id, const char *attr_name, hid_t aapl_id)
+func (g *Group) OpenAttribute(name string) (*Attribute, error) {
+	return openAttribute(g.id, name)
+}
+
+// AttributeExists reports whether the group has an attribute called name.
+// htri_t H5Aexists(hid_t obj_id, const char *attr_name)
+func (g *Group) AttributeExists(name string) (bool, error) {
+	return attributeExists(g.id, name)
+}
+
+// RenameAttribute renames the attribute oldName of the group to newName.
+// herr_t H5Arename(hid_t loc_id, const char *old_attr_name, const char *new_attr_name)
+func (g *Group) RenameAttribute(oldName, newName string) error {
+	return renameAttribute(g.id, oldName, newName)
+}
+
+// DeleteAttribute removes the attribute name from the group.
+// herr_t H5Adelete(hid_t loc_id, const char *attr_name)
+func (g *Group) DeleteAttribute(name string) error {
+	return deleteAttribute(g.id, name)
+}
+
+// Returns the number of attributes attached to the group.
+func (g *Group) NumAttributes() (uint, error) {
+	return numAttributes(g.id)
+}
+
+// Returns the name of the attribute at idx, in name order.
+// ssize_t H5Aget_name_by_idx(hid_t loc_id, const char *obj_name, H5_index_t idx_type, H5_iter_order_t order, hsize_t n, char *name, size_t size, hid_t lapl_id)
+func (g *Group) AttributeNameByIndex(idx uint) (string, error) {
+	return attributeNameByIndex(g.id, idx)
+}
+
+// CreateAttributeByName creates the attribute name of the object objName,
+// a path relative to the group that may go through soft links.
+// hid_t H5Acreate_by_name(hid_t loc_id, const char *obj_name, const char *attr_name, hid_t type_id, hid_t space_id, hid_t acpl_id, hid_t aapl_id, hid_t lapl_id)
+func (g *Group) CreateAttributeByName(objName, name string, dtype *Datatype, dspace *Dataspace) (*Attribute, error) {
+	return createAttributeByName(g.id, objName, name, dtype, dspace, P_DEFAULT)
+}
+
+// OpenAttributeByName opens the attribute name of the object objName,
+// relative to the group.
+// hid_t H5Aopen_by_name(hid_t loc_id, const char *obj_name, const char *attr_name, hid_t aapl_id, hid_t lapl_id)
+func (g *Group) OpenAttributeByName(objName, name string) (*Attribute, error) {
+	return openAttributeByName(g.id, objName, name)
+}
+
+// AttributeExistsByName reports whether the object objName, relative to
+// the group, has an attribute called name.
+// htri_t H5Aexists_by_name(hid_t loc_id, const char *obj_name, const char *attr_name, hid_t lapl_id)
+func (g *Group) AttributeExistsByName(objName, name string) (bool, error) {
+	return attributeExistsByName(g.id, objName, name)
+}
+
+// RenameAttributeByName renames the attribute oldName of the object
+// objName, relative to the group, to newName.
+// herr_t H5Arename_by_name(hid_t loc_id, const char *obj_name, const char *old_attr_name, const char *new_attr_name, hid_t lapl_id)
+func (g *Group) RenameAttributeByName(objName, oldName, newName string) error {
+	return renameAttributeByName(g.id, objName, oldName, newName)
+}
+
+// DeleteAttributeByName removes the attribute name from the object
+// objName, relative to the group.
+// herr_t H5Adelete_by_name(hid_t loc_id, const char *obj_name, const char *attr_name, hid_t lapl_id)
+func (g *Group) DeleteAttributeByName(objName, name string) error {
+	return deleteAttributeByName(g.id, objName, name)
+}
+
+// CreateTableFromCSV creates a packet table of the struct type of schema,
+// whose fields must be numbers or strings, and loads the CSV records read
+// from r into it. String fields become variable-length string members,
+// holding the cells as they are. The header line of r names the fields of
+// each column, with nested structs and arrays flattened like
+// Dataset.WriteCSV does.
+func (g *Group) CreateTableFromCSV(name string, r io.Reader, schema interface{}, chunkSize, compression int) (*Table, error) {
+	return createTableFromCSV(g.id, name, r, schema, chunkSize, compression)
+}
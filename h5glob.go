@@ -0,0 +1,124 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"path"
+	"strings"
+)
+
+// GlobMatch is an object whose path matches the pattern given to Glob.
+type GlobMatch struct {
+	Path string
+	Type ObjectType
+}
+
+// Glob returns the objects whose paths match pattern, with the syntax of
+// path.Match applied to each component, e.g. "/run*/detector[0-9]/waveform".
+// Paths are absolute if pattern is, and relative to the root group if not.
+// The matches are in name order.
+func (f *File) Glob(pattern string) ([]GlobMatch, error) {
+	return glob(f.id, pattern)
+}
+
+// Glob returns the objects whose paths relative to the group match
+// pattern, with the syntax of path.Match applied to each component.
+// The matches are in name order.
+func (g *Group) Glob(pattern string) ([]GlobMatch, error) {
+	return glob(g.id, pattern)
+}
+
+func glob(loc C.hid_t, pattern string) ([]GlobMatch, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	prefix := ""
+	if strings.HasPrefix(pattern, "/") {
+		prefix = "/"
+	}
+
+	// Expand the pattern one component at a time, so that only the groups
+	// on matching paths are listed.
+	paths := []string{prefix}
+	for _, elem := range strings.Split(pattern, "/") {
+		if elem == "" {
+			continue
+		}
+		var next []string
+		for _, dir := range paths {
+			names, err := globGroup(loc, dir, elem)
+			if err != nil {
+				return nil, err
+			}
+			for _, name := range names {
+				next = append(next, dir+name)
+			}
+		}
+		paths = next
+		for i := range paths {
+			paths[i] += "/"
+		}
+	}
+
+	matches := make([]GlobMatch, 0, len(paths))
+	for _, p := range paths {
+		p = strings.TrimSuffix(p, "/")
+		if p == "" {
+			continue
+		}
+		info, err := objectInfoByName(loc, p)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, GlobMatch{Path: p, Type: info.Type})
+	}
+	return matches, nil
+}
+
+// globGroup returns the names of the links of the group dir that match
+// elem and resolve to an object, or nil if dir is not a group.
+func globGroup(loc C.hid_t, dir, elem string) ([]string, error) {
+	if dir != "" && dir != "/" {
+		info, err := objectInfoByName(loc, strings.TrimSuffix(dir, "/"))
+		if err != nil || info.Type != O_TYPE_GROUP {
+			return nil, err
+		}
+	}
+	if !strings.ContainsAny(elem, `*?[\`) {
+		ok, err := pathExists(loc, dir+elem, true)
+		if err != nil || !ok {
+			return nil, err
+		}
+		return []string{elem}, nil
+	}
+
+	if dir == "" {
+		dir = "."
+	}
+	id, err := openObject(loc, dir)
+	if err != nil {
+		return nil, err
+	}
+	defer C.H5Oclose(id)
+	n, err := numObjects(id)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for i := uint(0); i < n; i++ {
+		name, err := objectNameByIndex(id, i)
+		if err != nil {
+			return nil, err
+		}
+		if ok, _ := path.Match(elem, name); !ok {
+			continue
+		}
+		if ok, err := pathExists(id, name, true); err != nil {
+			return nil, err
+		} else if ok {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,71 @@
+package hdf5
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestGlob(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	dspace, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatalf("CreateDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	for _, run := range []string{"run1", "run2", "other"} {
+		g, err := f.CreateGroup(run)
+		if err != nil {
+			t.Fatalf("CreateGroup failed: %s", err)
+		}
+		for _, det := range []string{"detector0", "detector1", "detectorX"} {
+			dg, err := g.CreateGroup(det, 0, 0, 0)
+			if err != nil {
+				t.Fatalf("CreateGroup failed: %s", err)
+			}
+			d, err := dg.CreateDataset("waveform", T_NATIVE_DOUBLE, dspace, P_DEFAULT)
+			if err != nil {
+				t.Fatalf("CreateDataset failed: %s", err)
+			}
+			d.Close()
+			dg.Close()
+		}
+		g.Close()
+	}
+
+	matches, err := f.Glob("/run*/detector[0-9]/waveform")
+	if err != nil {
+		t.Fatalf("Glob failed: %s", err)
+	}
+	want := []GlobMatch{
+		{"/run1/detector0/waveform", O_TYPE_DATASET},
+		{"/run1/detector1/waveform", O_TYPE_DATASET},
+		{"/run2/detector0/waveform", O_TYPE_DATASET},
+		{"/run2/detector1/waveform", O_TYPE_DATASET},
+	}
+	if !reflect.DeepEqual(matches, want) {
+		t.Errorf("Glob returned %v, want %v", matches, want)
+	}
+
+	matches, err = f.Glob("other/*X")
+	if err != nil {
+		t.Fatalf("Glob failed: %s", err)
+	}
+	want = []GlobMatch{{"other/detectorX", O_TYPE_GROUP}}
+	if !reflect.DeepEqual(matches, want) {
+		t.Errorf("Glob returned %v, want %v", matches, want)
+	}
+
+	if matches, err := f.Glob("/run1/detector0/waveform/*"); err != nil || len(matches) != 0 {
+		t.Errorf("Glob below a dataset returned %v, %v", matches, err)
+	}
+	if _, err := f.Glob("/run[1"); err == nil {
+		t.Errorf("expected an error for a malformed pattern")
+	}
+}
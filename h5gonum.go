@@ -0,0 +1,87 @@
+//go:build gonum
+
+package hdf5
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ---- gonum matrix conversion (build with -tags gonum) ----
+
+// ReadMatrix reads a two-dimensional dataset, converted to float64, into a
+// new matrix whose rows are those of the dataset, or its columns if
+// transpose is true. An empty dataset gives an empty matrix.
+func (s *Dataset) ReadMatrix(transpose bool) (*mat.Dense, error) {
+	r, c, err := s.matrixDims()
+	if err != nil {
+		return nil, err
+	}
+	if r*c == 0 {
+		// mat.NewDense panics on zero dimensions.
+		return &mat.Dense{}, nil
+	}
+	data := make([]float64, r*c)
+	if err := s.Read(&data[0], T_NATIVE_DOUBLE); err != nil {
+		return nil, err
+	}
+	m := mat.NewDense(r, c, data)
+	if !transpose {
+		return m, nil
+	}
+	t := mat.NewDense(c, r, nil)
+	t.Copy(m.T())
+	return t, nil
+}
+
+// WriteMatrix writes the matrix m, or its transpose if transpose is true,
+// to a two-dimensional dataset of the same shape.
+func (s *Dataset) WriteMatrix(m mat.Matrix, transpose bool) error {
+	if transpose {
+		m = m.T()
+	}
+	r, c, err := s.matrixDims()
+	if err != nil {
+		return err
+	}
+	if mr, mc := m.Dims(); mr != r || mc != c {
+		return fmt.Errorf("cannot write a %dx%d matrix to a %dx%d dataset", mr, mc, r, c)
+	}
+	if r*c == 0 {
+		return nil
+	}
+
+	// Dense matrices whose rows are contiguous are written in place.
+	var data []float64
+	if d, ok := m.(*mat.Dense); ok {
+		if raw := d.RawMatrix(); raw.Stride == c {
+			data = raw.Data[:r*c]
+		}
+	}
+	if data == nil {
+		data = make([]float64, 0, r*c)
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				data = append(data, m.At(i, j))
+			}
+		}
+	}
+	return s.Write(&data[0], T_NATIVE_DOUBLE)
+}
+
+func (s *Dataset) matrixDims() (int, int, error) {
+	dspace := s.Space()
+	if dspace == nil {
+		return 0, 0, fmt.Errorf("could not get dataspace of dataset %q", s.Name())
+	}
+	defer dspace.Close()
+	dims, err := extentDims(dspace)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(dims) != 2 {
+		return 0, 0, fmt.Errorf("dataset %q has rank %d, not 2", s.Name(), len(dims))
+	}
+	return int(dims[0]), int(dims[1]), nil
+}
@@ -0,0 +1,92 @@
+//go:build gonum
+
+package hdf5
+
+import (
+	"os"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestMatrix(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{3, 4}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	s, err := f.CreateDataset("m", T_NATIVE_DOUBLE, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer s.Close()
+
+	// a view of a larger matrix, whose rows are not contiguous
+	big := mat.NewDense(5, 6, nil)
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 6; j++ {
+			big.Set(i, j, float64(10*i+j))
+		}
+	}
+	sub := big.Slice(1, 4, 1, 5).(*mat.Dense)
+	if raw := sub.RawMatrix(); raw.Stride == raw.Cols {
+		t.Fatalf("the view has contiguous rows")
+	}
+	if err := s.WriteMatrix(sub, false); err != nil {
+		t.Fatalf("WriteMatrix failed: %s", err)
+	}
+	got, err := s.ReadMatrix(false)
+	if err != nil {
+		t.Fatalf("ReadMatrix failed: %s", err)
+	}
+	if !mat.Equal(got, sub) {
+		t.Errorf("ReadMatrix returned\n%v\nwant\n%v", mat.Formatted(got), mat.Formatted(sub))
+	}
+	flat := make([]float64, 12)
+	if err := s.Read(flat, T_NATIVE_DOUBLE); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if flat[0] != 11 || flat[4] != 21 || flat[11] != 34 {
+		t.Errorf("dataset holds %v, want the rows of the view", flat)
+	}
+
+	// transposed
+	tr := mat.NewDense(4, 3, nil)
+	tr.Copy(sub.T())
+	if err := s.WriteMatrix(tr, true); err != nil {
+		t.Fatalf("WriteMatrix transposed failed: %s", err)
+	}
+	got, err = s.ReadMatrix(true)
+	if err != nil {
+		t.Fatalf("ReadMatrix transposed failed: %s", err)
+	}
+	if !mat.Equal(got, tr) {
+		t.Errorf("ReadMatrix transposed returned\n%v\nwant\n%v", mat.Formatted(got), mat.Formatted(tr))
+	}
+
+	if err := s.WriteMatrix(mat.NewDense(4, 3, nil), false); err == nil {
+		t.Errorf("WriteMatrix of a 4x3 matrix to a 3x4 dataset succeeded")
+	}
+	vspace, err := CreateSimpleDataspace([]uint{12}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer vspace.Close()
+	v, err := f.CreateDataset("v", T_NATIVE_DOUBLE, vspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer v.Close()
+	if _, err := v.ReadMatrix(false); err == nil {
+		t.Errorf("ReadMatrix of a one-dimensional dataset succeeded")
+	}
+	if err := v.WriteMatrix(sub, false); err == nil {
+		t.Errorf("WriteMatrix to a one-dimensional dataset succeeded")
+	}
+}
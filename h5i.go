@@ -37,5 +37,6 @@ func getFile(id C.hid_t) *File {
 	if fid < 0 {
 		return nil
 	}
+	trackID(fid)
 	return &File{fid}
 }
@@ -0,0 +1,102 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Handle is an identifier of the library, a hid_t, as held by other cgo
+// code, which converts it with Handle(id) and C.hid_t(h).
+type Handle int64
+
+// FileHandle, GroupHandle and DatasetHandle are the identifiers of a
+// file, group and dataset, typed so they are not mistaken for each other,
+// or for other integers.
+type (
+	FileHandle    Handle
+	GroupHandle   Handle
+	DatasetHandle Handle
+)
+
+// Handle returns the identifier of the file, which remains owned by f,
+// and is valid until f is closed.
+func (f *File) Handle() FileHandle {
+	return FileHandle(f.id)
+}
+
+// Handle returns the identifier of the group, which remains owned by g,
+// and is valid until g is closed.
+func (g *Group) Handle() GroupHandle {
+	return GroupHandle(g.id)
+}
+
+// Handle returns the identifier of the dataset, which remains owned by s,
+// and is valid until s is closed.
+func (s *Dataset) Handle() DatasetHandle {
+	return DatasetHandle(s.id)
+}
+
+// FileFromId returns a File of the identifier of an open file, held by
+// other code. The reference count of the identifier is incremented, so
+// closing the File, and closing the identifier by the other code, each
+// release only their own reference.
+// int H5Iinc_ref(hid_t obj_id)
+func FileFromId(h Handle) (*File, error) {
+	id, err := refID(h, C.H5I_FILE)
+	if err != nil {
+		return nil, err
+	}
+	return newFile(id), nil
+}
+
+// GroupFromId returns a Group of the identifier of an open group, held by
+// other code, whose reference count is incremented as by FileFromId.
+// int H5Iinc_ref(hid_t obj_id)
+func GroupFromId(h Handle) (*Group, error) {
+	id, err := refID(h, C.H5I_GROUP)
+	if err != nil {
+		return nil, err
+	}
+	g := &Group{id: id}
+	runtime.SetFinalizer(g, (*Group).finalizer)
+	trackID(id)
+	return g, nil
+}
+
+// DatasetFromId returns a Dataset of the identifier of an open dataset,
+// held by other code, whose reference count is incremented as by
+// FileFromId.
+// int H5Iinc_ref(hid_t obj_id)
+func DatasetFromId(h Handle) (*Dataset, error) {
+	id, err := refID(h, C.H5I_DATASET)
+	if err != nil {
+		return nil, err
+	}
+	return newDataset(id), nil
+}
+
+// refID checks that h is a valid identifier of type typ, and increments
+// its reference count.
+func refID(h Handle, typ C.H5I_type_t) (C.hid_t, error) {
+	id := C.hid_t(h)
+	if C.H5Iis_valid(id) <= 0 {
+		return 0, fmt.Errorf("hdf5: %d is not a valid identifier", h)
+	}
+	if got := C.H5Iget_type(id); got != typ {
+		return 0, fmt.Errorf("hdf5: identifier %d is of a %s, not a %s: %w", h, idTypeName(got), idTypeName(typ), ErrTypeMismatch)
+	}
+	if err := h5err(C.herr_t(C.H5Iinc_ref(id))); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func idTypeName(typ C.H5I_type_t) string {
+	if s, ok := idTypeNames[typ]; ok {
+		return s
+	}
+	return "object"
+}
@@ -0,0 +1,159 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// --- Identifier tracking ---
+
+// handles records where the identifiers the package opens were created,
+// while tracking is on.
+var handles struct {
+	sync.Mutex
+	w      io.Writer
+	stacks map[C.hid_t]string
+}
+
+// TrackHandles turns on recording the stack trace of the creation of each
+// identifier the package opens, for OpenObjects and DumpOpenObjects, and
+// writing a warning to w when a file is closed while objects opened
+// through it are still open, which keeps the file open. A nil w turns
+// tracking off and forgets the recorded identifiers. Tracking slows down
+// opening objects, and is meant for debugging.
+func TrackHandles(w io.Writer) {
+	handles.Lock()
+	defer handles.Unlock()
+	handles.w = w
+	if w == nil {
+		handles.stacks = nil
+	} else if handles.stacks == nil {
+		handles.stacks = make(map[C.hid_t]string)
+	}
+}
+
+// trackID records the creation of id, if tracking is on.
+func trackID(id C.hid_t) {
+	handles.Lock()
+	defer handles.Unlock()
+	if handles.stacks == nil || id <= 0 {
+		return
+	}
+	pc := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pc[:runtime.Callers(3, pc)])
+	var b strings.Builder
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "\t%s\n\t\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	handles.stacks[id] = b.String()
+}
+
+// OpenObject is an identifier opened by the package and still open.
+type OpenObject struct {
+	ID    int
+	Type  string // "file", "group", "dataset", ...
+	Name  string // path of the object, if it has one
+	Stack string // stack trace of its creation
+}
+
+func (o OpenObject) String() string {
+	s := fmt.Sprintf("%s %d", o.Type, o.ID)
+	if o.Name != "" {
+		s += fmt.Sprintf(" %q", o.Name)
+	}
+	return s
+}
+
+var idTypeNames = map[C.H5I_type_t]string{
+	C.H5I_FILE:        "file",
+	C.H5I_GROUP:       "group",
+	C.H5I_DATATYPE:    "datatype",
+	C.H5I_DATASPACE:   "dataspace",
+	C.H5I_DATASET:     "dataset",
+	C.H5I_ATTR:        "attribute",
+	C.H5I_GENPROP_LST: "property list",
+}
+
+func describeID(id C.hid_t, stack string) OpenObject {
+	typ, ok := idTypeNames[C.H5Iget_type(id)]
+	if !ok {
+		typ = "object"
+	}
+	o := OpenObject{ID: int(id), Type: typ, Stack: stack}
+	switch typ {
+	case "file":
+		o.Name = (&File{id: id}).FileName()
+	case "group", "dataset", "datatype":
+		o.Name = getName(id)
+	}
+	return o
+}
+
+// OpenObjects returns the identifiers recorded since tracking was turned
+// on that are still open, in the order they were opened.
+func OpenObjects() []OpenObject {
+	handles.Lock()
+	defer handles.Unlock()
+	var objs []OpenObject
+	for id, stack := range handles.stacks {
+		if C.H5Iis_valid(id) <= 0 {
+			delete(handles.stacks, id)
+			continue
+		}
+		objs = append(objs, describeID(id, stack))
+	}
+	sort.Slice(objs, func(i, j int) bool { return objs[i].ID < objs[j].ID })
+	return objs
+}
+
+// DumpOpenObjects writes the objects returned by OpenObjects to w, each
+// with the stack trace of its creation.
+func DumpOpenObjects(w io.Writer) error {
+	objs := OpenObjects()
+	if _, err := fmt.Fprintf(w, "%d open objects\n", len(objs)); err != nil {
+		return err
+	}
+	for _, o := range objs {
+		if _, err := fmt.Fprintf(w, "%s opened at\n%s", o, o.Stack); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// warnOpenObjects warns, if tracking is on, about the objects opened
+// through the file fid that are still open as it is closed.
+func warnOpenObjects(fid C.hid_t) {
+	handles.Lock()
+	w := handles.w
+	handles.Unlock()
+	if w == nil {
+		return
+	}
+	ids, err := fileObjectIDs(fid, childObjects|C.H5F_OBJ_LOCAL)
+	if err != nil || len(ids) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "hdf5: closing file %q with %d objects still open\n", (&File{id: fid}).FileName(), len(ids))
+	handles.Lock()
+	defer handles.Unlock()
+	for _, id := range ids {
+		o := describeID(id, handles.stacks[id])
+		if o.Stack == "" {
+			fmt.Fprintf(w, "\t%s\n", o)
+		} else {
+			fmt.Fprintf(w, "\t%s opened at\n%s", o, o.Stack)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package hdf5
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestTrackHandles(t *testing.T) {
+	var warnings bytes.Buffer
+	TrackHandles(&warnings)
+	defer TrackHandles(nil)
+
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	g, err := f.CreateGroup("leaked")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close failed: %s", err)
+	}
+	if w := warnings.String(); !strings.Contains(w, "1 objects still open") || !strings.Contains(w, `group`) {
+		t.Errorf("unexpected warning %q", w)
+	}
+
+	objs := OpenObjects()
+	if len(objs) != 1 || objs[0].Type != "group" || objs[0].Name != "/leaked" {
+		t.Fatalf("OpenObjects returned %v", objs)
+	}
+	if !strings.Contains(objs[0].Stack, "TestTrackHandles") {
+		t.Errorf("stack does not show the creation:\n%s", objs[0].Stack)
+	}
+	var dump bytes.Buffer
+	if err := DumpOpenObjects(&dump); err != nil {
+		t.Fatalf("DumpOpenObjects failed: %s", err)
+	}
+	if !strings.HasPrefix(dump.String(), "1 open objects\ngroup") {
+		t.Errorf("DumpOpenObjects wrote %q", dump.String())
+	}
+
+	g.Close()
+	if objs := OpenObjects(); len(objs) != 0 {
+		t.Errorf("OpenObjects returned %v after closing", objs)
+	}
+}
+
+func TestFromId(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	g, err := f.CreateGroup("shared")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	defer g.Close()
+
+	// another reference to the group, closed independently
+	g2, err := GroupFromId(Handle(g.Handle()))
+	if err != nil {
+		t.Fatalf("GroupFromId failed: %s", err)
+	}
+	if err := g2.Close(); err != nil {
+		t.Fatalf("Close failed: %s", err)
+	}
+	if g.Name() != "/shared" {
+		t.Errorf("group not open after closing the other reference")
+	}
+
+	f2, err := FileFromId(Handle(f.Handle()))
+	if err != nil {
+		t.Fatalf("FileFromId failed: %s", err)
+	}
+	if err := f2.Close(); err != nil {
+		t.Fatalf("Close failed: %s", err)
+	}
+
+	if _, err := DatasetFromId(Handle(g.Handle())); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("DatasetFromId of a group returned %v", err)
+	}
+	if _, err := FileFromId(-1); err == nil {
+		t.Errorf("FileFromId of an invalid identifier succeeded")
+	}
+}
@@ -0,0 +1,368 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// #include <string.h>
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+// JSONObject describes a group, dataset or named datatype in the JSON
+// documents produced by ExportJSON and read by ImportJSON.
+type JSONObject struct {
+	// Kind is "group", "dataset" or "datatype".
+	Kind string `json:"kind"`
+	// Type is the datatype of a dataset or named datatype, in the DDL
+	// syntax of h5dump, e.g. "H5T_STD_I32LE".
+	Type string `json:"type,omitempty"`
+	// Shape holds the dimensions of a dataset; datasets without a shape
+	// are scalars. Datasets with a null dataspace are exported without
+	// shape or data, and so are imported as scalars.
+	Shape []uint `json:"shape,omitempty"`
+	// Data holds the values of a dataset in row-major order. Datasets
+	// without data are created filled with their fill value.
+	Data []interface{} `json:"data,omitempty"`
+	// Attributes holds the attributes of the object, by name.
+	Attributes map[string]*JSONAttribute `json:"attributes,omitempty"`
+	// Members holds the objects of a group, by link name.
+	Members map[string]*JSONObject `json:"members,omitempty"`
+}
+
+// JSONAttribute describes an attribute in the JSON documents produced by
+// ExportJSON and read by ImportJSON. Attributes with a null dataspace have
+// null data and no shape.
+type JSONAttribute struct {
+	Type  string        `json:"type"`
+	Shape []uint        `json:"shape,omitempty"`
+	Data  []interface{} `json:"data"`
+}
+
+// ExportJSON writes the hierarchy of the file, with its attributes and
+// the data of datasets of at most maxElements elements, as a JSON document
+// that ImportJSON reads back. Larger datasets are described without data.
+func (f *File) ExportJSON(w io.Writer, maxElements int) error {
+	return exportJSON(f.id, w, maxElements)
+}
+
+// ExportJSON writes the hierarchy of the group, with its attributes and
+// the data of datasets of at most maxElements elements, as a JSON document
+// that ImportJSON reads back. Larger datasets are described without data.
+func (g *Group) ExportJSON(w io.Writer, maxElements int) error {
+	return exportJSON(g.id, w, maxElements)
+}
+
+// ImportJSON creates the attributes and members described by a JSON
+// document, as written by ExportJSON, in the root group of the file.
+func (f *File) ImportJSON(r io.Reader) error {
+	return importJSON(f.id, r)
+}
+
+// ImportJSON creates the attributes and members described by a JSON
+// document, as written by ExportJSON, in the group.
+func (g *Group) ImportJSON(r io.Reader) error {
+	return importJSON(g.id, r)
+}
+
+func exportJSON(loc C.hid_t, w io.Writer, maxElements int) error {
+	root, err := newJSONTree(loc, maxElements)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(root)
+}
+
+func newJSONTree(loc C.hid_t, maxElements int) (*JSONObject, error) {
+	root := &JSONObject{Kind: "group"}
+	var err error
+	if root.Attributes, err = jsonAttributes(loc); err != nil {
+		return nil, err
+	}
+
+	objects := map[string]*JSONObject{"": root}
+	err = walkObjects(loc, func(name string, info *ObjectInfo) error {
+		id, err := openObject(loc, name)
+		if err != nil {
+			return err
+		}
+		defer C.H5Oclose(id)
+
+		o := &JSONObject{}
+		switch info.Type {
+		case O_TYPE_GROUP:
+			o.Kind = "group"
+		case O_TYPE_DATASET:
+			o.Kind = "dataset"
+			err = o.describeDataset(id, maxElements)
+		case O_TYPE_NAMED_DATATYPE:
+			o.Kind = "datatype"
+			o.Type, err = datatypeText(id)
+		default:
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if o.Attributes, err = jsonAttributes(id); err != nil {
+			return err
+		}
+
+		dir, base := path.Split(name)
+		parent := objects[strings.TrimSuffix(dir, "/")]
+		if parent.Members == nil {
+			parent.Members = make(map[string]*JSONObject)
+		}
+		parent.Members[base] = o
+		objects[name] = o
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+func (o *JSONObject) describeDataset(id C.hid_t, maxElements int) error {
+	tid := C.H5Dget_type(id)
+	if err := h5err(C.herr_t(int(tid))); err != nil {
+		return err
+	}
+	defer C.H5Tclose(tid)
+	var err error
+	if o.Type, err = datatypeText(tid); err != nil {
+		return err
+	}
+	shape, npoints, err := jsonShape(C.H5Dget_space(id))
+	if err != nil {
+		return err
+	}
+	o.Shape = shape
+	if npoints > maxElements || isNullShape(shape) {
+		return nil
+	}
+	o.Data, err = readValues(tid, npoints, func(mtype C.hid_t, buf unsafe.Pointer) error {
+		return h5err(C.H5Dread(id, mtype, C.H5S_ALL, C.H5S_ALL, C.H5P_DEFAULT, buf))
+	})
+	return err
+}
+
+// jsonShape returns the dimensions, nil for a scalar and empty for a null
+// dataspace, and the number of elements of the dataspace id, which it
+// closes.
+func jsonShape(id C.hid_t) ([]uint, int, error) {
+	s, err := newDataspaceFromId(id)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer s.Close()
+
+	switch s.SimpleExtentType() {
+	case S_SIMPLE:
+		dims, _, err := s.SimpleExtentDims()
+		return dims, s.SimpleExtentNPoints(), err
+	case S_SCALAR:
+		return nil, 1, nil
+	case S_NULL:
+		return []uint{}, 0, nil
+	}
+	return nil, 0, fmt.Errorf("unknown dataspace class %d", s.SimpleExtentType())
+}
+
+// isNullShape reports whether shape, from jsonShape, is that of a null
+// dataspace, which holds no elements at all.
+func isNullShape(shape []uint) bool {
+	return shape != nil && len(shape) == 0
+}
+
+func jsonAttributes(id C.hid_t) (map[string]*JSONAttribute, error) {
+	names, err := attributeNames(id)
+	if err != nil || len(names) == 0 {
+		return nil, err
+	}
+	attrs := make(map[string]*JSONAttribute, len(names))
+	for _, name := range names {
+		a, err := openAttribute(id, name)
+		if err != nil {
+			return nil, err
+		}
+		attrs[name], err = newJSONAttribute(a)
+		a.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return attrs, nil
+}
+
+func newJSONAttribute(a *Attribute) (*JSONAttribute, error) {
+	tid := C.H5Aget_type(a.id)
+	if err := h5err(C.herr_t(int(tid))); err != nil {
+		return nil, err
+	}
+	defer C.H5Tclose(tid)
+
+	attr := &JSONAttribute{}
+	var err error
+	if attr.Type, err = datatypeText(tid); err != nil {
+		return nil, err
+	}
+	shape, npoints, err := jsonShape(C.H5Aget_space(a.id))
+	if err != nil {
+		return nil, err
+	}
+	attr.Shape = shape
+	if isNullShape(shape) {
+		return attr, nil
+	}
+	attr.Data, err = readValues(tid, npoints, func(mtype C.hid_t, buf unsafe.Pointer) error {
+		return h5err(C.H5Aread(a.id, mtype, buf))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attr, nil
+}
+
+func importJSON(loc C.hid_t, r io.Reader) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var root JSONObject
+	if err := dec.Decode(&root); err != nil {
+		return err
+	}
+	if root.Kind != "" && root.Kind != "group" {
+		return fmt.Errorf("cannot import a %s into a group", root.Kind)
+	}
+	if err := createJSONAttributes(loc, root.Attributes); err != nil {
+		return err
+	}
+	return createJSONMembers(loc, "", root.Members)
+}
+
+// sortedKeys returns the keys of m in order, so that objects are created
+// in a reproducible order.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func createJSONMembers(loc C.hid_t, prefix string, members map[string]*JSONObject) error {
+	for _, name := range sortedKeys(members) {
+		if err := createJSONObject(loc, name, members[name]); err != nil {
+			return fmt.Errorf("%s%s: %w", prefix, name, err)
+		}
+	}
+	return nil
+}
+
+func createJSONObject(loc C.hid_t, name string, o *JSONObject) error {
+	switch o.Kind {
+	case "group":
+		g, err := createGroup(loc, name, C.H5P_DEFAULT, C.H5P_DEFAULT, C.H5P_DEFAULT)
+		if err != nil {
+			return err
+		}
+		defer g.Close()
+		if err := createJSONAttributes(g.id, o.Attributes); err != nil {
+			return err
+		}
+		return createJSONMembers(g.id, name+"/", o.Members)
+
+	case "dataset":
+		dtype, err := NewDatatypeFromText(o.Type)
+		if err != nil {
+			return err
+		}
+		defer dtype.Close()
+		dspace, err := jsonDataspace(o.Shape)
+		if err != nil {
+			return err
+		}
+		defer dspace.Close()
+		if o.Data != nil && len(o.Data) != dspace.SimpleExtentNPoints() {
+			return fmt.Errorf("got %d values for %d elements", len(o.Data), dspace.SimpleExtentNPoints())
+		}
+		d, err := createDataset(loc, name, dtype, dspace, P_DEFAULT)
+		if err != nil {
+			return err
+		}
+		defer d.Close()
+		err = writeValues(dtype.id, o.Data, func(mtype C.hid_t, buf unsafe.Pointer) error {
+			return h5err(C.H5Dwrite(d.id, mtype, C.H5S_ALL, C.H5S_ALL, C.H5P_DEFAULT, buf))
+		})
+		if err != nil {
+			return err
+		}
+		return createJSONAttributes(d.id, o.Attributes)
+
+	case "datatype":
+		dtype, err := NewDatatypeFromText(o.Type)
+		if err != nil {
+			return err
+		}
+		defer dtype.Close()
+		if err := commitDatatype(loc, name, dtype); err != nil {
+			return err
+		}
+		return createJSONAttributes(dtype.id, o.Attributes)
+	}
+	return fmt.Errorf("unknown object kind %q", o.Kind)
+}
+
+func jsonDataspace(shape []uint) (*Dataspace, error) {
+	if shape == nil {
+		return CreateDataspace(S_SCALAR)
+	}
+	return CreateSimpleDataspace(shape, nil)
+}
+
+func createJSONAttributes(id C.hid_t, attrs map[string]*JSONAttribute) error {
+	for _, name := range sortedKeys(attrs) {
+		if err := createJSONAttribute(id, name, attrs[name]); err != nil {
+			return fmt.Errorf("attribute %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func createJSONAttribute(id C.hid_t, name string, attr *JSONAttribute) error {
+	dtype, err := NewDatatypeFromText(attr.Type)
+	if err != nil {
+		return err
+	}
+	defer dtype.Close()
+	var dspace *Dataspace
+	if attr.Data == nil && attr.Shape == nil {
+		dspace, err = CreateDataspace(S_NULL)
+	} else {
+		dspace, err = jsonDataspace(attr.Shape)
+	}
+	if err != nil {
+		return err
+	}
+	defer dspace.Close()
+	if len(attr.Data) != dspace.SimpleExtentNPoints() {
+		return fmt.Errorf("got %d values for %d elements", len(attr.Data), dspace.SimpleExtentNPoints())
+	}
+	a, err := createAttribute(id, name, dtype, dspace, P_DEFAULT)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+	return writeValues(dtype.id, attr.Data, func(mtype C.hid_t, buf unsafe.Pointer) error {
+		return h5err(C.H5Awrite(a.id, mtype, buf))
+	})
+}
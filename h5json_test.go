@@ -0,0 +1,112 @@
+package hdf5
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	const template = `{
+  "kind": "group",
+  "attributes": {
+    "title": {
+      "type": "H5T_STRING { STRSIZE 16; STRPAD H5T_STR_NULLTERM; CSET H5T_CSET_ASCII; CTYPE H5T_C_S1; }",
+      "data": ["run 42"]
+    },
+    "empty": {"type": "H5T_STD_I32LE", "data": null}
+  },
+  "members": {
+    "calib": {
+      "kind": "group",
+      "members": {
+        "gains": {
+          "kind": "dataset",
+          "type": "H5T_IEEE_F64LE",
+          "shape": [2, 3],
+          "data": [1, 1.5, 2, 2.5, 3, 3.5],
+          "attributes": {
+            "channel": {"type": "H5T_STD_U16LE", "shape": [2], "data": [7, 9]}
+          }
+        }
+      }
+    },
+    "counts": {"kind": "dataset", "type": "H5T_STD_I64LE", "shape": [1000]}
+  }
+}`
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	if err := f.ImportJSON(strings.NewReader(template)); err != nil {
+		t.Fatalf("ImportJSON failed: %s", err)
+	}
+
+	dset, err := f.OpenDataset("/calib/gains")
+	if err != nil {
+		t.Fatalf("OpenDataset failed: %s", err)
+	}
+	defer dset.Close()
+	gains := make([]float64, 6)
+	if err := dset.Read(gains, T_NATIVE_DOUBLE); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if gains[5] != 3.5 {
+		t.Errorf("gains = %v", gains)
+	}
+
+	var buf bytes.Buffer
+	if err := f.ExportJSON(&buf, 100); err != nil {
+		t.Fatalf("ExportJSON failed: %s", err)
+	}
+	if !strings.Contains(buf.String(), `"run 42"`) {
+		t.Errorf("exported document lacks the title attribute:\n%s", buf.String())
+	}
+	var doc JSONObject
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("exported document does not decode: %s", err)
+	}
+	if empty := doc.Attributes["empty"]; empty == nil || empty.Data != nil || empty.Shape != nil {
+		t.Errorf("attribute with a null dataspace exported as %+v", empty)
+	}
+
+	const copyName = "ex_json_copy.h5"
+	g, err := CreateFile(copyName, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(copyName)
+	defer g.Close()
+	if err := g.ImportJSON(&buf); err != nil {
+		t.Fatalf("ImportJSON failed: %s", err)
+	}
+	r, err := Diff(f, g, nil)
+	if err != nil {
+		t.Fatalf("Diff failed: %s", err)
+	}
+	if !r.Equal() {
+		t.Errorf("imported file differs:\n%s", r)
+	}
+	a, err := g.OpenAttribute("empty")
+	if err != nil {
+		t.Fatalf("OpenAttribute failed: %s", err)
+	}
+	defer a.Close()
+	if space := a.Space(); space == nil {
+		t.Errorf("attribute has no dataspace")
+	} else {
+		if class := space.SimpleExtentType(); class != S_NULL {
+			t.Errorf("attribute imported with a %d dataspace, want S_NULL", class)
+		}
+		space.Close()
+	}
+
+	bad := `{"members": {"x": {"kind": "dataset", "type": "H5T_STD_I32LE", "shape": [2], "data": [1]}}}`
+	if err := g.ImportJSON(strings.NewReader(bad)); err == nil {
+		t.Errorf("expected an error for mismatched data")
+	}
+}
@@ -0,0 +1,278 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// #include <string.h>
+//
+// static haddr_t _go_hdf5_link_address(H5L_info_t *info) { return info->u.address; }
+// static size_t _go_hdf5_link_val_size(H5L_info_t *info) { return info->u.val_size; }
+import "C"
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// IndexType is the index by which links and attributes are ordered.
+type IndexType C.H5_index_t
+
+const (
+	INDEX_UNKNOWN   IndexType = -1 // Unknown index type
+	INDEX_NAME      IndexType = 0  // Index on names
+	INDEX_CRT_ORDER IndexType = 1  // Index on creation order
+)
+
+// LinkType is the kind of a link.
+type LinkType C.H5L_type_t
+
+const (
+	L_TYPE_ERROR    LinkType = -1 // invalid link type
+	L_TYPE_HARD     LinkType = 0  // hard link to an object of the file
+	L_TYPE_SOFT     LinkType = 1  // soft link to a path in the file
+	L_TYPE_EXTERNAL LinkType = 64 // external link to an object of another file
+)
+
+func (t LinkType) String() string {
+	switch t {
+	case L_TYPE_HARD:
+		return "hard"
+	case L_TYPE_SOFT:
+		return "soft"
+	case L_TYPE_EXTERNAL:
+		return "external"
+	}
+	return "unknown"
+}
+
+// LinkInfo holds the metadata of a link, as opposed to that of the object
+// it points to. Soft and external links may point to nothing.
+type LinkInfo struct {
+	Type          LinkType // kind of link
+	HasCrtOrder   bool     // whether CreationOrder is tracked
+	CreationOrder int64    // position of the link in creation order
+	CharSet       CharSet  // character set of the link name
+	Addr          uint64   // address of the object header of hard links
+	ValueSize     uint     // size of the value of soft and external links
+}
+
+func linkInfo(id C.hid_t, name string) (*LinkInfo, error) {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	var info C.H5L_info_t
+	if err := h5err(C.H5Lget_info(id, c_name, &info, C.H5P_DEFAULT)); err != nil {
+		return nil, err
+	}
+	l := &LinkInfo{
+		Type:          LinkType(info._type),
+		HasCrtOrder:   info.corder_valid > 0,
+		CreationOrder: int64(info.corder),
+		CharSet:       CharSet(info.cset),
+	}
+	if l.Type == L_TYPE_HARD {
+		l.Addr = uint64(C._go_hdf5_link_address(&info))
+	} else {
+		l.ValueSize = uint(C._go_hdf5_link_val_size(&info))
+	}
+	return l, nil
+}
+
+// linkValue returns the value of the soft or external link name, which is
+// of type want.
+func linkValue(id C.hid_t, name string, want LinkType) ([]byte, error) {
+	info, err := linkInfo(id, name)
+	if err != nil {
+		return nil, err
+	}
+	if info.Type != want {
+		return nil, fmt.Errorf("hdf5: %q is a %s link, not a %s link", name, info.Type, want)
+	}
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	buf := make([]byte, info.ValueSize+1)
+	if err := h5err(C.H5Lget_val(id, c_name, unsafe.Pointer(&buf[0]), C.size_t(len(buf)), C.H5P_DEFAULT)); err != nil {
+		return nil, err
+	}
+	return buf[:info.ValueSize], nil
+}
+
+func softLinkTarget(id C.hid_t, name string) (string, error) {
+	val, err := linkValue(id, name, L_TYPE_SOFT)
+	if err != nil {
+		return "", err
+	}
+	if i := bytes.IndexByte(val, 0); i >= 0 {
+		val = val[:i]
+	}
+	return string(val), nil
+}
+
+func externalLinkTarget(id C.hid_t, name string) (file, obj string, err error) {
+	val, err := linkValue(id, name, L_TYPE_EXTERNAL)
+	if err != nil {
+		return "", "", err
+	}
+	if len(val) == 0 {
+		return "", "", fmt.Errorf("hdf5: external link %q has no value", name)
+	}
+	var c_file, c_obj *C.char
+	if err := h5err(C.H5Lunpack_elink_val(unsafe.Pointer(&val[0]), C.size_t(len(val)), nil, &c_file, &c_obj)); err != nil {
+		return "", "", err
+	}
+	return C.GoString(c_file), C.GoString(c_obj), nil
+}
+
+func createSoftLink(id C.hid_t, target, name string) error {
+	c_target := C.CString(target)
+	defer C.free(unsafe.Pointer(c_target))
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return h5err(C.H5Lcreate_soft(c_target, id, c_name, C.H5P_DEFAULT, C.H5P_DEFAULT))
+}
+
+func createExternalLink(id C.hid_t, file, obj, name string) error {
+	c_file := C.CString(file)
+	defer C.free(unsafe.Pointer(c_file))
+	c_obj := C.CString(obj)
+	defer C.free(unsafe.Pointer(c_obj))
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return h5err(C.H5Lcreate_external(c_file, c_obj, id, c_name, C.H5P_DEFAULT, C.H5P_DEFAULT))
+}
+
+// ObjectState is the result of ObjectExists.
+type ObjectState int
+
+const (
+	OBJECT_MISSING  ObjectState = 0 // a link along the path does not exist
+	OBJECT_DANGLING ObjectState = 1 // the last link exists but resolves to no object
+	OBJECT_EXISTS   ObjectState = 2 // the path resolves to an object
+)
+
+func (s ObjectState) String() string {
+	switch s {
+	case OBJECT_MISSING:
+		return "missing"
+	case OBJECT_DANGLING:
+		return "dangling"
+	case OBJECT_EXISTS:
+		return "exists"
+	}
+	return "unknown"
+}
+
+// objectExists walks path one link at a time from id, so that missing
+// links are not errors, and reports whether its last link resolves to an
+// object.
+func objectExists(id C.hid_t, path string) (state ObjectState, err error) {
+	quietly(func() {
+		state, err = walkPath(id, path)
+	})
+	return state, err
+}
+
+func walkPath(id C.hid_t, path string) (ObjectState, error) {
+	cur := ""
+	if strings.HasPrefix(path, "/") {
+		cur = "/"
+	}
+	elems := strings.FieldsFunc(path, func(r rune) bool { return r == '/' })
+	for i, elem := range elems {
+		if elem == "." {
+			continue
+		}
+		if cur != "" && cur != "/" {
+			cur += "/"
+		}
+		cur += elem
+		if ok, err := linkExists(id, cur); err != nil || !ok {
+			return OBJECT_MISSING, err
+		}
+
+		c_cur := C.CString(cur)
+		o := C.H5Oexists_by_name(id, c_cur, C.H5P_DEFAULT)
+		C.free(unsafe.Pointer(c_cur))
+		// External links to missing files fail, and dangle like soft
+		// links to missing objects.
+		exists := o > 0
+		if i == len(elems)-1 {
+			if !exists {
+				return OBJECT_DANGLING, nil
+			}
+			return OBJECT_EXISTS, nil
+		}
+		if !exists {
+			return OBJECT_MISSING, nil
+		}
+		if info, err := objectInfoByName(id, cur); err != nil || info.Type != O_TYPE_GROUP {
+			return OBJECT_MISSING, err
+		}
+	}
+	return OBJECT_EXISTS, nil
+}
+
+func linkExists(id C.hid_t, name string) (bool, error) {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	o := C.H5Lexists(id, c_name, C.H5P_DEFAULT)
+	if err := h5err(C.herr_t(o)); err != nil {
+		return false, err
+	}
+	return o > 0, nil
+}
+
+func deleteLink(id C.hid_t, name string) error {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return h5err(C.H5Ldelete(id, c_name, C.H5P_DEFAULT))
+}
+
+func moveLink(id C.hid_t, src, dst string) error {
+	c_src := C.CString(src)
+	defer C.free(unsafe.Pointer(c_src))
+	c_dst := C.CString(dst)
+	defer C.free(unsafe.Pointer(c_dst))
+
+	return h5err(C.H5Lmove(id, c_src, id, c_dst, C.H5P_DEFAULT, C.H5P_DEFAULT))
+}
+
+// linkNameByIndex returns the name of the link at position idx of the
+// group id, in increasing order of the given index.
+func linkNameByIndex(id C.hid_t, index IndexType, idx uint) (string, error) {
+	cidx := C.hsize_t(idx)
+	c_index := C.H5_index_t(index)
+	size := C.H5Lget_name_by_idx(id, cdot, c_index, C.H5_ITER_INC, cidx, nil, 0, C.H5P_DEFAULT)
+	if size < 0 {
+		return "", fmt.Errorf("could not get name")
+	}
+
+	name := make([]C.char, size+1)
+	size = C.H5Lget_name_by_idx(id, cdot, c_index, C.H5_ITER_INC, cidx, &name[0], C.size_t(size)+1, C.H5P_DEFAULT)
+	if size < 0 {
+		return "", fmt.Errorf("could not get name")
+	}
+	return C.GoString(&name[0]), nil
+}
+
+// linkNames returns the names of the links of the group id, in increasing
+// order of the given index.
+func linkNames(id C.hid_t, index IndexType) ([]string, error) {
+	n, err := numObjects(id)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, n)
+	for i := range names {
+		if names[i], err = linkNameByIndex(id, index, uint(i)); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,95 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// --- Logging ---
+
+// logging holds the loggers events are emitted to: that of the file of the
+// object, by file identifier, or else the global one.
+var logging struct {
+	sync.RWMutex
+	global *slog.Logger
+	files  map[C.hid_t]*slog.Logger
+}
+
+// SetLogger sets the logger the package emits events to at the debug
+// level: the opening, creation and closing of files and datasets, and the
+// reads and writes of datasets, with the paths of the objects and the
+// sizes of the transfers. Loggers set with File.SetLogger take precedence.
+// A nil l turns logging off.
+func SetLogger(l *slog.Logger) {
+	logging.Lock()
+	defer logging.Unlock()
+	logging.global = l
+}
+
+// SetLogger sets the logger of the events on the file, and the objects
+// opened through it, until it is closed. Other handles to the same file,
+// from another OpenFile, keep theirs. A nil l reverts to the global logger.
+func (f *File) SetLogger(l *slog.Logger) {
+	logging.Lock()
+	defer logging.Unlock()
+	if l == nil {
+		delete(logging.files, f.id)
+		return
+	}
+	if logging.files == nil {
+		logging.files = make(map[C.hid_t]*slog.Logger)
+	}
+	logging.files[f.id] = l
+}
+
+// logger returns the logger of the events on the object id, or nil if
+// there is none or it discards debug events.
+func logger(id C.hid_t) *slog.Logger {
+	logging.RLock()
+	l, files := logging.global, len(logging.files)
+	logging.RUnlock()
+	if files > 0 {
+		if fid := C.H5Iget_file_id(id); fid >= 0 {
+			logging.RLock()
+			if fl, ok := logging.files[fid]; ok {
+				l = fl
+			}
+			logging.RUnlock()
+			C.H5Fclose(fid)
+		}
+	}
+	if l == nil || !l.Enabled(context.Background(), slog.LevelDebug) {
+		return nil
+	}
+	return l
+}
+
+// forgetFileLogger drops the logger of the file fid as its last reference
+// is closed; File values returned by the File methods of objects share the
+// identifier.
+func forgetFileLogger(fid C.hid_t) {
+	logging.RLock()
+	files := len(logging.files)
+	logging.RUnlock()
+	if files == 0 || C.H5Iget_ref(fid) > 1 {
+		return
+	}
+	logging.Lock()
+	defer logging.Unlock()
+	delete(logging.files, fid)
+}
+
+// transferSize returns the size in bytes of the whole dataset d in the
+// memory datatype dtype.
+func transferSize(d C.hid_t, dtype *Datatype) int64 {
+	sid := C.H5Dget_space(d)
+	if sid < 0 {
+		return -1
+	}
+	defer C.H5Sclose(sid)
+	return int64(C.H5Sget_simple_extent_npoints(sid)) * int64(C.H5Tget_size(dtype.id))
+}
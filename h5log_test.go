@@ -0,0 +1,59 @@
+package hdf5
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	var global, local bytes.Buffer
+	debug := &slog.HandlerOptions{Level: slog.LevelDebug}
+	SetLogger(slog.New(slog.NewTextHandler(&global, debug)))
+	defer SetLogger(nil)
+
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	dspace, err := CreateSimpleDataspace([]uint{3}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("d", T_NATIVE_INT32, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	if err := dset.Write([]int32{1, 2, 3}, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+
+	f.SetLogger(slog.New(slog.NewTextHandler(&local, debug)))
+	// closing another File value of the same identifier keeps the logger
+	if err := dset.File().Close(); err != nil {
+		t.Fatalf("Close failed: %s", err)
+	}
+	if err := dset.Read(make([]int32, 3), T_NATIVE_INT32); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	dset.Close()
+	f.Close()
+
+	for _, want := range []string{`msg="create file" file=` + FNAME, `msg="create dataset" path=/d`, `msg=write path=/d bytes=12`} {
+		if !strings.Contains(global.String(), want) {
+			t.Errorf("global log lacks %q:\n%s", want, global.String())
+		}
+	}
+	for _, want := range []string{`msg=read path=/d bytes=12`, `msg="close dataset" path=/d`, `msg="close file"`} {
+		if !strings.Contains(local.String(), want) {
+			t.Errorf("file log lacks %q:\n%s", want, local.String())
+		}
+	}
+	if strings.Contains(global.String(), "msg=read") {
+		t.Errorf("global log has the events of the file logger:\n%s", global.String())
+	}
+}
@@ -0,0 +1,61 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include "hdf5_hl.h"
+// #include <stdlib.h>
+// #include <string.h>
+import "C"
+
+import (
+	"unsafe"
+)
+
+// ---- HDF5 Lite (H5LT) ----
+
+// NewDatatypeFromText creates a datatype from its DDL description, as
+// printed by h5dump, e.g. "H5T_STD_I32LE" or
+// "H5T_COMPOUND { H5T_NATIVE_INT \"a\"; H5T_NATIVE_DOUBLE \"b\"; }".
+// hid_t H5LTtext_to_dtype(const char *text, H5LT_lang_t lang_type)
+func NewDatatypeFromText(text string) (*Datatype, error) {
+	c_text := C.CString(text)
+	defer C.free(unsafe.Pointer(c_text))
+
+	hid := C.H5LTtext_to_dtype(c_text, C.H5LT_DDL)
+	err := h5err(C.herr_t(int(hid)))
+	if err != nil {
+		return nil, err
+	}
+	return NewDatatype(hid, nil), nil
+}
+
+// Text returns the DDL description of the datatype.
+// herr_t H5LTdtype_to_text(hid_t dtype, char *str, H5LT_lang_t lang_type, size_t *len)
+func (t *Datatype) Text() (string, error) {
+	var sz C.size_t
+	err := h5err(C.H5LTdtype_to_text(t.id, nil, C.H5LT_DDL, &sz))
+	if err != nil {
+		return "", err
+	}
+	sz++
+	buf := make([]C.char, sz)
+	err = h5err(C.H5LTdtype_to_text(t.id, &buf[0], C.H5LT_DDL, &sz))
+	if err != nil {
+		return "", err
+	}
+	return C.GoString(&buf[0]), nil
+}
+
+func pathExists(id C.hid_t, path string, followLinks bool) (bool, error) {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	var c_follow C.hbool_t = 0
+	if followLinks {
+		c_follow = 1
+	}
+	o := C.H5LTpath_valid(id, c_path, c_follow)
+	if err := h5err(C.herr_t(o)); err != nil {
+		return false, err
+	}
+	return o > 0, nil
+}
@@ -0,0 +1,183 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"expvar"
+	"sort"
+	"sync"
+	"time"
+)
+
+// --- I/O metrics ---
+
+// LatencyBounds are the upper bounds, in seconds, of the buckets of the
+// latency histograms of the metrics.
+var LatencyBounds = []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10}
+
+// Histogram is a distribution of observations in seconds, in the form of
+// Prometheus histograms: Counts[i] is the number of observations of at
+// most Bounds[i].
+type Histogram struct {
+	Bounds []float64
+	Counts []uint64
+	Count  uint64  // number of observations
+	Sum    float64 // sum of the observations
+}
+
+// Buckets returns the counts by upper bound, as prometheus.NewConstHistogram
+// takes them.
+func (h *Histogram) Buckets() map[float64]uint64 {
+	b := make(map[float64]uint64, len(h.Bounds))
+	for i, bound := range h.Bounds {
+		b[bound] = h.Counts[i]
+	}
+	return b
+}
+
+func (h *Histogram) observe(seconds float64) {
+	if h.Counts == nil {
+		h.Bounds = LatencyBounds
+		h.Counts = make([]uint64, len(LatencyBounds))
+	}
+	for i := sort.SearchFloat64s(h.Bounds, seconds); i < len(h.Bounds); i++ {
+		h.Counts[i]++
+	}
+	h.Count++
+	h.Sum += seconds
+}
+
+func (h Histogram) clone() Histogram {
+	h.Counts = append([]uint64(nil), h.Counts...)
+	return h
+}
+
+// TransferStats count the transfers of one direction.
+type TransferStats struct {
+	Calls   uint64
+	Errors  uint64
+	Bytes   uint64 // bytes transferred in memory datatypes
+	Latency Histogram
+}
+
+func (s *TransferStats) observe(bytes int64, d time.Duration, err error) {
+	s.Calls++
+	if err != nil {
+		s.Errors++
+	} else if bytes > 0 {
+		s.Bytes += uint64(bytes)
+	}
+	s.Latency.observe(d.Seconds())
+}
+
+// IOStats count the reads and writes of datasets.
+type IOStats struct {
+	Read  TransferStats
+	Write TransferStats
+}
+
+func (s IOStats) clone() IOStats {
+	s.Read.Latency = s.Read.Latency.clone()
+	s.Write.Latency = s.Write.Latency.clone()
+	return s
+}
+
+// Metrics are the counts of the dataset transfers of the package since
+// metrics were enabled or reset.
+type Metrics struct {
+	IOStats
+	// Datasets are the counts of each dataset, by "file:path", if enabled.
+	Datasets map[string]IOStats
+}
+
+var metrics struct {
+	sync.Mutex
+	on         bool
+	perDataset bool
+	m          Metrics
+}
+
+// EnableMetrics turns on counting the reads and writes of datasets, also
+// per dataset if perDataset is true, which costs a lookup of the name of
+// the dataset on each transfer.
+func EnableMetrics(perDataset bool) {
+	metrics.Lock()
+	defer metrics.Unlock()
+	metrics.on, metrics.perDataset = true, perDataset
+}
+
+// DisableMetrics turns off counting transfers. The counts are kept.
+func DisableMetrics() {
+	metrics.Lock()
+	defer metrics.Unlock()
+	metrics.on = false
+}
+
+// ResetMetrics sets the counts back to zero.
+func ResetMetrics() {
+	metrics.Lock()
+	defer metrics.Unlock()
+	metrics.m = Metrics{}
+}
+
+// ReadMetrics returns a copy of the current counts.
+func ReadMetrics() Metrics {
+	metrics.Lock()
+	defer metrics.Unlock()
+	m := Metrics{IOStats: metrics.m.IOStats.clone()}
+	if metrics.m.Datasets != nil {
+		m.Datasets = make(map[string]IOStats, len(metrics.m.Datasets))
+		for k, s := range metrics.m.Datasets {
+			m.Datasets[k] = s.clone()
+		}
+	}
+	return m
+}
+
+// MetricsVar returns the metrics as an expvar.Var, to be published with
+// expvar.Publish("hdf5", hdf5.MetricsVar()).
+func MetricsVar() expvar.Var {
+	return expvar.Func(func() interface{} { return ReadMetrics() })
+}
+
+// metricsOn reports whether transfers are counted, so that callers can
+// skip measuring them.
+func metricsOn() bool {
+	metrics.Lock()
+	defer metrics.Unlock()
+	return metrics.on
+}
+
+// observeTransfer counts a read, or a write if write is true, of the
+// dataset d with the memory datatype dtype, which started at start.
+func observeTransfer(d C.hid_t, dtype *Datatype, write bool, start time.Time, err error) {
+	elapsed := time.Since(start)
+	bytes := transferSize(d, dtype)
+	key := ""
+	metrics.Lock()
+	perDataset := metrics.perDataset
+	metrics.Unlock()
+	if perDataset {
+		key = (&File{id: d}).FileName() + ":" + getName(d)
+	}
+
+	metrics.Lock()
+	defer metrics.Unlock()
+	observe := func(s *IOStats) {
+		if write {
+			s.Write.observe(bytes, elapsed, err)
+		} else {
+			s.Read.observe(bytes, elapsed, err)
+		}
+	}
+	observe(&metrics.m.IOStats)
+	if key != "" {
+		if metrics.m.Datasets == nil {
+			metrics.m.Datasets = make(map[string]IOStats)
+		}
+		s := metrics.m.Datasets[key]
+		observe(&s)
+		metrics.m.Datasets[key] = s
+	}
+}
@@ -0,0 +1,63 @@
+package hdf5
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	ResetMetrics()
+	EnableMetrics(true)
+	defer DisableMetrics()
+
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{4}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("d", T_NATIVE_DOUBLE, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	if err := dset.Write([]float64{1, 2, 3, 4}, T_NATIVE_DOUBLE); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := dset.Read(make([]float32, 4), T_NATIVE_FLOAT); err != nil {
+			t.Fatalf("Read failed: %s", err)
+		}
+	}
+
+	m := ReadMetrics()
+	if m.Write.Calls != 1 || m.Write.Bytes != 32 || m.Read.Calls != 2 || m.Read.Bytes != 32 {
+		t.Errorf("ReadMetrics returned %+v", m.IOStats)
+	}
+	if m.Read.Latency.Count != 2 || m.Read.Latency.Counts[len(LatencyBounds)-1] != 2 {
+		t.Errorf("read latency histogram is %+v", m.Read.Latency)
+	}
+	if s, ok := m.Datasets[FNAME+":/d"]; !ok || s.Read.Calls != 2 {
+		t.Errorf("Datasets returned %+v", m.Datasets)
+	}
+
+	var decoded Metrics
+	if err := json.Unmarshal([]byte(MetricsVar().String()), &decoded); err != nil {
+		t.Fatalf("MetricsVar is not JSON: %s", err)
+	}
+	if decoded.Write.Bytes != 32 {
+		t.Errorf("MetricsVar returned %+v", decoded.IOStats)
+	}
+
+	DisableMetrics()
+	dset.Read(make([]float32, 4), T_NATIVE_FLOAT)
+	if m := ReadMetrics(); m.Read.Calls != 2 {
+		t.Errorf("counted %d reads while disabled", m.Read.Calls)
+	}
+}
@@ -0,0 +1,251 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include "hdf5_hl.h"
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// ---- netCDF-4 conventions ----
+
+// ncDimidAttr is the attribute netCDF-4 numbers dimensions with.
+const ncDimidAttr = "_Netcdf4Dimid"
+
+// CreateNetCDFFile creates a file that netCDF-4 libraries can read. It
+// tracks the creation order of links and attributes, which netCDF-4 uses
+// to order dimensions, variables and attributes.
+func CreateNetCDFFile(name string, flags int) (*File, error) {
+	fcpl, err := NewPropList(P_FILE_CREATE)
+	if err != nil {
+		return nil, err
+	}
+	defer fcpl.Close()
+	order := P_CRT_ORDER_TRACKED | P_CRT_ORDER_INDEXED
+	if err := fcpl.SetLinkCreationOrder(order); err != nil {
+		return nil, err
+	}
+	if err := fcpl.SetAttrCreationOrder(order); err != nil {
+		return nil, err
+	}
+	return CreateFileWith(name, flags, fcpl, P_DEFAULT)
+}
+
+// CreateNCDimension creates a netCDF dimension of the given length, or an
+// unlimited one if length is 0, as a dimension scale. If coord is not nil
+// the dimension is also a coordinate variable of that datatype, whose
+// values can be written to the returned dataset.
+func (f *File) CreateNCDimension(name string, length uint, coord *Datatype) (*Dataset, error) {
+	return createNCDimension(f.id, name, length, coord)
+}
+
+// CreateNCDimension creates a netCDF dimension of the given length, or an
+// unlimited one if length is 0, as a dimension scale. If coord is not nil
+// the dimension is also a coordinate variable of that datatype, whose
+// values can be written to the returned dataset.
+func (g *Group) CreateNCDimension(name string, length uint, coord *Datatype) (*Dataset, error) {
+	return createNCDimension(g.id, name, length, coord)
+}
+
+// CreateNCVariable creates a netCDF variable of the given datatype whose
+// dimensions are the dimension scales at the paths dims, relative to the
+// file, as created by CreateNCDimension.
+func (f *File) CreateNCVariable(name string, dtype *Datatype, dims []string) (*Dataset, error) {
+	return createNCVariable(f.id, name, dtype, dims)
+}
+
+// CreateNCVariable creates a netCDF variable of the given datatype whose
+// dimensions are the dimension scales at the paths dims, relative to the
+// group, as created by CreateNCDimension.
+func (g *Group) CreateNCVariable(name string, dtype *Datatype, dims []string) (*Dataset, error) {
+	return createNCVariable(g.id, name, dtype, dims)
+}
+
+func createNCDimension(loc C.hid_t, name string, length uint, coord *Datatype) (*Dataset, error) {
+	dimid, err := nextNCDimid(loc)
+	if err != nil {
+		return nil, err
+	}
+	dtype := coord
+	if dtype == nil {
+		dtype = T_IEEE_F32BE
+	}
+	maxlen := length
+	if length == 0 {
+		maxlen = S_UNLIMITED
+	}
+	dspace, err := CreateSimpleDataspace([]uint{length}, []uint{maxlen})
+	if err != nil {
+		return nil, err
+	}
+	defer dspace.Close()
+	dcpl, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		return nil, err
+	}
+	defer dcpl.Close()
+	if length == 0 {
+		if err := dcpl.SetChunk([]uint{1024}); err != nil {
+			return nil, err
+		}
+	}
+
+	d, err := createDataset(loc, name, dtype, dspace, dcpl)
+	if err != nil {
+		return nil, err
+	}
+	// Dimensions without a coordinate variable are named the way the
+	// netCDF library names them.
+	scaleName := name
+	if coord == nil {
+		scaleName = fmt.Sprintf("This is a netCDF dimension but not a netCDF variable.%10d", length)
+	}
+	c_name := C.CString(scaleName)
+	defer C.free(unsafe.Pointer(c_name))
+	if err := h5err(C.H5DSset_scale(d.id, c_name)); err != nil {
+		d.Close()
+		return nil, err
+	}
+	if err := setNCDimid(d.id, dimid); err != nil {
+		d.Close()
+		return nil, err
+	}
+	return d, nil
+}
+
+func setNCDimid(id C.hid_t, dimid int32) error {
+	dspace, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		return err
+	}
+	defer dspace.Close()
+	a, err := createAttribute(id, ncDimidAttr, T_NATIVE_INT32, dspace, P_DEFAULT)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+	return a.Write(&dimid, T_NATIVE_INT32)
+}
+
+// nextNCDimid returns the netCDF dimension id following those of the
+// dimensions of the file of loc.
+func nextNCDimid(loc C.hid_t) (int32, error) {
+	fid := C.H5Iget_file_id(loc)
+	if err := h5err(C.herr_t(int(fid))); err != nil {
+		return 0, err
+	}
+	defer C.H5Fclose(fid)
+
+	next := int32(0)
+	err := walkObjects(fid, func(name string, info *ObjectInfo) error {
+		if info.Type != O_TYPE_DATASET {
+			return nil
+		}
+		id, err := openObject(fid, name)
+		if err != nil {
+			return err
+		}
+		defer C.H5Oclose(id)
+		if ok, err := attributeExists(id, ncDimidAttr); err != nil || !ok {
+			return err
+		}
+		a, err := openAttribute(id, ncDimidAttr)
+		if err != nil {
+			return err
+		}
+		defer a.Close()
+		var dimid int32
+		if err := a.Read(&dimid, T_NATIVE_INT32); err != nil {
+			return err
+		}
+		if dimid >= next {
+			next = dimid + 1
+		}
+		return nil
+	})
+	return next, err
+}
+
+func createNCVariable(loc C.hid_t, name string, dtype *Datatype, dims []string) (*Dataset, error) {
+	scales := make([]*Dataset, len(dims))
+	defer func() {
+		for _, s := range scales {
+			if s != nil {
+				s.Close()
+			}
+		}
+	}()
+	shape := make([]uint, len(dims))
+	maxShape := make([]uint, len(dims))
+	chunk := make([]uint, len(dims))
+	chunked := false
+	for i, dim := range dims {
+		s, err := openDataset(loc, dim)
+		if err != nil {
+			return nil, err
+		}
+		scales[i] = s
+		if C.H5DSis_scale(s.id) <= 0 {
+			return nil, fmt.Errorf("%q is not a dimension scale", dim)
+		}
+		dspace := s.Space()
+		if dspace == nil {
+			return nil, fmt.Errorf("could not get dataspace of dimension %q", dim)
+		}
+		d, m, err := dspace.SimpleExtentDims()
+		dspace.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(d) != 1 {
+			return nil, fmt.Errorf("dimension %q is not one-dimensional", dim)
+		}
+		shape[i], maxShape[i], chunk[i] = d[0], m[0], d[0]
+		if m[0] == S_UNLIMITED {
+			chunked = true
+			chunk[i] = 1
+		}
+	}
+
+	var dspace *Dataspace
+	var err error
+	if len(dims) == 0 {
+		dspace, err = CreateDataspace(S_SCALAR)
+	} else {
+		dspace, err = CreateSimpleDataspace(shape, maxShape)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer dspace.Close()
+	dcpl, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		return nil, err
+	}
+	defer dcpl.Close()
+	if chunked {
+		for i := range chunk {
+			if chunk[i] == 0 {
+				chunk[i] = 1
+			}
+		}
+		if err := dcpl.SetChunk(chunk); err != nil {
+			return nil, err
+		}
+	}
+
+	d, err := createDataset(loc, name, dtype, dspace, dcpl)
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range scales {
+		if err := h5err(C.H5DSattach_scale(d.id, s.id, C.uint(i))); err != nil {
+			d.Close()
+			return nil, err
+		}
+	}
+	return d, nil
+}
@@ -0,0 +1,64 @@
+package hdf5
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestNetCDF(t *testing.T) {
+	f, err := CreateNetCDFFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateNetCDFFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	tdim, err := f.CreateNCDimension("time", 0, T_NATIVE_DOUBLE)
+	if err != nil {
+		t.Fatalf("CreateNCDimension failed: %s", err)
+	}
+	defer tdim.Close()
+	xdim, err := f.CreateNCDimension("x", 3, nil)
+	if err != nil {
+		t.Fatalf("CreateNCDimension failed: %s", err)
+	}
+	defer xdim.Close()
+	for i, d := range []*Dataset{tdim, xdim} {
+		a, err := d.OpenAttribute(ncDimidAttr)
+		if err != nil {
+			t.Fatalf("OpenAttribute failed: %s", err)
+		}
+		var dimid int32
+		err = a.Read(&dimid, T_NATIVE_INT32)
+		a.Close()
+		if err != nil {
+			t.Fatalf("Read failed: %s", err)
+		}
+		if dimid != int32(i) {
+			t.Errorf("dimension %d has %s %d", i, ncDimidAttr, dimid)
+		}
+	}
+
+	v, err := f.CreateNCVariable("temp", T_NATIVE_FLOAT, []string{"time", "x"})
+	if err != nil {
+		t.Fatalf("CreateNCVariable failed: %s", err)
+	}
+	defer v.Close()
+	dspace := v.Space()
+	defer dspace.Close()
+	dims, maxdims, err := dspace.SimpleExtentDims()
+	if err != nil {
+		t.Fatalf("SimpleExtentDims failed: %s", err)
+	}
+	if !reflect.DeepEqual(dims, []uint{0, 3}) || !reflect.DeepEqual(maxdims, []uint{S_UNLIMITED, 3}) {
+		t.Errorf("variable has dims %v and maxdims %v", dims, maxdims)
+	}
+	if ok, err := attributeExists(v.id, "DIMENSION_LIST"); err != nil || !ok {
+		t.Errorf("variable has no DIMENSION_LIST attribute (%v)", err)
+	}
+
+	if _, err := f.CreateNCVariable("bad", T_NATIVE_FLOAT, []string{"temp"}); err == nil {
+		t.Errorf("expected an error for a dimension that is not a dimension scale")
+	}
+}
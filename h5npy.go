@@ -0,0 +1,617 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+import "C"
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+const npyMagic = "\x93NUMPY"
+
+// npyTypes maps the NumPy type codes of numeric types to their little- and
+// big-endian HDF5 datatypes.
+var npyTypes = map[string][2]*Datatype{
+	"i1": {T_STD_I8LE, T_STD_I8BE},
+	"i2": {T_STD_I16LE, T_STD_I16BE},
+	"i4": {T_STD_I32LE, T_STD_I32BE},
+	"i8": {T_STD_I64LE, T_STD_I64BE},
+	"u1": {T_STD_U8LE, T_STD_U8BE},
+	"u2": {T_STD_U16LE, T_STD_U16BE},
+	"u4": {T_STD_U32LE, T_STD_U32BE},
+	"u8": {T_STD_U64LE, T_STD_U64BE},
+	"f4": {T_IEEE_F32LE, T_IEEE_F32BE},
+	"f8": {T_IEEE_F64LE, T_IEEE_F64BE},
+}
+
+// npyBool is the enumeration h5py stores NumPy booleans as.
+const npyBool = `H5T_ENUM { H5T_STD_I8LE; "FALSE" 0; "TRUE" 1; }`
+
+// WriteNPY writes the dataset as a NumPy .npy array. Integer,
+// floating-point and fixed-length string datasets are supported;
+// enumerations are written as their base integer type, except for h5py
+// booleans. Values keep the byte order of the file.
+func (s *Dataset) WriteNPY(w io.Writer) error {
+	return writeNPY(s.id, w)
+}
+
+// CreateDatasetFromNPY creates a dataset in the file holding the NumPy
+// .npy array read from r.
+func (f *File) CreateDatasetFromNPY(name string, r io.Reader) (*Dataset, error) {
+	return createDatasetFromNPY(f.id, name, r)
+}
+
+// CreateDatasetFromNPY creates a dataset in the group holding the NumPy
+// .npy array read from r.
+func (g *Group) CreateDatasetFromNPY(name string, r io.Reader) (*Dataset, error) {
+	return createDatasetFromNPY(g.id, name, r)
+}
+
+// ExportNPZ writes the datasets of the file and of its groups to a NumPy
+// .npz archive, each under its path in the file.
+func (f *File) ExportNPZ(w io.Writer) error {
+	return exportNPZ(f.id, w)
+}
+
+// ExportNPZ writes the datasets of the group and of its subgroups to a
+// NumPy .npz archive, each under its path relative to the group.
+func (g *Group) ExportNPZ(w io.Writer) error {
+	return exportNPZ(g.id, w)
+}
+
+// ImportNPZ creates a dataset in the file for each array of the NumPy .npz
+// archive r of the given size, creating the groups of their paths as
+// needed.
+func (f *File) ImportNPZ(r io.ReaderAt, size int64) error {
+	return importNPZ(f.id, r, size)
+}
+
+// ImportNPZ creates a dataset in the group for each array of the NumPy
+// .npz archive r of the given size, creating the groups of their paths as
+// needed.
+func (g *Group) ImportNPZ(r io.ReaderAt, size int64) error {
+	return importNPZ(g.id, r, size)
+}
+
+func writeNPY(id C.hid_t, w io.Writer) error {
+	tid := C.H5Dget_type(id)
+	if err := h5err(C.herr_t(int(tid))); err != nil {
+		return err
+	}
+	defer C.H5Tclose(tid)
+	mtype, descr, err := npyDescr(tid)
+	if err != nil {
+		return err
+	}
+	defer C.H5Tclose(mtype)
+
+	shape, npoints, err := jsonShape(C.H5Dget_space(id))
+	if err != nil {
+		return err
+	}
+	if isNullShape(shape) {
+		return fmt.Errorf("cannot write a dataset with a null dataspace as .npy")
+	}
+	data := make([]byte, npoints*int(C.H5Tget_size(mtype)))
+	if len(data) > 0 {
+		err := h5err(C.H5Dread(id, mtype, C.H5S_ALL, C.H5S_ALL, C.H5P_DEFAULT, unsafe.Pointer(&data[0])))
+		if err != nil {
+			return err
+		}
+	}
+	if _, err := w.Write(npyHeader(descr, shape)); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// npyDescr returns the datatype to read values of the datatype t with,
+// which the caller must close, and its NumPy type string.
+func npyDescr(t C.hid_t) (C.hid_t, string, error) {
+	base := t
+	var descr string
+	switch TypeClass(C.H5Tget_class(t)) {
+	case T_ENUM:
+		if isBoolEnum(t) {
+			descr = "|b1"
+			break
+		}
+		// Enumerations do not convert to integers, but their values have
+		// the layout of their base type.
+		base = C.H5Tget_super(t)
+		defer C.H5Tclose(base)
+		if TypeClass(C.H5Tget_class(base)) != T_INTEGER {
+			return 0, "", fmt.Errorf("unsupported enumeration base type")
+		}
+		fallthrough
+	case T_INTEGER:
+		kind := "i"
+		if C.H5Tget_sign(base) == C.H5T_SGN_NONE {
+			kind = "u"
+		}
+		descr = npyOrder(base) + kind + strconv.Itoa(int(C.H5Tget_size(base)))
+	case T_FLOAT:
+		descr = npyOrder(t) + "f" + strconv.Itoa(int(C.H5Tget_size(t)))
+	case T_STRING:
+		if C.H5Tis_variable_str(t) > 0 {
+			return 0, "", fmt.Errorf("variable-length strings cannot be written to .npy")
+		}
+		descr = "|S" + strconv.Itoa(int(C.H5Tget_size(t)))
+	default:
+		return 0, "", fmt.Errorf("datatype class %d cannot be written to .npy", int(C.H5Tget_class(t)))
+	}
+
+	mtype := C.H5Tcopy(t)
+	if err := h5err(C.herr_t(int(mtype))); err != nil {
+		return 0, "", err
+	}
+	if TypeClass(C.H5Tget_class(t)) == T_STRING {
+		// NumPy strings are padded with nulls, without a terminator.
+		if err := h5err(C.H5Tset_strpad(mtype, C.H5T_STR_NULLPAD)); err != nil {
+			C.H5Tclose(mtype)
+			return 0, "", err
+		}
+	}
+	return mtype, descr, nil
+}
+
+func npyOrder(t C.hid_t) string {
+	switch {
+	case C.H5Tget_size(t) == 1:
+		return "|"
+	case C.H5Tget_order(t) == C.H5T_ORDER_BE:
+		return ">"
+	}
+	return "<"
+}
+
+// isBoolEnum reports whether t is the enumeration h5py stores booleans as.
+func isBoolEnum(t C.hid_t) bool {
+	if C.H5Tget_size(t) != 1 || C.H5Tget_nmembers(t) != 2 {
+		return false
+	}
+	for i, want := range []string{"FALSE", "TRUE"} {
+		c_name := C.H5Tget_member_name(t, C.uint(i))
+		name := C.GoString(c_name)
+		C.free(unsafe.Pointer(c_name))
+		if name != want {
+			return false
+		}
+	}
+	return true
+}
+
+// npyHeader returns the header of a .npy array of type descr and the given
+// shape, nil for a scalar. The header is padded so that the data starts on
+// a 64-byte boundary.
+func npyHeader(descr string, shape []uint) []byte {
+	dims := make([]string, len(shape))
+	for i, d := range shape {
+		dims[i] = strconv.FormatUint(uint64(d), 10)
+	}
+	tuple := strings.Join(dims, ", ")
+	if len(shape) == 1 {
+		tuple += ","
+	}
+	dict := fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (%s), }", descr, tuple)
+
+	version, lenSize := byte(1), 2
+	total := len(npyMagic) + 2 + lenSize + len(dict) + 1
+	pad := (64 - total%64) % 64
+	if len(dict)+pad+1 > 0xffff {
+		version, lenSize = 2, 4
+		total += 2
+		pad = (64 - total%64) % 64
+	}
+	n := len(dict) + pad + 1
+
+	buf := make([]byte, 0, total+pad)
+	buf = append(buf, npyMagic...)
+	buf = append(buf, version, 0)
+	if lenSize == 2 {
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(n))
+	} else {
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(n))
+	}
+	buf = append(buf, dict...)
+	buf = append(buf, bytes.Repeat([]byte{' '}, pad)...)
+	return append(buf, '\n')
+}
+
+func createDatasetFromNPY(loc C.hid_t, name string, r io.Reader) (*Dataset, error) {
+	h, err := readNPYHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	dtype, err := npyDatatype(h.descr)
+	if err != nil {
+		return nil, err
+	}
+	defer dtype.Close()
+
+	// the shape is checked for overflow, and the data read as it comes
+	// rather than into a buffer of the size the header claims
+	esize := int(dtype.Size())
+	size := esize
+	for _, d := range h.shape {
+		if d != 0 && uint(size) > uint(math.MaxInt)/d {
+			return nil, fmt.Errorf("reading .npy header: shape %v is too large", h.shape)
+		}
+		size *= int(d)
+	}
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, r, int64(size)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("reading .npy data: %s", err)
+	}
+	data := buf.Bytes()
+	if h.fortranOrder {
+		data = fortranToC(data, h.shape, esize)
+	}
+
+	dspace, err := jsonDataspace(h.shape)
+	if err != nil {
+		return nil, err
+	}
+	defer dspace.Close()
+	d, err := createDataset(loc, name, dtype, dspace, P_DEFAULT)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > 0 {
+		err := h5err(C.H5Dwrite(d.id, dtype.id, C.H5S_ALL, C.H5S_ALL, C.H5P_DEFAULT, unsafe.Pointer(&data[0])))
+		if err != nil {
+			d.Close()
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// npyDatatype returns a new datatype for the NumPy type string descr.
+func npyDatatype(descr string) (*Datatype, error) {
+	if len(descr) < 3 {
+		return nil, fmt.Errorf("unsupported .npy type %q", descr)
+	}
+	order, code := descr[0], descr[1:]
+	size, err := strconv.Atoi(descr[2:])
+	if err != nil || size <= 0 {
+		return nil, fmt.Errorf("unsupported .npy type %q", descr)
+	}
+
+	switch code[0] {
+	case 'S':
+		t, err := T_C_S1.Copy()
+		if err != nil {
+			return nil, err
+		}
+		if err := t.SetSize(uint(size)); err != nil {
+			t.Close()
+			return nil, err
+		}
+		if err := h5err(C.H5Tset_strpad(t.id, C.H5T_STR_NULLPAD)); err != nil {
+			t.Close()
+			return nil, err
+		}
+		return t, nil
+	case 'b':
+		if size == 1 {
+			return NewDatatypeFromText(npyBool)
+		}
+	}
+
+	types, ok := npyTypes[code]
+	if !ok {
+		return nil, fmt.Errorf("unsupported .npy type %q", descr)
+	}
+	switch order {
+	case '<', '|':
+		return types[0].Copy()
+	case '>':
+		return types[1].Copy()
+	case '=':
+		if C.H5Tget_order(T_NATIVE_INT.id) == C.H5T_ORDER_BE {
+			return types[1].Copy()
+		}
+		return types[0].Copy()
+	}
+	return nil, fmt.Errorf("unsupported .npy type %q", descr)
+}
+
+// fortranToC reorders the elements, of size esize, of an array of the
+// given shape from column-major to row-major order.
+func fortranToC(data []byte, shape []uint, esize int) []byte {
+	rank := len(shape)
+	strides := make([]int, rank)
+	stride := 1
+	for k := range shape {
+		strides[k] = stride
+		stride *= int(shape[k])
+	}
+
+	out := make([]byte, len(data))
+	idx := make([]int, rank)
+	for i := 0; i < len(data)/esize; i++ {
+		off := 0
+		for k, v := range idx {
+			off += v * strides[k]
+		}
+		copy(out[i*esize:(i+1)*esize], data[off*esize:(off+1)*esize])
+		for k := rank - 1; k >= 0; k-- {
+			idx[k]++
+			if idx[k] < int(shape[k]) {
+				break
+			}
+			idx[k] = 0
+		}
+	}
+	return out
+}
+
+// npyHeaderInfo holds the fields of the header of a .npy array.
+type npyHeaderInfo struct {
+	descr        string
+	fortranOrder bool
+	shape        []uint
+}
+
+func readNPYHeader(r io.Reader) (*npyHeaderInfo, error) {
+	var pre [8]byte
+	if _, err := io.ReadFull(r, pre[:]); err != nil {
+		return nil, fmt.Errorf("reading .npy header: %s", err)
+	}
+	if string(pre[:6]) != npyMagic {
+		return nil, fmt.Errorf("not a .npy array")
+	}
+	var lenSize int
+	switch pre[6] {
+	case 1:
+		lenSize = 2
+	case 2, 3:
+		lenSize = 4
+	default:
+		return nil, fmt.Errorf("unsupported .npy version %d.%d", pre[6], pre[7])
+	}
+	var b [4]byte
+	if _, err := io.ReadFull(r, b[:lenSize]); err != nil {
+		return nil, fmt.Errorf("reading .npy header: %s", err)
+	}
+	n := int(binary.LittleEndian.Uint32(b[:]))
+	hdr := make([]byte, n)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return nil, fmt.Errorf("reading .npy header: %s", err)
+	}
+	return parseNPYHeader(string(hdr))
+}
+
+func parseNPYHeader(s string) (*npyHeaderInfo, error) {
+	p := &npyParser{s: s}
+	v, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	dict, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("malformed .npy header %q", s)
+	}
+
+	h := &npyHeaderInfo{}
+	switch descr := dict["descr"].(type) {
+	case string:
+		h.descr = descr
+	case []interface{}:
+		return nil, fmt.Errorf("structured .npy arrays are not supported")
+	default:
+		return nil, fmt.Errorf("malformed .npy header %q", s)
+	}
+	if h.fortranOrder, ok = dict["fortran_order"].(bool); !ok {
+		return nil, fmt.Errorf("malformed .npy header %q", s)
+	}
+	shape, ok := dict["shape"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("malformed .npy header %q", s)
+	}
+	for _, d := range shape {
+		n, ok := d.(uint)
+		if !ok {
+			return nil, fmt.Errorf("malformed .npy header %q", s)
+		}
+		h.shape = append(h.shape, n)
+	}
+	return h, nil
+}
+
+// npyParser parses the Python literals of .npy headers: dictionaries,
+// tuples, lists, strings, booleans and non-negative integers.
+type npyParser struct {
+	s   string
+	pos int
+}
+
+func (p *npyParser) skipSpace() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *npyParser) accept(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *npyParser) errorf() error {
+	return fmt.Errorf("malformed .npy header %q at offset %d", p.s, p.pos)
+}
+
+func (p *npyParser) value() (interface{}, error) {
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		return nil, p.errorf()
+	}
+	switch c := p.s[p.pos]; c {
+	case '\'', '"':
+		end := strings.IndexByte(p.s[p.pos+1:], c)
+		if end < 0 {
+			return nil, p.errorf()
+		}
+		v := p.s[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return v, nil
+
+	case '(', '[':
+		closer := byte(')')
+		if c == '[' {
+			closer = ']'
+		}
+		p.pos++
+		items := []interface{}{}
+		for !p.accept(closer) {
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+			if !p.accept(',') {
+				if !p.accept(closer) {
+					return nil, p.errorf()
+				}
+				break
+			}
+		}
+		return items, nil
+
+	case '{':
+		p.pos++
+		dict := make(map[string]interface{})
+		for !p.accept('}') {
+			k, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			key, ok := k.(string)
+			if !ok || !p.accept(':') {
+				return nil, p.errorf()
+			}
+			if dict[key], err = p.value(); err != nil {
+				return nil, err
+			}
+			if !p.accept(',') {
+				if !p.accept('}') {
+					return nil, p.errorf()
+				}
+				break
+			}
+		}
+		return dict, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n,:)]}", p.s[p.pos]) < 0 {
+		p.pos++
+	}
+	switch tok := p.s[start:p.pos]; tok {
+	case "True":
+		return true, nil
+	case "False":
+		return false, nil
+	default:
+		// Python 2 wrote long integers with an L suffix.
+		n, err := strconv.ParseUint(strings.TrimSuffix(tok, "L"), 10, 0)
+		if err != nil {
+			p.pos = start
+			return nil, p.errorf()
+		}
+		return uint(n), nil
+	}
+}
+
+func exportNPZ(loc C.hid_t, w io.Writer) error {
+	z := zip.NewWriter(w)
+	err := walkObjects(loc, func(name string, info *ObjectInfo) error {
+		if info.Type != O_TYPE_DATASET {
+			return nil
+		}
+		id, err := openObject(loc, name)
+		if err != nil {
+			return err
+		}
+		defer C.H5Oclose(id)
+		zw, err := z.Create(name + ".npy")
+		if err != nil {
+			return err
+		}
+		if err := writeNPY(id, zw); err != nil {
+			return fmt.Errorf("%s: %s", name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return z.Close()
+}
+
+func importNPZ(loc C.hid_t, r io.ReaderAt, size int64) error {
+	z, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	for _, zf := range z.File {
+		if strings.HasSuffix(zf.Name, "/") {
+			continue
+		}
+		name := strings.TrimSuffix(zf.Name, ".npy")
+		if err := createParentGroups(loc, name); err != nil {
+			return err
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return err
+		}
+		d, err := createDatasetFromNPY(loc, name, rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %s", zf.Name, err)
+		}
+		d.Close()
+	}
+	return nil
+}
+
+// createParentGroups creates the missing groups on the path to name,
+// relative to loc.
+func createParentGroups(loc C.hid_t, name string) error {
+	dir := path.Dir(name)
+	if dir == "." || dir == "/" {
+		return nil
+	}
+	if err := createParentGroups(loc, dir); err != nil {
+		return err
+	}
+	ok, err := linkExists(loc, dir)
+	if err != nil || ok {
+		return err
+	}
+	g, err := createGroup(loc, dir, C.H5P_DEFAULT, C.H5P_DEFAULT, C.H5P_DEFAULT)
+	if err != nil {
+		return err
+	}
+	return g.Close()
+}
@@ -0,0 +1,156 @@
+package hdf5
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNPY(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	dspace, err := CreateSimpleDataspace([]uint{2, 3}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("grid", T_IEEE_F64LE, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	values := []float64{1, 2, 3, 4, 5, 6.5}
+	if err := dset.Write(&values[0], T_NATIVE_DOUBLE); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+
+	var buf bytes.Buffer
+	if err := dset.WriteNPY(&buf); err != nil {
+		t.Fatalf("WriteNPY failed: %s", err)
+	}
+	npy := buf.Bytes()
+	hlen := 10 + int(binary.LittleEndian.Uint16(npy[8:10]))
+	if hlen%64 != 0 {
+		t.Errorf("header of %d bytes is not 64-byte aligned", hlen)
+	}
+	if hdr := string(npy[10:hlen]); !strings.HasPrefix(hdr, "{'descr': '<f8', 'fortran_order': False, 'shape': (2, 3), }") {
+		t.Errorf("unexpected header %q", hdr)
+	}
+	if len(npy) != hlen+6*8 {
+		t.Errorf("WriteNPY wrote %d bytes, want %d", len(npy), hlen+6*8)
+	}
+
+	copied, err := f.CreateDatasetFromNPY("copy", bytes.NewReader(npy))
+	if err != nil {
+		t.Fatalf("CreateDatasetFromNPY failed: %s", err)
+	}
+	defer copied.Close()
+	got := make([]float64, 6)
+	if err := copied.Read(&got[0], T_NATIVE_DOUBLE); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, values) {
+		t.Errorf("read %v, want %v", got, values)
+	}
+
+	// big-endian, column-major array
+	dict := "{'descr': '>i2', 'fortran_order': True, 'shape': (2L, 3L), }"
+	be := []byte("\x93NUMPY\x01\x00")
+	be = binary.LittleEndian.AppendUint16(be, uint16(len(dict)+1))
+	be = append(be, dict+"\n"...)
+	for _, v := range []int16{1, 4, 2, 5, 3, 6} {
+		be = binary.BigEndian.AppendUint16(be, uint16(v))
+	}
+	fdset, err := f.CreateDatasetFromNPY("fortran", bytes.NewReader(be))
+	if err != nil {
+		t.Fatalf("CreateDatasetFromNPY failed: %s", err)
+	}
+	defer fdset.Close()
+	ints := make([]int16, 6)
+	if err := fdset.Read(&ints[0], T_NATIVE_INT16); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if want := []int16{1, 2, 3, 4, 5, 6}; !reflect.DeepEqual(ints, want) {
+		t.Errorf("read %v, want %v", ints, want)
+	}
+
+	bad := append([]byte("\x93NUMPY\x01\x00\x1c\x00"), "{'descr': [('a', '<i4')], }\n"...)
+	if _, err := f.CreateDatasetFromNPY("bad", bytes.NewReader(bad)); err == nil {
+		t.Errorf("expected an error for a structured array")
+	}
+
+	// shapes that overflow, or claim more data than there is
+	for _, shape := range []string{"(4294967296, 4294967296)", "(1000000000000,)"} {
+		dict := "{'descr': '<f8', 'fortran_order': False, 'shape': " + shape + ", }"
+		huge := []byte("\x93NUMPY\x01\x00")
+		huge = binary.LittleEndian.AppendUint16(huge, uint16(len(dict)+1))
+		huge = append(huge, dict+"\n"...)
+		huge = append(huge, make([]byte, 16)...)
+		if _, err := f.CreateDatasetFromNPY("huge", bytes.NewReader(huge)); err == nil {
+			t.Errorf("expected an error for the shape %s", shape)
+		}
+	}
+}
+
+func TestNPZ(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	g, err := f.CreateGroup("sub")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	defer g.Close()
+	dspace, err := CreateSimpleDataspace([]uint{4}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := g.CreateDataset("counts", T_STD_U32BE, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	values := []uint32{1, 2, 3, 4}
+	if err := dset.Write(&values[0], T_NATIVE_UINT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+
+	var buf bytes.Buffer
+	if err := f.ExportNPZ(&buf); err != nil {
+		t.Fatalf("ExportNPZ failed: %s", err)
+	}
+	g2, err := f.CreateGroup("imported")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	defer g2.Close()
+	if err := g2.ImportNPZ(bytes.NewReader(buf.Bytes()), int64(buf.Len())); err != nil {
+		t.Fatalf("ImportNPZ failed: %s", err)
+	}
+
+	copied, err := f.OpenDataset("imported/sub/counts")
+	if err != nil {
+		t.Fatalf("OpenDataset failed: %s", err)
+	}
+	defer copied.Close()
+	got := make([]uint32, 4)
+	if err := copied.Read(&got[0], T_NATIVE_UINT32); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, values) {
+		t.Errorf("read %v, want %v", got, values)
+	}
+}
@@ -0,0 +1,250 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// #include <string.h>
+import "C"
+
+import (
+	"time"
+	"unsafe"
+)
+
+type ObjectType C.H5O_type_t
+
+const (
+	O_TYPE_UNKNOWN        ObjectType = -1 // Unknown object type
+	O_TYPE_GROUP          ObjectType = 0  // Object is a group
+	O_TYPE_DATASET        ObjectType = 1  // Object is a dataset
+	O_TYPE_NAMED_DATATYPE ObjectType = 2  // Object is a named data type
+)
+
+func (t ObjectType) String() string {
+	switch t {
+	case O_TYPE_GROUP:
+		return "group"
+	case O_TYPE_DATASET:
+		return "dataset"
+	case O_TYPE_NAMED_DATATYPE:
+		return "named datatype"
+	}
+	return "unknown"
+}
+
+// ObjectInfo holds the metadata HDF5 keeps about an object in a file.
+// The times are zero unless the object was created with time tracking
+// enabled.
+type ObjectInfo struct {
+	FileNo     uint64     // number of the file the object is in
+	Addr       uint64     // address of the object header in the file
+	Type       ObjectType // kind of object
+	RefCount   uint       // number of hard links to the object
+	AccessTime time.Time  // last access time
+	ModTime    time.Time  // last modification time of the raw data
+	ChangeTime time.Time  // last change time of the metadata
+	BirthTime  time.Time  // creation time
+	NumAttrs   uint       // number of attributes attached to the object
+
+	HeaderVersion  uint   // version of the object header
+	HeaderMessages uint   // number of messages in the object header
+	HeaderChunks   uint   // number of chunks in the object header
+	HeaderSize     uint64 // total space used by the object header
+	HeaderFree     uint64 // free space within the object header
+
+	ObjIndexSize  uint64 // B-tree of the links of a group or the chunks of a dataset
+	ObjHeapSize   uint64 // heap of the links of a group
+	AttrIndexSize uint64 // B-tree of densely stored attributes
+	AttrHeapSize  uint64 // heap of densely stored attributes
+}
+
+func unixTime(t C.time_t) time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(t), 0)
+}
+
+func newObjectInfo(c *C.H5O_info_t) *ObjectInfo {
+	return &ObjectInfo{
+		FileNo:         uint64(c.fileno),
+		Addr:           uint64(c.addr),
+		Type:           ObjectType(c._type),
+		RefCount:       uint(c.rc),
+		AccessTime:     unixTime(c.atime),
+		ModTime:        unixTime(c.mtime),
+		ChangeTime:     unixTime(c.ctime),
+		BirthTime:      unixTime(c.btime),
+		NumAttrs:       uint(c.num_attrs),
+		HeaderVersion:  uint(c.hdr.version),
+		HeaderMessages: uint(c.hdr.nmesgs),
+		HeaderChunks:   uint(c.hdr.nchunks),
+		HeaderSize:     uint64(c.hdr.space.total),
+		HeaderFree:     uint64(c.hdr.space.free),
+		ObjIndexSize:   uint64(c.meta_size.obj.index_size),
+		ObjHeapSize:    uint64(c.meta_size.obj.heap_size),
+		AttrIndexSize:  uint64(c.meta_size.attr.index_size),
+		AttrHeapSize:   uint64(c.meta_size.attr.heap_size),
+	}
+}
+
+// openObject opens the group, dataset or named datatype name and returns its
+// identifier, which must be closed with H5Oclose.
+func openObject(id C.hid_t, name string) (C.hid_t, error) {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	hid := C.H5Oopen(id, c_name, C.H5P_DEFAULT)
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return 0, err
+	}
+	return hid, nil
+}
+
+func linkObject(id C.hid_t, obj Object, name string) error {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return h5err(C.H5Olink(C.hid_t(obj.Id()), id, c_name, C.H5P_DEFAULT, C.H5P_DEFAULT))
+}
+
+func objectInfo(id C.hid_t) (*ObjectInfo, error) {
+	var info C.H5O_info_t
+	if err := h5err(C.H5Oget_info(id, &info)); err != nil {
+		return nil, err
+	}
+	return newObjectInfo(&info), nil
+}
+
+func objectInfoByName(id C.hid_t, name string) (*ObjectInfo, error) {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	var info C.H5O_info_t
+	if err := h5err(C.H5Oget_info_by_name(id, c_name, &info, C.H5P_DEFAULT)); err != nil {
+		return nil, err
+	}
+	return newObjectInfo(&info), nil
+}
+
+func setComment(id C.hid_t, comment string) error {
+	c_comment := C.CString(comment)
+	defer C.free(unsafe.Pointer(c_comment))
+
+	return h5err(C.H5Oset_comment(id, c_comment))
+}
+
+func getComment(id C.hid_t) (string, error) {
+	size := C.H5Oget_comment(id, nil, 0)
+	if err := h5err(C.herr_t(size)); err != nil {
+		return "", err
+	}
+	if size == 0 {
+		return "", nil
+	}
+	buf := make([]C.char, size+1)
+	size = C.H5Oget_comment(id, &buf[0], C.size_t(size)+1)
+	if err := h5err(C.herr_t(size)); err != nil {
+		return "", err
+	}
+	return C.GoString(&buf[0]), nil
+}
+
+func setCommentByName(id C.hid_t, name, comment string) error {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+	c_comment := C.CString(comment)
+	defer C.free(unsafe.Pointer(c_comment))
+
+	return h5err(C.H5Oset_comment_by_name(id, c_name, c_comment, C.H5P_DEFAULT))
+}
+
+func getCommentByName(id C.hid_t, name string) (string, error) {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	size := C.H5Oget_comment_by_name(id, c_name, nil, 0, C.H5P_DEFAULT)
+	if err := h5err(C.herr_t(size)); err != nil {
+		return "", err
+	}
+	if size == 0 {
+		return "", nil
+	}
+	buf := make([]C.char, size+1)
+	size = C.H5Oget_comment_by_name(id, c_name, &buf[0], C.size_t(size)+1, C.H5P_DEFAULT)
+	if err := h5err(C.herr_t(size)); err != nil {
+		return "", err
+	}
+	return C.GoString(&buf[0]), nil
+}
+
+// walkObjects calls fn for every object reachable from the group loc, in
+// depth-first name order, with its path relative to loc. Each object is
+// visited once, even if it is linked from several groups, and links that do
+// not resolve to an object are skipped.
+func walkObjects(loc C.hid_t, fn func(path string, info *ObjectInfo) error) error {
+	return walkLinks(loc, fn, nil)
+}
+
+// walkLinks is walkObjects, except that if link is not nil soft and
+// external links are passed to it, dangling or not, instead of being
+// followed.
+func walkLinks(loc C.hid_t, fn func(path string, info *ObjectInfo) error, link func(path string, info *LinkInfo) error) error {
+	seen := make(map[[2]uint64]bool)
+
+	var walk func(id C.hid_t, prefix string) error
+	walk = func(id C.hid_t, prefix string) error {
+		n, err := numObjects(id)
+		if err != nil {
+			return err
+		}
+		for i := uint(0); i < n; i++ {
+			name, err := objectNameByIndex(id, i)
+			if err != nil {
+				return err
+			}
+			path := prefix + name
+			if link != nil {
+				li, err := linkInfo(id, name)
+				if err != nil {
+					return err
+				}
+				if li.Type != L_TYPE_HARD {
+					if err := link(path, li); err != nil {
+						return err
+					}
+					continue
+				}
+			}
+			if ok, err := pathExists(loc, path, true); err != nil {
+				return err
+			} else if !ok {
+				continue
+			}
+			info, err := objectInfoByName(loc, path)
+			if err != nil {
+				return err
+			}
+			key := [2]uint64{info.FileNo, info.Addr}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if err := fn(path, info); err != nil {
+				return err
+			}
+			if info.Type == O_TYPE_GROUP {
+				g, err := openGroup(id, name, C.H5P_DEFAULT)
+				if err != nil {
+					return err
+				}
+				err = walk(g.id, path+"/")
+				g.Close()
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return walk(loc, "")
+}
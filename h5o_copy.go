@@ -0,0 +1,108 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+import "C"
+
+import (
+	"unsafe"
+)
+
+// CopyOptions controls how much of an object CopyObject duplicates. The
+// zero value copies the object with its attributes and the whole
+// hierarchy below it, keeping soft and external links and references as
+// they are.
+type CopyOptions struct {
+	// ShallowHierarchy copies only the immediate members of a group, whose
+	// own member groups are copied empty.
+	ShallowHierarchy bool
+	// ExpandSoftLinks copies the objects soft links point to in place of
+	// the links.
+	ExpandSoftLinks bool
+	// ExpandExternalLinks copies the objects external links point to in
+	// place of the links.
+	ExpandExternalLinks bool
+	// ExpandReferences copies the objects the object references of
+	// datasets and attributes point to and updates the references.
+	ExpandReferences bool
+	// WithoutAttributes copies the objects without their attributes.
+	WithoutAttributes bool
+}
+
+// flags returns the H5O_COPY flags of the options.
+func (o *CopyOptions) flags() C.uint {
+	var flags C.uint
+	if o.ShallowHierarchy {
+		flags |= C.H5O_COPY_SHALLOW_HIERARCHY_FLAG
+	}
+	if o.ExpandSoftLinks {
+		flags |= C.H5O_COPY_EXPAND_SOFT_LINK_FLAG
+	}
+	if o.ExpandExternalLinks {
+		flags |= C.H5O_COPY_EXPAND_EXT_LINK_FLAG
+	}
+	if o.ExpandReferences {
+		flags |= C.H5O_COPY_EXPAND_REFERENCE_FLAG
+	}
+	if o.WithoutAttributes {
+		flags |= C.H5O_COPY_WITHOUT_ATTR_FLAG
+	}
+	return flags
+}
+
+// SetCopyObject sets the options of object copies made with this object
+// copy property list.
+// herr_t H5Pset_copy_object(hid_t ocpypl_id, unsigned copy_options)
+func (p *PropList) SetCopyObject(opts CopyOptions) error {
+	return h5err(C.H5Pset_copy_object(p.id, opts.flags()))
+}
+
+// CopyObject returns the options set by SetCopyObject.
+// herr_t H5Pget_copy_object(hid_t ocpypl_id, unsigned *copy_options)
+func (p *PropList) CopyObject() (CopyOptions, error) {
+	var flags C.uint
+	err := h5err(C.H5Pget_copy_object(p.id, &flags))
+	return CopyOptions{
+		ShallowHierarchy:    flags&C.H5O_COPY_SHALLOW_HIERARCHY_FLAG != 0,
+		ExpandSoftLinks:     flags&C.H5O_COPY_EXPAND_SOFT_LINK_FLAG != 0,
+		ExpandExternalLinks: flags&C.H5O_COPY_EXPAND_EXT_LINK_FLAG != 0,
+		ExpandReferences:    flags&C.H5O_COPY_EXPAND_REFERENCE_FLAG != 0,
+		WithoutAttributes:   flags&C.H5O_COPY_WITHOUT_ATTR_FLAG != 0,
+	}, err
+}
+
+// CopyObject copies the object src of this file, which may be a group,
+// dataset or named datatype, to dstName under the file or group dst, which
+// may be in another file. opts may be nil for the default options.
+// herr_t H5Ocopy(hid_t src_loc_id, const char *src_name, hid_t dst_loc_id, const char *dst_name, hid_t ocpypl_id, hid_t lcpl_id)
+func (f *File) CopyObject(src string, dst Object, dstName string, opts *CopyOptions) error {
+	return copyObject(f.id, src, C.hid_t(dst.Id()), dstName, opts)
+}
+
+// CopyObject copies the object src of this group, which may be a group,
+// dataset or named datatype, to dstName under the file or group dst, which
+// may be in another file. opts may be nil for the default options.
+// herr_t H5Ocopy(hid_t src_loc_id, const char *src_name, hid_t dst_loc_id, const char *dst_name, hid_t ocpypl_id, hid_t lcpl_id)
+func (g *Group) CopyObject(src string, dst Object, dstName string, opts *CopyOptions) error {
+	return copyObject(g.id, src, C.hid_t(dst.Id()), dstName, opts)
+}
+
+func copyObject(loc C.hid_t, src string, dst C.hid_t, dstName string, opts *CopyOptions) error {
+	ocpypl := P_DEFAULT
+	if opts != nil {
+		p, err := NewPropList(P_OBJECT_COPY)
+		if err != nil {
+			return err
+		}
+		defer p.Close()
+		if err := p.SetCopyObject(*opts); err != nil {
+			return err
+		}
+		ocpypl = p
+	}
+	c_src := C.CString(src)
+	defer C.free(unsafe.Pointer(c_src))
+	c_dst := C.CString(dstName)
+	defer C.free(unsafe.Pointer(c_dst))
+	return h5err(C.H5Ocopy(loc, c_src, dst, c_dst, ocpypl.id, C.H5P_DEFAULT))
+}
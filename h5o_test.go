@@ -0,0 +1,264 @@
+package hdf5
+
+import (
+	"os"
+	"testing"
+)
+
+func TestObjectInfo(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	g, err := f.CreateGroup("grp")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	defer g.Close()
+
+	dspace, err := CreateSimpleDataspace([]uint{4}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := g.CreateDataset("dset", T_NATIVE_INT32, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+
+	info, err := g.Info()
+	if err != nil {
+		t.Fatalf("Group Info failed: %s", err)
+	}
+	if info.Type != O_TYPE_GROUP {
+		t.Errorf("group has type %v", info.Type)
+	}
+	if info.RefCount != 1 {
+		t.Errorf("group has %d references, want 1", info.RefCount)
+	}
+
+	dinfo, err := dset.Info()
+	if err != nil {
+		t.Fatalf("Dataset Info failed: %s", err)
+	}
+	if dinfo.Type != O_TYPE_DATASET {
+		t.Errorf("dataset has type %v", dinfo.Type)
+	}
+	if dinfo.NumAttrs != 0 {
+		t.Errorf("dataset has %d attributes, want 0", dinfo.NumAttrs)
+	}
+	if dinfo.HeaderSize == 0 {
+		t.Errorf("dataset header size is 0")
+	}
+
+	byName, err := f.InfoByName("/grp/dset")
+	if err != nil {
+		t.Fatalf("InfoByName failed: %s", err)
+	}
+	if byName.Addr != dinfo.Addr {
+		t.Errorf("InfoByName address %d, want %d", byName.Addr, dinfo.Addr)
+	}
+	if _, err := f.InfoByName("/nope"); err == nil {
+		t.Errorf("expected an error for a missing object")
+	}
+}
+
+func TestComment(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	g, err := f.CreateGroup("grp")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	defer g.Close()
+
+	if c, err := g.Comment(); err != nil {
+		t.Fatalf("Comment failed: %s", err)
+	} else if c != "" {
+		t.Errorf("new group has comment %q", c)
+	}
+
+	const comment = "calibration runs, May 2014"
+	if err := g.SetComment(comment); err != nil {
+		t.Fatalf("SetComment failed: %s", err)
+	}
+	if c, err := g.Comment(); err != nil {
+		t.Fatalf("Comment failed: %s", err)
+	} else if c != comment {
+		t.Errorf("Comment() = %q, want %q", c, comment)
+	}
+	if c, err := f.CommentByName("grp"); err != nil {
+		t.Fatalf("CommentByName failed: %s", err)
+	} else if c != comment {
+		t.Errorf("CommentByName() = %q, want %q", c, comment)
+	}
+
+	if err := f.SetCommentByName("/grp", ""); err != nil {
+		t.Fatalf("SetCommentByName failed: %s", err)
+	}
+	if c, err := g.Comment(); err != nil {
+		t.Fatalf("Comment failed: %s", err)
+	} else if c != "" {
+		t.Errorf("comment %q not removed", c)
+	}
+}
+
+func TestCopyObject(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	dspace, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatalf("CreateDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	g, err := f.CreateGroup("src")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	defer g.Close()
+	dset, err := g.CreateDataset("dset", T_NATIVE_INT32, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	a, err := dset.CreateAttribute("units", T_NATIVE_INT32, dspace)
+	if err != nil {
+		t.Fatalf("CreateAttribute failed: %s", err)
+	}
+	a.Close()
+	dset.Close()
+	sub, err := g.CreateGroup("sub", 0, 0, 0)
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	deep, err := sub.CreateGroup("deep", 0, 0, 0)
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	deep.Close()
+	sub.Close()
+	if err := g.CreateSoftLink("/src/dset", "alias"); err != nil {
+		t.Fatalf("CreateSoftLink failed: %s", err)
+	}
+
+	if err := f.CopyObject("src", f, "full", nil); err != nil {
+		t.Fatalf("CopyObject failed: %s", err)
+	}
+	opts := &CopyOptions{ShallowHierarchy: true, ExpandSoftLinks: true, WithoutAttributes: true}
+	if err := f.CopyObject("src", f, "shallow", opts); err != nil {
+		t.Fatalf("CopyObject failed: %s", err)
+	}
+
+	for _, c := range []struct {
+		path string
+		want bool
+	}{
+		{"full/sub/deep", true},
+		{"shallow/sub", true},
+		{"shallow/sub/deep", false},
+	} {
+		if ok, err := f.LinkExists(c.path); err != nil || ok != c.want {
+			t.Errorf("LinkExists(%q) returned %v, %v, want %v", c.path, ok, err, c.want)
+		}
+	}
+	for _, c := range []struct {
+		path string
+		want LinkType
+	}{
+		{"full/alias", L_TYPE_SOFT},
+		{"shallow/alias", L_TYPE_HARD},
+	} {
+		info, err := f.LinkInfo(c.path)
+		if err != nil {
+			t.Fatalf("LinkInfo failed: %s", err)
+		}
+		if info.Type != c.want {
+			t.Errorf("%s is a %v link, want %v", c.path, info.Type, c.want)
+		}
+	}
+	for _, c := range []struct {
+		path  string
+		attrs uint
+	}{
+		{"full/dset", 1},
+		{"shallow/dset", 0},
+	} {
+		info, err := f.InfoByName(c.path)
+		if err != nil {
+			t.Fatalf("InfoByName failed: %s", err)
+		}
+		if info.NumAttrs != c.attrs {
+			t.Errorf("%s has %d attributes, want %d", c.path, info.NumAttrs, c.attrs)
+		}
+	}
+
+	ocpypl, err := NewPropList(P_OBJECT_COPY)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer ocpypl.Close()
+	if err := ocpypl.SetCopyObject(*opts); err != nil {
+		t.Fatalf("SetCopyObject failed: %s", err)
+	}
+	if got, err := ocpypl.CopyObject(); err != nil || got != *opts {
+		t.Errorf("CopyObject returned %+v, %v, want %+v", got, err, *opts)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	g, err := f.CreateGroup("run")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	defer g.Close()
+	s, err := g.CreateDatasetFromValue("samples", make([]float64, 1000), nil)
+	if err != nil {
+		t.Fatalf("CreateDatasetFromValue failed: %s", err)
+	}
+	s.Close()
+
+	u, err := f.Usage("/")
+	if err != nil {
+		t.Fatalf("Usage failed: %s", err)
+	}
+	if u.Path != "/" || len(u.Children) != 1 || len(u.Children[0].Children) != 1 {
+		t.Fatalf("Usage returned %+v", u)
+	}
+	run, samples := u.Children[0], u.Children[0].Children[0]
+	if run.Path != "/run" || samples.Path != "/run/samples" || samples.Type != O_TYPE_DATASET {
+		t.Errorf("Usage returned %+v and %+v", run, samples)
+	}
+	if samples.Raw != 8000 || samples.Metadata == 0 {
+		t.Errorf("dataset uses %d bytes of raw data and %d of metadata", samples.Raw, samples.Metadata)
+	}
+	if u.Total != u.Metadata+run.Total || run.Total != run.Metadata+samples.Total {
+		t.Errorf("totals %d, %d and %d do not add up", u.Total, run.Total, samples.Total)
+	}
+
+	d, err := g.Usage("samples")
+	if err != nil {
+		t.Fatalf("Usage failed: %s", err)
+	}
+	if d.Path != "/run/samples" || d.Total != samples.Total {
+		t.Errorf("Usage of the dataset returned %+v", d)
+	}
+}
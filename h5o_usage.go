@@ -0,0 +1,98 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"path"
+	"strings"
+)
+
+// Usage is the storage used by an object of a file and, for a group, by
+// the objects below it, as returned by Usage. Objects linked from several
+// groups are counted once, under the first path they are found at.
+type Usage struct {
+	Path     string
+	Type     ObjectType
+	Metadata uint64   // object header, link and attribute indices and heaps
+	Raw      uint64   // raw data allocated to a dataset
+	Total    uint64   // Metadata and Raw of the object and all those below it
+	Children []*Usage // objects of a group, in name order
+}
+
+// Usage returns the storage used by the object at path, "/" for the whole
+// file, and the objects below it. The size of a file also includes its
+// superblock and free space, which no object accounts for.
+// herr_t H5Oget_info(hid_t object_id, H5O_info_t *object_info)
+// hsize_t H5Dget_storage_size(hid_t dataset_id)
+func (f *File) Usage(path string) (*Usage, error) {
+	return usage(f.id, path)
+}
+
+// Usage returns the storage used by the object at path, relative to the
+// group, and the objects below it.
+// herr_t H5Oget_info(hid_t object_id, H5O_info_t *object_info)
+// hsize_t H5Dget_storage_size(hid_t dataset_id)
+func (g *Group) Usage(path string) (*Usage, error) {
+	return usage(g.id, path)
+}
+
+func usage(loc C.hid_t, name string) (*Usage, error) {
+	id, err := openObject(loc, name)
+	if err != nil {
+		return nil, err
+	}
+	defer C.H5Oclose(id)
+	info, err := objectInfo(id)
+	if err != nil {
+		return nil, err
+	}
+	root := &Usage{Path: getName(id), Type: info.Type, Metadata: objectMetadata(info)}
+	if info.Type == O_TYPE_DATASET {
+		root.Raw = uint64(C.H5Dget_storage_size(id))
+	}
+	root.Total = root.Metadata + root.Raw
+	if info.Type != O_TYPE_GROUP {
+		return root, nil
+	}
+
+	// walkObjects visits groups before the objects below them
+	byPath := map[string]*Usage{"": root}
+	err = walkObjects(id, func(rel string, info *ObjectInfo) error {
+		u := &Usage{Path: path.Join(root.Path, rel), Type: info.Type, Metadata: objectMetadata(info)}
+		if info.Type == O_TYPE_DATASET {
+			did, err := openObject(id, rel)
+			if err != nil {
+				return err
+			}
+			u.Raw = uint64(C.H5Dget_storage_size(did))
+			C.H5Oclose(did)
+		}
+		parent := ""
+		if i := strings.LastIndex(rel, "/"); i >= 0 {
+			parent = rel[:i]
+		}
+		byPath[parent].Children = append(byPath[parent].Children, u)
+		byPath[rel] = u
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	root.total()
+	return root, nil
+}
+
+// objectMetadata returns the bytes of metadata of the object of info.
+func objectMetadata(info *ObjectInfo) uint64 {
+	return info.HeaderSize + info.ObjIndexSize + info.ObjHeapSize + info.AttrIndexSize + info.AttrHeapSize
+}
+
+// total sets the totals of u and the objects below it.
+func (u *Usage) total() uint64 {
+	u.Total = u.Metadata + u.Raw
+	for _, c := range u.Children {
+		u.Total += c.total()
+	}
+	return u.Total
+}
@@ -5,6 +5,22 @@ package hdf5
 // #include <string.h>
 // inline static
 // hid_t _go_hdf5_H5P_DEFAULT() { return H5P_DEFAULT; }
+// hid_t _go_hdf5_H5P_OBJECT_CREATE() { return H5P_OBJECT_CREATE; }
+// hid_t _go_hdf5_H5P_FILE_CREATE() { return H5P_FILE_CREATE; }
+// hid_t _go_hdf5_H5P_FILE_ACCESS() { return H5P_FILE_ACCESS; }
+// hid_t _go_hdf5_H5P_DATASET_CREATE() { return H5P_DATASET_CREATE; }
+// hid_t _go_hdf5_H5P_DATASET_ACCESS() { return H5P_DATASET_ACCESS; }
+// hid_t _go_hdf5_H5P_DATASET_XFER() { return H5P_DATASET_XFER; }
+// hid_t _go_hdf5_H5P_FILE_MOUNT() { return H5P_FILE_MOUNT; }
+// hid_t _go_hdf5_H5P_GROUP_CREATE() { return H5P_GROUP_CREATE; }
+// hid_t _go_hdf5_H5P_GROUP_ACCESS() { return H5P_GROUP_ACCESS; }
+// hid_t _go_hdf5_H5P_DATATYPE_CREATE() { return H5P_DATATYPE_CREATE; }
+// hid_t _go_hdf5_H5P_DATATYPE_ACCESS() { return H5P_DATATYPE_ACCESS; }
+// hid_t _go_hdf5_H5P_STRING_CREATE() { return H5P_STRING_CREATE; }
+// hid_t _go_hdf5_H5P_ATTRIBUTE_CREATE() { return H5P_ATTRIBUTE_CREATE; }
+// hid_t _go_hdf5_H5P_OBJECT_COPY() { return H5P_OBJECT_COPY; }
+// hid_t _go_hdf5_H5P_LINK_CREATE() { return H5P_LINK_CREATE; }
+// hid_t _go_hdf5_H5P_LINK_ACCESS() { return H5P_LINK_ACCESS; }
 import "C"
 
 import (
@@ -16,6 +32,26 @@ import (
 
 type PropType C.hid_t
 
+// Property list classes
+var (
+	P_OBJECT_CREATE    PropType = PropType(C._go_hdf5_H5P_OBJECT_CREATE())
+	P_FILE_CREATE      PropType = PropType(C._go_hdf5_H5P_FILE_CREATE())
+	P_FILE_ACCESS      PropType = PropType(C._go_hdf5_H5P_FILE_ACCESS())
+	P_DATASET_CREATE   PropType = PropType(C._go_hdf5_H5P_DATASET_CREATE())
+	P_DATASET_ACCESS   PropType = PropType(C._go_hdf5_H5P_DATASET_ACCESS())
+	P_DATASET_XFER     PropType = PropType(C._go_hdf5_H5P_DATASET_XFER())
+	P_FILE_MOUNT       PropType = PropType(C._go_hdf5_H5P_FILE_MOUNT())
+	P_GROUP_CREATE     PropType = PropType(C._go_hdf5_H5P_GROUP_CREATE())
+	P_GROUP_ACCESS     PropType = PropType(C._go_hdf5_H5P_GROUP_ACCESS())
+	P_DATATYPE_CREATE  PropType = PropType(C._go_hdf5_H5P_DATATYPE_CREATE())
+	P_DATATYPE_ACCESS  PropType = PropType(C._go_hdf5_H5P_DATATYPE_ACCESS())
+	P_STRING_CREATE    PropType = PropType(C._go_hdf5_H5P_STRING_CREATE())
+	P_ATTRIBUTE_CREATE PropType = PropType(C._go_hdf5_H5P_ATTRIBUTE_CREATE())
+	P_OBJECT_COPY      PropType = PropType(C._go_hdf5_H5P_OBJECT_COPY())
+	P_LINK_CREATE      PropType = PropType(C._go_hdf5_H5P_LINK_CREATE())
+	P_LINK_ACCESS      PropType = PropType(C._go_hdf5_H5P_LINK_ACCESS())
+)
+
 // --- H5P: Property List Interface ---
 
 type PropList struct {
@@ -29,6 +65,7 @@ var (
 func new_proplist(id C.hid_t) *PropList {
 	p := &PropList{id: id}
 	runtime.SetFinalizer(p, (*PropList).finalizer)
+	trackID(id)
 	return p
 }
 
@@ -55,8 +92,13 @@ func NewPropList(cls_id PropType) (*PropList, error) {
 // Terminates access to a property list.
 // herr_t H5Pclose(hid_t plist )
 func (p *PropList) Close() error {
-	err := C.H5Pclose(p.id)
-	return h5err(err)
+	if p.id > 0 {
+		releaseConvHandler(p.id)
+		err := C.H5Pclose(p.id)
+		p.id = 0
+		return h5err(err)
+	}
+	return nil
 }
 
 // Copies an existing property list to create a new property list.
@@ -69,6 +111,7 @@ func (p *PropList) Copy() (*PropList, error) {
 		return nil, err
 	}
 	o := new_proplist(hid)
+	shareConvHandler(p.id, hid)
 	return o, err
 
 }
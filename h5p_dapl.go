@@ -0,0 +1,34 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+// --- Dataset access properties ---
+
+// Values of SetChunkCache that keep the settings of the file access
+// property list.
+const (
+	D_CHUNK_CACHE_NSLOTS_DEFAULT uint    = ^uint(0)
+	D_CHUNK_CACHE_NBYTES_DEFAULT uint    = ^uint(0)
+	D_CHUNK_CACHE_W0_DEFAULT     float64 = -1
+)
+
+// SetChunkCache sets the raw data chunk cache of datasets opened with this
+// dataset access property list to nbytes bytes in nslots hash slots, which
+// should be a prime about 100 times the number of chunks that fit. w0,
+// between 0 and 1, is how strongly fully read or written chunks are
+// preferred for eviction. Chunks larger than the cache are not cached, so
+// the cache should hold at least the chunks a read spans.
+// herr_t H5Pset_chunk_cache(hid_t dapl_id, size_t rdcc_nslots, size_t rdcc_nbytes, double rdcc_w0)
+func (p *PropList) SetChunkCache(nslots, nbytes uint, w0 float64) error {
+	return h5err(C.H5Pset_chunk_cache(p.id, C.size_t(nslots), C.size_t(nbytes), C.double(w0)))
+}
+
+// ChunkCache returns the chunk cache settings set by SetChunkCache.
+// herr_t H5Pget_chunk_cache(hid_t dapl_id, size_t *rdcc_nslots, size_t *rdcc_nbytes, double *rdcc_w0)
+func (p *PropList) ChunkCache() (nslots, nbytes uint, w0 float64, err error) {
+	var c_nslots, c_nbytes C.size_t
+	var c_w0 C.double
+	err = h5err(C.H5Pget_chunk_cache(p.id, &c_nslots, &c_nbytes, &c_w0))
+	return uint(c_nslots), uint(c_nbytes), float64(c_w0), err
+}
@@ -0,0 +1,194 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// #include <string.h>
+import "C"
+
+import (
+	"fmt"
+)
+
+// --- Dataset creation properties ---
+
+// AllocTime is the time at which storage space for a dataset is allocated.
+type AllocTime C.H5D_alloc_time_t
+
+const (
+	D_ALLOC_TIME_ERROR   AllocTime = -1 // Error
+	D_ALLOC_TIME_DEFAULT AllocTime = 0  // default for the dataset's storage layout
+	D_ALLOC_TIME_EARLY   AllocTime = 1  // allocate all space when the dataset is created
+	D_ALLOC_TIME_LATE    AllocTime = 2  // allocate all space when data is first written
+	D_ALLOC_TIME_INCR    AllocTime = 3  // allocate space incrementally, as data is written
+)
+
+// FillTime is the time at which allocated space is set to the fill value.
+type FillTime C.H5D_fill_time_t
+
+const (
+	D_FILL_TIME_ERROR FillTime = -1 // Error
+	D_FILL_TIME_ALLOC FillTime = 0  // write the fill value when space is allocated
+	D_FILL_TIME_NEVER FillTime = 1  // never write the fill value
+	D_FILL_TIME_IFSET FillTime = 2  // write the fill value if one has been set
+)
+
+// Layout is the storage layout of the raw data of a dataset.
+type Layout C.H5D_layout_t
+
+const (
+	D_LAYOUT_ERROR Layout = -1 // Error
+	D_COMPACT      Layout = 0  // raw data in the object header, for datasets under 64KB
+	D_CONTIGUOUS   Layout = 1  // raw data in one contiguous block of the file
+	D_CHUNKED      Layout = 2  // raw data in chunks, required by filters and resizing
+	D_VIRTUAL      Layout = 3  // raw data mapped from other datasets
+)
+
+func (l Layout) String() string {
+	switch l {
+	case D_COMPACT:
+		return "compact"
+	case D_CONTIGUOUS:
+		return "contiguous"
+	case D_CHUNKED:
+		return "chunked"
+	case D_VIRTUAL:
+		return "virtual"
+	}
+	return "unknown"
+}
+
+// SetLayout sets the storage layout of datasets created with this
+// property list. Compact storage keeps the data of tiny datasets in their
+// object header, which makes them much faster to access, but their data
+// must fit in 64KB. SetChunk sets the chunked layout itself.
+// herr_t H5Pset_layout(hid_t plist_id, H5D_layout_t layout)
+func (p *PropList) SetLayout(layout Layout) error {
+	return h5err(C.H5Pset_layout(p.id, C.H5D_layout_t(layout)))
+}
+
+// Layout returns the storage layout of this property list.
+// H5D_layout_t H5Pget_layout(hid_t plist_id)
+func (p *PropList) Layout() (Layout, error) {
+	layout := Layout(C.H5Pget_layout(p.id))
+	if layout < 0 {
+		return layout, fmt.Errorf("hdf5: could not get the layout of the property list")
+	}
+	return layout, nil
+}
+
+// SetAllocTime sets the time at which storage space is allocated for
+// datasets created with this property list.
+// herr_t H5Pset_alloc_time(hid_t plist_id, H5D_alloc_time_t alloc_time)
+func (p *PropList) SetAllocTime(t AllocTime) error {
+	return h5err(C.H5Pset_alloc_time(p.id, C.H5D_alloc_time_t(t)))
+}
+
+// AllocTime returns the storage allocation time of this property list.
+// herr_t H5Pget_alloc_time(hid_t plist_id, H5D_alloc_time_t *alloc_time)
+func (p *PropList) AllocTime() (AllocTime, error) {
+	var t C.H5D_alloc_time_t
+	err := h5err(C.H5Pget_alloc_time(p.id, &t))
+	return AllocTime(t), err
+}
+
+// SetFillTime sets the time at which the fill value is written to
+// allocated storage for datasets created with this property list.
+// herr_t H5Pset_fill_time(hid_t plist_id, H5D_fill_time_t fill_time)
+func (p *PropList) SetFillTime(t FillTime) error {
+	return h5err(C.H5Pset_fill_time(p.id, C.H5D_fill_time_t(t)))
+}
+
+// FillTime returns the fill value write time of this property list.
+// herr_t H5Pget_fill_time(hid_t plist_id, H5D_fill_time_t *fill_time)
+func (p *PropList) FillTime() (FillTime, error) {
+	var t C.H5D_fill_time_t
+	err := h5err(C.H5Pget_fill_time(p.id, &t))
+	return FillTime(t), err
+}
+
+// SetChunk sets the size of the chunks used to store a chunked dataset.
+// herr_t H5Pset_chunk(hid_t plist_id, int ndims, const hsize_t * dim)
+func (p *PropList) SetChunk(dims []uint) error {
+	if len(dims) == 0 {
+		return fmt.Errorf("chunk dimensions must not be empty")
+	}
+	c_dims := make([]C.hsize_t, len(dims))
+	for i, d := range dims {
+		c_dims[i] = C.hsize_t(d)
+	}
+	return h5err(C.H5Pset_chunk(p.id, C.int(len(dims)), &c_dims[0]))
+}
+
+// Chunk returns the chunk dimensions of this property list, or nil if the
+// layout is not chunked.
+// int H5Pget_chunk(hid_t plist_id, int max_ndims, hsize_t * dims)
+func (p *PropList) Chunk() ([]uint, error) {
+	c_dims := make([]C.hsize_t, 32) // H5S_MAX_RANK
+	rank := C.H5Pget_chunk(p.id, C.int(len(c_dims)), &c_dims[0])
+	if rank < 0 {
+		// not a chunked layout
+		return nil, nil
+	}
+	dims := make([]uint, int(rank))
+	for i := range dims {
+		dims[i] = uint(c_dims[i])
+	}
+	return dims, nil
+}
+
+// SetDeflate sets deflate (gzip) compression with the given level, from 0
+// to 9. The dataset must be chunked.
+// herr_t H5Pset_deflate(hid_t plist_id, uint level)
+func (p *PropList) SetDeflate(level uint) error {
+	return h5err(C.H5Pset_deflate(p.id, C.uint(level)))
+}
+
+// Szip option flags of SetSzip.
+const (
+	SZIP_ALLOW_K13_OPTION_MASK uint = C.H5_SZIP_ALLOW_K13_OPTION_MASK // allow k=13 compression mode
+	SZIP_CHIP_OPTION_MASK      uint = C.H5_SZIP_CHIP_OPTION_MASK      // compatible with the szip chip
+	SZIP_EC_OPTION_MASK        uint = C.H5_SZIP_EC_OPTION_MASK        // entropy coding, for data without spatial correlation
+	SZIP_NN_OPTION_MASK        uint = C.H5_SZIP_NN_OPTION_MASK        // nearest neighbor preprocessing, then entropy coding
+
+	SZIP_MAX_PIXELS_PER_BLOCK uint = C.H5_SZIP_MAX_PIXELS_PER_BLOCK
+)
+
+// SetSzip sets szip compression with the coding method of options, one of
+// SZIP_EC_OPTION_MASK and SZIP_NN_OPTION_MASK, and pixelsPerBlock, an even
+// number up to SZIP_MAX_PIXELS_PER_BLOCK. The dataset must be chunked.
+// Libraries may decode szip without being able to encode it, see
+// SzipAvailable.
+// herr_t H5Pset_szip(hid_t plist, unsigned int options_mask, unsigned int pixels_per_block)
+func (p *PropList) SetSzip(options, pixelsPerBlock uint) error {
+	if _, encode, err := SzipAvailable(); err != nil {
+		return err
+	} else if !encode {
+		return fmt.Errorf("hdf5: szip encoding is not available in this library")
+	}
+	return h5err(C.H5Pset_szip(p.id, C.uint(options), C.uint(pixelsPerBlock)))
+}
+
+// SetFletcher32 adds the Fletcher32 checksum filter, which detects the
+// corruption of chunks as they are read. The dataset must be chunked.
+// herr_t H5Pset_fletcher32(hid_t plist_id)
+func (p *PropList) SetFletcher32() error {
+	return h5err(C.H5Pset_fletcher32(p.id))
+}
+
+// RemoveFilters removes all filters, such as compression, from the filter
+// pipeline of this property list.
+// herr_t H5Premove_filter(hid_t plist_id, H5Z_filter_t filter)
+func (p *PropList) RemoveFilters() error {
+	return h5err(C.H5Premove_filter(p.id, C.H5Z_FILTER_ALL))
+}
+
+// NumFilters returns the number of filters in the filter pipeline of this
+// property list.
+// int H5Pget_nfilters(hid_t plist_id)
+func (p *PropList) NumFilters() (int, error) {
+	n := C.H5Pget_nfilters(p.id)
+	if err := h5err(C.herr_t(n)); err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
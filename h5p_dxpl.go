@@ -0,0 +1,255 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdint.h>
+// #include <stdlib.h>
+//
+// extern H5T_conv_ret_t _go_hdf5_conv_except(H5T_conv_except_t except, hid_t src_id, hid_t dst_id, void *src, void *dst, uintptr_t handler);
+//
+// static H5T_conv_ret_t _go_hdf5_conv_except_cb(H5T_conv_except_t except, hid_t src_id, hid_t dst_id, void *src, void *dst, void *user_data) {
+//   return _go_hdf5_conv_except(except, src_id, dst_id, src, dst, (uintptr_t)user_data);
+// }
+// static herr_t _go_hdf5_set_type_conv_cb(hid_t dxpl_id, uintptr_t handler) {
+//   if (handler == 0) return H5Pset_type_conv_cb(dxpl_id, NULL, NULL);
+//   return H5Pset_type_conv_cb(dxpl_id, _go_hdf5_conv_except_cb, (void *)handler);
+// }
+import "C"
+
+import (
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// --- Dataset transfer properties ---
+
+// SetBuffer sets the size in bytes of the type conversion and background
+// buffers of transfers with this dataset transfer property list, which the
+// library allocates. Transfers needing conversion are done in strips of
+// this size, 1MB by default, so larger buffers speed up the conversion of
+// compound types.
+// herr_t H5Pset_buffer(hid_t plist, size_t size, void *tconv, void *bkg)
+func (p *PropList) SetBuffer(size uint) error {
+	return h5err(C.H5Pset_buffer(p.id, C.size_t(size), nil, nil))
+}
+
+// Buffer returns the conversion buffer size set by SetBuffer.
+// size_t H5Pget_buffer(hid_t plist, void **tconv, void **bkg)
+func (p *PropList) Buffer() (uint, error) {
+	size := C.H5Pget_buffer(p.id, nil, nil)
+	if size == 0 {
+		return 0, fmt.Errorf("could not get the buffer size")
+	}
+	return uint(size), nil
+}
+
+// SetPreserve sets whether transfers with this dataset transfer property
+// list that write only some fields of a compound type keep the values of
+// the other fields in the file.
+// herr_t H5Pset_preserve(hid_t plist, hbool_t status)
+func (p *PropList) SetPreserve(preserve bool) error {
+	var c_preserve C.hbool_t
+	if preserve {
+		c_preserve = 1
+	}
+	return h5err(C.H5Pset_preserve(p.id, c_preserve))
+}
+
+// Preserve returns the setting set by SetPreserve.
+// int H5Pget_preserve(hid_t plist)
+func (p *PropList) Preserve() (bool, error) {
+	o := C.H5Pget_preserve(p.id)
+	if err := h5err(C.herr_t(o)); err != nil {
+		return false, err
+	}
+	return o > 0, nil
+}
+
+// SetEDCCheck sets whether reads with this dataset transfer property list
+// verify the checksums of chunks, such as those of the Fletcher32 filter,
+// which they do by default. Reads without verification return corrupted
+// data rather than fail.
+// herr_t H5Pset_edc_check(hid_t plist, H5Z_EDC_t check)
+func (p *PropList) SetEDCCheck(check bool) error {
+	c_check := C.H5Z_EDC_t(C.H5Z_DISABLE_EDC)
+	if check {
+		c_check = C.H5Z_ENABLE_EDC
+	}
+	return h5err(C.H5Pset_edc_check(p.id, c_check))
+}
+
+// EDCCheck returns the setting set by SetEDCCheck.
+// H5Z_EDC_t H5Pget_edc_check(hid_t plist)
+func (p *PropList) EDCCheck() (bool, error) {
+	c_check := C.H5Pget_edc_check(p.id)
+	if err := h5err(C.herr_t(c_check)); err != nil {
+		return false, err
+	}
+	return c_check == C.H5Z_ENABLE_EDC, nil
+}
+
+// SetHyperVectorSize sets the number of I/O vectors hyperslab transfers
+// with this dataset transfer property list are built from, 1024 by
+// default.
+// herr_t H5Pset_hyper_vector_size(hid_t dxpl_id, size_t vector_size)
+func (p *PropList) SetHyperVectorSize(size uint) error {
+	return h5err(C.H5Pset_hyper_vector_size(p.id, C.size_t(size)))
+}
+
+// HyperVectorSize returns the vector size set by SetHyperVectorSize.
+// herr_t H5Pget_hyper_vector_size(hid_t dxpl_id, size_t *vector_size)
+func (p *PropList) HyperVectorSize() (uint, error) {
+	var size C.size_t
+	err := h5err(C.H5Pget_hyper_vector_size(p.id, &size))
+	return uint(size), err
+}
+
+// SetDataTransform sets an algebraic expression in x, such as
+// "(x+100)/5", that transfers with this dataset transfer property list
+// apply to each value: reads transform the values of the file and writes
+// the values of memory. Only integer and floating-point data can be
+// transformed.
+// herr_t H5Pset_data_transform(hid_t plist_id, const char *expression)
+func (p *PropList) SetDataTransform(expr string) error {
+	c_expr := C.CString(expr)
+	defer C.free(unsafe.Pointer(c_expr))
+
+	return h5err(C.H5Pset_data_transform(p.id, c_expr))
+}
+
+// DataTransform returns the expression set by SetDataTransform.
+// ssize_t H5Pget_data_transform(hid_t plist_id, char *expression, size_t size)
+func (p *PropList) DataTransform() (string, error) {
+	size := C.H5Pget_data_transform(p.id, nil, 0)
+	if size < 0 {
+		return "", fmt.Errorf("could not get the data transform")
+	}
+	buf := make([]C.char, size+1)
+	size = C.H5Pget_data_transform(p.id, &buf[0], C.size_t(size)+1)
+	if size < 0 {
+		return "", fmt.Errorf("could not get the data transform")
+	}
+	return C.GoString(&buf[0]), nil
+}
+
+// ConvException is a kind of exception of a datatype conversion, a value
+// of the source type that the destination type cannot represent exactly.
+type ConvException int
+
+const (
+	T_CONV_EXCEPT_RANGE_HI  ConvException = C.H5T_CONV_EXCEPT_RANGE_HI  // above the range of the destination type
+	T_CONV_EXCEPT_RANGE_LOW ConvException = C.H5T_CONV_EXCEPT_RANGE_LOW // below the range of the destination type
+	T_CONV_EXCEPT_PRECISION ConvException = C.H5T_CONV_EXCEPT_PRECISION // an integer losing precision as a float
+	T_CONV_EXCEPT_TRUNCATE  ConvException = C.H5T_CONV_EXCEPT_TRUNCATE  // a float losing its fraction as an integer
+	T_CONV_EXCEPT_PINF      ConvException = C.H5T_CONV_EXCEPT_PINF      // positive infinity as an integer
+	T_CONV_EXCEPT_NINF      ConvException = C.H5T_CONV_EXCEPT_NINF      // negative infinity as an integer
+	T_CONV_EXCEPT_NAN       ConvException = C.H5T_CONV_EXCEPT_NAN       // NaN as an integer
+)
+
+// ConvAction is what a ConvHandler did with an exception.
+type ConvAction int
+
+const (
+	T_CONV_ABORT     ConvAction = C.H5T_CONV_ABORT     // fail the transfer
+	T_CONV_UNHANDLED ConvAction = C.H5T_CONV_UNHANDLED // let the library convert the value, clamping it to the range of the destination
+	T_CONV_HANDLED   ConvAction = C.H5T_CONV_HANDLED   // the handler wrote the converted value
+)
+
+// ConvHandler handles an exception of a datatype conversion of the value
+// src, the bytes of an element of the source type, to dst, those of an
+// element of the destination type, which it sets when it returns
+// T_CONV_HANDLED. The slices are only valid during the call.
+type ConvHandler func(except ConvException, src, dst []byte) ConvAction
+
+// AbortConvOn returns a ConvHandler that fails the transfer on the given
+// exceptions, so that e.g. a read narrowing the datatype of a dataset
+// returns an error rather than clamped values, and leaves the others to
+// the library.
+func AbortConvOn(excepts ...ConvException) ConvHandler {
+	return func(except ConvException, src, dst []byte) ConvAction {
+		for _, e := range excepts {
+			if e == except {
+				return T_CONV_ABORT
+			}
+		}
+		return T_CONV_UNHANDLED
+	}
+}
+
+// convHandlers holds the handlers set on dataset transfer property lists,
+// under the handles the C side refers to them by, and the handle set on
+// each list, so that a handler is released once the lists sharing it,
+// those it was set on and their copies, are closed or given another.
+var convHandlers = struct {
+	sync.Mutex
+	next     uintptr
+	handlers map[uintptr]*convHandler
+	lists    map[C.hid_t]uintptr
+}{
+	handlers: make(map[uintptr]*convHandler),
+	lists:    make(map[C.hid_t]uintptr),
+}
+
+type convHandler struct {
+	h    ConvHandler
+	refs int // property lists holding the handle
+}
+
+// SetTypeConvHandler sets the handler of the exceptions of the datatype
+// conversions of transfers with this dataset transfer property list, such
+// as values overflowing a narrower integer type on read, or removes it if
+// h is nil. Without a handler, out of range values are clamped. The
+// handler is called from the goroutine of the transfer, and is released
+// when the list and its copies are closed.
+// herr_t H5Pset_type_conv_cb(hid_t dxpl_id, H5T_conv_except_func_t op, void *operate_data)
+func (p *PropList) SetTypeConvHandler(h ConvHandler) error {
+	convHandlers.Lock()
+	defer convHandlers.Unlock()
+	var handle uintptr
+	if h != nil {
+		convHandlers.next++
+		handle = convHandlers.next
+	}
+	if err := h5err(C._go_hdf5_set_type_conv_cb(p.id, C.uintptr_t(handle))); err != nil {
+		return err
+	}
+	unrefConvHandler(p.id)
+	if h != nil {
+		convHandlers.handlers[handle] = &convHandler{h: h, refs: 1}
+		convHandlers.lists[p.id] = handle
+	}
+	return nil
+}
+
+// shareConvHandler records that the property list dst, a copy of src,
+// holds the handle of the handler of src, if any.
+func shareConvHandler(src, dst C.hid_t) {
+	convHandlers.Lock()
+	defer convHandlers.Unlock()
+	if handle, ok := convHandlers.lists[src]; ok {
+		convHandlers.handlers[handle].refs++
+		convHandlers.lists[dst] = handle
+	}
+}
+
+// releaseConvHandler drops the reference of the property list id to its
+// handler as it is closed.
+func releaseConvHandler(id C.hid_t) {
+	convHandlers.Lock()
+	defer convHandlers.Unlock()
+	unrefConvHandler(id)
+}
+
+// unrefConvHandler is releaseConvHandler with convHandlers locked.
+func unrefConvHandler(id C.hid_t) {
+	handle, ok := convHandlers.lists[id]
+	if !ok {
+		return
+	}
+	delete(convHandlers.lists, id)
+	c := convHandlers.handlers[handle]
+	c.refs--
+	if c.refs == 0 {
+		delete(convHandlers.handlers, handle)
+	}
+}
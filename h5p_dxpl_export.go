@@ -0,0 +1,24 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdint.h>
+import "C"
+
+import "unsafe"
+
+// The callback of the ConvHandlers, called by the C callback in
+// h5p_dxpl.go. A released handler leaves the exception to the library.
+
+//export _go_hdf5_conv_except
+func _go_hdf5_conv_except(except C.H5T_conv_except_t, src_id, dst_id C.hid_t, src, dst unsafe.Pointer, handler C.uintptr_t) C.H5T_conv_ret_t {
+	convHandlers.Lock()
+	c := convHandlers.handlers[uintptr(handler)]
+	convHandlers.Unlock()
+	if c == nil {
+		return C.H5T_CONV_UNHANDLED
+	}
+	h := c.h
+	srcBuf := unsafe.Slice((*byte)(src), int(C.H5Tget_size(src_id)))
+	dstBuf := unsafe.Slice((*byte)(dst), int(C.H5Tget_size(dst_id)))
+	return C.H5T_conv_ret_t(h(ConvException(except), srcBuf, dstBuf))
+}
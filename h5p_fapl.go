@@ -0,0 +1,315 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// #if H5_VERSION_GE(1,10,1)
+// static herr_t _go_hdf5_H5Pset_page_buffer_size(hid_t plist_id, size_t buf_size, unsigned min_meta_per, unsigned min_raw_per) {
+//   return H5Pset_page_buffer_size(plist_id, buf_size, min_meta_per, min_raw_per);
+// }
+// static herr_t _go_hdf5_H5Pget_page_buffer_size(hid_t plist_id, size_t *buf_size, unsigned *min_meta_per, unsigned *min_raw_per) {
+//   return H5Pget_page_buffer_size(plist_id, buf_size, min_meta_per, min_raw_per);
+// }
+// static herr_t _go_hdf5_H5Pset_evict_on_close(hid_t fapl_id, unsigned evict_on_close) {
+//   return H5Pset_evict_on_close(fapl_id, evict_on_close != 0);
+// }
+// static herr_t _go_hdf5_H5Pget_evict_on_close(hid_t fapl_id, unsigned *evict_on_close) {
+//   hbool_t evict;
+//   herr_t err = H5Pget_evict_on_close(fapl_id, &evict);
+//   *evict_on_close = evict;
+//   return err;
+// }
+// #else
+// static herr_t _go_hdf5_H5Pset_page_buffer_size(hid_t plist_id, size_t buf_size, unsigned min_meta_per, unsigned min_raw_per) { return -1; }
+// static herr_t _go_hdf5_H5Pget_page_buffer_size(hid_t plist_id, size_t *buf_size, unsigned *min_meta_per, unsigned *min_raw_per) { return -1; }
+// static herr_t _go_hdf5_H5Pset_evict_on_close(hid_t fapl_id, unsigned evict_on_close) { return -1; }
+// static herr_t _go_hdf5_H5Pget_evict_on_close(hid_t fapl_id, unsigned *evict_on_close) { return -1; }
+// #endif
+// #if H5_VERSION_GE(1,12,1) || (H5_VERSION_GE(1,10,7) && !H5_VERSION_GE(1,11,0))
+// #define _GO_HDF5_HAVE_FILE_LOCKING 1
+// static herr_t _go_hdf5_H5Pset_file_locking(hid_t fapl_id, unsigned use_file_locking, unsigned ignore_when_disabled) {
+//   return H5Pset_file_locking(fapl_id, use_file_locking != 0, ignore_when_disabled != 0);
+// }
+// static herr_t _go_hdf5_H5Pget_file_locking(hid_t fapl_id, unsigned *use_file_locking, unsigned *ignore_when_disabled) {
+//   hbool_t use, ignore;
+//   herr_t err = H5Pget_file_locking(fapl_id, &use, &ignore);
+//   *use_file_locking = use;
+//   *ignore_when_disabled = ignore;
+//   return err;
+// }
+// #else
+// #define _GO_HDF5_HAVE_FILE_LOCKING 0
+// static herr_t _go_hdf5_H5Pset_file_locking(hid_t fapl_id, unsigned use_file_locking, unsigned ignore_when_disabled) { return -1; }
+// static herr_t _go_hdf5_H5Pget_file_locking(hid_t fapl_id, unsigned *use_file_locking, unsigned *ignore_when_disabled) { return -1; }
+// #endif
+// #if H5_VERSION_GE(1,10,0)
+// static herr_t _go_hdf5_H5Pset_mdc_log_options(hid_t plist_id, unsigned is_enabled, const char *location, unsigned start_on_access) {
+//   return H5Pset_mdc_log_options(plist_id, is_enabled != 0, location, start_on_access != 0);
+// }
+// static herr_t _go_hdf5_H5Pget_mdc_log_options(hid_t plist_id, unsigned *is_enabled, char *location, size_t *location_size, unsigned *start_on_access) {
+//   hbool_t enabled, start;
+//   herr_t err = H5Pget_mdc_log_options(plist_id, &enabled, location, location_size, &start);
+//   *is_enabled = enabled;
+//   *start_on_access = start;
+//   return err;
+// }
+// #else
+// static herr_t _go_hdf5_H5Pset_mdc_log_options(hid_t plist_id, unsigned is_enabled, const char *location, unsigned start_on_access) { return -1; }
+// static herr_t _go_hdf5_H5Pget_mdc_log_options(hid_t plist_id, unsigned *is_enabled, char *location, size_t *location_size, unsigned *start_on_access) { return -1; }
+// #endif
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// --- File access properties ---
+
+// Libver is a version of the library, which bounds the versions of the
+// file format objects are written with.
+type Libver C.H5F_libver_t
+
+const (
+	F_LIBVER_EARLIEST Libver = 0                   // earliest format able to store each object
+	F_LIBVER_V18      Libver = 1                   // formats of the 1.8 library
+	F_LIBVER_V110     Libver = 2                   // formats of the 1.10 library, unknown to 1.8 libraries
+	F_LIBVER_LATEST   Libver = C.H5F_LIBVER_LATEST // latest formats of the linked library
+)
+
+// SetLibverBounds sets the earliest and latest library versions whose file
+// formats objects in files opened with this file access property list are
+// written with. low = F_LIBVER_V18 keeps files readable by 1.8 readers,
+// while features such as SWMR need high = F_LIBVER_LATEST.
+// herr_t H5Pset_libver_bounds(hid_t fapl_id, H5F_libver_t low, H5F_libver_t high)
+func (p *PropList) SetLibverBounds(low, high Libver) error {
+	return h5err(C.H5Pset_libver_bounds(p.id, C.H5F_libver_t(low), C.H5F_libver_t(high)))
+}
+
+// LibverBounds returns the library version bounds set by SetLibverBounds.
+// herr_t H5Pget_libver_bounds(hid_t fapl_id, H5F_libver_t *low, H5F_libver_t *high)
+func (p *PropList) LibverBounds() (low, high Libver, err error) {
+	var c_low, c_high C.H5F_libver_t
+	err = h5err(C.H5Pget_libver_bounds(p.id, &c_low, &c_high))
+	return Libver(c_low), Libver(c_high), err
+}
+
+// SetMDCConfig sets the initial configuration of the metadata cache of
+// files opened with this file access property list.
+// herr_t H5Pset_mdc_config(hid_t plist_id, H5AC_cache_config_t *config_ptr)
+func (p *PropList) SetMDCConfig(cfg *MDCConfig) error {
+	var c C.H5AC_cache_config_t
+	c.version = C.H5AC__CURR_CACHE_CONFIG_VERSION
+	if err := h5err(C.H5Pget_mdc_config(p.id, &c)); err != nil {
+		return err
+	}
+	cfg.apply(&c)
+	return h5err(C.H5Pset_mdc_config(p.id, &c))
+}
+
+// MDCConfig returns the metadata cache configuration set by SetMDCConfig.
+// herr_t H5Pget_mdc_config(hid_t plist_id, H5AC_cache_config_t *config_ptr)
+func (p *PropList) MDCConfig() (*MDCConfig, error) {
+	var c C.H5AC_cache_config_t
+	c.version = C.H5AC__CURR_CACHE_CONFIG_VERSION
+	if err := h5err(C.H5Pget_mdc_config(p.id, &c)); err != nil {
+		return nil, err
+	}
+	return newMDCConfig(&c), nil
+}
+
+// SetMDCLogOptions sets whether the accesses to the metadata cache of files
+// opened with this file access property list can be logged, to the file
+// location, and whether logging starts when they are opened rather than
+// with File.StartMDCLogging. It needs HDF5 1.10.0.
+// herr_t H5Pset_mdc_log_options(hid_t plist_id, hbool_t is_enabled, const char *location, hbool_t start_on_access)
+func (p *PropList) SetMDCLogOptions(enabled bool, location string, startOnAccess bool) error {
+	if err := requireVersion("H5Pset_mdc_log_options", 1, 10, 0); err != nil {
+		return err
+	}
+	c_location := C.CString(location)
+	defer C.free(unsafe.Pointer(c_location))
+	return h5err(C._go_hdf5_H5Pset_mdc_log_options(p.id, C.uint(cbool(enabled)), c_location, C.uint(cbool(startOnAccess))))
+}
+
+// MDCLogOptions returns the metadata cache logging options set by
+// SetMDCLogOptions.
+// herr_t H5Pget_mdc_log_options(hid_t plist_id, hbool_t *is_enabled, char *location, size_t *location_size, hbool_t *start_on_access)
+func (p *PropList) MDCLogOptions() (enabled bool, location string, startOnAccess bool, err error) {
+	if err := requireVersion("H5Pget_mdc_log_options", 1, 10, 0); err != nil {
+		return false, "", false, err
+	}
+	var c_enabled, c_start C.uint
+	var c_size C.size_t
+	if err := h5err(C._go_hdf5_H5Pget_mdc_log_options(p.id, &c_enabled, nil, &c_size, &c_start)); err != nil {
+		return false, "", false, err
+	}
+	if c_size > 0 {
+		buf := make([]byte, c_size)
+		c_buf := (*C.char)(unsafe.Pointer(&buf[0]))
+		if err := h5err(C._go_hdf5_H5Pget_mdc_log_options(p.id, &c_enabled, c_buf, &c_size, &c_start)); err != nil {
+			return false, "", false, err
+		}
+		location = C.GoString(c_buf)
+	}
+	return c_enabled != 0, location, c_start != 0, nil
+}
+
+// SetPageBufferSize sets the size in bytes of the page buffer of files
+// opened with this file access property list, which caches whole pages of
+// files created with paged aggregation, see SetPagedAggregation. At least
+// minMetaPercent and minRawPercent percent of the buffer are kept for
+// metadata and raw data pages. The size must be a multiple of the page
+// size of the file. It needs HDF5 1.10.1.
+// herr_t H5Pset_page_buffer_size(hid_t plist_id, size_t buf_size, unsigned min_meta_per, unsigned min_raw_per)
+func (p *PropList) SetPageBufferSize(size, minMetaPercent, minRawPercent uint) error {
+	if err := requireVersion("H5Pset_page_buffer_size", 1, 10, 1); err != nil {
+		return err
+	}
+	return h5err(C._go_hdf5_H5Pset_page_buffer_size(p.id, C.size_t(size), C.uint(minMetaPercent), C.uint(minRawPercent)))
+}
+
+// PageBufferSize returns the page buffer settings set by SetPageBufferSize.
+// herr_t H5Pget_page_buffer_size(hid_t plist_id, size_t *buf_size, unsigned *min_meta_per, unsigned *min_raw_per)
+func (p *PropList) PageBufferSize() (size, minMetaPercent, minRawPercent uint, err error) {
+	if err := requireVersion("H5Pget_page_buffer_size", 1, 10, 1); err != nil {
+		return 0, 0, 0, err
+	}
+	var c_size C.size_t
+	var c_meta, c_raw C.uint
+	err = h5err(C._go_hdf5_H5Pget_page_buffer_size(p.id, &c_size, &c_meta, &c_raw))
+	return uint(c_size), uint(c_meta), uint(c_raw), err
+}
+
+// SetEvictOnClose sets whether the metadata of objects in files opened with
+// this file access property list is evicted from the metadata cache when
+// the objects are closed, so that scanning many objects does not keep
+// growing the cache. It needs HDF5 1.10.1.
+// herr_t H5Pset_evict_on_close(hid_t fapl_id, hbool_t evict_on_close)
+func (p *PropList) SetEvictOnClose(evict bool) error {
+	if err := requireVersion("H5Pset_evict_on_close", 1, 10, 1); err != nil {
+		return err
+	}
+	var c_evict C.uint
+	if evict {
+		c_evict = 1
+	}
+	return h5err(C._go_hdf5_H5Pset_evict_on_close(p.id, c_evict))
+}
+
+// EvictOnClose returns the setting set by SetEvictOnClose.
+// herr_t H5Pget_evict_on_close(hid_t fapl_id, hbool_t *evict_on_close)
+func (p *PropList) EvictOnClose() (bool, error) {
+	if err := requireVersion("H5Pget_evict_on_close", 1, 10, 1); err != nil {
+		return false, err
+	}
+	var c_evict C.uint
+	err := h5err(C._go_hdf5_H5Pget_evict_on_close(p.id, &c_evict))
+	return c_evict != 0, err
+}
+
+// SetCache sets the default raw data chunk cache of the datasets of files
+// opened with this file access property list, as SetChunkCache does for a
+// single dataset.
+// herr_t H5Pset_cache(hid_t plist_id, int mdc_nelmts, size_t rdcc_nslots, size_t rdcc_nbytes, double rdcc_w0)
+func (p *PropList) SetCache(nslots, nbytes uint, w0 float64) error {
+	return h5err(C.H5Pset_cache(p.id, 0, C.size_t(nslots), C.size_t(nbytes), C.double(w0)))
+}
+
+// Cache returns the chunk cache settings set by SetCache.
+// herr_t H5Pget_cache(hid_t plist_id, int *mdc_nelmts, size_t *rdcc_nslots, size_t *rdcc_nbytes, double *rdcc_w0)
+func (p *PropList) Cache() (nslots, nbytes uint, w0 float64, err error) {
+	var c_nslots, c_nbytes C.size_t
+	var c_w0 C.double
+	err = h5err(C.H5Pget_cache(p.id, nil, &c_nslots, &c_nbytes, &c_w0))
+	return uint(c_nslots), uint(c_nbytes), float64(c_w0), err
+}
+
+// SetAlignment makes files opened with this file access property list
+// place every object of at least threshold bytes at an address that is a
+// multiple of alignment, e.g. the stripe size of a parallel file system.
+// herr_t H5Pset_alignment(hid_t plist, hsize_t threshold, hsize_t alignment)
+func (p *PropList) SetAlignment(threshold, alignment uint) error {
+	return h5err(C.H5Pset_alignment(p.id, C.hsize_t(threshold), C.hsize_t(alignment)))
+}
+
+// Alignment returns the alignment settings set by SetAlignment.
+// herr_t H5Pget_alignment(hid_t plist, hsize_t *threshold, hsize_t *alignment)
+func (p *PropList) Alignment() (threshold, alignment uint, err error) {
+	var c_threshold, c_alignment C.hsize_t
+	err = h5err(C.H5Pget_alignment(p.id, &c_threshold, &c_alignment))
+	return uint(c_threshold), uint(c_alignment), err
+}
+
+// SetMetaBlockSize sets the minimum size in bytes of the blocks metadata
+// is allocated in, in files opened with this file access property list.
+// herr_t H5Pset_meta_block_size(hid_t fapl_id, hsize_t size)
+func (p *PropList) SetMetaBlockSize(size uint) error {
+	return h5err(C.H5Pset_meta_block_size(p.id, C.hsize_t(size)))
+}
+
+// MetaBlockSize returns the metadata block size set by SetMetaBlockSize.
+// herr_t H5Pget_meta_block_size(hid_t fapl_id, hsize_t *size)
+func (p *PropList) MetaBlockSize() (uint, error) {
+	var size C.hsize_t
+	err := h5err(C.H5Pget_meta_block_size(p.id, &size))
+	return uint(size), err
+}
+
+// SetCore selects the core driver for files opened with this file access
+// property list, which holds the whole file in memory, growing it by
+// increment bytes at a time. If backingStore is set, the file is read from
+// disk when opened and written back when closed; otherwise a created file
+// exists only until it is closed.
+// herr_t H5Pset_fapl_core(hid_t fapl_id, size_t increment, hbool_t backing_store)
+func (p *PropList) SetCore(increment uint, backingStore bool) error {
+	return h5err(C.H5Pset_fapl_core(p.id, C.size_t(increment), cbool(backingStore)))
+}
+
+// Core returns the settings of the core driver set by SetCore.
+// herr_t H5Pget_fapl_core(hid_t fapl_id, size_t *increment, hbool_t *backing_store)
+func (p *PropList) Core() (increment uint, backingStore bool, err error) {
+	var c_increment C.size_t
+	var c_backing C.hbool_t
+	err = h5err(C.H5Pget_fapl_core(p.id, &c_increment, &c_backing))
+	return uint(c_increment), c_backing != 0, err
+}
+
+func haveFileLocking() error {
+	if C._GO_HDF5_HAVE_FILE_LOCKING == 0 {
+		return fmt.Errorf("H5Pset_file_locking needs HDF5 1.10.7 or 1.12.1, built with %s", headerVersion)
+	}
+	return nil
+}
+
+// SetFileLocking sets whether files opened with this file access property
+// list are locked, which fails on file systems such as some NFS mounts
+// that do not support locks, and if so whether a failure to lock because
+// the file system has locks disabled is ignored. The
+// HDF5_USE_FILE_LOCKING environment variable, set to FALSE or BEST_EFFORT,
+// overrides it, and is the only control with libraries from 1.10.0 to
+// 1.10.6; 1.8 libraries do not lock files. It needs HDF5 1.10.7 or 1.12.1.
+// herr_t H5Pset_file_locking(hid_t fapl_id, hbool_t use_file_locking, hbool_t ignore_when_disabled)
+func (p *PropList) SetFileLocking(use, ignoreWhenDisabled bool) error {
+	if err := haveFileLocking(); err != nil {
+		return err
+	}
+	var c_use, c_ignore C.uint
+	if use {
+		c_use = 1
+	}
+	if ignoreWhenDisabled {
+		c_ignore = 1
+	}
+	return h5err(C._go_hdf5_H5Pset_file_locking(p.id, c_use, c_ignore))
+}
+
+// FileLocking returns the file locking settings set by SetFileLocking.
+// herr_t H5Pget_file_locking(hid_t fapl_id, hbool_t *use_file_locking, hbool_t *ignore_when_disabled)
+func (p *PropList) FileLocking() (use, ignoreWhenDisabled bool, err error) {
+	if err := haveFileLocking(); err != nil {
+		return false, false, err
+	}
+	var c_use, c_ignore C.uint
+	err = h5err(C._go_hdf5_H5Pget_file_locking(p.id, &c_use, &c_ignore))
+	return c_use != 0, c_ignore != 0, err
+}
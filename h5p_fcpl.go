@@ -0,0 +1,125 @@
+package hdf5
+
+// #include "hdf5.h"
+// #if H5_VERSION_GE(1,10,1)
+// static herr_t _go_hdf5_H5Pset_file_space_strategy(hid_t plist_id, int strategy, unsigned persist, hsize_t threshold) {
+//   return H5Pset_file_space_strategy(plist_id, (H5F_fspace_strategy_t)strategy, persist != 0, threshold);
+// }
+// static herr_t _go_hdf5_H5Pget_file_space_strategy(hid_t plist_id, int *strategy, unsigned *persist, hsize_t *threshold) {
+//   H5F_fspace_strategy_t c_strategy;
+//   hbool_t c_persist;
+//   herr_t err = H5Pget_file_space_strategy(plist_id, &c_strategy, &c_persist, threshold);
+//   *strategy = c_strategy;
+//   *persist = c_persist;
+//   return err;
+// }
+// static herr_t _go_hdf5_H5Pset_file_space_page_size(hid_t plist_id, hsize_t fsp_size) {
+//   return H5Pset_file_space_page_size(plist_id, fsp_size);
+// }
+// static herr_t _go_hdf5_H5Pget_file_space_page_size(hid_t plist_id, hsize_t *fsp_size) {
+//   return H5Pget_file_space_page_size(plist_id, fsp_size);
+// }
+// #else
+// static herr_t _go_hdf5_H5Pset_file_space_strategy(hid_t plist_id, int strategy, unsigned persist, hsize_t threshold) { return -1; }
+// static herr_t _go_hdf5_H5Pget_file_space_strategy(hid_t plist_id, int *strategy, unsigned *persist, hsize_t *threshold) { return -1; }
+// static herr_t _go_hdf5_H5Pset_file_space_page_size(hid_t plist_id, hsize_t fsp_size) { return -1; }
+// static herr_t _go_hdf5_H5Pget_file_space_page_size(hid_t plist_id, hsize_t *fsp_size) { return -1; }
+// #endif
+import "C"
+
+// --- File creation properties ---
+
+// SetUserblock sets the size of the user block, a region at the start of
+// files created with this file creation property list that the library
+// leaves for the application, e.g. for a shell script or a magic header.
+// The size must be 0 or a power of two of at least 512.
+// herr_t H5Pset_userblock(hid_t plist, hsize_t size)
+func (p *PropList) SetUserblock(size uint) error {
+	return h5err(C.H5Pset_userblock(p.id, C.hsize_t(size)))
+}
+
+// Userblock returns the size of the user block set by SetUserblock.
+// herr_t H5Pget_userblock(hid_t plist, hsize_t *size)
+func (p *PropList) Userblock() (uint, error) {
+	var size C.hsize_t
+	err := h5err(C.H5Pget_userblock(p.id, &size))
+	return uint(size), err
+}
+
+// FileSpaceStrategy is how the library allocates space in a file and
+// reuses the space of deleted objects.
+type FileSpaceStrategy int
+
+const (
+	F_FSPACE_STRATEGY_FSM_AGGR FileSpaceStrategy = 0 // free-space managers with aggregators, the default
+	F_FSPACE_STRATEGY_PAGE     FileSpaceStrategy = 1 // paged aggregation, with free-space managers
+	F_FSPACE_STRATEGY_AGGR     FileSpaceStrategy = 2 // aggregators only, without reuse of freed space
+	F_FSPACE_STRATEGY_NONE     FileSpaceStrategy = 3 // neither aggregators nor reuse of freed space
+)
+
+// SetFileSpaceStrategy sets the file space strategy of files created with
+// this file creation property list. If persist is true freed space is
+// tracked across closing and reopening the file, so that files with
+// objects appended and deleted do not keep growing. Free sections smaller
+// than threshold bytes are not tracked. It needs HDF5 1.10.1.
+// herr_t H5Pset_file_space_strategy(hid_t plist_id, H5F_fspace_strategy_t strategy, hbool_t persist, hsize_t threshold)
+func (p *PropList) SetFileSpaceStrategy(strategy FileSpaceStrategy, persist bool, threshold uint) error {
+	if err := requireVersion("H5Pset_file_space_strategy", 1, 10, 1); err != nil {
+		return err
+	}
+	var c_persist C.uint
+	if persist {
+		c_persist = 1
+	}
+	return h5err(C._go_hdf5_H5Pset_file_space_strategy(p.id, C.int(strategy), c_persist, C.hsize_t(threshold)))
+}
+
+// FileSpaceStrategy returns the file space strategy set by
+// SetFileSpaceStrategy.
+// herr_t H5Pget_file_space_strategy(hid_t plist_id, H5F_fspace_strategy_t *strategy, hbool_t *persist, hsize_t *threshold)
+func (p *PropList) FileSpaceStrategy() (strategy FileSpaceStrategy, persist bool, threshold uint, err error) {
+	if err := requireVersion("H5Pget_file_space_strategy", 1, 10, 1); err != nil {
+		return 0, false, 0, err
+	}
+	var c_strategy C.int
+	var c_persist C.uint
+	var c_threshold C.hsize_t
+	err = h5err(C._go_hdf5_H5Pget_file_space_strategy(p.id, &c_strategy, &c_persist, &c_threshold))
+	return FileSpaceStrategy(c_strategy), c_persist != 0, uint(c_threshold), err
+}
+
+// SetFileSpacePageSize sets the size in bytes of the pages of files
+// created with this file creation property list with paged aggregation.
+// It needs HDF5 1.10.1.
+// herr_t H5Pset_file_space_page_size(hid_t plist_id, hsize_t fsp_size)
+func (p *PropList) SetFileSpacePageSize(size uint) error {
+	if err := requireVersion("H5Pset_file_space_page_size", 1, 10, 1); err != nil {
+		return err
+	}
+	return h5err(C._go_hdf5_H5Pset_file_space_page_size(p.id, C.hsize_t(size)))
+}
+
+// FileSpacePageSize returns the page size set by SetFileSpacePageSize.
+// herr_t H5Pget_file_space_page_size(hid_t plist_id, hsize_t *fsp_size)
+func (p *PropList) FileSpacePageSize() (uint, error) {
+	if err := requireVersion("H5Pget_file_space_page_size", 1, 10, 1); err != nil {
+		return 0, err
+	}
+	var size C.hsize_t
+	err := h5err(C._go_hdf5_H5Pget_file_space_page_size(p.id, &size))
+	return uint(size), err
+}
+
+// SetPagedAggregation makes files created with this file creation property
+// list allocate space in pages of pageSize bytes, or of the default 4096
+// bytes if pageSize is 0, which the page buffer then reads whole, see
+// SetPageBufferSize. It needs HDF5 1.10.1.
+func (p *PropList) SetPagedAggregation(pageSize uint) error {
+	if err := p.SetFileSpaceStrategy(F_FSPACE_STRATEGY_PAGE, false, 1); err != nil {
+		return err
+	}
+	if pageSize == 0 {
+		return nil
+	}
+	return p.SetFileSpacePageSize(pageSize)
+}
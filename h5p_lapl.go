@@ -0,0 +1,61 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// --- Link access properties ---
+
+// SetElinkPrefix sets the directory prepended to the relative file names
+// of external links traversed with this link access property list, such
+// as a dataset or group access property list, so that links still resolve
+// once a tree of files has been moved. The environment variable
+// HDF5_EXT_PREFIX takes precedence.
+// herr_t H5Pset_elink_prefix(hid_t lapl_id, const char *prefix)
+func (p *PropList) SetElinkPrefix(prefix string) error {
+	c_prefix := C.CString(prefix)
+	defer C.free(unsafe.Pointer(c_prefix))
+	return h5err(C.H5Pset_elink_prefix(p.id, c_prefix))
+}
+
+// ElinkPrefix returns the external link prefix set by SetElinkPrefix.
+// ssize_t H5Pget_elink_prefix(hid_t lapl_id, char *prefix, size_t size)
+func (p *PropList) ElinkPrefix() (string, error) {
+	size := C.H5Pget_elink_prefix(p.id, nil, 0)
+	if size < 0 {
+		return "", fmt.Errorf("hdf5: could not get the external link prefix")
+	}
+	if size == 0 {
+		return "", nil
+	}
+	buf := make([]C.char, size+1)
+	if C.H5Pget_elink_prefix(p.id, &buf[0], C.size_t(size)+1) < 0 {
+		return "", fmt.Errorf("hdf5: could not get the external link prefix")
+	}
+	return C.GoString(&buf[0]), nil
+}
+
+// SetElinkFapl sets the file access property list the files of external
+// links traversed with this link access property list are opened with,
+// e.g. for a driver such as ros3. The files are otherwise opened with the
+// file access properties of the file holding the link.
+// herr_t H5Pset_elink_fapl(hid_t lapl_id, hid_t fapl_id)
+func (p *PropList) SetElinkFapl(fapl *PropList) error {
+	return h5err(C.H5Pset_elink_fapl(p.id, fapl.id))
+}
+
+// ElinkFapl returns a copy of the file access property list set by
+// SetElinkFapl, which must be closed.
+// hid_t H5Pget_elink_fapl(hid_t lapl_id)
+func (p *PropList) ElinkFapl() (*PropList, error) {
+	hid := C.H5Pget_elink_fapl(p.id)
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return nil, err
+	}
+	return new_proplist(hid), nil
+}
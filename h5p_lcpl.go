@@ -0,0 +1,42 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+// --- Link and attribute creation properties ---
+
+// SetCreateIntermediateGroup sets whether objects created with this
+// property list also create the missing groups on their path.
+// herr_t H5Pset_create_intermediate_group(hid_t lcpl_id, unsigned crt_intermed_group)
+func (p *PropList) SetCreateIntermediateGroup(create bool) error {
+	var c_create C.uint
+	if create {
+		c_create = 1
+	}
+	return h5err(C.H5Pset_create_intermediate_group(p.id, c_create))
+}
+
+// CreateIntermediateGroup returns whether objects created with this
+// property list also create the missing groups on their path.
+// herr_t H5Pget_create_intermediate_group(hid_t lcpl_id, unsigned *crt_intermed_group)
+func (p *PropList) CreateIntermediateGroup() (bool, error) {
+	var c_create C.uint
+	err := h5err(C.H5Pget_create_intermediate_group(p.id, &c_create))
+	return c_create != 0, err
+}
+
+// SetCharEncoding sets the character set of the names of links created
+// with this link creation property list, or of attributes created with
+// this attribute creation property list.
+// herr_t H5Pset_char_encoding(hid_t plist_id, H5T_cset_t encoding)
+func (p *PropList) SetCharEncoding(cset CharSet) error {
+	return h5err(C.H5Pset_char_encoding(p.id, C.H5T_cset_t(cset)))
+}
+
+// CharEncoding returns the character set of names set by SetCharEncoding.
+// herr_t H5Pget_char_encoding(hid_t plist_id, H5T_cset_t *encoding)
+func (p *PropList) CharEncoding() (CharSet, error) {
+	var cset C.H5T_cset_t
+	err := h5err(C.H5Pget_char_encoding(p.id, &cset))
+	return CharSet(cset), err
+}
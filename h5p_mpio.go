@@ -0,0 +1,122 @@
+package hdf5
+
+// #include "hdf5.h"
+// #ifdef H5_HAVE_PARALLEL
+// #define _GO_HDF5_HAVE_PARALLEL 1
+// static herr_t _go_hdf5_H5Pset_dxpl_mpio(hid_t dxpl_id, int xfer_mode) {
+//   return H5Pset_dxpl_mpio(dxpl_id, (H5FD_mpio_xfer_t)xfer_mode);
+// }
+// static herr_t _go_hdf5_H5Pget_dxpl_mpio(hid_t dxpl_id, int *xfer_mode) {
+//   H5FD_mpio_xfer_t mode;
+//   herr_t err = H5Pget_dxpl_mpio(dxpl_id, &mode);
+//   *xfer_mode = mode;
+//   return err;
+// }
+// static herr_t _go_hdf5_H5Pget_mpio_actual_io_mode(hid_t dxpl_id, int *actual_io_mode) {
+//   H5D_mpio_actual_io_mode_t mode;
+//   herr_t err = H5Pget_mpio_actual_io_mode(dxpl_id, &mode);
+//   *actual_io_mode = mode;
+//   return err;
+// }
+// static herr_t _go_hdf5_H5Pget_mpio_no_collective_cause(hid_t dxpl_id, uint32_t *local_cause, uint32_t *global_cause) {
+//   return H5Pget_mpio_no_collective_cause(dxpl_id, local_cause, global_cause);
+// }
+// #else
+// #define _GO_HDF5_HAVE_PARALLEL 0
+// static herr_t _go_hdf5_H5Pset_dxpl_mpio(hid_t dxpl_id, int xfer_mode) { return -1; }
+// static herr_t _go_hdf5_H5Pget_dxpl_mpio(hid_t dxpl_id, int *xfer_mode) { return -1; }
+// static herr_t _go_hdf5_H5Pget_mpio_actual_io_mode(hid_t dxpl_id, int *actual_io_mode) { return -1; }
+// static herr_t _go_hdf5_H5Pget_mpio_no_collective_cause(hid_t dxpl_id, uint32_t *local_cause, uint32_t *global_cause) { return -1; }
+// #endif
+import "C"
+
+import (
+	"fmt"
+)
+
+// --- Parallel (MPI-IO) transfer properties ---
+
+// MPIOXfer is how the processes of a parallel program transfer data.
+type MPIOXfer int
+
+const (
+	FD_MPIO_INDEPENDENT MPIOXfer = 0 // each process does its own I/O
+	FD_MPIO_COLLECTIVE  MPIOXfer = 1 // the processes do their I/O together
+)
+
+// MPIOActualIOMode is the kind of I/O the last parallel transfer did.
+type MPIOActualIOMode int
+
+const (
+	D_MPIO_NO_COLLECTIVE         MPIOActualIOMode = 0 // no collective I/O
+	D_MPIO_CHUNK_INDEPENDENT     MPIOActualIOMode = 1 // independent I/O of all chunks
+	D_MPIO_CHUNK_COLLECTIVE      MPIOActualIOMode = 2 // collective I/O of all chunks
+	D_MPIO_CHUNK_MIXED           MPIOActualIOMode = 3 // collective I/O of some chunks
+	D_MPIO_CONTIGUOUS_COLLECTIVE MPIOActualIOMode = 4 // collective I/O of a contiguous dataset
+)
+
+// Causes reported by MPIONoCollectiveCause for collective I/O not to occur,
+// as bits of a mask.
+const (
+	D_MPIO_SET_INDEPENDENT                   uint32 = 0x01 // independent I/O was requested
+	D_MPIO_DATATYPE_CONVERSION               uint32 = 0x02 // the datatype needed conversion
+	D_MPIO_DATA_TRANSFORMS                   uint32 = 0x04 // a data transform was set
+	D_MPIO_NOT_SIMPLE_OR_SCALAR_DATASPACES   uint32 = 0x10 // a dataspace was neither simple nor scalar
+	D_MPIO_NOT_CONTIGUOUS_OR_CHUNKED_DATASET uint32 = 0x20 // the dataset was neither contiguous nor chunked
+)
+
+func haveParallel() error {
+	if C._GO_HDF5_HAVE_PARALLEL == 0 {
+		return fmt.Errorf("MPI-IO transfers need a parallel HDF5 library")
+	}
+	return nil
+}
+
+// SetMPIOXfer sets whether parallel transfers with this dataset transfer
+// property list are collective or independent. It needs a parallel
+// library.
+// herr_t H5Pset_dxpl_mpio(hid_t dxpl_id, H5FD_mpio_xfer_t xfer_mode)
+func (p *PropList) SetMPIOXfer(mode MPIOXfer) error {
+	if err := haveParallel(); err != nil {
+		return err
+	}
+	return h5err(C._go_hdf5_H5Pset_dxpl_mpio(p.id, C.int(mode)))
+}
+
+// MPIOXfer returns the transfer mode set by SetMPIOXfer.
+// herr_t H5Pget_dxpl_mpio(hid_t dxpl_id, H5FD_mpio_xfer_t *xfer_mode)
+func (p *PropList) MPIOXfer() (MPIOXfer, error) {
+	if err := haveParallel(); err != nil {
+		return 0, err
+	}
+	var mode C.int
+	err := h5err(C._go_hdf5_H5Pget_dxpl_mpio(p.id, &mode))
+	return MPIOXfer(mode), err
+}
+
+// MPIOActualIOMode returns the kind of I/O the last parallel transfer with
+// this dataset transfer property list did, to check that a collective
+// transfer was really collective.
+// herr_t H5Pget_mpio_actual_io_mode(hid_t dxpl_id, H5D_mpio_actual_io_mode_t *actual_io_mode)
+func (p *PropList) MPIOActualIOMode() (MPIOActualIOMode, error) {
+	if err := haveParallel(); err != nil {
+		return 0, err
+	}
+	var mode C.int
+	err := h5err(C._go_hdf5_H5Pget_mpio_actual_io_mode(p.id, &mode))
+	return MPIOActualIOMode(mode), err
+}
+
+// MPIONoCollectiveCause returns why the last parallel transfer with this
+// dataset transfer property list was not collective, as masks of the
+// D_MPIO_ causes of this process and of all processes. Both are 0 if it
+// was collective.
+// herr_t H5Pget_mpio_no_collective_cause(hid_t plist_id, uint32_t *local_no_collective_cause, uint32_t *global_no_collective_cause)
+func (p *PropList) MPIONoCollectiveCause() (local, global uint32, err error) {
+	if err := haveParallel(); err != nil {
+		return 0, 0, err
+	}
+	var c_local, c_global C.uint32_t
+	err = h5err(C._go_hdf5_H5Pget_mpio_no_collective_cause(p.id, &c_local, &c_global))
+	return uint32(c_local), uint32(c_global), err
+}
@@ -0,0 +1,86 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+// --- Object creation properties ---
+
+// Creation order flags.
+const (
+	P_CRT_ORDER_TRACKED uint = 0x0001 // track the creation order
+	P_CRT_ORDER_INDEXED uint = 0x0002 // index the creation order, to iterate in it
+)
+
+// SetLinkCreationOrder sets whether the creation order of the links of
+// groups created with this group or file creation property list is
+// tracked and indexed, as a combination of P_CRT_ORDER_TRACKED and
+// P_CRT_ORDER_INDEXED.
+// herr_t H5Pset_link_creation_order(hid_t gcpl_id, unsigned crt_order_flags)
+func (p *PropList) SetLinkCreationOrder(flags uint) error {
+	return h5err(C.H5Pset_link_creation_order(p.id, C.uint(flags)))
+}
+
+// LinkCreationOrder returns the link creation order flags of this property
+// list.
+// herr_t H5Pget_link_creation_order(hid_t gcpl_id, unsigned *crt_order_flags)
+func (p *PropList) LinkCreationOrder() (uint, error) {
+	var flags C.uint
+	err := h5err(C.H5Pget_link_creation_order(p.id, &flags))
+	return uint(flags), err
+}
+
+// SetAttrCreationOrder sets whether the creation order of the attributes
+// of objects created with this object creation property list is tracked
+// and indexed, as a combination of P_CRT_ORDER_TRACKED and
+// P_CRT_ORDER_INDEXED.
+// herr_t H5Pset_attr_creation_order(hid_t ocpl_id, unsigned crt_order_flags)
+func (p *PropList) SetAttrCreationOrder(flags uint) error {
+	return h5err(C.H5Pset_attr_creation_order(p.id, C.uint(flags)))
+}
+
+// AttrCreationOrder returns the attribute creation order flags of this
+// property list.
+// herr_t H5Pget_attr_creation_order(hid_t ocpl_id, unsigned *crt_order_flags)
+func (p *PropList) AttrCreationOrder() (uint, error) {
+	var flags C.uint
+	err := h5err(C.H5Pget_attr_creation_order(p.id, &flags))
+	return uint(flags), err
+}
+
+// SetAttrPhaseChange sets the thresholds at which the attributes of
+// objects created with this object creation property list move between
+// compact storage in the object header and dense storage in a heap:
+// they become dense past maxCompact attributes and compact again below
+// minDense. Objects with many attributes stay fast with dense storage;
+// SetAttrPhaseChange(0, 0) stores attributes densely from the start.
+// herr_t H5Pset_attr_phase_change(hid_t ocpl_id, unsigned max_compact, unsigned min_dense)
+func (p *PropList) SetAttrPhaseChange(maxCompact, minDense uint) error {
+	return h5err(C.H5Pset_attr_phase_change(p.id, C.uint(maxCompact), C.uint(minDense)))
+}
+
+// AttrPhaseChange returns the attribute storage thresholds of this
+// property list, as set by SetAttrPhaseChange.
+// herr_t H5Pget_attr_phase_change(hid_t ocpl_id, unsigned *max_compact, unsigned *min_dense)
+func (p *PropList) AttrPhaseChange() (maxCompact, minDense uint, err error) {
+	var c_max, c_min C.uint
+	err = h5err(C.H5Pget_attr_phase_change(p.id, &c_max, &c_min))
+	return uint(c_max), uint(c_min), err
+}
+
+// SetObjTrackTimes sets whether objects created with this object creation
+// property list record their access, modification, change and birth
+// times, as they do by default. Files written without times are identical
+// byte for byte when written again with the same data.
+// herr_t H5Pset_obj_track_times(hid_t plist_id, hbool_t track_times)
+func (p *PropList) SetObjTrackTimes(track bool) error {
+	return h5err(C.H5Pset_obj_track_times(p.id, cbool(track)))
+}
+
+// ObjTrackTimes returns whether objects created with this property list
+// record their times, as set by SetObjTrackTimes.
+// herr_t H5Pget_obj_track_times(hid_t plist_id, hbool_t *track_times)
+func (p *PropList) ObjTrackTimes() (bool, error) {
+	var c_track C.hbool_t
+	err := h5err(C.H5Pget_obj_track_times(p.id, &c_track))
+	return c_track != 0, err
+}
@@ -0,0 +1,100 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// #include <string.h>
+// #if H5_VERSION_GE(1,14,0)
+// static herr_t _go_hdf5_H5Pset_fapl_onion(hid_t fapl_id, uint64_t revision_num, uint32_t page_size, const char *comment) {
+//   H5FD_onion_fapl_info_t fa;
+//   memset(&fa, 0, sizeof(fa));
+//   fa.version = H5FD_ONION_FAPL_INFO_VERSION_CURR;
+//   fa.backing_fapl_id = H5P_DEFAULT;
+//   fa.page_size = page_size;
+//   fa.store_target = H5FD_ONION_STORE_TARGET_ONION;
+//   fa.revision_num = revision_num;
+//   strncpy(fa.comment, comment, H5FD_ONION_FAPL_INFO_COMMENT_MAX_LEN);
+//   return H5Pset_fapl_onion(fapl_id, &fa);
+// }
+// static herr_t _go_hdf5_H5Pget_fapl_onion(hid_t fapl_id, uint64_t *revision_num, uint32_t *page_size, char *comment) {
+//   H5FD_onion_fapl_info_t fa;
+//   herr_t err = H5Pget_fapl_onion(fapl_id, &fa);
+//   if (err < 0) return err;
+//   *revision_num = fa.revision_num;
+//   *page_size = fa.page_size;
+//   memcpy(comment, fa.comment, H5FD_ONION_FAPL_INFO_COMMENT_MAX_LEN + 1);
+//   return err;
+// }
+// static herr_t _go_hdf5_H5FDonion_get_revision_count(const char *filename, hid_t fapl_id, uint64_t *revision_count) {
+//   return H5FDonion_get_revision_count(filename, fapl_id, revision_count);
+// }
+// #else
+// #define H5FD_ONION_FAPL_INFO_COMMENT_MAX_LEN 255
+// static herr_t _go_hdf5_H5Pset_fapl_onion(hid_t fapl_id, uint64_t revision_num, uint32_t page_size, const char *comment) { return -1; }
+// static herr_t _go_hdf5_H5Pget_fapl_onion(hid_t fapl_id, uint64_t *revision_num, uint32_t *page_size, char *comment) { return -1; }
+// static herr_t _go_hdf5_H5FDonion_get_revision_count(const char *filename, hid_t fapl_id, uint64_t *revision_count) { return -1; }
+// #endif
+import "C"
+
+import (
+	"unsafe"
+)
+
+// --- Onion file driver ---
+
+// FD_ONION_REVISION_LATEST opens the latest revision of an onion file.
+const FD_ONION_REVISION_LATEST = ^uint64(0)
+
+// OnionConfig configures the onion driver, which keeps the revisions of a
+// file in a separate append-only "name.onion" file, leaving the original
+// untouched. Each time the file is opened for writing and closed a new
+// revision is committed.
+type OnionConfig struct {
+	Revision uint64 // revision to open, or FD_ONION_REVISION_LATEST
+	PageSize uint32 // size of the pages revisions are recorded in, a power of two
+	Comment  string // recorded with the revision written
+}
+
+// SetOnion sets the file access property list to use the onion driver
+// over the default driver. It needs HDF5 1.14.0.
+// herr_t H5Pset_fapl_onion(hid_t fapl_id, const H5FD_onion_fapl_info_t *fa)
+func (p *PropList) SetOnion(cfg *OnionConfig) error {
+	if err := requireVersion("H5Pset_fapl_onion", 1, 14, 0); err != nil {
+		return err
+	}
+	c_comment := C.CString(cfg.Comment)
+	defer C.free(unsafe.Pointer(c_comment))
+	pageSize := cfg.PageSize
+	if pageSize == 0 {
+		pageSize = 4096
+	}
+	return h5err(C._go_hdf5_H5Pset_fapl_onion(p.id, C.uint64_t(cfg.Revision), C.uint32_t(pageSize), c_comment))
+}
+
+// Onion returns the onion configuration of the file access property list.
+// herr_t H5Pget_fapl_onion(hid_t fapl_id, H5FD_onion_fapl_info_t *fa_out)
+func (p *PropList) Onion() (*OnionConfig, error) {
+	if err := requireVersion("H5Pget_fapl_onion", 1, 14, 0); err != nil {
+		return nil, err
+	}
+	var rev C.uint64_t
+	var pageSize C.uint32_t
+	comment := make([]C.char, C.H5FD_ONION_FAPL_INFO_COMMENT_MAX_LEN+1)
+	if err := h5err(C._go_hdf5_H5Pget_fapl_onion(p.id, &rev, &pageSize, &comment[0])); err != nil {
+		return nil, err
+	}
+	return &OnionConfig{Revision: uint64(rev), PageSize: uint32(pageSize), Comment: C.GoString(&comment[0])}, nil
+}
+
+// OnionRevisionCount returns the number of revisions committed to the
+// onion file of name, opened with fapl, which must use the onion driver.
+// herr_t H5FDonion_get_revision_count(const char *filename, hid_t fapl_id, uint64_t *revision_count)
+func OnionRevisionCount(name string, fapl *PropList) (uint64, error) {
+	if err := requireVersion("H5FDonion_get_revision_count", 1, 14, 0); err != nil {
+		return 0, err
+	}
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+	var n C.uint64_t
+	err := h5err(C._go_hdf5_H5FDonion_get_revision_count(c_name, fapl.id, &n))
+	return uint64(n), err
+}
@@ -0,0 +1,175 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// #include <string.h>
+// #if H5_VERSION_GE(1,12,0)
+// static herr_t _go_hdf5_H5Pset_fapl_splitter(hid_t fapl_id, hid_t rw_fapl_id, hid_t wo_fapl_id, const char *wo_path, const char *log_file_path, unsigned ignore_wo_errs) {
+//   H5FD_splitter_vfd_config_t *config = calloc(1, sizeof(*config));
+//   if (config == NULL) return -1;
+//   config->magic = H5FD_SPLITTER_MAGIC;
+//   config->version = H5FD_CURR_SPLITTER_VFD_CONFIG_VERSION;
+//   config->rw_fapl_id = rw_fapl_id;
+//   config->wo_fapl_id = wo_fapl_id;
+//   strncpy(config->wo_path, wo_path, H5FD_SPLITTER_PATH_MAX);
+//   strncpy(config->log_file_path, log_file_path, H5FD_SPLITTER_PATH_MAX);
+//   config->ignore_wo_errs = ignore_wo_errs;
+//   herr_t err = H5Pset_fapl_splitter(fapl_id, config);
+//   free(config);
+//   return err;
+// }
+// static herr_t _go_hdf5_H5Pget_fapl_splitter(hid_t fapl_id, hid_t *rw_fapl_id, hid_t *wo_fapl_id, char **wo_path, char **log_file_path, unsigned *ignore_wo_errs) {
+//   H5FD_splitter_vfd_config_t *config = calloc(1, sizeof(*config));
+//   if (config == NULL) return -1;
+//   config->magic = H5FD_SPLITTER_MAGIC;
+//   config->version = H5FD_CURR_SPLITTER_VFD_CONFIG_VERSION;
+//   herr_t err = H5Pget_fapl_splitter(fapl_id, config);
+//   if (err >= 0) {
+//     *rw_fapl_id = config->rw_fapl_id;
+//     *wo_fapl_id = config->wo_fapl_id;
+//     *wo_path = strdup(config->wo_path);
+//     *log_file_path = strdup(config->log_file_path);
+//     *ignore_wo_errs = config->ignore_wo_errs;
+//   }
+//   free(config);
+//   return err;
+// }
+// #else
+// static herr_t _go_hdf5_H5Pset_fapl_splitter(hid_t fapl_id, hid_t rw_fapl_id, hid_t wo_fapl_id, const char *wo_path, const char *log_file_path, unsigned ignore_wo_errs) { return -1; }
+// static herr_t _go_hdf5_H5Pget_fapl_splitter(hid_t fapl_id, hid_t *rw_fapl_id, hid_t *wo_fapl_id, char **wo_path, char **log_file_path, unsigned *ignore_wo_errs) { return -1; }
+// #endif
+// #if defined(H5_HAVE_MIRROR_VFD) && H5_VERSION_GE(1,12,0)
+// #define _GO_HDF5_HAVE_MIRROR 1
+// static herr_t _go_hdf5_H5Pset_fapl_mirror(hid_t fapl_id, const char *remote_ip, int handshake_port) {
+//   H5FD_mirror_fapl_t fa;
+//   memset(&fa, 0, sizeof(fa));
+//   fa.magic = H5FD_MIRROR_FAPL_MAGIC;
+//   fa.version = H5FD_MIRROR_CURR_FAPL_T_VERSION;
+//   fa.handshake_port = handshake_port;
+//   strncpy(fa.remote_ip, remote_ip, H5FD_MIRROR_MAX_IP_LEN);
+//   return H5Pset_fapl_mirror(fapl_id, &fa);
+// }
+// static herr_t _go_hdf5_H5Pget_fapl_mirror(hid_t fapl_id, char *remote_ip, int *handshake_port) {
+//   H5FD_mirror_fapl_t fa;
+//   herr_t err = H5Pget_fapl_mirror(fapl_id, &fa);
+//   if (err < 0) return err;
+//   memcpy(remote_ip, fa.remote_ip, H5FD_MIRROR_MAX_IP_LEN + 1);
+//   *handshake_port = fa.handshake_port;
+//   return err;
+// }
+// #else
+// #define _GO_HDF5_HAVE_MIRROR 0
+// #define H5FD_MIRROR_MAX_IP_LEN 32
+// static herr_t _go_hdf5_H5Pset_fapl_mirror(hid_t fapl_id, const char *remote_ip, int handshake_port) { return -1; }
+// static herr_t _go_hdf5_H5Pget_fapl_mirror(hid_t fapl_id, char *remote_ip, int *handshake_port) { return -1; }
+// #endif
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// --- Splitter and mirror file drivers ---
+
+// SplitterConfig configures the splitter driver, which sends every write
+// to two files: the read-write channel, which also serves reads, and a
+// write-only copy.
+type SplitterConfig struct {
+	ReadWrite     *PropList // file access of the read-write channel, or P_DEFAULT
+	WriteOnly     *PropList // file access of the copy, e.g. with SetMirror, or P_DEFAULT
+	WriteOnlyPath string    // path of the copy
+	LogPath       string    // file the driver logs to, if not empty
+	// IgnoreWriteOnlyErrors keeps the read-write channel going when
+	// writes to the copy fail.
+	IgnoreWriteOnlyErrors bool
+}
+
+// SetSplitter sets the file access property list to use the splitter
+// driver. It needs HDF5 1.12.0.
+// herr_t H5Pset_fapl_splitter(hid_t fapl_id, H5FD_splitter_vfd_config_t *config_ptr)
+func (p *PropList) SetSplitter(cfg *SplitterConfig) error {
+	if err := requireVersion("H5Pset_fapl_splitter", 1, 12, 0); err != nil {
+		return err
+	}
+	rw, wo := cfg.ReadWrite, cfg.WriteOnly
+	if rw == nil {
+		rw = P_DEFAULT
+	}
+	if wo == nil {
+		wo = P_DEFAULT
+	}
+	c_wo_path := C.CString(cfg.WriteOnlyPath)
+	defer C.free(unsafe.Pointer(c_wo_path))
+	c_log_path := C.CString(cfg.LogPath)
+	defer C.free(unsafe.Pointer(c_log_path))
+	c_ignore := C.uint(0)
+	if cfg.IgnoreWriteOnlyErrors {
+		c_ignore = 1
+	}
+	return h5err(C._go_hdf5_H5Pset_fapl_splitter(p.id, rw.id, wo.id, c_wo_path, c_log_path, c_ignore))
+}
+
+// Splitter returns the splitter configuration of the file access
+// property list. The caller must close its ReadWrite and WriteOnly
+// property lists.
+// herr_t H5Pget_fapl_splitter(hid_t fapl_id, H5FD_splitter_vfd_config_t *config_ptr)
+func (p *PropList) Splitter() (*SplitterConfig, error) {
+	if err := requireVersion("H5Pget_fapl_splitter", 1, 12, 0); err != nil {
+		return nil, err
+	}
+	var rw, wo C.hid_t
+	var c_wo_path, c_log_path *C.char
+	var c_ignore C.uint
+	if err := h5err(C._go_hdf5_H5Pget_fapl_splitter(p.id, &rw, &wo, &c_wo_path, &c_log_path, &c_ignore)); err != nil {
+		return nil, err
+	}
+	defer C.free(unsafe.Pointer(c_wo_path))
+	defer C.free(unsafe.Pointer(c_log_path))
+	return &SplitterConfig{
+		ReadWrite:             new_proplist(rw),
+		WriteOnly:             new_proplist(wo),
+		WriteOnlyPath:         C.GoString(c_wo_path),
+		LogPath:               C.GoString(c_log_path),
+		IgnoreWriteOnlyErrors: c_ignore != 0,
+	}, nil
+}
+
+func haveMirror() error {
+	if C._GO_HDF5_HAVE_MIRROR == 0 {
+		return fmt.Errorf("the mirror driver needs an HDF5 1.12 library built with it")
+	}
+	return nil
+}
+
+// SetMirror sets the file access property list to use the mirror driver,
+// which writes to a file kept by the mirror server listening at ip and
+// port, typically as the write-only channel of the splitter driver. It
+// needs a library built with the driver.
+// herr_t H5Pset_fapl_mirror(hid_t fapl_id, H5FD_mirror_fapl_t *fa)
+func (p *PropList) SetMirror(ip string, port int) error {
+	if err := haveMirror(); err != nil {
+		return err
+	}
+	if len(ip) > C.H5FD_MIRROR_MAX_IP_LEN {
+		return fmt.Errorf("mirror address %q is too long", ip)
+	}
+	c_ip := C.CString(ip)
+	defer C.free(unsafe.Pointer(c_ip))
+	return h5err(C._go_hdf5_H5Pset_fapl_mirror(p.id, c_ip, C.int(port)))
+}
+
+// Mirror returns the address of the mirror server of the file access
+// property list.
+// herr_t H5Pget_fapl_mirror(hid_t fapl_id, H5FD_mirror_fapl_t *fa_out)
+func (p *PropList) Mirror() (ip string, port int, err error) {
+	if err := haveMirror(); err != nil {
+		return "", 0, err
+	}
+	c_ip := make([]C.char, C.H5FD_MIRROR_MAX_IP_LEN+1)
+	var c_port C.int
+	if err := h5err(C._go_hdf5_H5Pget_fapl_mirror(p.id, &c_ip[0], &c_port)); err != nil {
+		return "", 0, err
+	}
+	return C.GoString(&c_ip[0]), int(c_port), nil
+}
@@ -0,0 +1,97 @@
+package hdf5
+
+// #include "hdf5.h"
+// #if defined(H5_HAVE_SUBFILING_VFD) && H5_VERSION_GE(1,14,0)
+// #define _GO_HDF5_HAVE_SUBFILING 1
+// static herr_t _go_hdf5_H5Pset_fapl_subfiling(hid_t fapl_id, int ioc_selection, int64_t stripe_size, int32_t stripe_count) {
+//   H5FD_subfiling_config_t config;
+//   herr_t err = H5Pget_fapl_subfiling(fapl_id, &config);
+//   if (err < 0) return err;
+//   if (ioc_selection >= 0) config.shared_cfg.ioc_selection = (H5FD_subfiling_ioc_select_t)ioc_selection;
+//   if (stripe_size > 0) config.shared_cfg.stripe_size = stripe_size;
+//   if (stripe_count > 0) config.shared_cfg.stripe_count = stripe_count;
+//   err = H5Pset_fapl_subfiling(fapl_id, &config);
+//   H5Pclose(config.ioc_fapl_id);
+//   return err;
+// }
+// static herr_t _go_hdf5_H5Pget_fapl_subfiling(hid_t fapl_id, int *ioc_selection, int64_t *stripe_size, int32_t *stripe_count) {
+//   H5FD_subfiling_config_t config;
+//   herr_t err = H5Pget_fapl_subfiling(fapl_id, &config);
+//   if (err < 0) return err;
+//   *ioc_selection = config.shared_cfg.ioc_selection;
+//   *stripe_size = config.shared_cfg.stripe_size;
+//   *stripe_count = config.shared_cfg.stripe_count;
+//   H5Pclose(config.ioc_fapl_id);
+//   return err;
+// }
+// #else
+// #define _GO_HDF5_HAVE_SUBFILING 0
+// static herr_t _go_hdf5_H5Pset_fapl_subfiling(hid_t fapl_id, int ioc_selection, int64_t stripe_size, int32_t stripe_count) { return -1; }
+// static herr_t _go_hdf5_H5Pget_fapl_subfiling(hid_t fapl_id, int *ioc_selection, int64_t *stripe_size, int32_t *stripe_count) { return -1; }
+// #endif
+import "C"
+
+import (
+	"fmt"
+)
+
+// --- Subfiling file driver ---
+
+// SubfilingIOCSelection is how the processes that do the I/O of a
+// subfiled file, the I/O concentrators, are chosen.
+type SubfilingIOCSelection int
+
+const (
+	FD_SUBFILING_IOC_ONE_PER_NODE   SubfilingIOCSelection = 0 // one process per node
+	FD_SUBFILING_IOC_EVERY_NTH_RANK SubfilingIOCSelection = 1 // every Nth process
+	FD_SUBFILING_IOC_WITH_CONFIG    SubfilingIOCSelection = 2 // processes listed in a file
+	FD_SUBFILING_IOC_TOTAL          SubfilingIOCSelection = 3 // a total number of processes
+)
+
+// SubfilingConfig configures the subfiling driver, which stripes a file
+// over subfiles written by the I/O concentrators, typically on node-local
+// storage. Zero sizes and counts keep the library defaults, which the
+// environment variables H5FD_SUBFILING_STRIPE_SIZE and
+// H5FD_SUBFILING_STRIPE_COUNT can also set.
+type SubfilingConfig struct {
+	IOCSelection SubfilingIOCSelection
+	StripeSize   int64 // bytes per stripe
+	StripeCount  int32 // number of subfiles
+}
+
+func haveSubfiling() error {
+	if C._GO_HDF5_HAVE_SUBFILING == 0 {
+		return fmt.Errorf("subfiling needs a parallel HDF5 1.14 library built with the subfiling driver")
+	}
+	return nil
+}
+
+// SetSubfiling sets the file access property list to use the subfiling
+// driver, configured by cfg or, if it is nil, by the library defaults.
+// It needs a library built with the driver, and MPI to be initialized.
+// herr_t H5Pset_fapl_subfiling(hid_t fapl_id, const H5FD_subfiling_config_t *config_ptr)
+func (p *PropList) SetSubfiling(cfg *SubfilingConfig) error {
+	if err := haveSubfiling(); err != nil {
+		return err
+	}
+	if cfg == nil {
+		cfg = &SubfilingConfig{IOCSelection: -1}
+	}
+	return h5err(C._go_hdf5_H5Pset_fapl_subfiling(p.id, C.int(cfg.IOCSelection), C.int64_t(cfg.StripeSize), C.int32_t(cfg.StripeCount)))
+}
+
+// Subfiling returns the subfiling configuration of the file access
+// property list, or the defaults if it does not use the driver.
+// herr_t H5Pget_fapl_subfiling(hid_t fapl_id, H5FD_subfiling_config_t *config_out)
+func (p *PropList) Subfiling() (*SubfilingConfig, error) {
+	if err := haveSubfiling(); err != nil {
+		return nil, err
+	}
+	var sel C.int
+	var size C.int64_t
+	var count C.int32_t
+	if err := h5err(C._go_hdf5_H5Pget_fapl_subfiling(p.id, &sel, &size, &count)); err != nil {
+		return nil, err
+	}
+	return &SubfilingConfig{IOCSelection: SubfilingIOCSelection(sel), StripeSize: int64(size), StripeCount: int32(count)}, nil
+}
@@ -0,0 +1,902 @@
+package hdf5
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAllocFillTime(t *testing.T) {
+	dcpl, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer dcpl.Close()
+
+	if err := dcpl.SetAllocTime(D_ALLOC_TIME_EARLY); err != nil {
+		t.Fatalf("SetAllocTime failed: %s", err)
+	}
+	if err := dcpl.SetFillTime(D_FILL_TIME_NEVER); err != nil {
+		t.Fatalf("SetFillTime failed: %s", err)
+	}
+	if at, err := dcpl.AllocTime(); err != nil {
+		t.Fatalf("AllocTime failed: %s", err)
+	} else if at != D_ALLOC_TIME_EARLY {
+		t.Errorf("AllocTime() = %v, want %v", at, D_ALLOC_TIME_EARLY)
+	}
+	if ft, err := dcpl.FillTime(); err != nil {
+		t.Fatalf("FillTime failed: %s", err)
+	} else if ft != D_FILL_TIME_NEVER {
+		t.Errorf("FillTime() = %v, want %v", ft, D_FILL_TIME_NEVER)
+	}
+
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	dspace, err := CreateSimpleDataspace([]uint{16}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("dset", T_NATIVE_DOUBLE, dspace, dcpl)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+
+	plist, err := dset.CreatePropList()
+	if err != nil {
+		t.Fatalf("CreatePropList failed: %s", err)
+	}
+	defer plist.Close()
+	if at, err := plist.AllocTime(); err != nil {
+		t.Fatalf("AllocTime failed: %s", err)
+	} else if at != D_ALLOC_TIME_EARLY {
+		t.Errorf("dataset AllocTime() = %v, want %v", at, D_ALLOC_TIME_EARLY)
+	}
+	if ft, err := plist.FillTime(); err != nil {
+		t.Fatalf("FillTime failed: %s", err)
+	} else if ft != D_FILL_TIME_NEVER {
+		t.Errorf("dataset FillTime() = %v, want %v", ft, D_FILL_TIME_NEVER)
+	}
+}
+
+func TestLayout(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	dcpl, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer dcpl.Close()
+	if layout, err := dcpl.Layout(); err != nil || layout != D_CONTIGUOUS {
+		t.Errorf("Layout returned %v, %v, want contiguous", layout, err)
+	}
+	if err := dcpl.SetLayout(D_COMPACT); err != nil {
+		t.Fatalf("SetLayout failed: %s", err)
+	}
+
+	small, err := CreateSimpleDataspace([]uint{4}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer small.Close()
+	dset, err := f.CreateDataset("small", T_NATIVE_INT32, small, dcpl)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	data := []int32{1, 2, 3, 4}
+	if err := dset.Write(data, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	got := make([]int32, len(data))
+	if err := dset.Read(got, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, data) {
+		t.Errorf("Read returned %v, want %v", got, data)
+	}
+	plist, err := dset.CreatePropList()
+	if err != nil {
+		t.Fatalf("CreatePropList failed: %s", err)
+	}
+	defer plist.Close()
+	if layout, err := plist.Layout(); err != nil || layout != D_COMPACT {
+		t.Errorf("dataset Layout returned %v, %v, want compact", layout, err)
+	}
+
+	large, err := CreateSimpleDataspace([]uint{1 << 15}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer large.Close()
+	if _, err := f.CreateDataset("large", T_NATIVE_DOUBLE, large, dcpl); err == nil {
+		t.Errorf("expected an error creating a compact dataset over 64KB")
+	}
+}
+
+func TestCharEncoding(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	lcpl, err := NewPropList(P_LINK_CREATE)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer lcpl.Close()
+	if err := lcpl.SetCharEncoding(T_CSET_UTF8); err != nil {
+		t.Fatalf("SetCharEncoding failed: %s", err)
+	}
+	if err := lcpl.SetCreateIntermediateGroup(true); err != nil {
+		t.Fatalf("SetCreateIntermediateGroup failed: %s", err)
+	}
+	if cset, err := lcpl.CharEncoding(); err != nil || cset != T_CSET_UTF8 {
+		t.Errorf("CharEncoding returned %v, %v", cset, err)
+	}
+	g, err := f.CreateGroupWith("grüße/δ", lcpl, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateGroupWith failed: %s", err)
+	}
+	defer g.Close()
+	if name, err := f.ObjectNameByIndex(0); err != nil || name != "grüße" {
+		t.Errorf("ObjectNameByIndex returned %q, %v", name, err)
+	}
+
+	acpl, err := NewPropList(P_ATTRIBUTE_CREATE)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer acpl.Close()
+	if err := acpl.SetCharEncoding(T_CSET_UTF8); err != nil {
+		t.Fatalf("SetCharEncoding failed: %s", err)
+	}
+	dspace, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatalf("CreateDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	a, err := g.CreateAttributeWith("température", T_NATIVE_DOUBLE, dspace, acpl)
+	if err != nil {
+		t.Fatalf("CreateAttributeWith failed: %s", err)
+	}
+	defer a.Close()
+	if name := a.Name(); name != "température" {
+		t.Errorf("attribute is named %q", name)
+	}
+}
+
+func TestCreationOrder(t *testing.T) {
+	order := P_CRT_ORDER_TRACKED | P_CRT_ORDER_INDEXED
+	fcpl, err := NewPropList(P_FILE_CREATE)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer fcpl.Close()
+	if err := fcpl.SetLinkCreationOrder(order); err != nil {
+		t.Fatalf("SetLinkCreationOrder failed: %s", err)
+	}
+	if flags, err := fcpl.LinkCreationOrder(); err != nil || flags != order {
+		t.Errorf("LinkCreationOrder returned %v, %v", flags, err)
+	}
+	f, err := CreateFileWith(FNAME, F_ACC_TRUNC, fcpl, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateFileWith failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	gcpl, err := NewPropList(P_GROUP_CREATE)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer gcpl.Close()
+	if err := gcpl.SetAttrCreationOrder(order); err != nil {
+		t.Fatalf("SetAttrCreationOrder failed: %s", err)
+	}
+	for _, name := range []string{"b", "a", "c"} {
+		g, err := f.CreateGroupWith(name, P_DEFAULT, gcpl)
+		if err != nil {
+			t.Fatalf("CreateGroupWith failed: %s", err)
+		}
+		g.Close()
+	}
+	if names, err := f.LinkNames(INDEX_CRT_ORDER); err != nil || !reflect.DeepEqual(names, []string{"b", "a", "c"}) {
+		t.Errorf("LinkNames(INDEX_CRT_ORDER) returned %v, %v", names, err)
+	}
+	if names, err := f.LinkNames(INDEX_NAME); err != nil || !reflect.DeepEqual(names, []string{"a", "b", "c"}) {
+		t.Errorf("LinkNames(INDEX_NAME) returned %v, %v", names, err)
+	}
+
+	g, err := f.OpenGroup("a")
+	if err != nil {
+		t.Fatalf("OpenGroup failed: %s", err)
+	}
+	defer g.Close()
+	dspace, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatalf("CreateDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	for _, name := range []string{"z", "y"} {
+		a, err := g.CreateAttribute(name, T_NATIVE_INT32, dspace)
+		if err != nil {
+			t.Fatalf("CreateAttribute failed: %s", err)
+		}
+		a.Close()
+	}
+	if names, err := g.AttributeNames(INDEX_CRT_ORDER); err != nil || !reflect.DeepEqual(names, []string{"z", "y"}) {
+		t.Errorf("AttributeNames(INDEX_CRT_ORDER) returned %v, %v", names, err)
+	}
+	if names, err := g.AttributeNames(INDEX_NAME); err != nil || !reflect.DeepEqual(names, []string{"y", "z"}) {
+		t.Errorf("AttributeNames(INDEX_NAME) returned %v, %v", names, err)
+	}
+}
+
+func TestAttrPhaseChange(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	gcpl, err := NewPropList(P_GROUP_CREATE)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer gcpl.Close()
+	if max, min, err := gcpl.AttrPhaseChange(); err != nil || max != 8 || min != 6 {
+		t.Errorf("AttrPhaseChange returned %d, %d, %v, want the defaults 8, 6", max, min, err)
+	}
+	if err := gcpl.SetAttrPhaseChange(0, 0); err != nil {
+		t.Fatalf("SetAttrPhaseChange failed: %s", err)
+	}
+	if max, min, err := gcpl.AttrPhaseChange(); err != nil || max != 0 || min != 0 {
+		t.Errorf("AttrPhaseChange returned %d, %d, %v", max, min, err)
+	}
+	if err := gcpl.SetAttrCreationOrder(P_CRT_ORDER_TRACKED | P_CRT_ORDER_INDEXED); err != nil {
+		t.Fatalf("SetAttrCreationOrder failed: %s", err)
+	}
+
+	g, err := f.CreateGroupWith("dense", P_DEFAULT, gcpl)
+	if err != nil {
+		t.Fatalf("CreateGroupWith failed: %s", err)
+	}
+	defer g.Close()
+	dspace, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatalf("CreateDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	var want []string
+	for i := 0; i < 100; i++ {
+		name := fmt.Sprintf("attr%d", i)
+		a, err := g.CreateAttribute(name, T_NATIVE_INT32, dspace)
+		if err != nil {
+			t.Fatalf("CreateAttribute failed: %s", err)
+		}
+		a.Close()
+		want = append(want, name)
+	}
+	if names, err := g.AttributeNames(INDEX_CRT_ORDER); err != nil || !reflect.DeepEqual(names, want) {
+		t.Errorf("AttributeNames(INDEX_CRT_ORDER) returned %v, %v", names, err)
+	}
+}
+
+func TestObjTrackTimes(t *testing.T) {
+	dcpl, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer dcpl.Close()
+	if track, err := dcpl.ObjTrackTimes(); err != nil || !track {
+		t.Errorf("ObjTrackTimes returned %v, %v by default", track, err)
+	}
+	if err := dcpl.SetObjTrackTimes(false); err != nil {
+		t.Fatalf("SetObjTrackTimes failed: %s", err)
+	}
+	if track, err := dcpl.ObjTrackTimes(); err != nil || track {
+		t.Errorf("ObjTrackTimes returned %v, %v", track, err)
+	}
+
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{4}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	s, err := f.CreateDataset("untimed", T_NATIVE_INT32, dspace, dcpl)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer s.Close()
+	info, err := s.Info()
+	if err != nil {
+		t.Fatalf("Info failed: %s", err)
+	}
+	if !info.ModTime.IsZero() || !info.BirthTime.IsZero() {
+		t.Errorf("untracked dataset has times %v and %v", info.ModTime, info.BirthTime)
+	}
+}
+
+func TestElinkAccess(t *testing.T) {
+	dir := t.TempDir()
+	ext, err := CreateFile(filepath.Join(dir, "calib.h5"), F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	dspace, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatalf("CreateDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	d, err := ext.CreateDataset("constants", T_NATIVE_DOUBLE, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	d.Close()
+	ext.Close()
+
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	if err := f.CreateExternalLink("calib.h5", "/constants", "calib"); err != nil {
+		t.Fatalf("CreateExternalLink failed: %s", err)
+	}
+	if _, err := f.OpenDataset("calib"); err == nil {
+		t.Fatalf("expected an error following a link to a file elsewhere")
+	}
+
+	dapl, err := NewPropList(P_DATASET_ACCESS)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer dapl.Close()
+	if err := dapl.SetElinkPrefix(dir + "/"); err != nil {
+		t.Fatalf("SetElinkPrefix failed: %s", err)
+	}
+	if prefix, err := dapl.ElinkPrefix(); err != nil || prefix != dir+"/" {
+		t.Errorf("ElinkPrefix returned %q, %v", prefix, err)
+	}
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer fapl.Close()
+	if err := fapl.SetLibverBounds(F_LIBVER_V18, F_LIBVER_LATEST); err != nil {
+		t.Fatalf("SetLibverBounds failed: %s", err)
+	}
+	if err := dapl.SetElinkFapl(fapl); err != nil {
+		t.Fatalf("SetElinkFapl failed: %s", err)
+	}
+	got, err := dapl.ElinkFapl()
+	if err != nil {
+		t.Fatalf("ElinkFapl failed: %s", err)
+	}
+	defer got.Close()
+	if low, _, err := got.LibverBounds(); err != nil || low != F_LIBVER_V18 {
+		t.Errorf("the external link file access properties have bounds %v, %v", low, err)
+	}
+
+	d, err = f.OpenDatasetWith("calib", dapl)
+	if err != nil {
+		t.Fatalf("OpenDatasetWith failed: %s", err)
+	}
+	d.Close()
+}
+
+func TestLibverBounds(t *testing.T) {
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer fapl.Close()
+	if err := fapl.SetLibverBounds(F_LIBVER_LATEST, F_LIBVER_LATEST); err != nil {
+		t.Fatalf("SetLibverBounds failed: %s", err)
+	}
+	f, err := CreateFileWith(FNAME, F_ACC_TRUNC, P_DEFAULT, fapl)
+	if err != nil {
+		t.Fatalf("CreateFileWith failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	p, err := f.AccessPropList()
+	if err != nil {
+		t.Fatalf("AccessPropList failed: %s", err)
+	}
+	defer p.Close()
+	if low, high, err := p.LibverBounds(); err != nil || low != F_LIBVER_LATEST || high != F_LIBVER_LATEST {
+		t.Errorf("LibverBounds returned %v, %v, %v", low, high, err)
+	}
+}
+
+func TestPageBuffer(t *testing.T) {
+	fcpl, err := NewPropList(P_FILE_CREATE)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer fcpl.Close()
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer fapl.Close()
+	if err := requireVersion("", 1, 10, 1); err != nil {
+		if fcpl.SetPagedAggregation(0) == nil || fapl.SetPageBufferSize(4096, 0, 0) == nil {
+			t.Errorf("expected errors from libraries before 1.10.1")
+		}
+		t.Skip(err)
+	}
+
+	if err := fcpl.SetPagedAggregation(4096); err != nil {
+		t.Fatalf("SetPagedAggregation failed: %s", err)
+	}
+	if err := fapl.SetPageBufferSize(16*4096, 25, 25); err != nil {
+		t.Fatalf("SetPageBufferSize failed: %s", err)
+	}
+	if size, meta, raw, err := fapl.PageBufferSize(); err != nil || size != 16*4096 || meta != 25 || raw != 25 {
+		t.Errorf("PageBufferSize returned %d, %d, %d, %v", size, meta, raw, err)
+	}
+	f, err := CreateFileWith(FNAME, F_ACC_TRUNC, fcpl, fapl)
+	if err != nil {
+		t.Fatalf("CreateFileWith failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	f.Close()
+	f, err = OpenFileWith(FNAME, F_ACC_RDONLY, fapl)
+	if err != nil {
+		t.Fatalf("OpenFileWith failed: %s", err)
+	}
+	f.Close()
+}
+
+func TestChunkCache(t *testing.T) {
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer fapl.Close()
+	if err := fapl.SetCache(1009, 4<<20, 0.5); err != nil {
+		t.Fatalf("SetCache failed: %s", err)
+	}
+	if nslots, nbytes, w0, err := fapl.Cache(); err != nil || nslots != 1009 || nbytes != 4<<20 || w0 != 0.5 {
+		t.Errorf("Cache returned %d, %d, %v, %v", nslots, nbytes, w0, err)
+	}
+	f, err := CreateFileWith(FNAME, F_ACC_TRUNC, P_DEFAULT, fapl)
+	if err != nil {
+		t.Fatalf("CreateFileWith failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	dspace, err := CreateSimpleDataspace([]uint{100, 100}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dcpl, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer dcpl.Close()
+	if err := dcpl.SetChunk([]uint{10, 100}); err != nil {
+		t.Fatalf("SetChunk failed: %s", err)
+	}
+	dset, err := f.CreateDataset("d", T_NATIVE_DOUBLE, dspace, dcpl)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	dset.Close()
+
+	dapl, err := NewPropList(P_DATASET_ACCESS)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer dapl.Close()
+	if err := dapl.SetChunkCache(D_CHUNK_CACHE_NSLOTS_DEFAULT, 1<<20, D_CHUNK_CACHE_W0_DEFAULT); err != nil {
+		t.Fatalf("SetChunkCache failed: %s", err)
+	}
+	dset, err = f.OpenDatasetWith("d", dapl)
+	if err != nil {
+		t.Fatalf("OpenDatasetWith failed: %s", err)
+	}
+	defer dset.Close()
+	p, err := dset.AccessPropList()
+	if err != nil {
+		t.Fatalf("AccessPropList failed: %s", err)
+	}
+	defer p.Close()
+	if nslots, nbytes, w0, err := p.ChunkCache(); err != nil || nslots != 1009 || nbytes != 1<<20 || w0 != 0.5 {
+		t.Errorf("ChunkCache returned %d, %d, %v, %v", nslots, nbytes, w0, err)
+	}
+}
+
+func TestAlignment(t *testing.T) {
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer fapl.Close()
+	if err := fapl.SetAlignment(1024, 4096); err != nil {
+		t.Fatalf("SetAlignment failed: %s", err)
+	}
+	if err := fapl.SetMetaBlockSize(8192); err != nil {
+		t.Fatalf("SetMetaBlockSize failed: %s", err)
+	}
+	if threshold, alignment, err := fapl.Alignment(); err != nil || threshold != 1024 || alignment != 4096 {
+		t.Errorf("Alignment returned %d, %d, %v", threshold, alignment, err)
+	}
+	if size, err := fapl.MetaBlockSize(); err != nil || size != 8192 {
+		t.Errorf("MetaBlockSize returned %d, %v", size, err)
+	}
+
+	f, err := CreateFileWith(FNAME, F_ACC_TRUNC, P_DEFAULT, fapl)
+	if err != nil {
+		t.Fatalf("CreateFileWith failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	f.Close()
+}
+
+func TestFileSpaceStrategy(t *testing.T) {
+	fcpl, err := NewPropList(P_FILE_CREATE)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer fcpl.Close()
+	if err := requireVersion("", 1, 10, 1); err != nil {
+		if fcpl.SetFileSpaceStrategy(F_FSPACE_STRATEGY_FSM_AGGR, true, 1) == nil {
+			t.Errorf("expected an error from libraries before 1.10.1")
+		}
+		t.Skip(err)
+	}
+
+	if err := fcpl.SetFileSpaceStrategy(F_FSPACE_STRATEGY_PAGE, true, 16); err != nil {
+		t.Fatalf("SetFileSpaceStrategy failed: %s", err)
+	}
+	if err := fcpl.SetFileSpacePageSize(8192); err != nil {
+		t.Fatalf("SetFileSpacePageSize failed: %s", err)
+	}
+	if strategy, persist, threshold, err := fcpl.FileSpaceStrategy(); err != nil || strategy != F_FSPACE_STRATEGY_PAGE || !persist || threshold != 16 {
+		t.Errorf("FileSpaceStrategy returned %v, %v, %d, %v", strategy, persist, threshold, err)
+	}
+	if size, err := fcpl.FileSpacePageSize(); err != nil || size != 8192 {
+		t.Errorf("FileSpacePageSize returned %d, %v", size, err)
+	}
+	f, err := CreateFileWith(FNAME, F_ACC_TRUNC, fcpl, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateFileWith failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	f.Close()
+}
+
+func TestFileLocking(t *testing.T) {
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer fapl.Close()
+	if err := haveFileLocking(); err != nil {
+		if fapl.SetFileLocking(false, true) == nil {
+			t.Errorf("expected an error from libraries without H5Pset_file_locking")
+		}
+		t.Skip(err)
+	}
+
+	if err := fapl.SetFileLocking(false, true); err != nil {
+		t.Fatalf("SetFileLocking failed: %s", err)
+	}
+	if use, ignore, err := fapl.FileLocking(); err != nil || use || !ignore {
+		t.Errorf("FileLocking returned %v, %v, %v", use, ignore, err)
+	}
+	f, err := CreateFileWith(FNAME, F_ACC_TRUNC, P_DEFAULT, fapl)
+	if err != nil {
+		t.Fatalf("CreateFileWith failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	f.Close()
+	f, err = OpenFileWith(FNAME, F_ACC_RDONLY, fapl)
+	if err != nil {
+		t.Fatalf("OpenFileWith failed: %s", err)
+	}
+	f.Close()
+}
+
+func TestEvictOnClose(t *testing.T) {
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer fapl.Close()
+	if err := requireVersion("", 1, 10, 1); err != nil {
+		if fapl.SetEvictOnClose(true) == nil {
+			t.Errorf("expected an error from libraries before 1.10.1")
+		}
+		t.Skip(err)
+	}
+
+	if err := fapl.SetEvictOnClose(true); err != nil {
+		t.Fatalf("SetEvictOnClose failed: %s", err)
+	}
+	if evict, err := fapl.EvictOnClose(); err != nil || !evict {
+		t.Errorf("EvictOnClose returned %v, %v", evict, err)
+	}
+	f, err := CreateFileWith(FNAME, F_ACC_TRUNC, P_DEFAULT, fapl)
+	if err != nil {
+		t.Fatalf("CreateFileWith failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	g, err := f.CreateGroup("g")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	if err := g.Close(); err != nil {
+		t.Errorf("Close failed: %s", err)
+	}
+	if ok, err := f.LinkExists("g"); err != nil || !ok {
+		t.Errorf("LinkExists returned %v, %v after eviction", ok, err)
+	}
+}
+
+func TestTransferPropList(t *testing.T) {
+	dxpl, err := NewPropList(P_DATASET_XFER)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer dxpl.Close()
+	if err := dxpl.SetBuffer(16 << 20); err != nil {
+		t.Fatalf("SetBuffer failed: %s", err)
+	}
+	if err := dxpl.SetPreserve(true); err != nil {
+		t.Fatalf("SetPreserve failed: %s", err)
+	}
+	if err := dxpl.SetHyperVectorSize(4096); err != nil {
+		t.Fatalf("SetHyperVectorSize failed: %s", err)
+	}
+	if size, err := dxpl.Buffer(); err != nil || size != 16<<20 {
+		t.Errorf("Buffer returned %d, %v", size, err)
+	}
+	if preserve, err := dxpl.Preserve(); err != nil || !preserve {
+		t.Errorf("Preserve returned %v, %v", preserve, err)
+	}
+	if size, err := dxpl.HyperVectorSize(); err != nil || size != 4096 {
+		t.Errorf("HyperVectorSize returned %d, %v", size, err)
+	}
+
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{4}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("d", T_STD_I16BE, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	if err := dset.WriteWith([]int32{1, 2, 3, 4}, T_NATIVE_INT32, dxpl); err != nil {
+		t.Fatalf("WriteWith failed: %s", err)
+	}
+	got := make([]float64, 4)
+	if err := dset.ReadWith(got, T_NATIVE_DOUBLE, dxpl); err != nil {
+		t.Fatalf("ReadWith failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, []float64{1, 2, 3, 4}) {
+		t.Errorf("ReadWith returned %v", got)
+	}
+}
+
+func TestDataTransform(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{3}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("celsius", T_NATIVE_DOUBLE, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	if err := dset.Write([]float64{0, 100, -40}, T_NATIVE_DOUBLE); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+
+	dxpl, err := NewPropList(P_DATASET_XFER)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer dxpl.Close()
+	const expr = "x*9/5+32"
+	if err := dxpl.SetDataTransform(expr); err != nil {
+		t.Fatalf("SetDataTransform failed: %s", err)
+	}
+	if got, err := dxpl.DataTransform(); err != nil || got != expr {
+		t.Errorf("DataTransform returned %q, %v", got, err)
+	}
+	got := make([]float64, 3)
+	if err := dset.ReadWith(got, T_NATIVE_DOUBLE, dxpl); err != nil {
+		t.Fatalf("ReadWith failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, []float64{32, 212, -40}) {
+		t.Errorf("ReadWith returned %v", got)
+	}
+}
+
+func TestTypeConvHandler(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{3}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("counts", T_NATIVE_INT32, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	if err := dset.Write([]int32{1, 300, -300}, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+
+	got := make([]int8, 3)
+	if err := dset.Read(got, T_NATIVE_INT8); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, []int8{1, 127, -128}) {
+		t.Errorf("Read returned %v, want clamped values", got)
+	}
+
+	dxpl, err := NewPropList(P_DATASET_XFER)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer dxpl.Close()
+	var excepts []ConvException
+	err = dxpl.SetTypeConvHandler(func(except ConvException, src, dst []byte) ConvAction {
+		excepts = append(excepts, except)
+		clear(dst)
+		return T_CONV_HANDLED
+	})
+	if err != nil {
+		t.Fatalf("SetTypeConvHandler failed: %s", err)
+	}
+	if err := dset.ReadWith(got, T_NATIVE_INT8, dxpl); err != nil {
+		t.Fatalf("ReadWith failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, []int8{1, 0, 0}) {
+		t.Errorf("ReadWith returned %v", got)
+	}
+	if want := []ConvException{T_CONV_EXCEPT_RANGE_HI, T_CONV_EXCEPT_RANGE_LOW}; !reflect.DeepEqual(excepts, want) {
+		t.Errorf("handler called with %v, want %v", excepts, want)
+	}
+
+	if err := dxpl.SetTypeConvHandler(AbortConvOn(T_CONV_EXCEPT_RANGE_HI)); err != nil {
+		t.Fatalf("SetTypeConvHandler failed: %s", err)
+	}
+	if err := dset.ReadWith(got, T_NATIVE_INT8, dxpl); err == nil {
+		t.Errorf("ReadWith of overflowing values succeeded")
+	}
+
+	if err := dxpl.SetTypeConvHandler(nil); err != nil {
+		t.Fatalf("SetTypeConvHandler failed: %s", err)
+	}
+	if err := dset.ReadWith(got, T_NATIVE_INT8, dxpl); err != nil || got[1] != 127 {
+		t.Errorf("ReadWith without handler returned %v, %v", got, err)
+	}
+
+	// handlers are released with the last list holding them
+	registered := func() int {
+		convHandlers.Lock()
+		defer convHandlers.Unlock()
+		return len(convHandlers.handlers)
+	}
+	before := registered()
+	if err := dxpl.SetTypeConvHandler(AbortConvOn(T_CONV_EXCEPT_RANGE_HI)); err != nil {
+		t.Fatalf("SetTypeConvHandler failed: %s", err)
+	}
+	if err := dxpl.SetTypeConvHandler(AbortConvOn(T_CONV_EXCEPT_RANGE_HI)); err != nil {
+		t.Fatalf("SetTypeConvHandler failed: %s", err)
+	}
+	if n := registered(); n != before+1 {
+		t.Errorf("%d handlers registered after replacing one, want %d", n, before+1)
+	}
+	cp, err := dxpl.Copy()
+	if err != nil {
+		t.Fatalf("Copy failed: %s", err)
+	}
+	defer cp.Close()
+	dxpl.Close()
+	if err := dset.ReadWith(got, T_NATIVE_INT8, cp); err == nil {
+		t.Errorf("ReadWith with a copy of the list succeeded")
+	}
+	cp.Close()
+	if n := registered(); n != before {
+		t.Errorf("%d handlers registered after closing the lists, want %d", n, before)
+	}
+}
+
+func TestMPIOXfer(t *testing.T) {
+	dxpl, err := NewPropList(P_DATASET_XFER)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer dxpl.Close()
+	if err := haveParallel(); err != nil {
+		if dxpl.SetMPIOXfer(FD_MPIO_COLLECTIVE) == nil {
+			t.Errorf("expected an error from a serial library")
+		}
+		t.Skip(err)
+	}
+
+	if err := dxpl.SetMPIOXfer(FD_MPIO_COLLECTIVE); err != nil {
+		t.Fatalf("SetMPIOXfer failed: %s", err)
+	}
+	if mode, err := dxpl.MPIOXfer(); err != nil || mode != FD_MPIO_COLLECTIVE {
+		t.Errorf("MPIOXfer returned %v, %v", mode, err)
+	}
+}
+
+func TestSubfiling(t *testing.T) {
+	fapl, err := NewPropList(P_FILE_ACCESS)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer fapl.Close()
+	if err := haveSubfiling(); err != nil {
+		if fapl.SetSubfiling(nil) == nil {
+			t.Errorf("expected an error from a library without subfiling")
+		}
+		t.Skip(err)
+	}
+
+	cfg := &SubfilingConfig{IOCSelection: FD_SUBFILING_IOC_ONE_PER_NODE, StripeSize: 1 << 20, StripeCount: 2}
+	if err := fapl.SetSubfiling(cfg); err != nil {
+		t.Fatalf("SetSubfiling failed: %s", err)
+	}
+	if got, err := fapl.Subfiling(); err != nil || *got != *cfg {
+		t.Errorf("Subfiling returned %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,126 @@
+package hdf5
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"unsafe"
+)
+
+// goBuffer is Go memory passed to the library: the elements of a slice or
+// array, or the value a pointer points to. The memory, and the Go memory
+// its values point to, such as the bytes of strings, is pinned until
+// unpin, so that the garbage collector neither moves nor frees it while
+// the library holds its address, including past the end of a call or in
+// C memory, as cgo requires.
+type goBuffer struct {
+	ptr    unsafe.Pointer // nil for no elements
+	size   int            // in bytes
+	pinner runtime.Pinner
+}
+
+// pinBuffer returns the memory of data, a slice, pointer, array, string or
+// other value, to be read from by the library if write is set and written
+// to otherwise. Arrays and other values passed by value are copied, and
+// can only be written from, as can strings.
+func pinBuffer(data interface{}, write bool) (*goBuffer, error) {
+	v := reflect.ValueOf(data)
+	b := &goBuffer{}
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil, fmt.Errorf("hdf5: cannot use nil as a buffer")
+	case reflect.Slice:
+		if v.Len() > 0 {
+			b.ptr = v.UnsafePointer()
+			b.size = v.Len() * int(v.Type().Elem().Size())
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			b.ptr = v.UnsafePointer()
+			b.size = int(v.Type().Elem().Size())
+		}
+		v = v.Elem()
+	case reflect.String:
+		if !write {
+			return nil, fmt.Errorf("hdf5: cannot read into a string, pass a slice of strings")
+		}
+		if v.Len() > 0 {
+			b.ptr = unsafe.Pointer(unsafe.StringData(v.String()))
+			b.size = v.Len()
+		}
+		return b, nil
+	default:
+		if !write {
+			return nil, fmt.Errorf("hdf5: cannot read into a %s value, pass a pointer to it", v.Type())
+		}
+		c := reflect.New(v.Type())
+		c.Elem().Set(v)
+		b.ptr = c.UnsafePointer()
+		b.size = int(v.Type().Size())
+		v = c.Elem()
+	}
+	if b.ptr != nil {
+		b.pinner.Pin(b.ptr)
+		if v.IsValid() && hasPointers(v.Type()) {
+			pinPointers(&b.pinner, v)
+		}
+	}
+	return b, nil
+}
+
+// unpin releases the memory of the buffer to the garbage collector.
+func (b *goBuffer) unpin() {
+	b.pinner.Unpin()
+}
+
+// hasPointers reports whether values of type t hold pointers to Go memory.
+func hasPointers(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Ptr, reflect.UnsafePointer, reflect.Map, reflect.Chan, reflect.Func, reflect.Interface:
+		return true
+	case reflect.Array:
+		return t.Len() > 0 && hasPointers(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if hasPointers(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pinPointers pins the Go memory the value v, or its elements or fields,
+// points to, recursively.
+func pinPointers(p *runtime.Pinner, v reflect.Value) {
+	if !hasPointers(v.Type()) {
+		return
+	}
+	switch v.Kind() {
+	case reflect.String:
+		if v.Len() > 0 {
+			p.Pin(unsafe.StringData(v.String()))
+		}
+	case reflect.Slice:
+		if v.Len() > 0 {
+			p.Pin(v.UnsafePointer())
+		}
+		for i := 0; i < v.Len(); i++ {
+			pinPointers(p, v.Index(i))
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			pinPointers(p, v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			pinPointers(p, v.Field(i))
+		}
+	case reflect.Ptr, reflect.UnsafePointer:
+		if !v.IsNil() {
+			p.Pin(v.UnsafePointer())
+		}
+	}
+	// maps, channels, functions and interfaces are meaningless to the
+	// library, and may not be pinned
+}
@@ -4,10 +4,27 @@ package hdf5
 // #include "hdf5_hl.h"
 // #include <stdlib.h>
 // #include <string.h>
+//
+// static hid_t _go_hdf5_H5PTget_type(hid_t table_id) {
+// #if H5_VERSION_GE(1,10,0)
+//   return H5PTget_type(table_id);
+// #else
+//   return 0;
+// #endif
+// }
+// static hid_t _go_hdf5_H5PTget_dataset(hid_t table_id) {
+// #if H5_VERSION_GE(1,10,0)
+//   return H5PTget_dataset(table_id);
+// #else
+//   return 0;
+// #endif
+// }
 import "C"
 
 import (
+	"context"
 	"fmt"
+	"iter"
 
 	"reflect"
 	"runtime"
@@ -24,10 +41,20 @@ type Table struct {
 func newPacketTable(id C.hid_t) *Table {
 	t := &Table{id: id}
 	runtime.SetFinalizer(t, (*Table).finalizer)
+	trackID(id)
 	return t
 }
 
 func (t *Table) finalizer() {
+	if t.id > 0 && headerVersion.atLeast(1, 10, 0) &&
+		C.H5Iis_valid(C._go_hdf5_H5PTget_dataset(t.id)) <= 0 {
+		// the dataset of the table was closed by File.CloseWith, so that
+		// only the table itself can still be released; libraries before
+		// 1.10 cannot tell, and the table is closed as usual
+		quietly(func() { C.H5PTclose(t.id) })
+		t.id = 0
+		return
+	}
 	err := t.Close()
 	if err != nil {
 		panic(fmt.Sprintf("error closing packet table: %s", err))
@@ -39,9 +66,7 @@ func (t *Table) finalizer() {
 func (t *Table) Close() error {
 	if t.id > 0 {
 		err := h5err(C.H5PTclose(t.id))
-		if err != nil {
-			t.id = 0
-		}
+		t.id = 0
 		return err
 	}
 	return nil
@@ -61,105 +86,300 @@ func (t *Table) Id() int {
 	return int(t.id)
 }
 
-// Reads a number of packets from a packet table.
+// Reads a number of packets from a packet table. data is a slice, or a
+// pointer to an array, with room for nrecords packets, or a pointer to a
+// slice, which is grown if needed and resliced to nrecords packets.
 // herr_t H5PTread_packets( hid_t table_id, hsize_t start, size_t nrecords, void* data)
 func (t *Table) ReadPackets(start, nrecords int, data interface{}) error {
 	c_start := C.hsize_t(start)
 	c_nrecords := C.size_t(nrecords)
-	rt := reflect.TypeOf(data)
+	c_data, err := packetBuffer(data, nrecords)
+	if err != nil || nrecords == 0 {
+		return err
+	}
+	err = h5err(C.H5PTread_packets(t.id, c_start, c_nrecords, c_data))
+	return err
+}
+
+// packetBuffer returns the address of the first of n packets of data, a
+// slice or pointer to an array with room for them, or a pointer to a
+// slice, which is grown if needed and resliced to n packets.
+func packetBuffer(data interface{}, n int) (unsafe.Pointer, error) {
 	rv := reflect.ValueOf(data)
-	c_data := unsafe.Pointer(nil)
-	switch rt.Kind() {
-	case reflect.Array:
-		//fmt.Printf("--> array\n")
-		if rv.Cap() < nrecords {
-			panic(fmt.Sprintf("not enough capacity in array (cap=%d)", rv.Cap()))
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.Elem().Kind() == reflect.Array {
+			return packetBuffer(rv.Elem().Slice(0, rv.Elem().Len()).Interface(), n)
+		}
+		if rv.Elem().Kind() != reflect.Slice {
+			return nil, fmt.Errorf("unhandled type (%T) need slice, array or pointer to slice", data)
 		}
-		c_data = unsafe.Pointer(rv.Index(0).UnsafeAddr())
-		//c_nrecords = C.size_t(rv.Cap())
-
-	case reflect.Slice:
-		//fmt.Printf("--> slice\n")
-		if rv.Cap() < nrecords {
-			panic(fmt.Sprintf("not enough capacity in slice (cap=%d)", rv.Cap()))
-			// buf_slice := reflect.MakeSlice(rt, nrecords, nrecords)
-			// rv.Set(reflect.AppendSlice(rv, buf_slice))
+		slice := rv.Elem()
+		if slice.Cap() < n {
+			grown := reflect.MakeSlice(slice.Type(), n, n)
+			reflect.Copy(grown, slice)
+			slice.Set(grown)
+		} else {
+			slice.SetLen(n)
+		}
+		rv = slice
+
+	case reflect.Array, reflect.Slice:
+		if rv.Len() < n {
+			return nil, fmt.Errorf("not enough room in %s for %d packets (len=%d)", rv.Kind(), n, rv.Len())
 		}
-		slice := (*reflect.SliceHeader)(unsafe.Pointer(rv.UnsafeAddr()))
-		c_data = unsafe.Pointer(slice.Data)
-		//c_nrecords = C.size_t(rv.Cap())
 
 	default:
-		panic(fmt.Sprintf("unhandled kind (%s) need slice or array", rt.Kind()))
+		return nil, fmt.Errorf("unhandled kind (%s) need slice, array or pointer to slice", rv.Kind())
 	}
-	err := C.H5PTread_packets(t.id, c_start, c_nrecords, c_data)
-	return h5err(err)
+	if n == 0 {
+		return nil, nil
+	}
+	if !rv.Index(0).CanAddr() {
+		return nil, fmt.Errorf("cannot read packets into an array value, pass a pointer to it")
+	}
+	return rv.Index(0).Addr().UnsafePointer(), nil
+}
+
+// ReadAll reads all the packets of a packet table into data, a pointer to
+// a slice of the packet type, which is grown to hold them and resliced to
+// their number. The packets are read tableBatch at a time.
+// herr_t H5PTread_packets( hid_t table_id, hsize_t start, size_t nrecords, void* data)
+func (t *Table) ReadAll(data interface{}) error {
+	rv := reflect.ValueOf(data)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("unsupported type (%T), need a pointer to a slice", data)
+	}
+	n, err := t.NumPackets()
+	if err != nil {
+		return err
+	}
+	slice := rv.Elem()
+	if slice.Cap() < n {
+		slice.Set(reflect.MakeSlice(slice.Type(), n, n))
+	} else {
+		slice.SetLen(n)
+	}
+	for start := 0; start < n; start += tableBatch {
+		count := min(n-start, tableBatch)
+		c_data := slice.Index(start).Addr().UnsafePointer()
+		if err := h5err(C.H5PTread_packets(t.id, C.hsize_t(start), C.size_t(count), c_data)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Appends packets to the end of a packet table.
+// data may be a single packet, a pointer to a packet, or an array or slice of
+// packets.
 // herr_t H5PTappend( hid_t table_id, size_t nrecords, const void *data)
 func (t *Table) Append(data interface{}) error {
-	rt := reflect.TypeOf(data)
 	v := reflect.ValueOf(data)
-	c_nrecords := C.size_t(0)
-	c_data := unsafe.Pointer(nil)
-
-	switch rt.Kind() {
-
-	case reflect.Array:
-		//fmt.Printf("-->array\n")
+	c_nrecords := C.size_t(1)
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice, reflect.String:
 		c_nrecords = C.size_t(v.Len())
-		c_data = unsafe.Pointer(v.UnsafeAddr())
+	}
+	if c_nrecords == 0 {
+		return nil
+	}
+	b, err := pinBuffer(data, true)
+	if err != nil {
+		return err
+	}
+	defer b.unpin()
+	err = h5err(C.H5PTappend(t.id, c_nrecords, b.ptr))
+	if l := logger(t.id); l != nil {
+		l.Debug("append", "path", getName(t.id), "packets", int(c_nrecords), "error", err)
+	}
+	return err
+}
 
-	case reflect.Slice:
-		fmt.Printf("-->slice\n")
-		c_nrecords = C.size_t(v.Len())
-		slice := (*reflect.SliceHeader)(unsafe.Pointer(v.UnsafeAddr()))
-		c_data = unsafe.Pointer(slice.Data)
-		//c_data = unsafe.Pointer(v.Index(0).Addr().UnsafeAddr())
-		//c_data = unsafe.Pointer(&c_data)
+// Appends variable-length packets to the end of a packet table created with
+// CreateVarLenTable. data must be a slice of slices (e.g. [][]byte or
+// [][]float64) whose element type matches the element type of the table.
+func (t *Table) AppendVarLen(data interface{}) error {
+	rv := reflect.ValueOf(data)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("unsupported type (%s), need a slice of slices", rv.Type())
+	}
+	n := rv.Len()
+	if n == 0 {
+		return nil
+	}
+	vtype, err := t.varLenType(rv.Type())
+	if err != nil {
+		return err
+	}
+	C.H5Tclose(vtype)
+	// the hvl_t descriptors point to the packets, which are pinned.
+	var pinner runtime.Pinner
+	defer pinner.Unpin()
+	hvl := make([]C.hvl_t, n)
+	for i := range hvl {
+		row := rv.Index(i)
+		hvl[i].len = C.size_t(row.Len())
+		if row.Len() > 0 {
+			hvl[i].p = row.UnsafePointer()
+			pinner.Pin(hvl[i].p)
+		}
+	}
+	return h5err(C.H5PTappend(t.id, C.size_t(n), unsafe.Pointer(&hvl[0])))
+}
 
-	case reflect.String:
-		c_nrecords = C.size_t(v.Len())
-		str := (*reflect.StringHeader)(unsafe.Pointer(v.UnsafeAddr()))
-		c_data = unsafe.Pointer(str.Data)
+// varLenType returns the datatype of the packets of the table, which the
+// caller must close, after checking that they are variable-length with
+// elements of the size of those of rows, a slice of slices. Libraries
+// before 1.10 cannot tell the type of a table, so that the packets are
+// taken to be of bytes, which are reclaimed the same way, unchecked.
+func (t *Table) varLenType(rows reflect.Type) (C.hid_t, error) {
+	vtype := C._go_hdf5_H5PTget_type(t.id)
+	if err := h5err(C.herr_t(int(vtype))); err != nil {
+		return 0, err
+	}
+	if vtype == 0 {
+		vtype = C.H5Tvlen_create(T_NATIVE_UCHAR.id)
+		return vtype, h5err(C.herr_t(int(vtype)))
+	}
+	if TypeClass(C.H5Tget_class(vtype)) != T_VLEN {
+		C.H5Tclose(vtype)
+		return 0, fmt.Errorf("hdf5: packets of the table are not variable-length")
+	}
+	super := C.H5Tget_super(vtype)
+	size := int(C.H5Tget_size(super))
+	C.H5Tclose(super)
+	if size != int(rows.Elem().Elem().Size()) {
+		C.H5Tclose(vtype)
+		return 0, fmt.Errorf("hdf5: packets of %d-byte elements do not match %s", size, rows)
+	}
+	return vtype, nil
+}
 
-	case reflect.Ptr:
-		//fmt.Printf("-->ptr %v\n", v.Elem())
-		c_nrecords = C.size_t(1)
-		c_data = unsafe.Pointer(v.Elem().UnsafeAddr())
+// Reads a number of variable-length packets from a packet table created with
+// CreateVarLenTable. data must be a pointer to a slice of slices, which is
+// resized to hold nrecords packets.
+func (t *Table) ReadVarLen(start, nrecords int, data interface{}) error {
+	rv := reflect.ValueOf(data)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice || rv.Elem().Type().Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("unsupported type (%s), need a pointer to a slice of slices", rv.Type())
+	}
+	if nrecords <= 0 {
+		rv.Elem().SetLen(0)
+		return nil
+	}
+	row_type := rv.Elem().Type().Elem()
+	elem_size := int(row_type.Elem().Size())
 
-	default:
-		//fmt.Printf("-->\n")
-		c_nrecords = C.size_t(1)
-		c_data = unsafe.Pointer(v.UnsafeAddr())
+	vtype, err := t.varLenType(rv.Elem().Type())
+	if err != nil {
+		return err
 	}
+	defer C.H5Tclose(vtype)
 
-	fmt.Printf(":: append(%d, %d, %v)\n", c_nrecords, c_data, t.id)
-	err := C.H5PTappend(t.id, c_nrecords, c_data)
-	fmt.Printf(":: append(%d, %d) -> %v\n", c_nrecords, c_data, err)
-	return h5err(err)
+	hvl := make([]C.hvl_t, nrecords)
+	err = h5err(C.H5PTread_packets(t.id, C.hsize_t(start), C.size_t(nrecords), unsafe.Pointer(&hvl[0])))
+	if err != nil {
+		return err
+	}
+	defer reclaimN(vtype, nrecords, unsafe.Pointer(&hvl[0]))
+
+	rows := reflect.MakeSlice(rv.Elem().Type(), nrecords, nrecords)
+	for i := range hvl {
+		n := int(hvl[i].len)
+		row := reflect.MakeSlice(row_type, n, n)
+		if n > 0 {
+			C.memcpy(row.UnsafePointer(), hvl[i].p, C.size_t(n*elem_size))
+		}
+		rows.Index(i).Set(row)
+	}
+	rv.Elem().Set(rows)
+	return nil
 }
 
-// Reads packets from a packet table starting at the current index.
+// Reads packets from a packet table starting at the current index, as
+// many as fit in data if it is a slice or a pointer to an array. If data is a pointer to a
+// slice, all the packets up to the end of the table are read, and the
+// slice is grown if needed and resliced to their number.
 // herr_t H5PTget_next( hid_t table_id, size_t nrecords, void *data)
 func (t *Table) Next(data interface{}) error {
-	rt := reflect.TypeOf(data)
 	rv := reflect.ValueOf(data)
-	n := C.size_t(0)
-	cdata := unsafe.Pointer(nil)
-	switch rt.Kind() {
+	if rv.Kind() == reflect.Ptr && rv.Elem().Kind() == reflect.Array {
+		rv = rv.Elem()
+	}
+	n := 0
+	switch rv.Kind() {
 	case reflect.Array, reflect.Slice:
-		if rv.Cap() <= 0 {
-			panic(fmt.Sprintf("not enough capacity in array (cap=%d)", rv.Cap()))
+		n = rv.Len()
+		if n == 0 {
+			return fmt.Errorf("not enough room in %s (len=0)", rv.Kind())
+		}
+	case reflect.Ptr:
+		idx, err := t.Index()
+		if err != nil {
+			return err
+		}
+		total, err := t.NumPackets()
+		if err != nil {
+			return err
+		}
+		n = max(total-idx, 0)
+	}
+	cdata, err := packetBuffer(data, n)
+	if err != nil || n == 0 {
+		return err
+	}
+	err = h5err(C.H5PTget_next(t.id, C.size_t(n), cdata))
+	return err
+}
+
+// Iter returns an iterator over the packets from the current index to the end
+// of the table. buf must be a non-empty slice of the packet type: its length
+// sets how many packets are fetched per H5PTget_next call, and each packet is
+// yielded as a copy of an element of buf. Iteration stops after the first
+// error, which is yielded with a nil packet; this includes ctx.Err() once ctx
+// is done.
+func (t *Table) Iter(ctx context.Context, buf interface{}) iter.Seq2[interface{}, error] {
+	return func(yield func(interface{}, error) bool) {
+		rv := reflect.ValueOf(buf)
+		if rv.Kind() != reflect.Slice || rv.Len() == 0 {
+			yield(nil, fmt.Errorf("unsupported buffer (%T), need a non-empty slice", buf))
+			return
+		}
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			idx, err := t.Index()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			total, err := t.NumPackets()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			n := total - idx
+			if n <= 0 {
+				return
+			}
+			if n > rv.Len() {
+				n = rv.Len()
+			}
+			err = h5err(C.H5PTget_next(t.id, C.size_t(n), rv.Index(0).Addr().UnsafePointer()))
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for i := 0; i < n; i++ {
+				if !yield(rv.Index(i).Interface(), nil) {
+					return
+				}
+			}
 		}
-		cdata = unsafe.Pointer(rv.Index(0).UnsafeAddr())
-		n = C.size_t(rv.Cap())
-	default:
-		panic(fmt.Sprintf("unsupported kind (%s), need slice or array", rt.Kind()))
 	}
-	err := C.H5PTget_next(t.id, n, cdata)
-	return h5err(err)
 }
 
 // Returns the number of packets in a packet table.
@@ -177,6 +397,14 @@ func (t *Table) CreateIndex() error {
 	return h5err(err)
 }
 
+// Returns the current index of a packet table.
+// herr_t H5PTget_index( hid_t table_id, hsize_t *pt_index)
+func (t *Table) Index() (int, error) {
+	c_idx := C.hsize_t(0)
+	err := C.H5PTget_index(t.id, &c_idx)
+	return int(c_idx), h5err(err)
+}
+
 // Sets a packet table's index.
 // herr_t H5PTset_index( hid_t table_id, hsize_t pt_index)
 func (t *Table) SetIndex(index int) error {
@@ -212,6 +440,15 @@ func createTable(id C.hid_t, name string, dtype *Datatype, chunkSize, compressio
 	return table, err
 }
 
+func createVarLenTable(id C.hid_t, name string, elem *Datatype, chunkSize, compression int) (*Table, error) {
+	vlen_dt, err := NewVarLenType(elem)
+	if err != nil {
+		return nil, err
+	}
+	defer vlen_dt.Close()
+	return createTable(id, name, &vlen_dt.Datatype, chunkSize, compression)
+}
+
 func createTableFrom(id C.hid_t, name string, dtype interface{}, chunkSize, compression int) (*Table, error) {
 	switch dt := dtype.(type) {
 	case reflect.Type:
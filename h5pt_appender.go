@@ -0,0 +1,149 @@
+package hdf5
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// AppenderConfig controls when an Appender flushes its buffered records to
+// the packet table. A zero field disables the corresponding trigger.
+type AppenderConfig struct {
+	Count    int           // flush once this many records are buffered
+	Bytes    int           // flush once this many bytes are buffered
+	Interval time.Duration // flush buffered records at least this often
+}
+
+// Appender buffers packets in memory and appends them to a Table in batches,
+// issuing one H5PTappend per batch instead of one per record.
+//
+// When an Interval is configured, flushes also happen from a background
+// goroutine. Unless the HDF5 library is built thread-safe, the caller must
+// then not use the library from other goroutines while the Appender is open.
+type Appender struct {
+	mu    sync.Mutex
+	table *Table
+	cfg   AppenderConfig
+	buf   reflect.Value // slice of buffered records
+	err   error         // first error of a background flush
+	done  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewAppender returns an Appender writing to t.
+func NewAppender(t *Table, cfg AppenderConfig) *Appender {
+	a := &Appender{table: t, cfg: cfg}
+	if cfg.Interval > 0 {
+		a.done = make(chan struct{})
+		a.wg.Add(1)
+		go a.loop()
+	}
+	return a
+}
+
+func (a *Appender) loop() {
+	defer a.wg.Done()
+	ticker := time.NewTicker(a.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.mu.Lock()
+			if err := a.flush(); err != nil && a.err == nil {
+				a.err = err
+			}
+			a.mu.Unlock()
+		case <-a.done:
+			return
+		}
+	}
+}
+
+// Append buffers a record, a pointer to a record, or a slice or array of
+// records, flushing if the configured count or size is reached. All records
+// appended through an Appender must have the same type.
+func (a *Appender) Append(data interface{}) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.err != nil {
+		return a.err
+	}
+
+	v := reflect.ValueOf(data)
+	var rt reflect.Type
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		rt = v.Type().Elem()
+	case reflect.Ptr:
+		v = v.Elem()
+		rt = v.Type()
+	default:
+		rt = v.Type()
+	}
+
+	if !a.buf.IsValid() {
+		a.buf = reflect.MakeSlice(reflect.SliceOf(rt), 0, a.cfg.Count)
+	} else if a.buf.Type().Elem() != rt {
+		return fmt.Errorf("record type %s does not match buffered type %s", rt, a.buf.Type().Elem())
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			a.buf = reflect.Append(a.buf, v.Index(i))
+		}
+	default:
+		a.buf = reflect.Append(a.buf, v)
+	}
+
+	n := a.buf.Len()
+	if (a.cfg.Count > 0 && n >= a.cfg.Count) ||
+		(a.cfg.Bytes > 0 && n*int(rt.Size()) >= a.cfg.Bytes) {
+		return a.flush()
+	}
+	return nil
+}
+
+// Buffered returns the number of records waiting to be flushed.
+func (a *Appender) Buffered() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.buf.IsValid() {
+		return 0
+	}
+	return a.buf.Len()
+}
+
+// Flush appends all buffered records to the table.
+func (a *Appender) Flush() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.err != nil {
+		return a.err
+	}
+	return a.flush()
+}
+
+func (a *Appender) flush() error {
+	if !a.buf.IsValid() || a.buf.Len() == 0 {
+		return nil
+	}
+	err := a.table.Append(a.buf.Interface())
+	if err != nil {
+		return err
+	}
+	a.buf = a.buf.Slice(0, 0)
+	return nil
+}
+
+// Close flushes the remaining records and stops the background flushes.
+// It does not close the underlying table.
+func (a *Appender) Close() error {
+	if a.done != nil {
+		close(a.done)
+		a.wg.Wait()
+		a.done = nil
+	}
+	return a.Flush()
+}
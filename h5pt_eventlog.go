@@ -0,0 +1,302 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LogEntry is a record of an EventLog: an event of type T stamped with the
+// time it was logged, in nanoseconds since the Unix epoch. The fields of a
+// struct T are members of the packet next to the time; read the tables of
+// an EventLog with OpenTable[LogEntry[T]].
+type LogEntry[T any] struct {
+	Time   int64 `hdf5:"time"`
+	Record T     `hdf5:",flatten"`
+}
+
+// Timestamp returns the time of the entry.
+func (e LogEntry[T]) Timestamp() time.Time {
+	return time.Unix(0, e.Time)
+}
+
+// EventLogConfig configures an EventLog. A zero field takes the default it
+// documents.
+type EventLogConfig struct {
+	Group       string // group of the tables under the location, created if missing; the location itself if empty
+	Prefix      string // the tables are named Prefix_000000, Prefix_000001...; "events" if empty
+	ChunkSize   int    // packets per chunk of the tables; 1024 if not positive
+	Compression int    // deflate level of the tables, none if not positive
+
+	MaxRecords int // rotate to a new table once the current one holds this many records
+	MaxBytes   int // rotate to a new table once the records of the current one reach this size
+
+	FlushCount    int           // append the buffered records once this many are buffered
+	FlushInterval time.Duration // append the buffered records at least this often
+
+	// SWMR flushes the metadata of the file after each append of buffered
+	// records, so that readers of a file opened for single-writer
+	// multiple-reader access see them.
+	SWMR bool
+}
+
+// EventLog appends timestamped records of type T, usually a struct of fixed
+// size, to packet tables, which it creates as needed. Records are buffered
+// and appended in batches, and the log moves on to a new table once the
+// current one reaches the configured size. A log opened on a group that
+// already holds its tables starts a new table after the last one.
+//
+// When a FlushInterval is configured, flushes also happen from a
+// background goroutine. Unless the HDF5 library is built thread-safe, the
+// caller must then not use the library from other goroutines while the
+// EventLog is open.
+type EventLog[T any] struct {
+	mu     sync.Mutex
+	loc    Object // the file or group of the tables
+	group  *Group // the group of the tables, when opened by the log
+	cfg    EventLogConfig
+	table  *TableOf[LogEntry[T]]
+	seq    int // sequence number of the current table
+	n      int // records in the current table
+	size   int // bytes of a record
+	buf    []LogEntry[T]
+	err    error // first error of a background flush
+	closed bool
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewEventLog returns a log of records of type T in tables under the file
+// or group loc, configured by cfg. The first table is created on the first
+// flush of records.
+func NewEventLog[T any](loc Object, cfg EventLogConfig) (*EventLog[T], error) {
+	dtype, err := recordType[LogEntry[T]]()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = "events"
+	}
+	if cfg.ChunkSize <= 0 {
+		cfg.ChunkSize = tableBatch
+	}
+	if cfg.Compression <= 0 {
+		cfg.Compression = -1
+	}
+	l := &EventLog[T]{loc: loc, cfg: cfg, size: int(dtype.Size())}
+	if cfg.Group != "" {
+		id := C.hid_t(loc.Id())
+		ok, err := linkExists(id, cfg.Group)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			l.group, err = openGroup(id, cfg.Group, P_DEFAULT.id)
+		} else {
+			l.group, err = createGroup(id, cfg.Group, C.H5P_DEFAULT, C.H5P_DEFAULT, C.H5P_DEFAULT)
+		}
+		if err != nil {
+			return nil, err
+		}
+		l.loc = l.group
+	}
+	// continue after the tables of an earlier event log
+	for {
+		ok, err := linkExists(C.hid_t(l.loc.Id()), l.tableName())
+		if err != nil {
+			l.closeGroup()
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		l.seq++
+	}
+	if cfg.FlushInterval > 0 {
+		l.done = make(chan struct{})
+		l.wg.Add(1)
+		go l.loop()
+	}
+	return l, nil
+}
+
+func (l *EventLog[T]) loop() {
+	defer l.wg.Done()
+	ticker := time.NewTicker(l.cfg.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			l.mu.Lock()
+			if err := l.flush(); err != nil && l.err == nil {
+				l.err = err
+			}
+			l.mu.Unlock()
+		case <-l.done:
+			return
+		}
+	}
+}
+
+// tableName returns the name of the current table.
+func (l *EventLog[T]) tableName() string {
+	return fmt.Sprintf("%s_%06d", l.cfg.Prefix, l.seq)
+}
+
+// Table returns the name of the table the next records are appended to,
+// relative to the location of the event log.
+func (l *EventLog[T]) Table() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.cfg.Group != "" {
+		return l.cfg.Group + "/" + l.tableName()
+	}
+	return l.tableName()
+}
+
+// Log buffers record, stamped with the current time.
+func (l *EventLog[T]) Log(record T) error {
+	return l.LogAt(time.Now(), record)
+}
+
+// LogAt buffers record, stamped with t, flushing if the configured count
+// is reached.
+func (l *EventLog[T]) LogAt(t time.Time, record T) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return l.err
+	}
+	if l.closed {
+		return errors.New("hdf5: log to a closed event log")
+	}
+	l.buf = append(l.buf, LogEntry[T]{Time: t.UnixNano(), Record: record})
+	if l.cfg.FlushCount > 0 && len(l.buf) >= l.cfg.FlushCount {
+		return l.flush()
+	}
+	return nil
+}
+
+// Buffered returns the number of records waiting to be flushed.
+func (l *EventLog[T]) Buffered() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buf)
+}
+
+// Flush appends all buffered records to the tables.
+func (l *EventLog[T]) Flush() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return l.err
+	}
+	return l.flush()
+}
+
+// flush appends the buffered records, splitting them between tables where
+// the current one reaches its maximum size.
+func (l *EventLog[T]) flush() error {
+	for len(l.buf) > 0 {
+		room := l.room()
+		if room == 0 {
+			if err := l.rotate(); err != nil {
+				return err
+			}
+			continue
+		}
+		if l.table == nil {
+			t, err := CreateTable[LogEntry[T]](l.loc, l.tableName(), l.cfg.ChunkSize, l.cfg.Compression)
+			if err != nil {
+				return err
+			}
+			l.table = t
+		}
+		k := len(l.buf)
+		if room > 0 {
+			k = min(k, room)
+		}
+		if err := l.table.Append(l.buf[:k]); err != nil {
+			return err
+		}
+		l.n += k
+		l.buf = append(l.buf[:0], l.buf[k:]...)
+	}
+	if l.cfg.SWMR && l.table != nil {
+		return h5err(C.H5Fflush(C.hid_t(l.loc.Id()), C.H5F_SCOPE_LOCAL))
+	}
+	return nil
+}
+
+// room returns the number of records the current table can still take, or
+// -1 for no limit.
+func (l *EventLog[T]) room() int {
+	room := -1
+	if l.cfg.MaxRecords > 0 {
+		room = max(l.cfg.MaxRecords-l.n, 0)
+	}
+	if l.cfg.MaxBytes > 0 {
+		// a table takes at least one record, however large
+		limit := max(l.cfg.MaxBytes/l.size, 1)
+		if room < 0 || limit-l.n < room {
+			room = max(limit-l.n, 0)
+		}
+	}
+	return room
+}
+
+// rotate closes the current table and moves on to the next one.
+func (l *EventLog[T]) rotate() error {
+	if l.table != nil {
+		if err := l.table.Close(); err != nil {
+			return err
+		}
+		l.table = nil
+	}
+	l.seq++
+	l.n = 0
+	return nil
+}
+
+func (l *EventLog[T]) closeGroup() error {
+	if l.group == nil {
+		return nil
+	}
+	err := l.group.Close()
+	l.group = nil
+	return err
+}
+
+// Close flushes the remaining records, stops the background flushes and
+// closes the current table, and the group the event log opened.
+func (l *EventLog[T]) Close() error {
+	if l.done != nil {
+		close(l.done)
+		l.wg.Wait()
+		l.done = nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	err := l.err
+	if err == nil {
+		err = l.flush()
+	}
+	if l.table != nil {
+		if cerr := l.table.Close(); err == nil {
+			err = cerr
+		}
+		l.table = nil
+	}
+	if cerr := l.closeGroup(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -1,7 +1,13 @@
 package hdf5
 
-import "os"
-import "testing"
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
 
 const (
 	FNAME      string = "ex_table_01.h5"
@@ -91,3 +97,471 @@ func TestTable(t *testing.T) {
 		t.Fatalf("ReadPackets failed: %s", err)
 	}
 }
+
+func TestVarLenTable(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	table, err := f.CreateVarLenTable(TABLE_NAME, T_NATIVE_INT32, 10, 0)
+	if err != nil {
+		t.Fatalf("CreateVarLenTable failed: %s", err)
+	}
+	defer table.Close()
+
+	data := [][]int32{{1}, {}, {2, 3, 4}, {5, 6}}
+	if err = table.AppendVarLen(data[:1]); err != nil {
+		t.Fatalf("AppendVarLen failed with single packet: %s", err)
+	}
+	if err = table.AppendVarLen(data[1:]); err != nil {
+		t.Fatalf("AppendVarLen failed with multiple packets: %s", err)
+	}
+
+	n, err := table.NumPackets()
+	if err != nil {
+		t.Fatalf("NumPackets failed: %s", err)
+	}
+	if n != len(data) {
+		t.Fatalf("wrong number of packets: got %d, want %d", n, len(data))
+	}
+
+	var got [][]int32
+	if err = table.ReadVarLen(0, n, &got); err != nil {
+		t.Fatalf("ReadVarLen failed: %s", err)
+	}
+	if len(got) != len(data) {
+		t.Fatalf("wrong number of packets read: got %d, want %d", len(got), len(data))
+	}
+	for i := range data {
+		if len(got[i]) != len(data[i]) {
+			t.Fatalf("packet %d: got %v, want %v", i, got[i], data[i])
+		}
+		for j := range data[i] {
+			if got[i][j] != data[i][j] {
+				t.Errorf("packet %d: got %v, want %v", i, got[i], data[i])
+			}
+		}
+	}
+
+	var wide [][]float64
+	if err = table.ReadVarLen(0, n, &wide); err == nil {
+		t.Errorf("expected an error reading int32 packets into %T", wide)
+	}
+
+	if err = table.AppendVarLen([][]float64{{1, 2}}); err == nil {
+		t.Errorf("expected an error appending float64 packets to an int32 table")
+	}
+
+	if err = table.AppendVarLen([]int32{1, 2}); err == nil {
+		t.Errorf("expected an error appending a flat slice")
+	}
+}
+
+type sample_t struct {
+	id    int32
+	value float64
+}
+
+func TestTableIter(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	table, err := f.CreateTableFrom(TABLE_NAME, sample_t{}, 10, 0)
+	if err != nil {
+		t.Fatalf("CreateTableFrom failed: %s", err)
+	}
+	defer table.Close()
+
+	for i := 0; i < NRECORDS; i++ {
+		s := sample_t{int32(i), float64(i) / 2}
+		if err := table.Append(&s); err != nil {
+			t.Fatalf("Append failed: %s", err)
+		}
+	}
+
+	if err := table.SetIndex(2); err != nil {
+		t.Fatalf("SetIndex failed: %s", err)
+	}
+	if idx, err := table.Index(); err != nil {
+		t.Fatalf("Index failed: %s", err)
+	} else if idx != 2 {
+		t.Fatalf("wrong index: got %d, want %d", idx, 2)
+	}
+
+	// a batch size that does not divide the number of packets
+	i := 2
+	for rec, err := range table.Iter(context.Background(), make([]sample_t, 3)) {
+		if err != nil {
+			t.Fatalf("Iter failed: %s", err)
+		}
+		s := rec.(sample_t)
+		if s.id != int32(i) || s.value != float64(i)/2 {
+			t.Errorf("packet %d: got %v", i, s)
+		}
+		i++
+	}
+	if i != NRECORDS {
+		t.Errorf("wrong number of packets iterated: got %d, want %d", i-2, NRECORDS-2)
+	}
+	if idx, err := table.Index(); err != nil {
+		t.Fatalf("Index failed: %s", err)
+	} else if idx != NRECORDS {
+		t.Errorf("wrong index after iteration: got %d, want %d", idx, NRECORDS)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	table.CreateIndex()
+	for _, err := range table.Iter(ctx, make([]sample_t, 3)) {
+		if err != context.Canceled {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	}
+}
+
+func TestAppender(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	table, err := f.CreateTableFrom(TABLE_NAME, sample_t{}, 10, 0)
+	if err != nil {
+		t.Fatalf("CreateTableFrom failed: %s", err)
+	}
+	defer table.Close()
+
+	a := NewAppender(table, AppenderConfig{Count: 3})
+	for i := 0; i < NRECORDS; i++ {
+		if err := a.Append(sample_t{int32(i), float64(i)}); err != nil {
+			t.Fatalf("Append failed: %s", err)
+		}
+	}
+	if n, err := table.NumPackets(); err != nil {
+		t.Fatalf("NumPackets failed: %s", err)
+	} else if n != 6 {
+		t.Errorf("wrong number of flushed packets: got %d, want %d", n, 6)
+	}
+	if n := a.Buffered(); n != 2 {
+		t.Errorf("wrong number of buffered packets: got %d, want %d", n, 2)
+	}
+
+	if err := a.Append(int32(0)); err == nil {
+		t.Errorf("expected an error appending a mismatched record type")
+	}
+
+	if err := a.Close(); err != nil {
+		t.Fatalf("Close failed: %s", err)
+	}
+	if n, err := table.NumPackets(); err != nil {
+		t.Fatalf("NumPackets failed: %s", err)
+	} else if n != NRECORDS {
+		t.Errorf("wrong number of packets: got %d, want %d", n, NRECORDS)
+	}
+
+	recs := make([]sample_t, NRECORDS)
+	if err := table.ReadPackets(0, NRECORDS, recs); err != nil {
+		t.Fatalf("ReadPackets failed: %s", err)
+	}
+	for i, s := range recs {
+		if s.id != int32(i) {
+			t.Errorf("packet %d: got %v", i, s)
+		}
+	}
+}
+
+type event_t struct {
+	id     int32
+	energy float64
+	pos    [3]float32
+}
+
+func TestTableOf(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	table, err := CreateTable[event_t](f, TABLE_NAME, 10, 0)
+	if err != nil {
+		t.Fatalf("CreateTable failed: %s", err)
+	}
+	events := make([]event_t, NRECORDS)
+	for i := range events {
+		events[i] = event_t{int32(i), float64(i) * 1.5, [3]float32{float32(i), 0, -float32(i)}}
+	}
+	if err := table.Append(events[:3]); err != nil {
+		t.Fatalf("Append failed: %s", err)
+	}
+	if err := table.Append(events[3:]); err != nil {
+		t.Fatalf("Append failed: %s", err)
+	}
+	table.Close()
+
+	table, err = OpenTable[event_t](f, TABLE_NAME)
+	if err != nil {
+		t.Fatalf("OpenTable failed: %s", err)
+	}
+	defer table.Close()
+	got, err := table.ReadAt(2, 4)
+	if err != nil {
+		t.Fatalf("ReadAt failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, events[2:6]) {
+		t.Errorf("ReadAt returned %v, want %v", got, events[2:6])
+	}
+	var all []event_t
+	for e, err := range table.Iter(context.Background(), 3) {
+		if err != nil {
+			t.Fatalf("Iter failed: %s", err)
+		}
+		all = append(all, e)
+	}
+	if !reflect.DeepEqual(all, events) {
+		t.Errorf("Iter yielded %v, want %v", all, events)
+	}
+
+	if _, err := OpenTable[sample_t](f, TABLE_NAME); err == nil {
+		t.Errorf("expected an error opening a table of another record type")
+	}
+	type named_t struct {
+		name string
+		id   int32
+	}
+	if _, err := CreateTable[named_t](f, "named", 10, 0); err == nil {
+		t.Errorf("expected an error creating a table of variable-length records")
+	}
+}
+
+func TestTableReadAll(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	table, err := CreateTable[sample_t](f, TABLE_NAME, 100, 0)
+	if err != nil {
+		t.Fatalf("CreateTable failed: %s", err)
+	}
+	defer table.Close()
+
+	// more packets than are read at a time
+	samples := make([]sample_t, 2*tableBatch+10)
+	for i := range samples {
+		samples[i] = sample_t{int32(i), float64(i) / 4}
+	}
+	if err := table.Append(samples); err != nil {
+		t.Fatalf("Append failed: %s", err)
+	}
+
+	got, err := table.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, samples) {
+		t.Errorf("ReadAll returned %d packets, not the %d appended", len(got), len(samples))
+	}
+
+	recs := make([]sample_t, 5, 3*tableBatch)
+	if err := table.Table.ReadAll(&recs); err != nil {
+		t.Fatalf("ReadAll failed: %s", err)
+	}
+	if !reflect.DeepEqual(recs, samples) {
+		t.Errorf("ReadAll returned %d packets, not the %d appended", len(recs), len(samples))
+	}
+	if err := table.Table.ReadAll(recs); err == nil {
+		t.Errorf("expected an error reading into a slice")
+	}
+}
+
+func TestTableGrow(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	table, err := f.CreateTableFrom(TABLE_NAME, sample_t{}, 10, 0)
+	if err != nil {
+		t.Fatalf("CreateTableFrom failed: %s", err)
+	}
+	defer table.Close()
+	samples := make([]sample_t, NRECORDS)
+	for i := range samples {
+		samples[i] = sample_t{int32(i), float64(i)}
+	}
+	if err := table.Append(samples); err != nil {
+		t.Fatalf("Append failed: %s", err)
+	}
+
+	var recs []sample_t
+	if err := table.ReadPackets(0, NRECORDS, &recs); err != nil {
+		t.Fatalf("ReadPackets failed: %s", err)
+	}
+	if !reflect.DeepEqual(recs, samples) {
+		t.Errorf("ReadPackets returned %v, want %v", recs, samples)
+	}
+	if err := table.ReadPackets(1, 2, &recs); err != nil {
+		t.Fatalf("ReadPackets failed: %s", err)
+	}
+	if !reflect.DeepEqual(recs, samples[1:3]) {
+		t.Errorf("ReadPackets returned %v, want %v", recs, samples[1:3])
+	}
+	if err := table.ReadPackets(0, NRECORDS, make([]sample_t, 2)); err == nil {
+		t.Errorf("expected an error reading into a short slice")
+	}
+	var arr [NRECORDS]sample_t
+	if err := table.ReadPackets(0, NRECORDS, &arr); err != nil || arr[NRECORDS-1] != samples[NRECORDS-1] {
+		t.Errorf("ReadPackets into an array returned %v, %v", arr, err)
+	}
+
+	if err := table.SetIndex(3); err != nil {
+		t.Fatalf("SetIndex failed: %s", err)
+	}
+	two := make([]sample_t, 2)
+	if err := table.Next(two); err != nil {
+		t.Fatalf("Next failed: %s", err)
+	}
+	if !reflect.DeepEqual(two, samples[3:5]) {
+		t.Errorf("Next returned %v, want %v", two, samples[3:5])
+	}
+	var rest []sample_t
+	if err := table.Next(&rest); err != nil {
+		t.Fatalf("Next failed: %s", err)
+	}
+	if !reflect.DeepEqual(rest, samples[5:]) {
+		t.Errorf("Next returned %v, want %v", rest, samples[5:])
+	}
+	if err := table.Next(&rest); err != nil || len(rest) != 0 {
+		t.Errorf("Next at the end returned %v, %v", rest, err)
+	}
+	if err := table.Next(3); err == nil {
+		t.Errorf("expected an error reading into a non-slice")
+	}
+}
+
+func TestTableQuery(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	table, err := CreateTable[event_t](f, TABLE_NAME, 256, 0)
+	if err != nil {
+		t.Fatalf("CreateTable failed: %s", err)
+	}
+	defer table.Close()
+	events := make([]event_t, 3*tableBatch)
+	for i := range events {
+		events[i] = event_t{int32(i), float64(i) * 1.5, [3]float32{}}
+	}
+	if err := table.Append(events); err != nil {
+		t.Fatalf("Append failed: %s", err)
+	}
+
+	var got []int32
+	for e, err := range table.Query(context.Background(), func(e event_t) bool { return e.id%1000 == 7 }) {
+		if err != nil {
+			t.Fatalf("Query failed: %s", err)
+		}
+		got = append(got, e.id)
+	}
+	if want := []int32{7, 1007, 2007}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Query yielded %v, want %v", got, want)
+	}
+	if idx, err := table.Index(); err != nil || idx != 0 {
+		t.Errorf("Query moved the index to %d, %v", idx, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, err := range table.Query(ctx, func(event_t) bool { return true }) {
+		if err != context.Canceled {
+			t.Errorf("Query yielded %v after cancellation", err)
+		}
+	}
+}
+
+func TestEventLog(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	log, err := NewEventLog[event_t](f, EventLogConfig{Group: "log", MaxRecords: 4, FlushCount: 3})
+	if err != nil {
+		t.Fatalf("NewEventLog failed: %s", err)
+	}
+	start := time.Unix(1700000000, 0)
+	for i := 0; i < 10; i++ {
+		if err := log.LogAt(start.Add(time.Duration(i)*time.Second), event_t{id: int32(i)}); err != nil {
+			t.Fatalf("LogAt failed: %s", err)
+		}
+	}
+	if n := log.Buffered(); n != 1 {
+		t.Errorf("Buffered = %d, want 1", n)
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close failed: %s", err)
+	}
+	if err := log.Log(event_t{}); err == nil {
+		t.Errorf("Log after Close succeeded")
+	}
+
+	g, err := f.OpenGroup("log")
+	if err != nil {
+		t.Fatalf("OpenGroup failed: %s", err)
+	}
+	defer g.Close()
+	id := int32(0)
+	for i, want := range []int{4, 4, 2} {
+		table, err := OpenTable[LogEntry[event_t]](g, fmt.Sprintf("events_%06d", i))
+		if err != nil {
+			t.Fatalf("OpenTable failed: %s", err)
+		}
+		entries, err := table.ReadAll()
+		table.Close()
+		if err != nil {
+			t.Fatalf("ReadAll failed: %s", err)
+		}
+		if len(entries) != want {
+			t.Fatalf("table %d holds %d records, want %d", i, len(entries), want)
+		}
+		for _, e := range entries {
+			if e.Record.id != id || !e.Timestamp().Equal(start.Add(time.Duration(id)*time.Second)) {
+				t.Errorf("record %d is %+v", id, e)
+			}
+			id++
+		}
+	}
+
+	// a new logger continues after the existing tables
+	log, err = NewEventLog[event_t](f, EventLogConfig{Group: "log"})
+	if err != nil {
+		t.Fatalf("NewEventLog failed: %s", err)
+	}
+	if name := log.Table(); name != "log/events_000003" {
+		t.Errorf("Table = %q, want log/events_000003", name)
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close failed: %s", err)
+	}
+}
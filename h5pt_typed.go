@@ -0,0 +1,212 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include "hdf5_hl.h"
+// #include <stdlib.h>
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"reflect"
+	"unsafe"
+)
+
+// tableBatch is the number of packets ReadAll reads at a time, and
+// TableOf.Iter when given no batch size.
+const tableBatch = 1024
+
+// TableOf is a packet table of records of the Go type T, usually a struct,
+// whose packets are appended and read as values of T. The compound
+// datatype of T is derived, and checked against the datatype of the
+// table, once when the table is created or opened, so that the packets
+// can be copied to and from Go memory without conversion. T may not hold
+// strings or slices, which map to variable-length datatypes; use fixed-size
+// arrays instead. The untyped methods of the embedded Table remain
+// available.
+type TableOf[T any] struct {
+	*Table
+}
+
+// CreateTable creates a packet table of records of type T named name under
+// the file or group loc, with the given chunk size in packets and, if not
+// negative, deflate compression level.
+// hid_t H5PTcreate_fl( hid_t loc_id, const char * dset_name, hid_t dtype_id, hsize_t chunk_size, int compression )
+func CreateTable[T any](loc Object, name string, chunkSize, compression int) (*TableOf[T], error) {
+	dtype, err := recordType[T]()
+	if err != nil {
+		return nil, err
+	}
+	t, err := createTable(C.hid_t(loc.Id()), name, dtype, chunkSize, compression)
+	if err != nil {
+		return nil, err
+	}
+	return &TableOf[T]{t}, nil
+}
+
+// OpenTable opens the packet table named name under the file or group loc
+// as a table of records of type T. It returns a *SchemaError if the native
+// layout of the datatype of the table is not that of T.
+// hid_t H5PTopen( hid_t loc_id, const char *dset_name )
+func OpenTable[T any](loc Object, name string) (*TableOf[T], error) {
+	if _, err := recordType[T](); err != nil {
+		return nil, err
+	}
+	id := C.hid_t(loc.Id())
+	if err := checkTableType(id, name, reflect.TypeOf((*T)(nil)).Elem()); err != nil {
+		return nil, err
+	}
+	t, err := openTable(id, name)
+	if err != nil {
+		return nil, err
+	}
+	return &TableOf[T]{t}, nil
+}
+
+// recordType returns the memory datatype of the records of type T, which
+// is shared and must not be closed.
+func recordType[T any]() (*Datatype, error) {
+	dtype, _, err := inferType((*T)(nil), nil)
+	if err != nil {
+		return nil, err
+	}
+	if hasVarLen(dtype.id) {
+		return nil, fmt.Errorf("hdf5: %s has variable-length fields, which a packet table cannot hold", reflect.TypeOf((*T)(nil)).Elem())
+	}
+	return dtype, nil
+}
+
+// checkTableType returns a *SchemaError if the native datatype of the
+// dataset name under loc differs from the memory datatype of its records of
+// type rt.
+func checkTableType(loc C.hid_t, name string, rt reflect.Type) error {
+	s, err := openDataset(loc, name)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	return validateSchema(s.id, rt)
+}
+
+// Append appends packets to the end of the table.
+// herr_t H5PTappend( hid_t table_id, size_t nrecords, const void *data)
+func (t *TableOf[T]) Append(packets []T) error {
+	if len(packets) == 0 {
+		return nil
+	}
+	err := h5err(C.H5PTappend(t.id, C.size_t(len(packets)), unsafe.Pointer(&packets[0])))
+	if l := logger(t.id); l != nil {
+		l.Debug("append", "path", getName(t.id), "packets", len(packets), "error", err)
+	}
+	return err
+}
+
+// ReadAt returns the n packets of the table starting at packet start.
+// herr_t H5PTread_packets( hid_t table_id, hsize_t start, size_t nrecords, void* data)
+func (t *TableOf[T]) ReadAt(start, n int) ([]T, error) {
+	packets := make([]T, n)
+	if n == 0 {
+		return packets, nil
+	}
+	err := h5err(C.H5PTread_packets(t.id, C.hsize_t(start), C.size_t(n), unsafe.Pointer(&packets[0])))
+	if err != nil {
+		return nil, err
+	}
+	return packets, nil
+}
+
+// ReadAll returns all the packets of the table.
+// herr_t H5PTread_packets( hid_t table_id, hsize_t start, size_t nrecords, void* data)
+func (t *TableOf[T]) ReadAll() ([]T, error) {
+	var packets []T
+	if err := t.Table.ReadAll(&packets); err != nil {
+		return nil, err
+	}
+	return packets, nil
+}
+
+// Query returns an iterator over the packets of the whole table for which
+// match returns true, in order, read tableBatch at a time without moving
+// the current index, so that scans need memory for one batch only.
+// Packets appended while iterating are scanned too. Iteration stops after
+// the first error, which is yielded with a zero packet; this includes
+// ctx.Err() once ctx is done.
+// herr_t H5PTread_packets( hid_t table_id, hsize_t start, size_t nrecords, void* data)
+func (t *TableOf[T]) Query(ctx context.Context, match func(T) bool) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		buf := make([]T, tableBatch)
+		for start := 0; ; start += tableBatch {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			total, err := t.NumPackets()
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			n := min(total-start, tableBatch)
+			if n <= 0 {
+				return
+			}
+			err = h5err(C.H5PTread_packets(t.id, C.hsize_t(start), C.size_t(n), unsafe.Pointer(&buf[0])))
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, p := range buf[:n] {
+				if match(p) && !yield(p, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Iter returns an iterator over the packets from the current index to the
+// end of the table, which are read batch at a time, or 1024 at a time if
+// batch is not positive. Iteration stops after the first error, which is
+// yielded with a zero packet; this includes ctx.Err() once ctx is done.
+func (t *TableOf[T]) Iter(ctx context.Context, batch int) iter.Seq2[T, error] {
+	if batch <= 0 {
+		batch = tableBatch
+	}
+	return func(yield func(T, error) bool) {
+		var zero T
+		var buf []T
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			idx, err := t.Index()
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			total, err := t.NumPackets()
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			n := min(total-idx, batch)
+			if n <= 0 {
+				return
+			}
+			if buf == nil {
+				buf = make([]T, batch)
+			}
+			if err := h5err(C.H5PTget_next(t.id, C.size_t(n), unsafe.Pointer(&buf[0]))); err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, p := range buf[:n] {
+				if !yield(p, nil) {
+					return
+				}
+			}
+		}
+	}
+}
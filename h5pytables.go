@@ -0,0 +1,228 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// ---- PyTables conventions, as used by pandas.HDFStore ----
+
+const (
+	pyTablesFormatVersion = "2.1"
+	pyTablesGroupVersion  = "1.0"
+	pyTablesTableVersion  = "2.7"
+)
+
+// PyTable describes a table stored with the conventions of PyTables.
+type PyTable struct {
+	Title   string
+	Version string
+	// Columns lists the columns of the table in order. The members of
+	// nested compound columns are named by their path, e.g. "pos/x", as
+	// PyTables does.
+	Columns []string
+}
+
+// CreatePyTable creates a packet table from dtype, like CreateTableFrom,
+// with the attributes PyTables recognizes tables by. The root group of
+// the file is marked as a PyTables group if it is not already.
+func (f *File) CreatePyTable(name, title string, dtype interface{}, chunkSize, compression int) (*Table, error) {
+	return createPyTable(f.id, true, name, title, dtype, chunkSize, compression)
+}
+
+// CreatePyTable creates a packet table from dtype, like CreateTableFrom,
+// with the attributes PyTables recognizes tables by. The group is marked as
+// a PyTables group if it is not already.
+func (g *Group) CreatePyTable(name, title string, dtype interface{}, chunkSize, compression int) (*Table, error) {
+	return createPyTable(g.id, false, name, title, dtype, chunkSize, compression)
+}
+
+// PyTable returns the description of a dataset stored as a PyTables table.
+func (s *Dataset) PyTable() (*PyTable, error) {
+	class, err := stringAttribute(s.id, "CLASS")
+	if err != nil {
+		return nil, err
+	}
+	if class != "TABLE" {
+		return nil, fmt.Errorf("dataset %q is not a PyTables table", s.Name())
+	}
+	t := &PyTable{}
+	if t.Title, err = stringAttribute(s.id, "TITLE"); err != nil {
+		return nil, err
+	}
+	if t.Version, err = stringAttribute(s.id, "VERSION"); err != nil {
+		return nil, err
+	}
+	tid := C.H5Dget_type(s.id)
+	if err := h5err(C.herr_t(int(tid))); err != nil {
+		return nil, err
+	}
+	defer C.H5Tclose(tid)
+	if TypeClass(C.H5Tget_class(tid)) != T_COMPOUND {
+		return nil, fmt.Errorf("dataset %q is not a PyTables table", s.Name())
+	}
+	t.Columns = pyTableColumns(tid, "", nil)
+	return t, nil
+}
+
+func createPyTable(loc C.hid_t, root bool, name, title string, dtype interface{}, chunkSize, compression int) (*Table, error) {
+	if err := markPyTablesGroup(loc, root); err != nil {
+		return nil, err
+	}
+	table, err := createTableFrom(loc, name, dtype, chunkSize, compression)
+	if err != nil {
+		return nil, err
+	}
+	d, err := openDataset(loc, name)
+	if err != nil {
+		table.Close()
+		return nil, err
+	}
+	defer d.Close()
+	if err := setPyTableAttributes(d.id, title); err != nil {
+		table.Close()
+		return nil, err
+	}
+	return table, nil
+}
+
+func setPyTableAttributes(id C.hid_t, title string) error {
+	tid := C.H5Dget_type(id)
+	if err := h5err(C.herr_t(int(tid))); err != nil {
+		return err
+	}
+	defer C.H5Tclose(tid)
+	if TypeClass(C.H5Tget_class(tid)) != T_COMPOUND {
+		return fmt.Errorf("PyTables tables must have a compound datatype")
+	}
+
+	attrs := [][2]string{
+		{"CLASS", "TABLE"},
+		{"VERSION", pyTablesTableVersion},
+		{"TITLE", title},
+	}
+	for i := 0; i < int(C.H5Tget_nmembers(tid)); i++ {
+		c_name := C.H5Tget_member_name(tid, C.uint(i))
+		attrs = append(attrs, [2]string{fmt.Sprintf("FIELD_%d_NAME", i), C.GoString(c_name)})
+		C.free(unsafe.Pointer(c_name))
+	}
+	for _, a := range attrs {
+		if err := setStringAttribute(id, a[0], a[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markPyTablesGroup adds the attributes of PyTables groups to the group
+// id, and those of the root group if root is true, unless it has a CLASS.
+func markPyTablesGroup(id C.hid_t, root bool) error {
+	if ok, err := attributeExists(id, "CLASS"); err != nil || ok {
+		return err
+	}
+	attrs := [][2]string{
+		{"CLASS", "GROUP"},
+		{"TITLE", ""},
+		{"VERSION", pyTablesGroupVersion},
+	}
+	if root {
+		attrs = append(attrs, [2]string{"PYTABLES_FORMAT_VERSION", pyTablesFormatVersion})
+	}
+	for _, a := range attrs {
+		if err := setStringAttribute(id, a[0], a[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pyTableColumns appends the column paths of the compound datatype t to
+// columns.
+func pyTableColumns(t C.hid_t, prefix string, columns []string) []string {
+	for i := 0; i < int(C.H5Tget_nmembers(t)); i++ {
+		c_name := C.H5Tget_member_name(t, C.uint(i))
+		name := prefix + C.GoString(c_name)
+		C.free(unsafe.Pointer(c_name))
+
+		mtype := C.H5Tget_member_type(t, C.uint(i))
+		if TypeClass(C.H5Tget_class(mtype)) == T_COMPOUND {
+			columns = pyTableColumns(mtype, name+"/", columns)
+		} else {
+			columns = append(columns, name)
+		}
+		C.H5Tclose(mtype)
+	}
+	return columns
+}
+
+// setStringAttribute creates a scalar attribute holding value as a
+// fixed-length string, the way PyTables and h5py write Python strings.
+func setStringAttribute(id C.hid_t, name, value string) error {
+	dtype, err := T_C_S1.Copy()
+	if err != nil {
+		return err
+	}
+	defer dtype.Close()
+	size := len(value)
+	if size == 0 {
+		size = 1
+	}
+	if err := dtype.SetSize(uint(size)); err != nil {
+		return err
+	}
+	if err := h5err(C.H5Tset_strpad(dtype.id, C.H5T_STR_NULLPAD)); err != nil {
+		return err
+	}
+	dspace, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		return err
+	}
+	defer dspace.Close()
+	a, err := createAttribute(id, name, dtype, dspace, P_DEFAULT)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+	buf := make([]byte, size)
+	copy(buf, value)
+	return a.Write(buf, dtype)
+}
+
+// stringAttribute returns the value of the string attribute name of the
+// object id, or "" if it has no such attribute.
+func stringAttribute(id C.hid_t, name string) (string, error) {
+	ok, err := attributeExists(id, name)
+	if err != nil || !ok {
+		return "", err
+	}
+	a, err := openAttribute(id, name)
+	if err != nil {
+		return "", err
+	}
+	defer a.Close()
+	tid := C.H5Aget_type(a.id)
+	if err := h5err(C.herr_t(int(tid))); err != nil {
+		return "", err
+	}
+	defer C.H5Tclose(tid)
+	if _, npoints, err := jsonShape(C.H5Aget_space(a.id)); err != nil {
+		return "", err
+	} else if npoints != 1 {
+		return "", fmt.Errorf("attribute %q is not a scalar", name)
+	}
+	values, err := readValues(tid, 1, func(mtype C.hid_t, buf unsafe.Pointer) error {
+		return h5err(C.H5Aread(a.id, mtype, buf))
+	})
+	if err != nil {
+		return "", err
+	}
+	s, ok := values[0].(string)
+	if !ok {
+		return "", fmt.Errorf("attribute %q is not a string", name)
+	}
+	return s, nil
+}
@@ -0,0 +1,79 @@
+package hdf5
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+type pyPos_t struct {
+	x float64
+	y float64
+}
+
+type pyRow_t struct {
+	id  int64
+	pos pyPos_t
+}
+
+func TestPyTable(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	table, err := f.CreatePyTable("points", "some points", pyRow_t{}, 16, 0)
+	if err != nil {
+		t.Fatalf("CreatePyTable failed: %s", err)
+	}
+	defer table.Close()
+	if err := table.Append([]pyRow_t{{1, pyPos_t{0.5, 1.5}}}); err != nil {
+		t.Fatalf("Append failed: %s", err)
+	}
+
+	class, err := stringAttribute(f.id, "CLASS")
+	if err != nil {
+		t.Fatalf("stringAttribute failed: %s", err)
+	}
+	if class != "GROUP" {
+		t.Errorf("root group has CLASS %q, want GROUP", class)
+	}
+
+	dset, err := f.OpenDataset("points")
+	if err != nil {
+		t.Fatalf("OpenDataset failed: %s", err)
+	}
+	defer dset.Close()
+	info, err := dset.PyTable()
+	if err != nil {
+		t.Fatalf("PyTable failed: %s", err)
+	}
+	want := &PyTable{
+		Title:   "some points",
+		Version: pyTablesTableVersion,
+		Columns: []string{"id", "pos/x", "pos/y"},
+	}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("PyTable returned %+v, want %+v", info, want)
+	}
+	if name, err := stringAttribute(dset.id, "FIELD_1_NAME"); err != nil || name != "pos" {
+		t.Errorf("FIELD_1_NAME is %q (%v), want pos", name, err)
+	}
+
+	// a table without the PyTables attributes
+	plain, err := f.CreateTableFrom("plain", pyRow_t{}, 16, 0)
+	if err != nil {
+		t.Fatalf("CreateTableFrom failed: %s", err)
+	}
+	defer plain.Close()
+	pdset, err := f.OpenDataset("plain")
+	if err != nil {
+		t.Fatalf("OpenDataset failed: %s", err)
+	}
+	defer pdset.Close()
+	if _, err := pdset.PyTable(); err == nil {
+		t.Errorf("expected an error for a table without PyTables attributes")
+	}
+}
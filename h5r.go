@@ -0,0 +1,73 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// static hid_t _go_hdf5_H5Rdereference(hid_t obj_id, H5R_type_t ref_type, const void *ref) {
+// #if H5_VERSION_GE(1,10,0)
+//   return H5Rdereference2(obj_id, H5P_DEFAULT, ref_type, ref);
+// #else
+//   return H5Rdereference(obj_id, ref_type, ref);
+// #endif
+// }
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// --- References ---
+
+// RegionRef is a reference to a region of a dataset, a selection of its
+// dataspace, as stored in datasets and attributes of datatype
+// T_STD_REF_DSETREG.
+type RegionRef [C.sizeof_hdset_reg_ref_t]byte
+
+// CreateRegionRef returns a reference to the region of the dataset
+// selected in space, a dataspace of its extent, such as one returned by
+// Space with a hyperslab selected. The selection is stored in the file,
+// and returned by DereferenceRegion.
+// herr_t H5Rcreate(void *ref, hid_t loc_id, const char *name, H5R_type_t ref_type, hid_t space_id)
+func (s *Dataset) CreateRegionRef(space *Dataspace) (RegionRef, error) {
+	var ref RegionRef
+	c_name := C.CString(".")
+	defer C.free(unsafe.Pointer(c_name))
+	err := h5err(C.H5Rcreate(unsafe.Pointer(&ref[0]), s.id, c_name, C.H5R_DATASET_REGION, space.id))
+	return ref, err
+}
+
+// DereferenceRegion opens the dataset the region reference ref points to,
+// in the file, and returns it with a copy of its dataspace in which the
+// region is selected, to read it with ReadSelection.
+// hid_t H5Rdereference2(hid_t obj_id, hid_t oapl_id, H5R_type_t ref_type, const void *ref)
+// hid_t H5Rget_region(hid_t dataset, H5R_type_t ref_type, const void *ref)
+func (f *File) DereferenceRegion(ref RegionRef) (*Dataset, *Dataspace, error) {
+	return dereferenceRegion(f.id, ref)
+}
+
+// DereferenceRegion opens the dataset the region reference ref points to,
+// in the file of the group, and returns it with a copy of its dataspace in
+// which the region is selected, to read it with ReadSelection.
+// hid_t H5Rdereference2(hid_t obj_id, hid_t oapl_id, H5R_type_t ref_type, const void *ref)
+// hid_t H5Rget_region(hid_t dataset, H5R_type_t ref_type, const void *ref)
+func (g *Group) DereferenceRegion(ref RegionRef) (*Dataset, *Dataspace, error) {
+	return dereferenceRegion(g.id, ref)
+}
+
+func dereferenceRegion(id C.hid_t, ref RegionRef) (*Dataset, *Dataspace, error) {
+	c_ref := unsafe.Pointer(&ref[0])
+	hid := C._go_hdf5_H5Rdereference(id, C.H5R_DATASET_REGION, c_ref)
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return nil, nil, err
+	}
+	if typ := C.H5Iget_type(hid); typ != C.H5I_DATASET {
+		C.H5Oclose(hid)
+		return nil, nil, fmt.Errorf("hdf5: region reference to a %s: %w", idTypeName(typ), ErrTypeMismatch)
+	}
+	sid := C.H5Rget_region(hid, C.H5R_DATASET_REGION, c_ref)
+	if err := h5err(C.herr_t(int(sid))); err != nil {
+		C.H5Dclose(hid)
+		return nil, nil, err
+	}
+	return newDataset(hid), newDataspace(sid), nil
+}
@@ -0,0 +1,228 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// #include <string.h>
+import "C"
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unsafe"
+)
+
+// RepackRule sets the storage of the datasets whose path matches Pattern.
+type RepackRule struct {
+	// Pattern is matched with path.Match against the absolute path of each
+	// dataset, e.g. "/raw/*".
+	Pattern string
+	// Chunk, if not nil, is the new chunk size. Datasets that are not
+	// chunked and get compressed without a chunk size are stored as a
+	// single chunk.
+	Chunk []uint
+	// Deflate, if not 0, replaces the filters of the dataset with deflate
+	// compression at this level, from 1 to 9.
+	Deflate uint
+	// NoFilters removes all filters, leaving the data uncompressed.
+	NoFilters bool
+}
+
+func (r *RepackRule) match(name string) (bool, error) {
+	return path.Match(r.Pattern, name)
+}
+
+// apply modifies the dataset creation property list dcpl of a dataset with
+// the dimensions dims according to the rule.
+func (r *RepackRule) apply(dcpl *PropList, dims []uint) error {
+	if r.NoFilters || r.Deflate > 0 {
+		if err := dcpl.RemoveFilters(); err != nil {
+			return err
+		}
+	}
+	chunk := r.Chunk
+	if chunk == nil && r.Deflate > 0 {
+		current, err := dcpl.Chunk()
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			chunk = make([]uint, len(dims))
+			for i, d := range dims {
+				chunk[i] = d
+				if d == 0 {
+					chunk[i] = 1
+				}
+			}
+		}
+	}
+	if chunk != nil {
+		if err := dcpl.SetChunk(chunk); err != nil {
+			return err
+		}
+	}
+	if r.Deflate > 0 {
+		return dcpl.SetDeflate(r.Deflate)
+	}
+	return nil
+}
+
+// Repack copies the whole of src to a new file named dst, overwriting any
+// existing file, like the h5repack tool. The storage of each dataset is set
+// by the first of rules whose pattern matches its path; datasets that match
+// no rule are copied unchanged. Since the objects are written anew, the
+// output does not contain the free space left behind in src by deleted or
+// rewritten objects. An object linked from several groups is copied once,
+// under the first of its paths in name order. Soft and external links are
+// recreated with the same targets, so external links still point to the
+// files they pointed to from src.
+func Repack(src *File, dst string, rules []RepackRule) error {
+	// validate the patterns before creating the output
+	for i := range rules {
+		if _, err := rules[i].match("/"); err != nil {
+			return err
+		}
+	}
+
+	f, err := CreateFile(dst, F_ACC_TRUNC)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := copyAttributes(src.id, f.id); err != nil {
+		return err
+	}
+	err = walkLinks(src.id, func(name string, info *ObjectInfo) error {
+		return repackObject(src, f, name, info.Type, rules)
+	}, func(name string, info *LinkInfo) error {
+		return repackLink(src, f, name, info.Type)
+	})
+	if err != nil {
+		return err
+	}
+	return f.Flush(F_SCOPE_LOCAL)
+}
+
+// openParent opens the group of dst that holds the object name, returning
+// the function that closes it.
+func openParent(dst *File, name string) (C.hid_t, string, func(), error) {
+	dir, base := path.Split(name)
+	if dir == "" {
+		return dst.id, base, func() {}, nil
+	}
+	g, err := openGroup(dst.id, strings.TrimSuffix(dir, "/"), C.H5P_DEFAULT)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	return g.id, base, func() { g.Close() }, nil
+}
+
+// repackLink recreates the soft or external link name of src in dst.
+func repackLink(src, dst *File, name string, typ LinkType) error {
+	parent, base, done, err := openParent(dst, name)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	switch typ {
+	case L_TYPE_SOFT:
+		target, err := softLinkTarget(src.id, name)
+		if err != nil {
+			return err
+		}
+		return createSoftLink(parent, target, base)
+	case L_TYPE_EXTERNAL:
+		file, obj, err := externalLinkTarget(src.id, name)
+		if err != nil {
+			return err
+		}
+		return createExternalLink(parent, file, obj, base)
+	}
+	return fmt.Errorf("hdf5: cannot repack the %s link %q", typ, name)
+}
+
+func repackObject(src, dst *File, name string, typ ObjectType, rules []RepackRule) error {
+	parent, base, done, err := openParent(dst, name)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	switch typ {
+	case O_TYPE_GROUP:
+		g, err := createGroup(parent, base, C.H5P_DEFAULT, C.H5P_DEFAULT, C.H5P_DEFAULT)
+		if err != nil {
+			return err
+		}
+		defer g.Close()
+		id, err := openObject(src.id, name)
+		if err != nil {
+			return err
+		}
+		defer C.H5Oclose(id)
+		return copyAttributes(id, g.id)
+
+	case O_TYPE_DATASET:
+		s, err := openDataset(src.id, name)
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+		for i := range rules {
+			ok, err := rules[i].match("/" + name)
+			if err != nil {
+				return err
+			}
+			if ok {
+				return repackDataset(s, parent, base, &rules[i])
+			}
+		}
+		o, err := copyDataset(s, parent, base, nil)
+		if err != nil {
+			return err
+		}
+		return o.Close()
+	}
+
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+	c_base := C.CString(base)
+	defer C.free(unsafe.Pointer(c_base))
+	return h5err(C.H5Ocopy(src.id, c_name, parent, c_base, C.H5P_DEFAULT, C.H5P_DEFAULT))
+}
+
+func repackDataset(s *Dataset, parent C.hid_t, name string, rule *RepackRule) error {
+	dspace := s.Space()
+	if dspace == nil {
+		return fmt.Errorf("could not get dataspace of dataset %q", s.Name())
+	}
+	defer dspace.Close()
+	dims, err := extentDims(dspace)
+	if err != nil {
+		return err
+	}
+	if len(dims) == 0 {
+		// scalar datasets cannot be chunked or compressed
+		o, err := copyDataset(s, parent, name, nil)
+		if err != nil {
+			return err
+		}
+		return o.Close()
+	}
+
+	dcpl, err := s.CreatePropList()
+	if err != nil {
+		return err
+	}
+	defer dcpl.Close()
+	if err := rule.apply(dcpl, dims); err != nil {
+		return err
+	}
+	o, err := copyDataset(s, parent, name, dcpl)
+	if err != nil {
+		return err
+	}
+	return o.Close()
+}
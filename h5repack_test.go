@@ -0,0 +1,116 @@
+package hdf5
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRepack(t *testing.T) {
+	const outName = "ex_repack_out.h5"
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	raw, err := f.CreateGroup("raw")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	defer raw.Close()
+
+	data := make([]int32, 1000)
+	for i := range data {
+		data[i] = int32(i % 10)
+	}
+	dspace, err := CreateSimpleDataspace([]uint{uint(len(data))}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	for _, name := range []string{"a", "b"} {
+		dset, err := raw.CreateDataset(name, T_NATIVE_INT32, dspace, P_DEFAULT)
+		if err != nil {
+			t.Fatalf("CreateDataset failed: %s", err)
+		}
+		if err := dset.Write(data, T_NATIVE_INT32); err != nil {
+			t.Fatalf("Write failed: %s", err)
+		}
+		dset.Close()
+	}
+	keep, err := f.CreateDataset("keep", T_NATIVE_INT32, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	if err := keep.Write(data, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	keep.Close()
+	if err := raw.CreateSoftLink("/keep", "latest"); err != nil {
+		t.Fatalf("CreateSoftLink failed: %s", err)
+	}
+	if err := f.CreateExternalLink("ex_repack_other.h5", "/data", "ext"); err != nil {
+		t.Fatalf("CreateExternalLink failed: %s", err)
+	}
+
+	rules := []RepackRule{{Pattern: "/raw/*", Deflate: 6}}
+	if err := Repack(f, outName, rules); err != nil {
+		t.Fatalf("Repack failed: %s", err)
+	}
+	defer os.Remove(outName)
+
+	out, err := OpenFile(outName, F_ACC_RDONLY)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %s", err)
+	}
+	defer out.Close()
+
+	r, err := Diff(f, out, nil)
+	if err != nil {
+		t.Fatalf("Diff failed: %s", err)
+	}
+	if !r.Equal() {
+		t.Errorf("repacked file differs:\n%s", r)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		nfilters int
+	}{
+		{"/raw/a", 1},
+		{"/raw/b", 1},
+		{"/keep", 0},
+	} {
+		dset, err := out.OpenDataset(tt.name)
+		if err != nil {
+			t.Fatalf("OpenDataset failed: %s", err)
+		}
+		dcpl, err := dset.CreatePropList()
+		if err != nil {
+			t.Fatalf("CreatePropList failed: %s", err)
+		}
+		if n, err := dcpl.NumFilters(); err != nil {
+			t.Errorf("NumFilters failed: %s", err)
+		} else if n != tt.nfilters {
+			t.Errorf("%s: NumFilters() = %d, want %d", tt.name, n, tt.nfilters)
+		}
+		dcpl.Close()
+		dset.Close()
+	}
+
+	if target, err := out.SoftLinkTarget("/raw/latest"); err != nil {
+		t.Errorf("SoftLinkTarget failed: %s", err)
+	} else if target != "/keep" {
+		t.Errorf("soft link points to %q, want /keep", target)
+	}
+	if file, obj, err := out.ExternalLinkTarget("ext"); err != nil {
+		t.Errorf("ExternalLinkTarget failed: %s", err)
+	} else if file != "ex_repack_other.h5" || obj != "/data" {
+		t.Errorf("external link points to %s:%s, want ex_repack_other.h5:/data", file, obj)
+	}
+
+	if err := Repack(f, outName, []RepackRule{{Pattern: "[", Deflate: 1}}); err == nil {
+		t.Errorf("expected an error for a malformed pattern")
+	}
+}
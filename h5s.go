@@ -8,8 +8,8 @@ import "C"
 import (
 	"errors"
 	"fmt"
-
 	"runtime"
+	"strconv"
 	"unsafe"
 )
 
@@ -26,9 +26,28 @@ const (
 	S_NULL     SpaceClass = 2  // null data space
 )
 
+// SelectOperator is how a new selection is combined with the current one.
+type SelectOperator C.H5S_seloper_t
+
+const (
+	S_SELECT_SET     SelectOperator = 0 // replace the selection
+	S_SELECT_OR      SelectOperator = 1 // add to the selection
+	S_SELECT_AND     SelectOperator = 2 // intersect with the selection
+	S_SELECT_XOR     SelectOperator = 3 // keep what is in only one of them
+	S_SELECT_NOTB    SelectOperator = 4 // remove from the selection
+	S_SELECT_NOTA    SelectOperator = 5 // keep what is not in the selection
+	S_SELECT_APPEND  SelectOperator = 6 // append points to the selection
+	S_SELECT_PREPEND SelectOperator = 7 // prepend points to the selection
+)
+
+// S_UNLIMITED is the maximum size of dimensions that can be extended
+// without limit.
+const S_UNLIMITED uint = ^uint(0)
+
 func newDataspace(id C.hid_t) *Dataspace {
 	ds := &Dataspace{id: id}
 	runtime.SetFinalizer(ds, (*Dataspace).finalizer)
+	trackID(id)
 	return ds
 }
 
@@ -63,8 +82,12 @@ func (s *Dataspace) Copy() (*Dataspace, error) {
 
 // Close releases and terminates access to a dataspace.
 func (s *Dataspace) Close() error {
-	err := C.H5Sclose(s.id)
-	return h5err(err)
+	if s.id > 0 {
+		err := C.H5Sclose(s.id)
+		s.id = 0
+		return h5err(err)
+	}
+	return nil
 }
 
 func (s *Dataspace) Id() int {
@@ -80,12 +103,12 @@ func CreateSimpleDataspace(dims, maxDims []uint) (*Dataspace, error) {
 	var c_dims, c_maxdims *C.hsize_t
 
 	rank := C.int(0)
-	if dims != nil {
+	if len(dims) > 0 {
 		rank = C.int(len(dims))
 		c_dims = (*C.hsize_t)(unsafe.Pointer(&dims[0]))
 
 	}
-	if maxDims != nil {
+	if len(maxDims) > 0 {
 		rank = C.int(len(maxDims))
 		c_maxdims = (*C.hsize_t)(unsafe.Pointer(&maxDims[0]))
 
@@ -123,11 +146,18 @@ func (s *Dataspace) SetOffset(offset []uint) error {
 	return h5err(err)
 }
 
-// SimpleExtentDims returns dataspace dimension size and maximum size.
+// SimpleExtentDims returns dataspace dimension size and maximum size,
+// which are empty for scalar and null dataspaces.
 func (s *Dataspace) SimpleExtentDims() (dims, maxdims []uint, err error) {
 	rank := s.SimpleExtentNDims()
+	if rank < 0 {
+		return nil, nil, h5err(C.herr_t(rank))
+	}
 	dims = make([]uint, rank)
 	maxdims = make([]uint, rank)
+	if rank == 0 {
+		return dims, maxdims, nil
+	}
 
 	c_dims := (*C.hsize_t)(unsafe.Pointer(&dims[0]))
 	c_maxdims := (*C.hsize_t)(unsafe.Pointer(&maxdims[0]))
@@ -150,3 +180,123 @@ func (s *Dataspace) SimpleExtentNPoints() int {
 func (s *Dataspace) SimpleExtentType() SpaceClass {
 	return SpaceClass(C.H5Sget_simple_extent_type(s.id))
 }
+
+// Shape returns the dimensions of the dataspace, which are empty for
+// scalar and null dataspaces.
+func (s *Dataspace) Shape() (Shape, error) {
+	dims, _, err := s.SimpleExtentDims()
+	return Shape(dims), err
+}
+
+// Shape is the dimensions of a dataspace, the slowest-changing first, as
+// in C arrays and Go slices of slices.
+type Shape []uint
+
+// Rank returns the number of dimensions, 0 for a scalar.
+func (s Shape) Rank() int {
+	return len(s)
+}
+
+// Size returns the number of elements, 1 for a scalar.
+func (s Shape) Size() uint {
+	n := uint(1)
+	for _, d := range s {
+		n *= d
+	}
+	return n
+}
+
+// Strides returns the number of elements between consecutive indices of
+// each dimension, in row-major order.
+func (s Shape) Strides() []uint {
+	strides := make([]uint, len(s))
+	n := uint(1)
+	for i := len(s) - 1; i >= 0; i-- {
+		strides[i] = n
+		n *= s[i]
+	}
+	return strides
+}
+
+// Offset returns the position in row-major order of the element at index,
+// which has one value per dimension.
+func (s Shape) Offset(index []uint) (uint, error) {
+	if len(index) != len(s) {
+		return 0, fmt.Errorf("index %v does not match shape %v", index, s)
+	}
+	off := uint(0)
+	for i, stride := range s.Strides() {
+		if index[i] >= s[i] {
+			return 0, fmt.Errorf("index %v out of range of shape %v", index, s)
+		}
+		off += index[i] * stride
+	}
+	return off, nil
+}
+
+// String returns the shape in the form (3, 4).
+func (s Shape) String() string {
+	b := []byte{'('}
+	for i, d := range s {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = strconv.AppendUint(b, uint64(d), 10)
+	}
+	return string(append(b, ')'))
+}
+
+func hsizes(v []uint) *C.hsize_t {
+	if len(v) == 0 {
+		return nil
+	}
+	c_v := make([]C.hsize_t, len(v))
+	for i, x := range v {
+		c_v[i] = C.hsize_t(x)
+	}
+	return &c_v[0]
+}
+
+// SelectHyperslab combines the selection of the dataspace with the count
+// blocks, of size block, that start at start in steps of stride, each of
+// which have one value per dimension. stride and block may be nil for
+// steps and blocks of 1. The hyperslab of a scalar dataspace, with empty
+// start and count, is its element, which can only be set as the selection.
+// herr_t H5Sselect_hyperslab(hid_t space_id, H5S_seloper_t op, const hsize_t *start, const hsize_t *stride, const hsize_t *count, const hsize_t *block)
+func (s *Dataspace) SelectHyperslab(op SelectOperator, start, stride, count, block []uint) error {
+	rank := s.SimpleExtentNDims()
+	for _, v := range [][]uint{start, stride, count, block} {
+		if v != nil && len(v) != rank {
+			return errors.New("size of hyperslab does not match extent")
+		}
+	}
+	if start == nil || count == nil {
+		return errors.New("hyperslab needs a start and a count")
+	}
+	if rank == 0 {
+		if op != S_SELECT_SET {
+			return errors.New("hyperslab of a scalar dataspace can only be set")
+		}
+		return s.SelectAll()
+	}
+	return h5err(C.H5Sselect_hyperslab(s.id, C.H5S_seloper_t(op), hsizes(start), hsizes(stride), hsizes(count), hsizes(block)))
+}
+
+// SelectAll selects the whole extent of the dataspace.
+// herr_t H5Sselect_all(hid_t dspace_id)
+func (s *Dataspace) SelectAll() error {
+	return h5err(C.H5Sselect_all(s.id))
+}
+
+// SelectNone clears the selection of the dataspace.
+// herr_t H5Sselect_none(hid_t spaceid)
+func (s *Dataspace) SelectNone() error {
+	return h5err(C.H5Sselect_none(s.id))
+}
+
+// SelectNPoints returns the number of elements in the selection of the
+// dataspace.
+// hssize_t H5Sget_select_npoints(hid_t space_id)
+func (s *Dataspace) SelectNPoints() int {
+	return int(C.H5Sget_select_npoints(s.id))
+}
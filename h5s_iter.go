@@ -0,0 +1,96 @@
+package hdf5
+
+// #include "hdf5.h"
+// #if H5_VERSION_GE(1,12,0)
+// static hid_t _go_hdf5_H5Ssel_iter_create(hid_t spaceid, size_t elmt_size, unsigned flags) {
+//   return H5Ssel_iter_create(spaceid, elmt_size, flags);
+// }
+// static herr_t _go_hdf5_H5Ssel_iter_get_seq_list(hid_t sel_iter_id, size_t maxseq, size_t maxbytes, size_t *nseq, size_t *nbytes, hsize_t *off, size_t *len) {
+//   return H5Ssel_iter_get_seq_list(sel_iter_id, maxseq, maxbytes, nseq, nbytes, off, len);
+// }
+// static herr_t _go_hdf5_H5Ssel_iter_close(hid_t sel_iter_id) { return H5Ssel_iter_close(sel_iter_id); }
+// #else
+// static hid_t _go_hdf5_H5Ssel_iter_create(hid_t spaceid, size_t elmt_size, unsigned flags) { return -1; }
+// static herr_t _go_hdf5_H5Ssel_iter_get_seq_list(hid_t sel_iter_id, size_t maxseq, size_t maxbytes, size_t *nseq, size_t *nbytes, hsize_t *off, size_t *len) { return -1; }
+// static herr_t _go_hdf5_H5Ssel_iter_close(hid_t sel_iter_id) { return -1; }
+// #endif
+import "C"
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Selection iterator flags.
+const (
+	S_SEL_ITER_GET_SEQ_LIST_SORTED  uint = 0x0001 // return runs in increasing offset order
+	S_SEL_ITER_SHARE_WITH_DATASPACE uint = 0x0002 // do not copy the dataspace, which must not change
+)
+
+// SelectionRun is a run of contiguous bytes of a selection.
+type SelectionRun struct {
+	Offset uint64 // offset in bytes from the start of the extent
+	Length uint64 // length in bytes
+}
+
+// SelectionIter walks the selection of a dataspace as runs of contiguous
+// bytes.
+type SelectionIter struct {
+	id C.hid_t
+}
+
+// SelectionIter returns an iterator over the selection of the dataspace,
+// for elements of elemSize bytes, with flags a combination of the
+// S_SEL_ITER flags. It needs HDF5 1.12.0.
+// hid_t H5Ssel_iter_create(hid_t spaceid, size_t elmt_size, unsigned flags)
+func (s *Dataspace) SelectionIter(elemSize, flags uint) (*SelectionIter, error) {
+	if err := requireVersion("H5Ssel_iter_create", 1, 12, 0); err != nil {
+		return nil, err
+	}
+	hid := C._go_hdf5_H5Ssel_iter_create(s.id, C.size_t(elemSize), C.uint(flags))
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return nil, err
+	}
+	it := &SelectionIter{id: hid}
+	runtime.SetFinalizer(it, (*SelectionIter).finalizer)
+	return it, nil
+}
+
+func (it *SelectionIter) finalizer() {
+	if err := it.Close(); err != nil {
+		panic(fmt.Sprintf("error closing selection iterator: %s", err))
+	}
+}
+
+// Next returns the next runs of the selection, at most maxRuns of them
+// and at most maxBytes bytes in total, or no runs once the selection has
+// been walked.
+// herr_t H5Ssel_iter_get_seq_list(hid_t sel_iter_id, size_t maxseq, size_t maxbytes, size_t *nseq, size_t *nbytes, hsize_t *off, size_t *len)
+func (it *SelectionIter) Next(maxRuns int, maxBytes uint) ([]SelectionRun, error) {
+	if maxRuns <= 0 {
+		return nil, fmt.Errorf("hdf5: maxRuns must be positive")
+	}
+	off := make([]C.hsize_t, maxRuns)
+	length := make([]C.size_t, maxRuns)
+	var nseq, nbytes C.size_t
+	err := h5err(C._go_hdf5_H5Ssel_iter_get_seq_list(it.id, C.size_t(maxRuns), C.size_t(maxBytes), &nseq, &nbytes, &off[0], &length[0]))
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]SelectionRun, int(nseq))
+	for i := range runs {
+		runs[i] = SelectionRun{Offset: uint64(off[i]), Length: uint64(length[i])}
+	}
+	return runs, nil
+}
+
+// Close releases the iterator.
+// herr_t H5Ssel_iter_close(hid_t sel_iter_id)
+func (it *SelectionIter) Close() error {
+	var err error
+	if it.id > 0 {
+		err = h5err(C._go_hdf5_H5Ssel_iter_close(it.id))
+		it.id = 0
+	}
+	return err
+}
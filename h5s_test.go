@@ -1,6 +1,7 @@
 package hdf5
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -99,3 +100,120 @@ func arrayEq(a, b []uint) bool {
 	}
 	return true
 }
+
+func TestSelectHyperslab(t *testing.T) {
+	ds, err := CreateSimpleDataspace([]uint{4, 6}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer ds.Close()
+	if n := ds.SelectNPoints(); n != 24 {
+		t.Errorf("SelectNPoints returned %d for the whole extent", n)
+	}
+	if err := ds.SelectHyperslab(S_SELECT_SET, []uint{0, 0}, []uint{2, 3}, []uint{2, 2}, []uint{1, 2}); err != nil {
+		t.Fatalf("SelectHyperslab failed: %s", err)
+	}
+	if n := ds.SelectNPoints(); n != 8 {
+		t.Errorf("SelectNPoints returned %d, want 8", n)
+	}
+	if err := ds.SelectHyperslab(S_SELECT_OR, []uint{3, 0}, nil, []uint{1, 6}, nil); err != nil {
+		t.Fatalf("SelectHyperslab failed: %s", err)
+	}
+	if n := ds.SelectNPoints(); n != 14 {
+		t.Errorf("SelectNPoints returned %d, want 14", n)
+	}
+	if err := ds.SelectHyperslab(S_SELECT_SET, []uint{0}, nil, []uint{1}, nil); err == nil {
+		t.Errorf("expected an error for a hyperslab of the wrong rank")
+	}
+	if err := ds.SelectNone(); err != nil || ds.SelectNPoints() != 0 {
+		t.Errorf("SelectNone left %d points, %v", ds.SelectNPoints(), err)
+	}
+	if err := ds.SelectAll(); err != nil || ds.SelectNPoints() != 24 {
+		t.Errorf("SelectAll selected %d points, %v", ds.SelectNPoints(), err)
+	}
+
+	scalar, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatalf("CreateDataspace failed: %s", err)
+	}
+	defer scalar.Close()
+	if err := scalar.SelectHyperslab(S_SELECT_SET, []uint{}, nil, []uint{}, nil); err != nil || scalar.SelectNPoints() != 1 {
+		t.Errorf("SelectHyperslab of a scalar selected %d points, %v", scalar.SelectNPoints(), err)
+	}
+	if err := scalar.SelectHyperslab(S_SELECT_OR, []uint{}, nil, []uint{}, nil); err == nil {
+		t.Errorf("expected an error combining the hyperslab of a scalar")
+	}
+}
+
+func TestSelectionIter(t *testing.T) {
+	ds, err := CreateSimpleDataspace([]uint{4, 6}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer ds.Close()
+	if err := requireVersion("", 1, 12, 0); err != nil {
+		if _, err := ds.SelectionIter(4, 0); err == nil {
+			t.Errorf("expected an error from libraries before 1.12.0")
+		}
+		t.Skip(err)
+	}
+
+	// Columns 1 and 2 of rows 1 and 3, of 4-byte elements.
+	if err := ds.SelectHyperslab(S_SELECT_SET, []uint{1, 1}, []uint{2, 1}, []uint{2, 1}, []uint{1, 2}); err != nil {
+		t.Fatalf("SelectHyperslab failed: %s", err)
+	}
+	it, err := ds.SelectionIter(4, S_SEL_ITER_GET_SEQ_LIST_SORTED)
+	if err != nil {
+		t.Fatalf("SelectionIter failed: %s", err)
+	}
+	defer it.Close()
+	var runs []SelectionRun
+	for {
+		next, err := it.Next(1, 1<<20)
+		if err != nil {
+			t.Fatalf("Next failed: %s", err)
+		}
+		if len(next) == 0 {
+			break
+		}
+		runs = append(runs, next...)
+	}
+	want := []SelectionRun{{28, 8}, {76, 8}}
+	if !reflect.DeepEqual(runs, want) {
+		t.Errorf("runs are %v, want %v", runs, want)
+	}
+}
+
+func TestShape(t *testing.T) {
+	scalar, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer scalar.Close()
+	shape, err := scalar.Shape()
+	if err != nil || shape.Rank() != 0 || shape.Size() != 1 {
+		t.Errorf("Shape of a scalar returned %v, %v", shape, err)
+	}
+
+	ds, err := CreateSimpleDataspace([]uint{2, 3, 4}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ds.Close()
+	shape, err = ds.Shape()
+	if err != nil {
+		t.Fatalf("Shape failed: %s", err)
+	}
+	if shape.Rank() != 3 || shape.Size() != 24 || shape.String() != "(2, 3, 4)" {
+		t.Errorf("Shape returned %v", shape)
+	}
+	if s := shape.Strides(); !reflect.DeepEqual(s, []uint{12, 4, 1}) {
+		t.Errorf("Strides returned %v", s)
+	}
+	if off, err := shape.Offset([]uint{1, 2, 3}); err != nil || off != 23 {
+		t.Errorf("Offset returned %d, %v", off, err)
+	}
+	if _, err := shape.Offset([]uint{2, 0, 0}); err == nil {
+		t.Errorf("expected an error for an index out of range")
+	}
+}
@@ -0,0 +1,144 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// ReadScalar reads the value of a dataset with a single element, such as
+// one with a scalar dataspace, into v, which must be a pointer to a
+// number or a string. The value is converted from the datatype of the
+// file.
+func (s *Dataset) ReadScalar(v interface{}) error {
+	return readScalar(C.H5Dget_type(s.id), C.H5Dget_space(s.id), v, func(mtype C.hid_t, buf unsafe.Pointer) error {
+		return h5err(C.H5Dread(s.id, mtype, C.H5S_ALL, C.H5S_ALL, C.H5P_DEFAULT, buf))
+	})
+}
+
+// WriteScalar writes v, a number or a string, as the value of a dataset
+// with a single element, such as one with a scalar dataspace. The value
+// is converted to the datatype of the file.
+func (s *Dataset) WriteScalar(v interface{}) error {
+	return writeScalar(C.H5Dget_type(s.id), C.H5Dget_space(s.id), v, func(mtype C.hid_t, buf unsafe.Pointer) error {
+		return h5err(C.H5Dwrite(s.id, mtype, C.H5S_ALL, C.H5S_ALL, C.H5P_DEFAULT, buf))
+	})
+}
+
+// ReadScalar reads the value of an attribute with a single element, such
+// as one with a scalar dataspace, into v, which must be a pointer to a
+// number or a string. The value is converted from the datatype of the
+// file.
+func (a *Attribute) ReadScalar(v interface{}) error {
+	return readScalar(C.H5Aget_type(a.id), C.H5Aget_space(a.id), v, func(mtype C.hid_t, buf unsafe.Pointer) error {
+		return h5err(C.H5Aread(a.id, mtype, buf))
+	})
+}
+
+// WriteScalar writes v, a number or a string, as the value of an
+// attribute with a single element, such as one with a scalar dataspace.
+// The value is converted to the datatype of the file.
+func (a *Attribute) WriteScalar(v interface{}) error {
+	return writeScalar(C.H5Aget_type(a.id), C.H5Aget_space(a.id), v, func(mtype C.hid_t, buf unsafe.Pointer) error {
+		return h5err(C.H5Awrite(a.id, mtype, buf))
+	})
+}
+
+// checkScalar closes the dataspace space of a dataset or attribute after
+// checking that it has one element, and closes its datatype ftype if not.
+func checkScalar(ftype, space C.hid_t) error {
+	if err := h5err(C.herr_t(int(ftype))); err != nil {
+		C.H5Sclose(space)
+		return err
+	}
+	_, npoints, err := jsonShape(space)
+	if err == nil && npoints != 1 {
+		err = fmt.Errorf("hdf5: cannot access %d elements as a scalar", npoints)
+	}
+	if err != nil {
+		C.H5Tclose(ftype)
+	}
+	return err
+}
+
+func readScalar(ftype, space C.hid_t, v interface{}, read func(C.hid_t, unsafe.Pointer) error) error {
+	if err := checkScalar(ftype, space); err != nil {
+		return err
+	}
+	defer C.H5Tclose(ftype)
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("hdf5: cannot read a scalar into a %T, need a non-nil pointer", v)
+	}
+	values, err := readValues(ftype, 1, read)
+	if err != nil {
+		return err
+	}
+
+	elem := rv.Elem()
+	switch x := values[0].(type) {
+	case int64:
+		return setScalar(elem, x, float64(x), x >= 0, uint64(x))
+	case uint64:
+		return setScalar(elem, int64(x), float64(x), true, x)
+	case float64:
+		return setScalar(elem, int64(x), x, x >= 0, uint64(x))
+	case string:
+		if elem.Kind() != reflect.String {
+			return fmt.Errorf("hdf5: cannot read a string into a %s", elem.Type())
+		}
+		elem.SetString(x)
+		return nil
+	}
+	return fmt.Errorf("hdf5: cannot read a %T into a %s", values[0], elem.Type())
+}
+
+// setScalar stores a number, given in each of its representations, in
+// elem, checking that it fits.
+func setScalar(elem reflect.Value, i int64, f float64, nonNeg bool, u uint64) error {
+	switch elem.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if elem.OverflowInt(i) {
+			return fmt.Errorf("hdf5: %d overflows a %s", i, elem.Type())
+		}
+		elem.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if !nonNeg || elem.OverflowUint(u) {
+			return fmt.Errorf("hdf5: %v overflows a %s", f, elem.Type())
+		}
+		elem.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		elem.SetFloat(f)
+	default:
+		return fmt.Errorf("hdf5: cannot read a number into a %s", elem.Type())
+	}
+	return nil
+}
+
+func writeScalar(ftype, space C.hid_t, v interface{}, write func(C.hid_t, unsafe.Pointer) error) error {
+	if err := checkScalar(ftype, space); err != nil {
+		return err
+	}
+	defer C.H5Tclose(ftype)
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	var value interface{}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value = rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		value = rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		value = rv.Float()
+	case reflect.String:
+		value = rv.String()
+	default:
+		return fmt.Errorf("hdf5: cannot write a %T as a scalar", v)
+	}
+	return writeValues(ftype, []interface{}{value}, write)
+}
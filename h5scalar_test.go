@@ -0,0 +1,90 @@
+package hdf5
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestScalar(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateDataspace(S_SCALAR)
+	if err != nil {
+		t.Fatalf("CreateDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+
+	dset, err := f.CreateDataset("answer", T_STD_I16BE, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	if err := dset.WriteScalar(42); err != nil {
+		t.Fatalf("WriteScalar failed: %s", err)
+	}
+	var i int64
+	if err := dset.ReadScalar(&i); err != nil || i != 42 {
+		t.Errorf("ReadScalar returned %d, %v", i, err)
+	}
+	var x float32
+	if err := dset.ReadScalar(&x); err != nil || x != 42 {
+		t.Errorf("ReadScalar returned %v, %v", x, err)
+	}
+	if err := dset.WriteScalar(-1); err != nil {
+		t.Fatalf("WriteScalar failed: %s", err)
+	}
+	var u uint8
+	if err := dset.ReadScalar(&u); err == nil {
+		t.Errorf("expected an error reading -1 into a uint8")
+	}
+	if err := dset.ReadScalar(i); err == nil {
+		t.Errorf("expected an error reading into a non-pointer")
+	}
+
+	for n, dtype := range []*Datatype{T_GO_STRING, T_C_S1} {
+		if dtype == T_C_S1 {
+			var err error
+			if dtype, err = T_C_S1.Copy(); err != nil {
+				t.Fatalf("Copy failed: %s", err)
+			}
+			defer dtype.Close()
+			if err := dtype.SetSize(16); err != nil {
+				t.Fatalf("SetSize failed: %s", err)
+			}
+		}
+		a, err := dset.CreateAttribute(fmt.Sprintf("units%d", n), dtype, dspace)
+		if err != nil {
+			t.Fatalf("CreateAttribute failed: %s", err)
+		}
+		if err := a.WriteScalar("metres"); err != nil {
+			t.Fatalf("WriteScalar failed: %s", err)
+		}
+		var units string
+		if err := a.ReadScalar(&units); err != nil || units != "metres" {
+			t.Errorf("ReadScalar returned %q, %v", units, err)
+		}
+		if err := a.ReadScalar(&i); err == nil {
+			t.Errorf("expected an error reading a string into an int64")
+		}
+		a.Close()
+	}
+
+	vspace, err := CreateSimpleDataspace([]uint{2}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer vspace.Close()
+	vec, err := f.CreateDataset("vec", T_NATIVE_DOUBLE, vspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer vec.Close()
+	if err := vec.WriteScalar(1.5); err == nil {
+		t.Errorf("expected an error writing a scalar to 2 elements")
+	}
+}
@@ -0,0 +1,34 @@
+package hdf5
+
+import (
+	"sync"
+)
+
+// maxScratch is the size in bytes of the largest scratch buffer kept for
+// reuse, so that a single huge read does not pin its buffer.
+const maxScratch = 4 << 20
+
+// scratchBufs holds the scratch buffers that reads and writes convert
+// elements in, such as the padded elements of fixed-length strings, as
+// *[]byte.
+var scratchBufs sync.Pool
+
+// getScratch returns a zeroed buffer of n bytes, reused if possible, which
+// is given back with putScratch once the library is done with it.
+func getScratch(n int) []byte {
+	if p, ok := scratchBufs.Get().(*[]byte); ok && cap(*p) >= n {
+		b := (*p)[:n]
+		clear(b)
+		return b
+	}
+	return make([]byte, n)
+}
+
+// putScratch makes the buffer b, from getScratch, available for reuse. b
+// must not be used afterwards.
+func putScratch(b []byte) {
+	if cap(b) == 0 || cap(b) > maxScratch {
+		return
+	}
+	scratchBufs.Put(&b)
+}
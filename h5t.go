@@ -9,12 +9,15 @@ import (
 	"fmt"
 	"reflect"
 	"runtime"
+	"strings"
+	"sync"
 	"unsafe"
 )
 
 type Datatype struct {
-	id C.hid_t
-	rt reflect.Type
+	id     C.hid_t
+	rt     reflect.Type
+	locked bool
 }
 
 type TypeClass C.H5T_class_t
@@ -35,6 +38,24 @@ const (
 	T_NCLASSES  TypeClass = 11 // nbr of classes -- MUST BE LAST
 )
 
+var typeClassNames = [...]string{"integer", "float", "time", "string", "bitfield", "opaque", "compound", "reference", "enum", "vlen", "array"}
+
+func (c TypeClass) String() string {
+	if c >= 0 && int(c) < len(typeClassNames) {
+		return typeClassNames[c]
+	}
+	return "unknown"
+}
+
+// CharSet is the character set of strings and of link and attribute names.
+type CharSet C.H5T_cset_t
+
+const (
+	T_CSET_ERROR CharSet = -1 // error
+	T_CSET_ASCII CharSet = 0  // US ASCII
+	T_CSET_UTF8  CharSet = 1  // UTF-8 Unicode encoding
+)
+
 // list of go types
 var (
 	_go_string_t reflect.Type = reflect.TypeOf(string(""))
@@ -91,12 +112,14 @@ func openDatatype(loc_id C.hid_t, name string, tapl_id int) (*Datatype, error) {
 	}
 	dt := &Datatype{id: hid}
 	runtime.SetFinalizer(dt, (*Datatype).finalizer)
+	trackID(hid)
 	return dt, err
 }
 
 func NewDatatype(id C.hid_t, rt reflect.Type) *Datatype {
 	t := &Datatype{id: id, rt: rt}
 	//runtime.SetFinalizer(t, (*Datatype).finalizer)
+	trackID(id)
 	return t
 }
 
@@ -115,16 +138,22 @@ func CreateDatatype(class TypeClass, size int) (t *Datatype, err error) {
 }
 
 func (t *Datatype) finalizer() {
+	if !t.locked && t.id > 0 && C.H5Iis_valid(t.id) <= 0 {
+		return // closed by File.CloseWith
+	}
 	err := t.Close()
 	if err != nil {
 		panic(fmt.Sprintf("error closing datatype: %s", err))
 	}
 }
 
-// Releases a datatype.
+// Releases a datatype. Locked datatypes are released by the library when
+// it shuts down, and Close leaves them open.
 func (t *Datatype) Close() error {
+	if t.locked {
+		return nil
+	}
 	if t.id > 0 {
-		fmt.Printf("--- closing dtype [%d]...\n", t.id)
 		err := h5err(C.H5Tclose(t.id))
 		t.id = 0
 		return err
@@ -133,6 +162,7 @@ func (t *Datatype) Close() error {
 }
 
 // Determines whether a datatype is a named type or a transient type.
+// htri_t H5Tcommitted(hid_t dtype_id)
 func (t *Datatype) Committed() bool {
 	o := int(C.H5Tcommitted(t.id))
 	if o > 0 {
@@ -141,7 +171,72 @@ func (t *Datatype) Committed() bool {
 	return false
 }
 
-// Copies an existing datatype.
+// commitDatatype links the transient datatype dtype into the file of loc
+// as name, making it a named datatype.
+func commitDatatype(loc C.hid_t, name string, dtype *Datatype) error {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+	return h5err(C.H5Tcommit2(loc, c_name, dtype.id, C.H5P_DEFAULT, C.H5P_DEFAULT, C.H5P_DEFAULT))
+}
+
+// Creates an attribute attached to the named datatype.
+// hid_t H5Acreate2(hid_t loc_id, const char *attr_name, hid_t type_id, hid_t space_id, hid_t acpl_id, hid_t aapl_id)
+func (t *Datatype) CreateAttribute(name string, dtype *Datatype, dspace *Dataspace) (*Attribute, error) {
+	return createAttribute(t.id, name, dtype, dspace, P_DEFAULT)
+}
+
+// CreateAttributeWith creates an attribute attached to the named datatype
+// with the attribute creation properties acpl, e.g. for a UTF-8 name.
+func (t *Datatype) CreateAttributeWith(name string, dtype *Datatype, dspace *Dataspace, acpl *PropList) (*Attribute, error) {
+	return createAttribute(t.id, name, dtype, dspace, acpl)
+}
+
+// AttributeNames returns the names of the attributes of the named
+// datatype, in increasing order of index.
+func (t *Datatype) AttributeNames(index IndexType) ([]string, error) {
+	return attributeNamesByOrder(t.id, index)
+}
+
+// Opens an attribute attached to the named datatype.
+// hid_t H5Aopen(hid_t obj_id, const char *attr_name, hid_t aapl_id)
+func (t *Datatype) OpenAttribute(name string) (*Attribute, error) {
+	return openAttribute(t.id, name)
+}
+
+// AttributeExists reports whether the named datatype has an attribute
+// called name.
+// htri_t H5Aexists(hid_t obj_id, const char *attr_name)
+func (t *Datatype) AttributeExists(name string) (bool, error) {
+	return attributeExists(t.id, name)
+}
+
+// RenameAttribute renames the attribute oldName of the named datatype to
+// newName.
+// herr_t H5Arename(hid_t loc_id, const char *old_attr_name, const char *new_attr_name)
+func (t *Datatype) RenameAttribute(oldName, newName string) error {
+	return renameAttribute(t.id, oldName, newName)
+}
+
+// DeleteAttribute removes the attribute name from the named datatype.
+// herr_t H5Adelete(hid_t loc_id, const char *attr_name)
+func (t *Datatype) DeleteAttribute(name string) error {
+	return deleteAttribute(t.id, name)
+}
+
+// Returns the number of attributes attached to the named datatype.
+func (t *Datatype) NumAttributes() (uint, error) {
+	return numAttributes(t.id)
+}
+
+// Returns the name of the attribute at idx, in name order.
+// ssize_t H5Aget_name_by_idx(hid_t loc_id, const char *obj_name, H5_index_t idx_type, H5_iter_order_t order, hsize_t n, char *name, size_t size, hid_t lapl_id)
+func (t *Datatype) AttributeNameByIndex(idx uint) (string, error) {
+	return attributeNameByIndex(t.id, idx)
+}
+
+// Copies an existing datatype. The copy is transient and modifiable,
+// even if t is committed or locked, and must be closed.
+// hid_t H5Tcopy(hid_t dtype_id)
 func (t *Datatype) Copy() (*Datatype, error) {
 	hid := C.H5Tcopy(t.id)
 	err := h5err(C.herr_t(int(hid)))
@@ -152,8 +247,13 @@ func (t *Datatype) Copy() (*Datatype, error) {
 	return o, err
 }
 
-// Determines whether two datatype identifiers refer to the same datatype.
+// Determines whether two datatype identifiers refer to the same datatype,
+// e.g. whether the datatype of a dataset is the one a schema expects.
+// htri_t H5Tequal(hid_t dtype_id1, hid_t dtype_id2)
 func (t *Datatype) Equal(o *Datatype) bool {
+	if o == nil {
+		return false
+	}
 	v := int(C.H5Tequal(t.id, o.id))
 	if v > 0 {
 		return true
@@ -161,9 +261,16 @@ func (t *Datatype) Equal(o *Datatype) bool {
 	return false
 }
 
-// Locks a datatype.
+// Locks a datatype, so that it cannot be modified or closed, e.g. a
+// datatype shared by several goroutines. It stays open until the library
+// shuts down.
+// herr_t H5Tlock(hid_t dtype_id)
 func (t *Datatype) Lock() error {
-	return h5err(C.H5Tlock(t.id))
+	if err := h5err(C.H5Tlock(t.id)); err != nil {
+		return err
+	}
+	t.locked = true
+	return nil
 }
 
 // Size returns the size of the Datatype.
@@ -171,6 +278,12 @@ func (t *Datatype) Size() uint {
 	return uint(C.H5Tget_size(t.id))
 }
 
+// Class returns the class of the Datatype.
+// H5T_class_t H5Tget_class(hid_t dtype_id)
+func (t *Datatype) Class() TypeClass {
+	return TypeClass(C.H5Tget_class(t.id))
+}
+
 // SetSize sets the total size of a Datatype.
 func (t *Datatype) SetSize(sz uint) error {
 	err := C.H5Tset_size(t.id, C.size_t(sz))
@@ -286,7 +399,13 @@ func (t *CompoundType) MemberType(mbr_idx int) (*Datatype, error) {
 	if err != nil {
 		return nil, err
 	}
-	dt := NewDatatype(hid, t.rt.Field(mbr_idx).Type)
+	rt := _type_cls_to_go_type[TypeClass(C.H5Tget_class(hid))]
+	if t.rt != nil && t.rt.Kind() == reflect.Struct {
+		if fields := structFields(t.rt); mbr_idx < len(fields) {
+			rt = fields[mbr_idx].typ
+		}
+	}
+	dt := NewDatatype(hid, rt)
 	return dt, nil
 }
 
@@ -323,15 +442,95 @@ func (t *OpaqueDatatype) Tag() string {
 }
 
 // NewDatatypeFromValue creates  a datatype from a value in an interface.
+// Structs map to compound datatypes, with struct-typed fields as nested
+// compound members unless flattened by their tag, as described by
+// fieldTag.
 func NewDatatypeFromValue(v interface{}) *Datatype {
 	t := reflect.TypeOf(v)
 	return newDataTypeFromType(t)
 }
 
+// memTypes caches the memory datatypes inferred by inferType.
+var memTypes sync.Map // reflect.Type -> *Datatype
+
+// inferType returns the memory datatype of the elements of data, a slice,
+// array or pointer, for reads and writes given a nil datatype, and
+// whether the caller must close it. Strings are variable-length, or the
+// fixed-length strings of the file datatype returned by ftype, if not nil.
+func inferType(data interface{}, ftype func() (*Datatype, error)) (dt *Datatype, owned bool, err error) {
+	t := reflect.TypeOf(data)
+	if t == nil {
+		return nil, false, fmt.Errorf("hdf5: cannot infer the datatype of nil")
+	}
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.String {
+		if ftype == nil {
+			return T_GO_STRING, false, nil
+		}
+		dt, err := ftype()
+		if err != nil {
+			return nil, false, err
+		}
+		if dt.Class() != T_STRING {
+			dt.Close()
+			return nil, false, fmt.Errorf("hdf5: cannot infer the datatype of %T, pass one", data)
+		}
+		if C.H5Tis_variable_str(dt.id) > 0 {
+			dt.Close()
+			return T_GO_STRING, false, nil
+		}
+		return dt, true, nil
+	}
+	if bt := bitfieldMemType(t); bt != nil && ftype != nil {
+		dt, err := ftype()
+		if err != nil {
+			return nil, false, err
+		}
+		class := dt.Class()
+		dt.Close()
+		if class == T_BITFIELD {
+			return bt, false, nil
+		}
+	}
+	if dt, ok := memTypes.Load(t); ok {
+		return dt.(*Datatype), false, nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			dt, err = nil, fmt.Errorf("hdf5: cannot infer the datatype of %T: %v", data, r)
+		}
+	}()
+	dt = newDataTypeFromType(t)
+	if prev, loaded := memTypes.LoadOrStore(t, dt); loaded {
+		// another goroutine stored the type first; predefined types are
+		// shared, and the same for both
+		if prev.(*Datatype) != dt {
+			dt.Close()
+		}
+		dt = prev.(*Datatype)
+	}
+	return dt, false, nil
+}
+
 func newDataTypeFromType(t reflect.Type) *Datatype {
 
 	var dt *Datatype = nil
 
+	switch t {
+	case float16Type:
+		return T_NATIVE_FLOAT16
+	case int128Type:
+		return T_NATIVE_INT128
+	case uint128Type:
+		return T_NATIVE_UINT128
+	}
+
 	switch t.Kind() {
 
 	case reflect.Int:
@@ -375,7 +574,15 @@ func newDataTypeFromType(t reflect.Type) *Datatype {
 		//dt = T_C_S1
 
 	case reflect.Array:
-		elem_type := newDataTypeFromType(t.Elem())
+		// Nested arrays are the dimensions of a single array datatype.
+		elem := t
+		for elem.Kind() == reflect.Array {
+			if elem.Len() == 0 {
+				panic(fmt.Sprintf("cannot map a zero-length %s to an array datatype", t))
+			}
+			elem = elem.Elem()
+		}
+		elem_type := newDataTypeFromType(elem)
 		dims := getArrayDims(t)
 		adt, err := NewArrayType(elem_type, dims)
 		if err != nil {
@@ -403,23 +610,26 @@ func newDataTypeFromType(t reflect.Type) *Datatype {
 		if err != nil {
 			panic(err)
 		}
+		// Keep the Go type, for the types of nested members.
+		hdf_dt.rt = t
 		cdt := &CompoundType{*hdf_dt}
-		n := t.NumField()
-		for i := 0; i < n; i++ {
-			f := t.Field(i)
+		for i, f := range structFields(t) {
 			var field_dt *Datatype = nil
-			field_dt = newDataTypeFromType(f.Type)
-			offset := int(f.Offset + 0)
-			if field_dt == nil {
-				panic(fmt.Sprintf("pb with field [%d-%s]", i, f.Name))
+			if f.bitfield {
+				field_dt = bitfieldMemType(f.typ)
+				if field_dt == nil {
+					panic(fmt.Sprintf("pb with field [%d-%s]: bitfield of %v", i, f.name, f.typ))
+				}
+			} else {
+				field_dt = newDataTypeFromType(f.typ)
 			}
-			field_name := string(f.Tag)
-			if len(field_name) == 0 {
-				field_name = f.Name
+			offset := int(f.offset + 0)
+			if field_dt == nil {
+				panic(fmt.Sprintf("pb with field [%d-%s]", i, f.name))
 			}
-			err = cdt.Insert(field_name, offset, field_dt)
+			err = cdt.Insert(f.name, offset, field_dt)
 			if err != nil {
-				panic(fmt.Sprintf("pb with field [%d-%s]: %s", i, f.Name, err))
+				panic(fmt.Sprintf("pb with field [%d-%s]: %s", i, f.name, err))
 			}
 		}
 		cdt.Lock()
@@ -438,6 +648,75 @@ func newDataTypeFromType(t reflect.Type) *Datatype {
 	return dt
 }
 
+// fieldTag parses the tag of a struct field. The name of the compound
+// member mapped to the field is the name in its hdf5 tag, e.g.
+// `hdf5:"energy"`, else its whole tag if it has no hdf5 key, else its
+// name. The options following the name in an hdf5 tag are "flatten",
+// which maps the fields of a struct-typed field to members of the parent
+// compound, "prefix=p", which does the same with member names prefixed by
+// p, and "bitfield", which maps an unsigned integer field to a bitfield
+// member.
+func fieldTag(f reflect.StructField) (name string, flatten bool, prefix string, bitfield bool) {
+	tag, ok := f.Tag.Lookup("hdf5")
+	if !ok {
+		if name := string(f.Tag); len(name) > 0 {
+			return name, false, "", false
+		}
+		return f.Name, false, "", false
+	}
+	opts := strings.Split(tag, ",")
+	name = opts[0]
+	for _, opt := range opts[1:] {
+		switch {
+		case opt == "flatten":
+			flatten = true
+		case opt == "bitfield":
+			bitfield = true
+		case strings.HasPrefix(opt, "prefix="):
+			flatten, prefix = true, strings.TrimPrefix(opt, "prefix=")
+		}
+	}
+	if flatten && f.Type.Kind() != reflect.Struct {
+		flatten, prefix = false, ""
+	}
+	if name == "" {
+		name = f.Name
+	}
+	return name, flatten, prefix, bitfield
+}
+
+// structField is a field of a struct type mapped to a compound member,
+// possibly of a flattened struct-typed field.
+type structField struct {
+	name     string
+	index    []int
+	offset   uintptr
+	typ      reflect.Type
+	bitfield bool
+}
+
+// structFields returns the fields of the struct type t mapped to compound
+// members, in order, with the fields of flattened struct-typed fields in
+// place of those.
+func structFields(t reflect.Type) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, flatten, prefix, bitfield := fieldTag(f)
+		if !flatten {
+			fields = append(fields, structField{name, []int{i}, f.Offset, f.Type, bitfield})
+			continue
+		}
+		for _, sub := range structFields(f.Type) {
+			sub.name = prefix + sub.name
+			sub.index = append([]int{i}, sub.index...)
+			sub.offset += f.Offset
+			fields = append(fields, sub)
+		}
+	}
+	return fields
+}
+
 func getArrayDims(dt reflect.Type) []int {
 	result := []int{}
 	if dt.Kind() == reflect.Array {
@@ -0,0 +1,90 @@
+package hdf5
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// --- Bitfield datatypes ---
+
+// Bitfield datatypes, such as T_STD_B16LE, hold words of independent bits
+// like the status words of instruments. Their values are read and written
+// as Go unsigned integers of their size with the memory datatypes
+// T_NATIVE_B8 to T_NATIVE_B64, which a nil datatype selects for datasets
+// and attributes of a bitfield datatype. The library does not convert
+// between bitfields and integers. Struct fields of unsigned integer types
+// map to bitfield members with the "bitfield" option of their hdf5 tag,
+// e.g. `hdf5:"status,bitfield"`.
+
+// NewBitfieldType creates a bitfield datatype of size bytes, which is 1,
+// 2, 4 or 8, in the native byte order. Its significant bits can be
+// narrowed with SetPrecision and SetOffset.
+func NewBitfieldType(size uint) (*Datatype, error) {
+	native := nativeBitfield(size)
+	if native == nil {
+		return nil, fmt.Errorf("hdf5: no bitfield datatype of %d bytes", size)
+	}
+	return native.Copy()
+}
+
+// nativeBitfield returns the native bitfield datatype of size bytes, or
+// nil if there is none.
+func nativeBitfield(size uint) *Datatype {
+	switch size {
+	case 1:
+		return T_NATIVE_B8
+	case 2:
+		return T_NATIVE_B16
+	case 4:
+		return T_NATIVE_B32
+	case 8:
+		return T_NATIVE_B64
+	}
+	return nil
+}
+
+// bitfieldMemType returns the native bitfield datatype of the unsigned
+// integer type t, or nil if t is not one.
+func bitfieldMemType(t reflect.Type) *Datatype {
+	switch t.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return nativeBitfield(uint(t.Size()))
+	}
+	return nil
+}
+
+// BitMask is a range of Width bits starting at bit Offset of a bitfield
+// value, such as a flag or a counter packed into a status word.
+type BitMask struct {
+	Offset uint
+	Width  uint
+}
+
+// Bit returns the mask of the single bit n.
+func Bit(n uint) BitMask {
+	return BitMask{Offset: n, Width: 1}
+}
+
+// Mask returns the bits of the mask set in a word.
+func (m BitMask) Mask() uint64 {
+	if m.Width >= 64 {
+		return ^uint64(0) << m.Offset
+	}
+	return (1<<m.Width - 1) << m.Offset
+}
+
+// Get returns the bits of v selected by the mask, shifted down to bit 0.
+func (m BitMask) Get(v uint64) uint64 {
+	return (v & m.Mask()) >> m.Offset
+}
+
+// IsSet reports whether any of the bits of v selected by the mask is set.
+func (m BitMask) IsSet(v uint64) bool {
+	return v&m.Mask() != 0
+}
+
+// Set returns v with the bits selected by the mask replaced by the low
+// bits of x.
+func (m BitMask) Set(v, x uint64) uint64 {
+	return v&^m.Mask() | x<<m.Offset&m.Mask()
+}
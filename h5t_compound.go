@@ -0,0 +1,143 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// CompoundBuilder builds a compound datatype member by member, for types
+// known only at run time, e.g. from a schema, rather than from a Go
+// struct. Members are placed one after the other, either packed or at the
+// alignment a C compiler would give them, unless given an offset.
+//
+//	b := NewCompoundBuilder(false)
+//	b.AddMember("id", T_NATIVE_INT32)
+//	b.AddMember("value", T_NATIVE_DOUBLE)
+//	dtype, err := b.Build()
+type CompoundBuilder struct {
+	packed  bool
+	members []compoundMember
+	size    uint // end of the last member
+	align   uint // largest alignment of a member
+	err     error
+}
+
+type compoundMember struct {
+	name   string
+	dtype  *Datatype
+	offset uint
+}
+
+// NewCompoundBuilder returns a builder of a compound datatype whose members
+// are packed without padding if packed is set, and otherwise aligned as in
+// a C struct, which native memory datatypes need.
+func NewCompoundBuilder(packed bool) *CompoundBuilder {
+	return &CompoundBuilder{packed: packed, align: 1}
+}
+
+// AddMember adds the member name of datatype dtype after the members
+// added before, or at offset, the only value of which may be given to
+// place it explicitly. dtype is copied by Build, and must remain open
+// until then. The first error, e.g. of an offset that overlaps other
+// members, is returned by Build.
+func (b *CompoundBuilder) AddMember(name string, dtype *Datatype, offset ...uint) *CompoundBuilder {
+	if b.err != nil {
+		return b
+	}
+	if len(offset) > 1 {
+		b.err = fmt.Errorf("hdf5: member %q given %d offsets", name, len(offset))
+		return b
+	}
+	size := dtype.Size()
+	align := uint(1)
+	if !b.packed {
+		align = alignOf(dtype.id)
+	}
+	var off uint
+	if len(offset) == 1 {
+		off = offset[0]
+		for _, m := range b.members {
+			if off < m.offset+m.dtype.Size() && m.offset < off+size {
+				b.err = fmt.Errorf("hdf5: member %q at offset %d overlaps member %q", name, off, m.name)
+				return b
+			}
+		}
+	} else {
+		off = alignUp(b.size, align)
+	}
+	b.members = append(b.members, compoundMember{name: name, dtype: dtype, offset: off})
+	b.size = max(b.size, off+size)
+	b.align = max(b.align, align)
+	return b
+}
+
+// Size returns the size the compound datatype will have, including the
+// padding of an aligned one to the alignment of its members.
+func (b *CompoundBuilder) Size() uint {
+	return alignUp(b.size, b.align)
+}
+
+// Build creates the compound datatype of the members added.
+// hid_t H5Tcreate(H5T_class_t class, size_t size)
+// herr_t H5Tinsert(hid_t dtype_id, const char * name, size_t offset, hid_t field_id)
+func (b *CompoundBuilder) Build() (*CompoundType, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.members) == 0 {
+		return nil, fmt.Errorf("hdf5: a compound datatype needs members")
+	}
+	dt, err := CreateDatatype(T_COMPOUND, int(b.Size()))
+	if err != nil {
+		return nil, err
+	}
+	t := &CompoundType{*dt}
+	for _, m := range b.members {
+		if err := t.Insert(m.name, int(m.offset), m.dtype); err != nil {
+			t.Close()
+			return nil, fmt.Errorf("hdf5: member %q: %w", m.name, err)
+		}
+	}
+	return t, nil
+}
+
+// alignOf returns the alignment a C compiler gives values of the memory
+// datatype t.
+func alignOf(t C.hid_t) uint {
+	switch C.H5Tget_class(t) {
+	case C.H5T_COMPOUND:
+		align := uint(1)
+		for i := C.uint(0); i < C.uint(C.H5Tget_nmembers(t)); i++ {
+			mt := C.H5Tget_member_type(t, i)
+			align = max(align, alignOf(mt))
+			C.H5Tclose(mt)
+		}
+		return align
+	case C.H5T_ARRAY, C.H5T_ENUM:
+		base := C.H5Tget_super(t)
+		defer C.H5Tclose(base)
+		return alignOf(base)
+	case C.H5T_VLEN:
+		return uint(unsafe.Alignof(uintptr(0)))
+	case C.H5T_STRING:
+		if C.H5Tis_variable_str(t) > 0 {
+			return uint(unsafe.Alignof(uintptr(0)))
+		}
+		return 1
+	case C.H5T_OPAQUE:
+		return 1
+	}
+	// integers, floats, bitfields and references are aligned to their size
+	size := uint(C.H5Tget_size(t))
+	if size == 0 || size&(size-1) != 0 {
+		return 1
+	}
+	return min(size, 16)
+}
+
+func alignUp(n, align uint) uint {
+	return (n + align - 1) / align * align
+}
@@ -0,0 +1,116 @@
+package hdf5
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Float16 is an IEEE 754 half-precision float, the memory type of
+// T_NATIVE_FLOAT16. Slices of Float16, or of uint16 with an explicit
+// T_NATIVE_FLOAT16, read and write 16-bit float datasets.
+type Float16 uint16
+
+var (
+	T_IEEE_F16BE     *Datatype = makeFloat16Datatype(T_IEEE_F32BE)
+	T_IEEE_F16LE     *Datatype = makeFloat16Datatype(T_IEEE_F32LE)
+	T_NATIVE_FLOAT16 *Datatype = makeFloat16Datatype(T_NATIVE_FLOAT)
+)
+
+// makeFloat16Datatype returns a 16-bit float datatype of the byte order
+// of the 32-bit float datatype f32, laid out like h5py's float16.
+func makeFloat16Datatype(f32 *Datatype) *Datatype {
+	dt, err := NewFloat16Type(f32)
+	if err != nil {
+		panic(err)
+	}
+	dt.rt = float16Type
+	return dt
+}
+
+// NewFloat16Type creates an IEEE 754 half-precision float datatype, with
+// the byte order of the float datatype like.
+func NewFloat16Type(like *Datatype) (*Datatype, error) {
+	dt, err := like.Copy()
+	if err != nil {
+		return nil, err
+	}
+	err = dt.SetFields(15, 10, 5, 0, 10)
+	if err == nil {
+		err = dt.SetSize(2)
+	}
+	if err == nil {
+		err = dt.SetEbias(15)
+	}
+	if err != nil {
+		dt.Close()
+		return nil, err
+	}
+	return dt, nil
+}
+
+var float16Type = reflect.TypeOf(Float16(0))
+
+// NewFloat16 returns the half-precision float nearest to f, rounding
+// halfway cases to even.
+func NewFloat16(f float32) Float16 {
+	b := math.Float32bits(f)
+	sign := uint16(b>>16) & 0x8000
+	exp := int(b>>23) & 0xff
+	mant := b & 0x7fffff
+	if exp == 0xff {
+		if mant != 0 {
+			return Float16(sign | 0x7e00) // NaN
+		}
+		return Float16(sign | 0x7c00) // infinity
+	}
+
+	e := exp - 127 + 15
+	if e >= 0x1f {
+		return Float16(sign | 0x7c00)
+	}
+	var half, rem, halfway uint32
+	if e <= 0 {
+		// A subnormal half, or zero.
+		if e < -10 {
+			return Float16(sign)
+		}
+		mant |= 0x800000
+		shift := uint(14 - e)
+		half, rem, halfway = mant>>shift, mant&(1<<shift-1), 1<<(shift-1)
+	} else {
+		half, rem, halfway = uint32(e)<<10|mant>>13, mant&0x1fff, 0x1000
+	}
+	// A carry out of the mantissa correctly increments the exponent.
+	if rem > halfway || (rem == halfway && half&1 == 1) {
+		half++
+	}
+	return Float16(sign | uint16(half))
+}
+
+// Float32 returns h as a float32, which represents it exactly.
+func (h Float16) Float32() float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h) & 0x3ff
+	switch {
+	case exp == 0x1f:
+		return math.Float32frombits(sign | 0x7f800000 | mant<<13)
+	case exp == 0 && mant == 0:
+		return math.Float32frombits(sign)
+	case exp == 0:
+		// Normalize a subnormal half.
+		e := uint32(127 - 15 + 1)
+		for mant&0x400 == 0 {
+			mant <<= 1
+			e--
+		}
+		return math.Float32frombits(sign | e<<23 | (mant&0x3ff)<<13)
+	}
+	return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+}
+
+// String formats h like a float32.
+func (h Float16) String() string {
+	return strconv.FormatFloat(float64(h.Float32()), 'g', -1, 32)
+}
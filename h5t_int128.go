@@ -0,0 +1,155 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// Int128 is a signed 128-bit integer, the memory type of T_NATIVE_INT128
+// on little-endian hosts. The library converts 128-bit integers to and
+// from other integer datatypes, also as compound members, so Int128
+// fields can be read as int64 where their values fit.
+type Int128 struct {
+	Lo uint64
+	Hi int64
+}
+
+// Uint128 is an unsigned 128-bit integer, the memory type of
+// T_NATIVE_UINT128 on little-endian hosts.
+type Uint128 struct {
+	Lo, Hi uint64
+}
+
+// Decimal is a decimal number stored as a compound of its unscaled value
+// and its scale, the number of digits after the decimal point: its value
+// is Unscaled * 10^-Scale.
+type Decimal struct {
+	Unscaled Int128 `hdf5:"unscaled"`
+	Scale    int32  `hdf5:"scale"`
+}
+
+var (
+	T_STD_I128BE *Datatype = make128BitDatatype(T_STD_I64BE)
+	T_STD_I128LE *Datatype = make128BitDatatype(T_STD_I64LE)
+	T_STD_U128BE *Datatype = make128BitDatatype(T_STD_U64BE)
+	T_STD_U128LE *Datatype = make128BitDatatype(T_STD_U64LE)
+
+	T_NATIVE_INT128  *Datatype = make128BitDatatype(T_NATIVE_INT64)
+	T_NATIVE_UINT128 *Datatype = make128BitDatatype(T_NATIVE_UINT64)
+)
+
+var (
+	int128Type  = reflect.TypeOf(Int128{})
+	uint128Type = reflect.TypeOf(Uint128{})
+)
+
+// make128BitDatatype returns a 128-bit integer datatype of the sign and
+// byte order of the 64-bit integer datatype i64.
+func make128BitDatatype(i64 *Datatype) *Datatype {
+	dt, err := i64.Copy()
+	if err != nil {
+		panic(err)
+	}
+	if err := dt.SetSize(16); err != nil {
+		panic(err)
+	}
+	if err := dt.SetPrecision(128); err != nil {
+		panic(err)
+	}
+	if C.H5Tget_sign(dt.id) == C.H5T_SGN_2 {
+		dt.rt = int128Type
+	} else {
+		dt.rt = uint128Type
+	}
+	return dt
+}
+
+var (
+	two64     = new(big.Int).Lsh(big.NewInt(1), 64)
+	maxInt128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+	minInt128 = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 127))
+)
+
+// NewInt128 returns x as an Int128, or an error if it does not fit.
+func NewInt128(x *big.Int) (Int128, error) {
+	if x.Cmp(minInt128) < 0 || x.Cmp(maxInt128) > 0 {
+		return Int128{}, fmt.Errorf("hdf5: %s overflows an Int128", x)
+	}
+	u := new(big.Int).Set(x)
+	if u.Sign() < 0 {
+		u.Add(u, new(big.Int).Lsh(two64, 64))
+	}
+	lo := new(big.Int).And(u, new(big.Int).Sub(two64, big.NewInt(1)))
+	hi := new(big.Int).Rsh(u, 64)
+	return Int128{Lo: lo.Uint64(), Hi: int64(hi.Uint64())}, nil
+}
+
+// Big returns x as a big.Int.
+func (x Int128) Big() *big.Int {
+	b := new(big.Int).Lsh(big.NewInt(x.Hi), 64)
+	return b.Add(b, new(big.Int).SetUint64(x.Lo))
+}
+
+func (x Int128) String() string {
+	return x.Big().String()
+}
+
+// NewUint128 returns x as a Uint128, or an error if it does not fit.
+func NewUint128(x *big.Int) (Uint128, error) {
+	if x.Sign() < 0 || x.BitLen() > 128 {
+		return Uint128{}, fmt.Errorf("hdf5: %s overflows a Uint128", x)
+	}
+	lo := new(big.Int).And(x, new(big.Int).Sub(two64, big.NewInt(1)))
+	hi := new(big.Int).Rsh(x, 64)
+	return Uint128{Lo: lo.Uint64(), Hi: hi.Uint64()}, nil
+}
+
+// Big returns x as a big.Int.
+func (x Uint128) Big() *big.Int {
+	b := new(big.Int).Lsh(new(big.Int).SetUint64(x.Hi), 64)
+	return b.Add(b, new(big.Int).SetUint64(x.Lo))
+}
+
+func (x Uint128) String() string {
+	return x.Big().String()
+}
+
+// ParseDecimal parses a decimal number such as "-12.340", keeping the
+// digits after the decimal point as its scale.
+func ParseDecimal(s string) (Decimal, error) {
+	digits, scale := s, 0
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		digits, scale = s[:i]+s[i+1:], len(s)-i-1
+	}
+	x, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("hdf5: invalid decimal %q", s)
+	}
+	u, err := NewInt128(x)
+	if err != nil {
+		return Decimal{}, err
+	}
+	return Decimal{Unscaled: u, Scale: int32(scale)}, nil
+}
+
+func (d Decimal) String() string {
+	s := d.Unscaled.Big().String()
+	if d.Scale <= 0 {
+		return s + strings.Repeat("0", int(-d.Scale))
+	}
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	if pad := int(d.Scale) + 1 - len(s); pad > 0 {
+		s = strings.Repeat("0", pad) + s
+	}
+	s = s[:len(s)-int(d.Scale)] + "." + s[len(s)-int(d.Scale):]
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
@@ -0,0 +1,80 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"fmt"
+)
+
+// --- Properties of integer and float datatypes ---
+
+// SetPrecision sets the number of significant bits of an integer or float
+// datatype, such as 12 for the samples of a 12-bit ADC stored in 16 bits.
+// The significant bits start at the offset set by SetOffset, and the
+// precision plus offset must not exceed the size of the datatype.
+// herr_t H5Tset_precision(hid_t dtype_id, size_t precision)
+func (t *Datatype) SetPrecision(precision uint) error {
+	return h5err(C.H5Tset_precision(t.id, C.size_t(precision)))
+}
+
+// Precision returns the number of significant bits of the datatype.
+// size_t H5Tget_precision(hid_t dtype_id)
+func (t *Datatype) Precision() (uint, error) {
+	precision := C.H5Tget_precision(t.id)
+	if precision == 0 {
+		return 0, fmt.Errorf("hdf5: could not get the precision of the datatype")
+	}
+	return uint(precision), nil
+}
+
+// SetOffset sets the bit offset of the first significant bit of an
+// integer or float datatype. The bits outside the precision are padding.
+// herr_t H5Tset_offset(hid_t dtype_id, size_t offset)
+func (t *Datatype) SetOffset(offset uint) error {
+	return h5err(C.H5Tset_offset(t.id, C.size_t(offset)))
+}
+
+// Offset returns the bit offset of the first significant bit of the
+// datatype.
+// int H5Tget_offset(hid_t dtype_id)
+func (t *Datatype) Offset() (uint, error) {
+	offset := C.H5Tget_offset(t.id)
+	if offset < 0 {
+		return 0, fmt.Errorf("hdf5: could not get the offset of the datatype")
+	}
+	return uint(offset), nil
+}
+
+// SetFields sets the layout of a float datatype as the bit positions of
+// its sign, exponent and mantissa, and the sizes in bits of the exponent
+// and mantissa. All fields must lie within the precision of the datatype.
+// herr_t H5Tset_fields(hid_t dtype_id, size_t spos, size_t epos, size_t esize, size_t mpos, size_t msize)
+func (t *Datatype) SetFields(spos, epos, esize, mpos, msize uint) error {
+	return h5err(C.H5Tset_fields(t.id, C.size_t(spos), C.size_t(epos), C.size_t(esize), C.size_t(mpos), C.size_t(msize)))
+}
+
+// Fields returns the layout of a float datatype, as set by SetFields.
+// herr_t H5Tget_fields(hid_t dtype_id, size_t *spos, size_t *epos, size_t *esize, size_t *mpos, size_t *msize)
+func (t *Datatype) Fields() (spos, epos, esize, mpos, msize uint, err error) {
+	var c_spos, c_epos, c_esize, c_mpos, c_msize C.size_t
+	err = h5err(C.H5Tget_fields(t.id, &c_spos, &c_epos, &c_esize, &c_mpos, &c_msize))
+	return uint(c_spos), uint(c_epos), uint(c_esize), uint(c_mpos), uint(c_msize), err
+}
+
+// SetEbias sets the exponent bias of a float datatype, e.g. 127 for IEEE
+// single precision.
+// herr_t H5Tset_ebias(hid_t dtype_id, size_t ebias)
+func (t *Datatype) SetEbias(ebias uint) error {
+	return h5err(C.H5Tset_ebias(t.id, C.size_t(ebias)))
+}
+
+// Ebias returns the exponent bias of a float datatype.
+// size_t H5Tget_ebias(hid_t dtype_id)
+func (t *Datatype) Ebias() (uint, error) {
+	ebias := C.H5Tget_ebias(t.id)
+	if ebias == 0 {
+		return 0, fmt.Errorf("hdf5: could not get the exponent bias of the datatype")
+	}
+	return uint(ebias), nil
+}
@@ -0,0 +1,64 @@
+package hdf5
+
+// #include "hdf5.h"
+// #if H5_VERSION_GE(1,12,0)
+// static herr_t _go_hdf5_reclaim(hid_t type_id, hid_t space_id, void *buf) {
+//   return H5Treclaim(type_id, space_id, H5P_DEFAULT, buf);
+// }
+// #else
+// static herr_t _go_hdf5_reclaim(hid_t type_id, hid_t space_id, void *buf) {
+//   return H5Dvlen_reclaim(type_id, space_id, H5P_DEFAULT, buf);
+// }
+// #endif
+import "C"
+
+import (
+	"unsafe"
+)
+
+// Reclaim releases the memory the library allocated for the
+// variable-length elements of buf, which holds the elements of space in
+// the memory datatype dtype, such as those read by a dataset or attribute
+// of a variable-length datatype. The reads of this package reclaim their
+// own buffers; Reclaim is for buffers read by other means, e.g. with
+// ReadSubset into a slice of C pointers. buf is a slice or a pointer.
+// herr_t H5Treclaim(hid_t type_id, hid_t space_id, hid_t plist_id, void *buf)
+func Reclaim(dtype *Datatype, space *Dataspace, buf interface{}) error {
+	b, err := pinBuffer(buf, false)
+	if err != nil || b.ptr == nil {
+		return err
+	}
+	defer b.unpin()
+	return reclaim(dtype.id, space.id, b.ptr)
+}
+
+// hasVarLen reports whether elements of the datatype t may hold memory
+// allocated by the library when read. H5Tdetect_class does not report
+// variable-length strings as H5T_VLEN, so types holding strings are
+// reclaimed too, for which it costs only a walk of the buffer.
+func hasVarLen(t C.hid_t) bool {
+	return C.H5Tdetect_class(t, C.H5T_VLEN) > 0 || C.H5Tdetect_class(t, C.H5T_STRING) > 0
+}
+
+// reclaim releases the variable-length memory of the elements of space in
+// buf, of the memory datatype t, if there may be any.
+func reclaim(t, space C.hid_t, buf unsafe.Pointer) error {
+	if !hasVarLen(t) {
+		return nil
+	}
+	return h5err(C._go_hdf5_reclaim(t, space, buf))
+}
+
+// reclaimN is reclaim for a buffer of n contiguous elements.
+func reclaimN(t C.hid_t, n int, buf unsafe.Pointer) error {
+	if n == 0 || !hasVarLen(t) {
+		return nil
+	}
+	c_n := C.hsize_t(n)
+	space := C.H5Screate_simple(1, &c_n, nil)
+	if err := h5err(C.herr_t(int(space))); err != nil {
+		return err
+	}
+	defer C.H5Sclose(space)
+	return reclaim(t, space, buf)
+}
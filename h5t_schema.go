@@ -0,0 +1,179 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// SchemaMismatch is a difference between a Go type and the datatype of a
+// dataset, at the compound member Member, a dotted path that is empty for
+// the whole type.
+type SchemaMismatch struct {
+	Member string
+	Reason string
+}
+
+func (m SchemaMismatch) String() string {
+	if m.Member == "" {
+		return m.Reason
+	}
+	return m.Member + ": " + m.Reason
+}
+
+// SchemaError reports the differences between the Go type Type and the
+// datatype of the dataset Name. It matches ErrTypeMismatch.
+type SchemaError struct {
+	Type       reflect.Type
+	Name       string
+	Mismatches []SchemaMismatch
+}
+
+func (e *SchemaError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "hdf5: %s does not match the datatype of %s", e.Type, e.Name)
+	for _, m := range e.Mismatches {
+		b.WriteString("\n\t")
+		b.WriteString(m.String())
+	}
+	return b.String()
+}
+
+// Is reports whether target is ErrTypeMismatch.
+func (e *SchemaError) Is(target error) bool {
+	return target == ErrTypeMismatch
+}
+
+// ValidateSchema compares the Go type T, usually a struct, with the native
+// layout of the datatype of the dataset s: the names, classes, sizes and
+// offsets of their members, recursively. It returns a *SchemaError listing
+// every difference, or nil if values of T are the dataset elements as they
+// are laid out in memory. Reads and writes convert between compound
+// members of the same name, so not every difference makes T unusable, but
+// members missing on either side are silently left unread or unwritten,
+// and copies without conversion, such as those of packet tables, need an
+// exact match.
+func ValidateSchema[T any](s *Dataset) error {
+	return validateSchema(s.id, reflect.TypeOf((*T)(nil)).Elem())
+}
+
+func validateSchema(id C.hid_t, rt reflect.Type) error {
+	mtype, _, err := inferType(reflect.Zero(reflect.PointerTo(rt)).Interface(), nil)
+	if err != nil {
+		return err
+	}
+	ftype := C.H5Dget_type(id)
+	if err := h5err(C.herr_t(int(ftype))); err != nil {
+		return err
+	}
+	defer C.H5Tclose(ftype)
+	native := C.H5Tget_native_type(ftype, C.H5T_DIR_DEFAULT)
+	if err := h5err(C.herr_t(int(native))); err != nil {
+		return err
+	}
+	defer C.H5Tclose(native)
+
+	var mismatches []SchemaMismatch
+	compareTypes("", mtype.id, native, &mismatches)
+	if len(mismatches) == 0 {
+		return nil
+	}
+	return &SchemaError{Type: rt, Name: getName(id), Mismatches: mismatches}
+}
+
+// compareTypes appends the differences between the memory datatype of a
+// Go type, mt, and the native datatype of a file, ft, at member path to
+// out.
+func compareTypes(path string, mt, ft C.hid_t, out *[]SchemaMismatch) {
+	add := func(member, format string, args ...interface{}) {
+		*out = append(*out, SchemaMismatch{Member: member, Reason: fmt.Sprintf(format, args...)})
+	}
+	mclass, fclass := TypeClass(C.H5Tget_class(mt)), TypeClass(C.H5Tget_class(ft))
+	if mclass != fclass {
+		add(path, "%s in Go, %s in the file", mclass, fclass)
+		return
+	}
+	msize, fsize := uint(C.H5Tget_size(mt)), uint(C.H5Tget_size(ft))
+
+	switch mclass {
+	case T_COMPOUND:
+		prefix := path
+		if prefix != "" {
+			prefix += "."
+		}
+		for i := C.uint(0); i < C.uint(C.H5Tget_nmembers(ft)); i++ {
+			c_name := C.H5Tget_member_name(ft, i)
+			name := C.GoString(c_name)
+			idx := C.H5Tget_member_index(mt, c_name)
+			C.free(unsafe.Pointer(c_name))
+			if idx < 0 {
+				add(prefix+name, "missing in Go")
+				continue
+			}
+			moff, foff := uint(C.H5Tget_member_offset(mt, C.uint(idx))), uint(C.H5Tget_member_offset(ft, i))
+			if moff != foff {
+				add(prefix+name, "at offset %d in Go, %d in the file", moff, foff)
+			}
+			mm := C.H5Tget_member_type(mt, C.uint(idx))
+			fm := C.H5Tget_member_type(ft, i)
+			compareTypes(prefix+name, mm, fm, out)
+			C.H5Tclose(mm)
+			C.H5Tclose(fm)
+		}
+		for i := C.uint(0); i < C.uint(C.H5Tget_nmembers(mt)); i++ {
+			c_name := C.H5Tget_member_name(mt, i)
+			if C.H5Tget_member_index(ft, c_name) < 0 {
+				add(prefix+C.GoString(c_name), "missing in the file")
+			}
+			C.free(unsafe.Pointer(c_name))
+		}
+
+	case T_ARRAY:
+		mdims, fdims := arrayTypeDims(mt), arrayTypeDims(ft)
+		if !reflect.DeepEqual(mdims, fdims) {
+			add(path, "array of dimensions %v in Go, %v in the file", mdims, fdims)
+			return
+		}
+		fallthrough
+	case T_VLEN, T_ENUM:
+		mbase, fbase := C.H5Tget_super(mt), C.H5Tget_super(ft)
+		compareTypes(path, mbase, fbase, out)
+		C.H5Tclose(mbase)
+		C.H5Tclose(fbase)
+		return
+
+	case T_STRING:
+		mvar, fvar := C.H5Tis_variable_str(mt) > 0, C.H5Tis_variable_str(ft) > 0
+		if mvar || fvar {
+			// Go strings read and write both kinds of strings.
+			return
+		}
+
+	case T_INTEGER:
+		if msign, fsign := C.H5Tget_sign(mt), C.H5Tget_sign(ft); msign != fsign {
+			signs := map[C.H5T_sign_t]string{C.H5T_SGN_NONE: "unsigned", C.H5T_SGN_2: "signed"}
+			add(path, "%s in Go, %s in the file", signs[msign], signs[fsign])
+		}
+	}
+	if msize != fsize {
+		add(path, "%d bytes in Go, %d in the file", msize, fsize)
+	}
+}
+
+// arrayTypeDims returns the dimensions of the array datatype t.
+func arrayTypeDims(t C.hid_t) []uint {
+	c_dims := make([]C.hsize_t, C.H5Tget_array_ndims(t))
+	if len(c_dims) > 0 {
+		C.H5Tget_array_dims2(t, &c_dims[0])
+	}
+	dims := make([]uint, len(c_dims))
+	for i, d := range c_dims {
+		dims[i] = uint(d)
+	}
+	return dims
+}
@@ -235,10 +235,10 @@ var (
 	//#if H5_SIZEOF_LONG_DOUBLE !=0
 	T_NATIVE_LDOUBLE *Datatype = NewDatatype(C._go_hdf5_H5T_NATIVE_LDOUBLE(), nil)
 	//#endif
-	T_NATIVE_B8     *Datatype = NewDatatype(C._go_hdf5_H5T_NATIVE_B8(), nil)
-	T_NATIVE_B16    *Datatype = NewDatatype(C._go_hdf5_H5T_NATIVE_B16(), nil)
-	T_NATIVE_B32    *Datatype = NewDatatype(C._go_hdf5_H5T_NATIVE_B32(), nil)
-	T_NATIVE_B64    *Datatype = NewDatatype(C._go_hdf5_H5T_NATIVE_B64(), nil)
+	T_NATIVE_B8     *Datatype = NewDatatype(C._go_hdf5_H5T_NATIVE_B8(), _go_uint8_t)
+	T_NATIVE_B16    *Datatype = NewDatatype(C._go_hdf5_H5T_NATIVE_B16(), _go_uint16_t)
+	T_NATIVE_B32    *Datatype = NewDatatype(C._go_hdf5_H5T_NATIVE_B32(), _go_uint32_t)
+	T_NATIVE_B64    *Datatype = NewDatatype(C._go_hdf5_H5T_NATIVE_B64(), _go_uint64_t)
 	T_NATIVE_OPAQUE *Datatype = NewDatatype(C._go_hdf5_H5T_NATIVE_OPAQUE(), nil)
 	T_NATIVE_HSIZE  *Datatype = NewDatatype(C._go_hdf5_H5T_NATIVE_HSIZE(), nil)
 	T_NATIVE_HSSIZE *Datatype = NewDatatype(C._go_hdf5_H5T_NATIVE_HSSIZE(), nil)
@@ -0,0 +1,181 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+import "C"
+
+import (
+	"bytes"
+	"reflect"
+	"unsafe"
+)
+
+// StringPad is the way fixed-length strings shorter than their datatype
+// are terminated and padded.
+type StringPad C.H5T_str_t
+
+const (
+	T_STR_ERROR    StringPad = -1 // error
+	T_STR_NULLTERM StringPad = 0  // null terminated, as in C
+	T_STR_NULLPAD  StringPad = 1  // padded with nulls
+	T_STR_SPACEPAD StringPad = 2  // padded with spaces, as in Fortran
+)
+
+// NewStringType creates a string datatype of size bytes, or a
+// variable-length one if size is 0, with the character set cset and, for
+// fixed-length strings, the padding pad.
+func NewStringType(size uint, cset CharSet, pad StringPad) (*Datatype, error) {
+	t, err := T_C_S1.Copy()
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		size = uint(h5t_VARIABLE)
+	}
+	if err := t.SetSize(size); err != nil {
+		t.Close()
+		return nil, err
+	}
+	if err := t.SetCharSet(cset); err != nil {
+		t.Close()
+		return nil, err
+	}
+	if err := t.SetStrPad(pad); err != nil {
+		t.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// SetCharSet sets the character set of a string datatype.
+// herr_t H5Tset_cset(hid_t dtype_id, H5T_cset_t cset)
+func (t *Datatype) SetCharSet(cset CharSet) error {
+	return h5err(C.H5Tset_cset(t.id, C.H5T_cset_t(cset)))
+}
+
+// CharSet returns the character set of a string datatype.
+// H5T_cset_t H5Tget_cset(hid_t dtype_id)
+func (t *Datatype) CharSet() CharSet {
+	return CharSet(C.H5Tget_cset(t.id))
+}
+
+// SetStrPad sets the padding of a fixed-length string datatype.
+// herr_t H5Tset_strpad(hid_t dtype_id, H5T_str_t strpad)
+func (t *Datatype) SetStrPad(pad StringPad) error {
+	return h5err(C.H5Tset_strpad(t.id, C.H5T_str_t(pad)))
+}
+
+// StrPad returns the padding of a fixed-length string datatype.
+// H5T_str_t H5Tget_strpad(hid_t dtype_id)
+func (t *Datatype) StrPad() StringPad {
+	return StringPad(C.H5Tget_strpad(t.id))
+}
+
+// IsVariableStr returns whether the datatype is a variable-length string.
+// htri_t H5Tis_variable_str(hid_t dtype_id)
+func (t *Datatype) IsVariableStr() bool {
+	return C.H5Tis_variable_str(t.id) > 0
+}
+
+// fixedString returns the string in b, an element of the fixed-length
+// string datatype t, without its terminator or padding.
+func fixedString(t C.hid_t, b []byte) string {
+	if StringPad(C.H5Tget_strpad(t)) == T_STR_SPACEPAD {
+		return string(bytes.TrimRight(b, " "))
+	}
+	if n := bytes.IndexByte(b, 0); n >= 0 {
+		b = b[:n]
+	}
+	return string(b)
+}
+
+// putFixedString stores s in b, an element of the fixed-length string
+// datatype t, truncated to its size and padded as t requires.
+func putFixedString(t C.hid_t, b []byte, s string) {
+	pad := byte(0)
+	if StringPad(C.H5Tget_strpad(t)) == T_STR_SPACEPAD {
+		pad = ' '
+	}
+	for i := copy(b, s); i < len(b); i++ {
+		b[i] = pad
+	}
+}
+
+// stringBuffer holds strings in the memory layout of a string datatype:
+// C strings for a variable-length datatype and padded elements of the
+// datatype's size for a fixed-length one.
+type stringBuffer struct {
+	dtype *Datatype
+	vlen  []*C.char
+	fixed []byte
+}
+
+func newStringBuffer(n int, dtype *Datatype) *stringBuffer {
+	b := &stringBuffer{dtype: dtype}
+	if C.H5Tis_variable_str(dtype.id) > 0 {
+		b.vlen = make([]*C.char, n)
+	} else {
+		b.fixed = getScratch(n * int(dtype.Size()))
+	}
+	return b
+}
+
+// encodeStrings returns a buffer holding the strings of the slice v, which
+// must be released with free once written.
+func encodeStrings(v reflect.Value, dtype *Datatype) *stringBuffer {
+	b := newStringBuffer(v.Len(), dtype)
+	size := int(dtype.Size())
+	for i := 0; i < v.Len(); i++ {
+		if b.vlen != nil {
+			b.vlen[i] = C.CString(v.Index(i).String())
+		} else {
+			putFixedString(dtype.id, b.fixed[i*size:(i+1)*size], v.Index(i).String())
+		}
+	}
+	return b
+}
+
+func (b *stringBuffer) ptr() unsafe.Pointer {
+	if b.vlen != nil {
+		return unsafe.Pointer(&b.vlen[0])
+	}
+	return unsafe.Pointer(&b.fixed[0])
+}
+
+// free releases the C strings of a buffer made by encodeStrings, and the
+// buffer itself.
+func (b *stringBuffer) free() {
+	for i, p := range b.vlen {
+		C.free(unsafe.Pointer(p))
+		b.vlen[i] = nil
+	}
+	b.release()
+}
+
+// release gives the scratch buffer of fixed-length strings back for reuse.
+func (b *stringBuffer) release() {
+	if b.fixed != nil {
+		putScratch(b.fixed)
+		b.fixed = nil
+	}
+}
+
+// decode stores the strings read into the buffer in the slice v, and
+// reclaims the C strings allocated by the library.
+func (b *stringBuffer) decode(v reflect.Value) {
+	if b.vlen != nil {
+		for i, p := range b.vlen {
+			if p != nil {
+				v.Index(i).SetString(C.GoString(p))
+			} else {
+				v.Index(i).SetString("")
+			}
+		}
+		reclaimN(b.dtype.id, len(b.vlen), b.ptr())
+		return
+	}
+	size := int(b.dtype.Size())
+	for i := 0; i < v.Len(); i++ {
+		v.Index(i).SetString(fixedString(b.dtype.id, b.fixed[i*size:(i+1)*size]))
+	}
+}
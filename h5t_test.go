@@ -1,6 +1,9 @@
 package hdf5
 
 import (
+	"math/big"
+	"os"
+	"reflect"
 	"testing"
 )
 
@@ -27,6 +30,206 @@ func TestSimpleDatatypes(t *testing.T) {
 	}
 }
 
+func TestDatatypeUtilities(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	dspace, err := CreateSimpleDataspace([]uint{4}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("counts", T_STD_I32LE, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	ftype, err := dset.Type()
+	if err != nil {
+		t.Fatalf("Type failed: %s", err)
+	}
+	defer ftype.Close()
+	if !ftype.Equal(T_STD_I32LE) {
+		t.Errorf("the datatype of the dataset is not T_STD_I32LE")
+	}
+	if ftype.Equal(T_STD_I64LE) || ftype.Equal(nil) {
+		t.Errorf("the datatype of the dataset equals a different datatype")
+	}
+	if ftype.Committed() {
+		t.Errorf("the datatype of the dataset is committed")
+	}
+
+	c, err := ftype.Copy()
+	if err != nil {
+		t.Fatalf("Copy failed: %s", err)
+	}
+	if !c.Equal(ftype) {
+		t.Errorf("the copy differs from the original")
+	}
+	if err := c.SetSize(8); err != nil {
+		t.Fatalf("SetSize failed: %s", err)
+	}
+	if ftype.Size() != 4 || c.Equal(ftype) {
+		t.Errorf("modifying the copy changed the original")
+	}
+	if err := c.Lock(); err != nil {
+		t.Fatalf("Lock failed: %s", err)
+	}
+	if err := c.SetSize(4); err == nil {
+		t.Errorf("expected an error modifying a locked datatype")
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("Close of a locked datatype failed: %s", err)
+	}
+}
+
+func TestNumericProperties(t *testing.T) {
+	if spos, epos, esize, mpos, msize, err := T_IEEE_F32LE.Fields(); err != nil || spos != 31 || epos != 23 || esize != 8 || mpos != 0 || msize != 23 {
+		t.Errorf("Fields returned %d, %d, %d, %d, %d, %v", spos, epos, esize, mpos, msize, err)
+	}
+	if ebias, err := T_IEEE_F32LE.Ebias(); err != nil || ebias != 127 {
+		t.Errorf("Ebias returned %d, %v", ebias, err)
+	}
+
+	// 12-bit samples in the upper bits of 16-bit words.
+	adc, err := T_STD_U16LE.Copy()
+	if err != nil {
+		t.Fatalf("Copy failed: %s", err)
+	}
+	defer adc.Close()
+	if err := adc.SetPrecision(12); err != nil {
+		t.Fatalf("SetPrecision failed: %s", err)
+	}
+	if err := adc.SetOffset(4); err != nil {
+		t.Fatalf("SetOffset failed: %s", err)
+	}
+	if p, err := adc.Precision(); err != nil || p != 12 {
+		t.Errorf("Precision returned %d, %v", p, err)
+	}
+	if o, err := adc.Offset(); err != nil || o != 4 {
+		t.Errorf("Offset returned %d, %v", o, err)
+	}
+	if err := adc.SetOffset(8); err == nil {
+		t.Errorf("expected an error for bits past the size of the datatype")
+	}
+
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	samples := []uint16{0, 1, 2048, 4095}
+	dspace, err := CreateSimpleDataspace([]uint{uint(len(samples))}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("samples", adc, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	if err := dset.Write(samples, T_NATIVE_UINT16); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	got := make([]uint16, len(samples))
+	if err := dset.Read(got, T_NATIVE_UINT16); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, samples) {
+		t.Errorf("Read returned %v, want %v", got, samples)
+	}
+}
+
+func TestBitfield(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	words := []uint16{0x0001, 0x8004, 0x0ff0}
+	dspace, err := CreateSimpleDataspace([]uint{uint(len(words))}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("status", T_STD_B16LE, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	if err := dset.Write(words, nil); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	got := make([]uint16, len(words))
+	if err := dset.Read(got, nil); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, words) {
+		t.Errorf("Read returned %v, want %v", got, words)
+	}
+	if all, _, err := dset.ReadAll(); err != nil || !reflect.DeepEqual(all, words) {
+		t.Errorf("ReadAll returned %v, %v", all, err)
+	}
+	if all, _, err := ReadAllAs[uint16](dset); err != nil || !reflect.DeepEqual(all, words) {
+		t.Errorf("ReadAllAs returned %v, %v", all, err)
+	}
+
+	type sample struct {
+		Status uint16 `hdf5:"status,bitfield"`
+		Count  int32  `hdf5:"count"`
+	}
+	dtype, err := NewDatatypeFromValue(sample{}).Copy()
+	if err != nil {
+		t.Fatalf("Copy failed: %s", err)
+	}
+	defer dtype.Close()
+	ct := &CompoundType{*dtype}
+	if mt, err := ct.MemberType(0); err != nil || mt.Class() != T_BITFIELD {
+		t.Fatalf("member status is not a bitfield")
+	}
+	samples := []sample{{0x8001, 7}, {0x0002, -1}, {0, 0}}
+	sset, err := f.CreateDataset("samples", dtype, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer sset.Close()
+	if err := sset.Write(samples, nil); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	back := make([]sample, len(samples))
+	if err := sset.Read(back, nil); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if !reflect.DeepEqual(back, samples) {
+		t.Errorf("Read returned %v, want %v", back, samples)
+	}
+
+	overflow, mode := Bit(15), BitMask{Offset: 4, Width: 8}
+	if !overflow.IsSet(uint64(words[1])) || overflow.IsSet(uint64(words[0])) {
+		t.Errorf("IsSet returned the wrong flags")
+	}
+	if got := mode.Get(uint64(words[2])); got != 0xff {
+		t.Errorf("Get returned %#x, want 0xff", got)
+	}
+	if got := mode.Set(uint64(words[2]), 0x12); got != 0x0120 {
+		t.Errorf("Set returned %#x, want 0x120", got)
+	}
+	if m := (BitMask{Offset: 0, Width: 64}).Mask(); m != ^uint64(0) {
+		t.Errorf("Mask returned %#x for 64 bits", m)
+	}
+	if _, err := NewBitfieldType(3); err == nil {
+		t.Errorf("expected an error for a 3-byte bitfield")
+	}
+}
+
 // Test for array datatypes. Checks that the number of dimensions is correct.
 func TestArrayDatatype(t *testing.T) {
 	tests := map[int]interface{}{
@@ -109,3 +312,561 @@ func TestStructDatatype(t *testing.T) {
 		}
 	}
 }
+
+func TestDatatypeText(t *testing.T) {
+	dt, err := NewDatatypeFromText("H5T_STD_I32LE")
+	if err != nil {
+		t.Fatalf("NewDatatypeFromText failed: %s", err)
+	}
+	if !dt.Equal(T_STD_I32LE) {
+		t.Errorf("parsed datatype is not H5T_STD_I32LE")
+	}
+	if text, err := dt.Text(); err != nil {
+		t.Fatalf("Text failed: %s", err)
+	} else if text != "H5T_STD_I32LE" {
+		t.Errorf("wrong text: got %q, want %q", text, "H5T_STD_I32LE")
+	}
+
+	// round trip a compound type
+	cdt := NewDatatypeFromValue(struct {
+		a int32
+		b [2]float64
+	}{})
+	text, err := cdt.Text()
+	if err != nil {
+		t.Fatalf("Text failed: %s", err)
+	}
+	dt, err = NewDatatypeFromText(text)
+	if err != nil {
+		t.Fatalf("NewDatatypeFromText(%q) failed: %s", text, err)
+	}
+	if !dt.Equal(cdt) {
+		t.Errorf("round-tripped datatype differs from the original: %q", text)
+	}
+
+	if _, err := NewDatatypeFromText("H5T_NOT_A_TYPE"); err == nil {
+		t.Errorf("expected an error parsing an invalid description")
+	}
+}
+
+func TestNestedStructDatatype(t *testing.T) {
+	type position struct {
+		X, Y, Z float64
+	}
+	type track struct {
+		ID  int32
+		Pos position
+		Dir position
+	}
+	dt := CompoundType{*NewDatatypeFromValue(track{})}
+	mt, err := dt.MemberType(1)
+	if err != nil {
+		t.Fatalf("MemberType failed: %s", err)
+	}
+	pos := CompoundType{*mt}
+	if pos.Class() != T_COMPOUND || pos.NMembers() != 3 || pos.MemberName(2) != "Z" {
+		t.Errorf("wrong nested member: class %v, %d members", pos.Class(), pos.NMembers())
+	}
+	if zt, err := pos.MemberType(2); err != nil || zt.Class() != T_FLOAT {
+		t.Errorf("MemberType returned %v, %v", zt, err)
+	}
+
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{2}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("tracks", &dt.Datatype, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	tracks := []track{{1, position{1, 2, 3}, position{0, 0, 1}}, {2, position{4, 5, 6}, position{1, 0, 0}}}
+	if err := dset.Write(tracks, &dt.Datatype); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	got := make([]track, 2)
+	if err := dset.Read(got, &dt.Datatype); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, tracks) {
+		t.Errorf("Read returned %v, want %v", got, tracks)
+	}
+
+	ftype, err := dset.Type()
+	if err != nil {
+		t.Fatalf("Type failed: %s", err)
+	}
+	defer ftype.Close()
+	if mt, err := (&CompoundType{*ftype}).MemberType(2); err != nil || mt.Class() != T_COMPOUND {
+		t.Errorf("MemberType of the file datatype returned %v, %v", mt, err)
+	}
+}
+
+func TestFlattenedStructDatatype(t *testing.T) {
+	type header struct {
+		Run, Event int32
+	}
+	type position struct {
+		X, Y float64
+	}
+	type hit struct {
+		header `hdf5:",flatten"`
+		Pos    position `hdf5:"pos,prefix=pos_"`
+		Energy float32  `hdf5:"energy"`
+	}
+	dt := CompoundType{*NewDatatypeFromValue(hit{})}
+	names := []string{"Run", "Event", "pos_X", "pos_Y", "energy"}
+	if dt.NMembers() != len(names) {
+		t.Fatalf("wrong number of members: got %d, want %d", dt.NMembers(), len(names))
+	}
+	for idx, name := range names {
+		if dt.MemberName(idx) != name {
+			t.Errorf("wrong name: got %q, want %q", dt.MemberName(idx), name)
+		}
+	}
+	if mt, err := dt.MemberType(3); err != nil || mt.Class() != T_FLOAT || mt.rt != reflect.TypeOf(float64(0)) {
+		t.Errorf("MemberType returned %v, %v", mt, err)
+	}
+
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{2}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("hits", &dt.Datatype, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	hits := []hit{
+		{header{1, 10}, position{0.5, 1.5}, 2},
+		{header{1, 11}, position{2.5, 3.5}, 4},
+	}
+	if err := dset.Write(hits, nil); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	got := make([]hit, 2)
+	if err := dset.Read(got, nil); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, hits) {
+		t.Errorf("Read returned %v, want %v", got, hits)
+	}
+}
+
+func TestArrayMembers(t *testing.T) {
+	type sample struct {
+		ID     int32
+		Weight [4]float32
+		Tag    [8]byte
+		Grid   [2][3]int16
+	}
+	dt := CompoundType{*NewDatatypeFromValue(sample{})}
+	for idx, dims := range map[int][]int{1: {4}, 2: {8}, 3: {2, 3}} {
+		if dt.MemberClass(idx) != T_ARRAY {
+			t.Errorf("member %d: wrong TypeClass: got %v, want %v", idx, dt.MemberClass(idx), T_ARRAY)
+			continue
+		}
+		mt, err := dt.MemberType(idx)
+		if err != nil {
+			t.Fatalf("MemberType failed: %s", err)
+		}
+		if got := (&ArrayType{*mt}).ArrayDims(); !reflect.DeepEqual(got, dims) {
+			t.Errorf("member %d: wrong dims: got %v, want %v", idx, got, dims)
+		}
+	}
+	if size := dt.Size(); size != uint(reflect.TypeOf(sample{}).Size()) {
+		t.Errorf("wrong size: got %d, want %d", size, reflect.TypeOf(sample{}).Size())
+	}
+
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	table, err := f.CreateTableFrom("samples", sample{}, 4, 0)
+	if err != nil {
+		t.Fatalf("CreateTableFrom failed: %s", err)
+	}
+	defer table.Close()
+	samples := []sample{
+		{1, [4]float32{1, 2, 3, 4}, [8]byte{'a', 'b'}, [2][3]int16{{1, 2, 3}, {4, 5, 6}}},
+		{2, [4]float32{5, 6, 7, 8}, [8]byte{'c'}, [2][3]int16{{7, 8, 9}, {10, 11, 12}}},
+	}
+	if err := table.Append(samples); err != nil {
+		t.Fatalf("Append failed: %s", err)
+	}
+	got := make([]sample, 2)
+	if err := table.ReadPackets(0, 2, got); err != nil {
+		t.Fatalf("ReadPackets failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, samples) {
+		t.Errorf("ReadPackets returned %v, want %v", got, samples)
+	}
+}
+
+func TestStringType(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{2}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+
+	// A Fortran writer's space-padded strings.
+	fortran, err := NewStringType(6, T_CSET_ASCII, T_STR_SPACEPAD)
+	if err != nil {
+		t.Fatalf("NewStringType failed: %s", err)
+	}
+	defer fortran.Close()
+	if fortran.StrPad() != T_STR_SPACEPAD || fortran.CharSet() != T_CSET_ASCII || fortran.Size() != 6 {
+		t.Errorf("wrong string type: pad %v, cset %v, size %d", fortran.StrPad(), fortran.CharSet(), fortran.Size())
+	}
+	dset, err := f.CreateDataset("units", fortran, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	if err := dset.Write([]string{"m", "kg"}, nil); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	got := make([]string, 2)
+	if err := dset.Read(got, nil); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, []string{"m", "kg"}) {
+		t.Errorf("Read returned %q", got)
+	}
+
+	// Reading into null-padded memory converts the padding.
+	nullpad, err := NewStringType(6, T_CSET_ASCII, T_STR_NULLPAD)
+	if err != nil {
+		t.Fatalf("NewStringType failed: %s", err)
+	}
+	defer nullpad.Close()
+	if err := dset.Read(got, nullpad); err != nil || !reflect.DeepEqual(got, []string{"m", "kg"}) {
+		t.Errorf("Read returned %q, %v", got, err)
+	}
+
+	utf8, err := NewStringType(0, T_CSET_UTF8, T_STR_NULLTERM)
+	if err != nil {
+		t.Fatalf("NewStringType failed: %s", err)
+	}
+	defer utf8.Close()
+	if !utf8.IsVariableStr() || utf8.CharSet() != T_CSET_UTF8 {
+		t.Errorf("wrong variable-length string type")
+	}
+}
+
+func TestFloat16(t *testing.T) {
+	for _, c := range []struct {
+		f    float32
+		bits Float16
+	}{
+		{0, 0x0000},
+		{1, 0x3c00},
+		{-2, 0xc000},
+		{0.5, 0x3800},
+		{65504, 0x7bff},
+		{1e6, 0x7c00},
+		{5.9604645e-08, 0x0001},
+		{6.1035156e-05, 0x0400},
+		{1.0009766, 0x3c01},
+		{1.00048828125, 0x3c00}, // halfway, rounds to even
+	} {
+		if got := NewFloat16(c.f); got != c.bits {
+			t.Errorf("NewFloat16(%v) = %#04x, want %#04x", c.f, uint16(got), uint16(c.bits))
+		}
+		if c.f < 65520 && c.f != 1.00048828125 {
+			if got := c.bits.Float32(); got != c.f {
+				t.Errorf("%#04x.Float32() = %v, want %v", uint16(c.bits), got, c.f)
+			}
+		}
+	}
+
+	if T_IEEE_F16LE.Size() != 2 || T_IEEE_F16LE.Class() != T_FLOAT {
+		t.Fatalf("wrong float16 datatype: size %d, class %v", T_IEEE_F16LE.Size(), T_IEEE_F16LE.Class())
+	}
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{3}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("half", T_IEEE_F16LE, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	if err := dset.Write([]float64{1.5, -0.25, 1024}, T_NATIVE_DOUBLE); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	halves := make([]Float16, 3)
+	if err := dset.Read(halves, nil); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if want := []Float16{NewFloat16(1.5), NewFloat16(-0.25), NewFloat16(1024)}; !reflect.DeepEqual(halves, want) {
+		t.Errorf("Read returned %v, want %v", halves, want)
+	}
+	raw := make([]uint16, 3)
+	if err := dset.Read(raw, T_NATIVE_FLOAT16); err != nil || raw[0] != 0x3e00 {
+		t.Errorf("Read returned %#04x, %v", raw, err)
+	}
+	data, _, err := dset.ReadAll()
+	if err != nil || !reflect.DeepEqual(data, halves) {
+		t.Errorf("ReadAll returned %v, %v", data, err)
+	}
+}
+
+func TestInt128(t *testing.T) {
+	big1, _ := new(big.Int).SetString("-170141183460469231731687303715884105728", 10)
+	big2, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	for _, x := range []*big.Int{big.NewInt(0), big.NewInt(-1), big.NewInt(42), big1, big2} {
+		v, err := NewInt128(x)
+		if err != nil {
+			t.Fatalf("NewInt128(%s) failed: %s", x, err)
+		}
+		if v.Big().Cmp(x) != 0 {
+			t.Errorf("NewInt128(%s).Big() = %s", x, v)
+		}
+	}
+	if _, err := NewInt128(new(big.Int).Neg(new(big.Int).Sub(big1, big.NewInt(1)))); err == nil {
+		t.Errorf("expected an error for 2^127")
+	}
+	if _, err := NewUint128(big.NewInt(-1)); err == nil {
+		t.Errorf("expected an error for a negative Uint128")
+	}
+
+	for _, s := range []string{"0", "-12.340", "0.005", "-0.5", "123456789012345678901234567.89"} {
+		d, err := ParseDecimal(s)
+		if err != nil {
+			t.Fatalf("ParseDecimal(%q) failed: %s", s, err)
+		}
+		if d.String() != s {
+			t.Errorf("ParseDecimal(%q).String() = %q", s, d)
+		}
+	}
+	if _, err := ParseDecimal("1.2.3"); err == nil {
+		t.Errorf("expected an error parsing 1.2.3")
+	}
+
+	type account struct {
+		ID      Int128
+		Balance Decimal
+	}
+	dt := CompoundType{*NewDatatypeFromValue(account{})}
+	if dt.MemberClass(0) != T_INTEGER || dt.MemberClass(1) != T_COMPOUND {
+		t.Fatalf("wrong member classes %v, %v", dt.MemberClass(0), dt.MemberClass(1))
+	}
+
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+	dspace, err := CreateSimpleDataspace([]uint{2}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("accounts", &dt.Datatype, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	id, _ := NewInt128(big2)
+	balance, _ := ParseDecimal("-1234.56")
+	accounts := []account{{id, balance}, {Int128{Lo: 7}, Decimal{Scale: 2}}}
+	if err := dset.Write(accounts, nil); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	got := make([]account, 2)
+	if err := dset.Read(got, nil); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if !reflect.DeepEqual(got, accounts) {
+		t.Errorf("Read returned %v, want %v", got, accounts)
+	}
+
+	// Small 128-bit values convert to 64-bit members.
+	type narrow struct {
+		ID int64
+	}
+	ids := make([]narrow, 2)
+	if err := dset.Read(ids, nil); err != nil || ids[1].ID != 7 {
+		t.Errorf("Read returned %v, %v", ids, err)
+	}
+}
+
+func TestReclaim(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	names := []string{"alpha", "", "gamma"}
+	dspace, err := CreateSimpleDataspace([]uint{uint(len(names))}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("names", T_GO_STRING, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+	if err := dset.Write(names, T_GO_STRING); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+
+	// Reading into pointers leaves the strings to the caller.
+	ptrs := make([]uintptr, len(names))
+	if err := dset.Read(ptrs, T_GO_STRING); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if ptrs[0] == 0 || ptrs[2] == 0 {
+		t.Fatalf("Read returned null strings: %v", ptrs)
+	}
+	if err := Reclaim(T_GO_STRING, dspace, ptrs); err != nil {
+		t.Errorf("Reclaim failed: %s", err)
+	}
+	if err := Reclaim(T_NATIVE_INT32, dspace, make([]int32, len(names))); err != nil {
+		t.Errorf("Reclaim of fixed-size data failed: %s", err)
+	}
+
+	got, _, err := dset.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll failed: %s", err)
+	}
+	want := []interface{}{"alpha", "", "gamma"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadAll returned %v, want %v", got, want)
+	}
+}
+
+func TestCompoundBuilder(t *testing.T) {
+	type row struct {
+		Flag  int8    `hdf5:"flag"`
+		Value float64 `hdf5:"value"`
+		Count int16   `hdf5:"count"`
+	}
+	b := NewCompoundBuilder(false)
+	b.AddMember("flag", T_NATIVE_INT8).AddMember("value", T_NATIVE_DOUBLE).AddMember("count", T_NATIVE_INT16)
+	aligned, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build failed: %s", err)
+	}
+	defer aligned.Close()
+	if aligned.Size() != 24 || aligned.MemberOffset(1) != 8 || aligned.MemberOffset(2) != 16 {
+		t.Errorf("aligned compound of size %d with offsets %d, %d", aligned.Size(), aligned.MemberOffset(1), aligned.MemberOffset(2))
+	}
+	if !aligned.Equal(NewDatatypeFromValue(row{})) {
+		t.Errorf("aligned compound differs from the datatype of %T", row{})
+	}
+
+	b = NewCompoundBuilder(true)
+	b.AddMember("flag", T_NATIVE_INT8).AddMember("value", T_NATIVE_DOUBLE).AddMember("count", T_NATIVE_INT16)
+	packed, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build failed: %s", err)
+	}
+	defer packed.Close()
+	if packed.Size() != 11 || packed.MemberOffset(1) != 1 || packed.MemberOffset(2) != 9 {
+		t.Errorf("packed compound of size %d with offsets %d, %d", packed.Size(), packed.MemberOffset(1), packed.MemberOffset(2))
+	}
+
+	// nested compounds are aligned to their widest member
+	b = NewCompoundBuilder(false)
+	b.AddMember("tag", T_NATIVE_UINT8).AddMember("row", &aligned.Datatype).AddMember("at", T_NATIVE_INT32, 40)
+	nested, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build failed: %s", err)
+	}
+	defer nested.Close()
+	if nested.MemberOffset(1) != 8 || nested.MemberOffset(2) != 40 || nested.Size() != 48 {
+		t.Errorf("nested compound of size %d with offsets %d, %d", nested.Size(), nested.MemberOffset(1), nested.MemberOffset(2))
+	}
+
+	if _, err := NewCompoundBuilder(true).AddMember("a", T_NATIVE_INT32).AddMember("b", T_NATIVE_INT32, 2).Build(); err == nil {
+		t.Errorf("expected an error for overlapping members")
+	}
+	if _, err := NewCompoundBuilder(true).Build(); err == nil {
+		t.Errorf("expected an error for a compound without members")
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	type reading struct {
+		Sensor int32      `hdf5:"sensor"`
+		Values [3]float64 `hdf5:"values"`
+	}
+	s, err := f.CreateDatasetFromValue("readings", []reading{{1, [3]float64{1, 2, 3}}}, nil)
+	if err != nil {
+		t.Fatalf("CreateDatasetFromValue failed: %s", err)
+	}
+	defer s.Close()
+	if err := ValidateSchema[reading](s); err != nil {
+		t.Errorf("ValidateSchema failed: %s", err)
+	}
+
+	type other struct {
+		Sensor uint64     `hdf5:"sensor"`
+		Values [2]float64 `hdf5:"values"`
+		Label  [8]byte    `hdf5:"label"`
+	}
+	err = ValidateSchema[other](s)
+	serr, ok := err.(*SchemaError)
+	if !ok {
+		t.Fatalf("ValidateSchema returned %v, want a *SchemaError", err)
+	}
+	want := []SchemaMismatch{
+		{"sensor", "unsigned in Go, signed in the file"},
+		{"sensor", "8 bytes in Go, 4 in the file"},
+		{"values", "array of dimensions [2] in Go, [3] in the file"},
+		{"label", "missing in the file"},
+	}
+	if !reflect.DeepEqual(serr.Mismatches, want) {
+		t.Errorf("ValidateSchema found %v, want %v", serr.Mismatches, want)
+	}
+	if serr.Name != "/readings" {
+		t.Errorf("SchemaError names %q", serr.Name)
+	}
+
+	if err := ValidateSchema[float64](s); err == nil {
+		t.Errorf("expected an error validating a float64 against a compound")
+	}
+}
@@ -0,0 +1,497 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// #include <string.h>
+import "C"
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// field is a member of a decoded compound value.
+type field struct {
+	Name  string
+	Value interface{}
+}
+
+// record is a decoded compound value. It keeps the order of the members,
+// in its text form as well as in JSON.
+type record []field
+
+func (r record) String() string {
+	parts := make([]string, len(r))
+	for i, f := range r {
+		parts[i] = f.Name + "=" + formatValue(f.Value)
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+func (r record) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range r {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, err := json.Marshal(f.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.Value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// formatValue formats a value returned by decodeValue for text output.
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return fmt.Sprintf("%q", v)
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, e := range v {
+			parts[i] = formatValue(e)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case nil:
+		return "?"
+	}
+	return fmt.Sprint(v)
+}
+
+// decodeValue converts the element at p, of the memory datatype t, to a Go
+// value: an int64 or uint64 for integers, a float64 for floats, a string
+// for strings and enumeration values, a record for compounds, a
+// []interface{} for arrays and variable-length sequences and a []byte for
+// opaque and bitfield values. Values of other classes are returned as nil.
+func decodeValue(t C.hid_t, p unsafe.Pointer) interface{} {
+	size := int(C.H5Tget_size(t))
+	switch TypeClass(C.H5Tget_class(t)) {
+	case T_INTEGER:
+		signed := C.H5Tget_sign(t) == C.H5T_SGN_2
+		switch size {
+		case 1:
+			if signed {
+				return int64(*(*int8)(p))
+			}
+			return uint64(*(*uint8)(p))
+		case 2:
+			if signed {
+				return int64(*(*int16)(p))
+			}
+			return uint64(*(*uint16)(p))
+		case 4:
+			if signed {
+				return int64(*(*int32)(p))
+			}
+			return uint64(*(*uint32)(p))
+		case 8:
+			if signed {
+				return *(*int64)(p)
+			}
+			return *(*uint64)(p)
+		}
+
+	case T_FLOAT:
+		switch size {
+		case 4:
+			return float64(*(*float32)(p))
+		case 8:
+			return *(*float64)(p)
+		}
+
+	case T_STRING:
+		if C.H5Tis_variable_str(t) > 0 {
+			s := *(**C.char)(p)
+			if s == nil {
+				return ""
+			}
+			return C.GoString(s)
+		}
+		return fixedString(t, unsafe.Slice((*byte)(p), size))
+
+	case T_COMPOUND:
+		n := int(C.H5Tget_nmembers(t))
+		rec := make(record, n)
+		for i := range rec {
+			c_name := C.H5Tget_member_name(t, C.uint(i))
+			rec[i].Name = C.GoString(c_name)
+			C.free(unsafe.Pointer(c_name))
+
+			mtype := C.H5Tget_member_type(t, C.uint(i))
+			offset := uintptr(C.H5Tget_member_offset(t, C.uint(i)))
+			rec[i].Value = decodeValue(mtype, unsafe.Add(p, offset))
+			C.H5Tclose(mtype)
+		}
+		return rec
+
+	case T_ARRAY:
+		rank := int(C.H5Tget_array_ndims(t))
+		if rank <= 0 {
+			return nil
+		}
+		dims := make([]C.hsize_t, rank)
+		C.H5Tget_array_dims2(t, &dims[0])
+		super := C.H5Tget_super(t)
+		defer C.H5Tclose(super)
+		return decodeArray(super, p, dims)
+
+	case T_ENUM:
+		name := make([]C.char, 256)
+		if C.H5Tenum_nameof(t, p, &name[0], C.size_t(len(name))) >= 0 {
+			return C.GoString(&name[0])
+		}
+		super := C.H5Tget_super(t)
+		defer C.H5Tclose(super)
+		return decodeValue(super, p)
+
+	case T_VLEN:
+		vl := (*C.hvl_t)(p)
+		super := C.H5Tget_super(t)
+		defer C.H5Tclose(super)
+		esize := uintptr(C.H5Tget_size(super))
+		values := make([]interface{}, int(vl.len))
+		for i := range values {
+			values[i] = decodeValue(super, unsafe.Add(vl.p, uintptr(i)*esize))
+		}
+		return values
+
+	case T_OPAQUE, T_BITFIELD:
+		return C.GoBytes(p, C.int(size))
+	}
+	return nil
+}
+
+func decodeArray(t C.hid_t, p unsafe.Pointer, dims []C.hsize_t) interface{} {
+	if len(dims) == 0 {
+		return decodeValue(t, p)
+	}
+	stride := uintptr(C.H5Tget_size(t))
+	for _, d := range dims[1:] {
+		stride *= uintptr(d)
+	}
+	values := make([]interface{}, int(dims[0]))
+	for i := range values {
+		values[i] = decodeArray(t, unsafe.Add(p, uintptr(i)*stride), dims[1:])
+	}
+	return values
+}
+
+// readValues reads n elements with read, converted to the native memory
+// type of the file datatype ftype, and decodes them with decodeValue.
+func readValues(ftype C.hid_t, n int, read func(mtype C.hid_t, buf unsafe.Pointer) error) ([]interface{}, error) {
+	if n == 0 {
+		return []interface{}{}, nil
+	}
+	mtype := C.H5Tget_native_type(ftype, C.H5T_DIR_DEFAULT)
+	if err := h5err(C.herr_t(int(mtype))); err != nil {
+		return nil, err
+	}
+	defer C.H5Tclose(mtype)
+
+	size := int(C.H5Tget_size(mtype))
+	buf := getScratch(n * size)
+	defer putScratch(buf)
+	c_buf := unsafe.Pointer(&buf[0])
+	if err := read(mtype, c_buf); err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, n)
+	for i := range values {
+		values[i] = decodeValue(mtype, unsafe.Add(c_buf, i*size))
+	}
+	reclaimN(mtype, n, c_buf)
+	return values, nil
+}
+
+// encodeValue stores v at p as an element of the memory datatype t. It
+// accepts the values returned by decodeValue as well as those decoded by
+// encoding/json: numbers as float64 or json.Number, compounds as
+// map[string]interface{}, and opaque and bitfield values as base64
+// strings. Memory allocated for variable-length data is appended to allocs
+// and must be released with C.free once the buffer has been written.
+func encodeValue(t C.hid_t, p unsafe.Pointer, v interface{}, allocs *[]unsafe.Pointer) error {
+	size := int(C.H5Tget_size(t))
+	switch TypeClass(C.H5Tget_class(t)) {
+	case T_INTEGER:
+		if C.H5Tget_sign(t) == C.H5T_SGN_2 {
+			i, err := toInt64(v)
+			if err != nil {
+				return err
+			}
+			switch size {
+			case 1:
+				*(*int8)(p) = int8(i)
+			case 2:
+				*(*int16)(p) = int16(i)
+			case 4:
+				*(*int32)(p) = int32(i)
+			case 8:
+				*(*int64)(p) = i
+			default:
+				return fmt.Errorf("unsupported integer size %d", size)
+			}
+			return nil
+		}
+		u, err := toUint64(v)
+		if err != nil {
+			return err
+		}
+		switch size {
+		case 1:
+			*(*uint8)(p) = uint8(u)
+		case 2:
+			*(*uint16)(p) = uint16(u)
+		case 4:
+			*(*uint32)(p) = uint32(u)
+		case 8:
+			*(*uint64)(p) = u
+		default:
+			return fmt.Errorf("unsupported integer size %d", size)
+		}
+		return nil
+
+	case T_FLOAT:
+		f, err := toFloat64(v)
+		if err != nil {
+			return err
+		}
+		switch size {
+		case 4:
+			*(*float32)(p) = float32(f)
+		case 8:
+			*(*float64)(p) = f
+		default:
+			return fmt.Errorf("unsupported float size %d", size)
+		}
+		return nil
+
+	case T_STRING:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("cannot store %T in a string", v)
+		}
+		if C.H5Tis_variable_str(t) > 0 {
+			c_s := C.CString(s)
+			*allocs = append(*allocs, unsafe.Pointer(c_s))
+			*(**C.char)(p) = c_s
+			return nil
+		}
+		putFixedString(t, unsafe.Slice((*byte)(p), size), s)
+		return nil
+
+	case T_COMPOUND:
+		n := int(C.H5Tget_nmembers(t))
+		for i := 0; i < n; i++ {
+			c_name := C.H5Tget_member_name(t, C.uint(i))
+			name := C.GoString(c_name)
+			C.free(unsafe.Pointer(c_name))
+
+			var mv interface{}
+			switch v := v.(type) {
+			case map[string]interface{}:
+				mv = v[name]
+			case record:
+				for _, f := range v {
+					if f.Name == name {
+						mv = f.Value
+					}
+				}
+			default:
+				return fmt.Errorf("cannot store %T in a compound", v)
+			}
+			if mv == nil {
+				continue
+			}
+			mtype := C.H5Tget_member_type(t, C.uint(i))
+			offset := uintptr(C.H5Tget_member_offset(t, C.uint(i)))
+			err := encodeValue(mtype, unsafe.Add(p, offset), mv, allocs)
+			C.H5Tclose(mtype)
+			if err != nil {
+				return fmt.Errorf("member %q: %s", name, err)
+			}
+		}
+		return nil
+
+	case T_ARRAY:
+		rank := int(C.H5Tget_array_ndims(t))
+		if rank <= 0 {
+			return fmt.Errorf("invalid array datatype")
+		}
+		dims := make([]C.hsize_t, rank)
+		C.H5Tget_array_dims2(t, &dims[0])
+		super := C.H5Tget_super(t)
+		defer C.H5Tclose(super)
+		return encodeArray(super, p, dims, v, allocs)
+
+	case T_ENUM:
+		if name, ok := v.(string); ok {
+			c_name := C.CString(name)
+			defer C.free(unsafe.Pointer(c_name))
+			if C.H5Tenum_valueof(t, c_name, p) < 0 {
+				return fmt.Errorf("unknown enumeration value %q", name)
+			}
+			return nil
+		}
+		super := C.H5Tget_super(t)
+		defer C.H5Tclose(super)
+		return encodeValue(super, p, v, allocs)
+
+	case T_VLEN:
+		values, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("cannot store %T in a variable-length sequence", v)
+		}
+		super := C.H5Tget_super(t)
+		defer C.H5Tclose(super)
+		esize := uintptr(C.H5Tget_size(super))
+		vl := (*C.hvl_t)(p)
+		vl.len = C.size_t(len(values))
+		vl.p = nil
+		if len(values) == 0 {
+			return nil
+		}
+		vl.p = C.malloc(C.size_t(uintptr(len(values)) * esize))
+		*allocs = append(*allocs, vl.p)
+		for i, e := range values {
+			if err := encodeValue(super, unsafe.Add(vl.p, uintptr(i)*esize), e, allocs); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case T_OPAQUE, T_BITFIELD:
+		var b []byte
+		switch v := v.(type) {
+		case []byte:
+			b = v
+		case string:
+			var err error
+			if b, err = base64.StdEncoding.DecodeString(v); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("cannot store %T in an opaque value", v)
+		}
+		if len(b) != size {
+			return fmt.Errorf("got %d bytes for a %d-byte value", len(b), size)
+		}
+		copy(unsafe.Slice((*byte)(p), size), b)
+		return nil
+	}
+	return fmt.Errorf("unsupported datatype class %d", int(C.H5Tget_class(t)))
+}
+
+func encodeArray(t C.hid_t, p unsafe.Pointer, dims []C.hsize_t, v interface{}, allocs *[]unsafe.Pointer) error {
+	if len(dims) == 0 {
+		return encodeValue(t, p, v, allocs)
+	}
+	values, ok := v.([]interface{})
+	if !ok || len(values) != int(dims[0]) {
+		return fmt.Errorf("array value does not have %d elements", int(dims[0]))
+	}
+	stride := uintptr(C.H5Tget_size(t))
+	for _, d := range dims[1:] {
+		stride *= uintptr(d)
+	}
+	for i, e := range values {
+		if err := encodeArray(t, unsafe.Add(p, uintptr(i)*stride), dims[1:], e, allocs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeValues encodes values with encodeValue, in the native memory type
+// of the file datatype ftype, and writes them with write.
+func writeValues(ftype C.hid_t, values []interface{}, write func(mtype C.hid_t, buf unsafe.Pointer) error) error {
+	if len(values) == 0 {
+		return nil
+	}
+	mtype := C.H5Tget_native_type(ftype, C.H5T_DIR_DEFAULT)
+	if err := h5err(C.herr_t(int(mtype))); err != nil {
+		return err
+	}
+	defer C.H5Tclose(mtype)
+
+	var allocs []unsafe.Pointer
+	defer func() {
+		for _, p := range allocs {
+			C.free(p)
+		}
+	}()
+	size := int(C.H5Tget_size(mtype))
+	buf := getScratch(len(values) * size)
+	defer putScratch(buf)
+	c_buf := unsafe.Pointer(&buf[0])
+	for i, v := range values {
+		if err := encodeValue(mtype, unsafe.Add(c_buf, i*size), v, &allocs); err != nil {
+			return fmt.Errorf("element %d: %s", i, err)
+		}
+	}
+	return write(mtype, c_buf)
+}
+
+func toFloat64(v interface{}) (float64, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case json.Number:
+		return v.Float64()
+	}
+	return 0, fmt.Errorf("cannot store %T in a float", v)
+}
+
+func toInt64(v interface{}) (int64, error) {
+	switch v := v.(type) {
+	case int64:
+		return v, nil
+	case uint64:
+		return int64(v), nil
+	case int:
+		return int64(v), nil
+	case float64:
+		return int64(v), nil
+	case json.Number:
+		return v.Int64()
+	}
+	return 0, fmt.Errorf("cannot store %T in an integer", v)
+}
+
+func toUint64(v interface{}) (uint64, error) {
+	switch v := v.(type) {
+	case uint64:
+		return v, nil
+	case int64:
+		return uint64(v), nil
+	case int:
+		return uint64(v), nil
+	case float64:
+		return uint64(v), nil
+	case json.Number:
+		return strconv.ParseUint(string(v), 10, 64)
+	}
+	return 0, fmt.Errorf("cannot store %T in an integer", v)
+}
@@ -0,0 +1,188 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include "hdf5_hl.h"
+// #include <stdlib.h>
+// #include <string.h>
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+// ---- HDF5 Table (H5TB) ----
+//
+// The H5TB functions operate on any chunked dataset of compound type,
+// including packet tables, addressed by their name relative to a location.
+
+// tableRecords describes a slice of structs used as an H5TB record buffer.
+type tableRecords struct {
+	v       reflect.Value
+	size    C.size_t
+	names   []string
+	offsets []C.size_t
+	sizes   []C.size_t
+}
+
+func newTableRecords(data interface{}, nrecords int) (*tableRecords, error) {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unsupported type (%T), need a slice of structs", data)
+	}
+	if v.Len() < nrecords {
+		return nil, fmt.Errorf("not enough records in slice (len=%d)", v.Len())
+	}
+	rt := v.Type().Elem()
+	r := &tableRecords{v: v, size: C.size_t(rt.Size())}
+	for _, f := range structFields(rt) {
+		r.names = append(r.names, f.name)
+		r.offsets = append(r.offsets, C.size_t(f.offset))
+		r.sizes = append(r.sizes, C.size_t(f.typ.Size()))
+	}
+	if len(r.names) == 0 {
+		return nil, fmt.Errorf("record type %s has no fields", rt)
+	}
+	return r, nil
+}
+
+func (r *tableRecords) data() unsafe.Pointer {
+	return r.v.UnsafePointer()
+}
+
+// sortByMember orders the record fields like the members of the table's
+// compound type, as the H5TB functions pair the offsets with the matching
+// members in that order. It returns the number of members of the table.
+func (r *tableRecords) sortByMember(id C.hid_t, c_name *C.char) (int, error) {
+	hid := C.H5Dopen2(id, c_name, P_DEFAULT.id)
+	if err := h5err(C.herr_t(int(hid))); err != nil {
+		return 0, err
+	}
+	defer C.H5Dclose(hid)
+	tid := C.H5Dget_type(hid)
+	if err := h5err(C.herr_t(int(tid))); err != nil {
+		return 0, err
+	}
+	defer C.H5Tclose(tid)
+
+	idx := make([]int, len(r.names))
+	for i, name := range r.names {
+		c_field := C.CString(name)
+		idx[i] = int(C.H5Tget_member_index(tid, c_field))
+		C.free(unsafe.Pointer(c_field))
+		if idx[i] < 0 {
+			return 0, fmt.Errorf("table has no field named %q", name)
+		}
+	}
+	sort.Sort(byMember{idx, r})
+	return int(C.H5Tget_nmembers(tid)), nil
+}
+
+type byMember struct {
+	idx []int
+	r   *tableRecords
+}
+
+func (b byMember) Len() int           { return len(b.idx) }
+func (b byMember) Less(i, j int) bool { return b.idx[i] < b.idx[j] }
+func (b byMember) Swap(i, j int) {
+	b.idx[i], b.idx[j] = b.idx[j], b.idx[i]
+	b.r.names[i], b.r.names[j] = b.r.names[j], b.r.names[i]
+	b.r.offsets[i], b.r.offsets[j] = b.r.offsets[j], b.r.offsets[i]
+	b.r.sizes[i], b.r.sizes[j] = b.r.sizes[j], b.r.sizes[i]
+}
+
+// readTableFields reads the fields of data's struct type, matched by name,
+// from nrecords records of a table starting at start.
+// herr_t H5TBread_fields_name( hid_t loc_id, const char *dset_name, const char *field_names, hsize_t start, hsize_t nrecords, size_t type_size, const size_t *field_offset, const size_t *dst_sizes, void *buf )
+func readTableFields(id C.hid_t, name string, start, nrecords int, data interface{}) error {
+	r, err := newTableRecords(data, nrecords)
+	if err != nil {
+		return err
+	}
+	if nrecords == 0 {
+		return nil
+	}
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	if _, err := r.sortByMember(id, c_name); err != nil {
+		return err
+	}
+	c_fields := C.CString(strings.Join(r.names, ","))
+	defer C.free(unsafe.Pointer(c_fields))
+
+	err = h5err(C.H5TBread_fields_name(id, c_name, c_fields,
+		C.hsize_t(start), C.hsize_t(nrecords), r.size,
+		&r.offsets[0], &r.sizes[0], r.data()))
+	return err
+}
+
+// readTableFieldsByIndex reads the fields at the given member indices of a
+// table into the fields of data's struct type, in order.
+// herr_t H5TBread_fields_index( hid_t loc_id, const char *dset_name, hsize_t nfields, const int *field_index, hsize_t start, hsize_t nrecords, size_t type_size, const size_t *field_offset, const size_t *dst_sizes, void *buf )
+func readTableFieldsByIndex(id C.hid_t, name string, fields []int, start, nrecords int, data interface{}) error {
+	r, err := newTableRecords(data, nrecords)
+	if err != nil {
+		return err
+	}
+	if len(fields) != len(r.names) {
+		return fmt.Errorf("got %d field indices for a record with %d fields", len(fields), len(r.names))
+	}
+	if nrecords == 0 {
+		return nil
+	}
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	c_index := make([]C.int, len(fields))
+	for i, idx := range fields {
+		c_index[i] = C.int(idx)
+	}
+	err = h5err(C.H5TBread_fields_index(id, c_name, C.hsize_t(len(fields)), &c_index[0],
+		C.hsize_t(start), C.hsize_t(nrecords), r.size,
+		&r.offsets[0], &r.sizes[0], r.data()))
+	return err
+}
+
+// insertTableRecords inserts the records in data, a slice of structs holding
+// every field of the table, before record start.
+// herr_t H5TBinsert_record( hid_t loc_id, const char *dset_name, hsize_t start, hsize_t nrecords, size_t type_size, const size_t *field_offset, const size_t *field_sizes, void *data )
+func insertTableRecords(id C.hid_t, name string, start int, data interface{}) error {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("unsupported type (%T), need a slice of structs", data)
+	}
+	r, err := newTableRecords(data, v.Len())
+	if err != nil {
+		return err
+	}
+	nrecords := v.Len()
+	if nrecords == 0 {
+		return nil
+	}
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	nfields, err := r.sortByMember(id, c_name)
+	if err != nil {
+		return err
+	}
+	if nfields != len(r.names) {
+		return fmt.Errorf("record type %s has %d fields, table has %d", v.Type().Elem(), len(r.names), nfields)
+	}
+	err = h5err(C.H5TBinsert_record(id, c_name, C.hsize_t(start), C.hsize_t(nrecords),
+		r.size, &r.offsets[0], &r.sizes[0], r.data()))
+	return err
+}
+
+// deleteTableRecords removes nrecords records of a table starting at start.
+// herr_t H5TBdelete_record( hid_t loc_id, const char *dset_name, hsize_t start, hsize_t nrecords )
+func deleteTableRecords(id C.hid_t, name string, start, nrecords int) error {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+	return h5err(C.H5TBdelete_record(id, c_name, C.hsize_t(start), C.hsize_t(nrecords)))
+}
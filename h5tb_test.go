@@ -0,0 +1,131 @@
+package hdf5
+
+import (
+	"os"
+	"testing"
+)
+
+type wide_t struct {
+	a int32
+	b float64
+	c int64
+	d float32
+}
+
+func createWideTable(t *testing.T, f *File, n int) []wide_t {
+	table, err := f.CreateTableFrom(TABLE_NAME, wide_t{}, 10, 0)
+	if err != nil {
+		t.Fatalf("CreateTableFrom failed: %s", err)
+	}
+	defer table.Close()
+
+	data := make([]wide_t, n)
+	for i := range data {
+		data[i] = wide_t{int32(i), float64(i) * 1.5, int64(-i), float32(i) / 4}
+	}
+	if err := table.Append(data); err != nil {
+		t.Fatalf("Append failed: %s", err)
+	}
+	return data
+}
+
+func TestReadTableFields(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	data := createWideTable(t, f, NRECORDS)
+
+	// fields in a different order than in the table
+	type narrow_t struct {
+		d float32
+		a int32
+	}
+	got := make([]narrow_t, NRECORDS-2)
+	if err := f.ReadTableFields(TABLE_NAME, 2, len(got), got); err != nil {
+		t.Fatalf("ReadTableFields failed: %s", err)
+	}
+	for i, r := range got {
+		want := data[i+2]
+		if r.a != want.a || r.d != want.d {
+			t.Errorf("record %d: got %v, want a=%v d=%v", i+2, r, want.a, want.d)
+		}
+	}
+
+	type by_index_t struct {
+		c int64
+		b float64
+	}
+	got2 := make([]by_index_t, NRECORDS)
+	if err := f.ReadTableFieldsByIndex(TABLE_NAME, []int{2, 1}, 0, NRECORDS, got2); err != nil {
+		t.Fatalf("ReadTableFieldsByIndex failed: %s", err)
+	}
+	for i, r := range got2 {
+		if r.c != data[i].c || r.b != data[i].b {
+			t.Errorf("record %d: got %v, want c=%v b=%v", i, r, data[i].c, data[i].b)
+		}
+	}
+
+	type unknown_t struct {
+		z int32
+	}
+	if err := f.ReadTableFields(TABLE_NAME, 0, 1, make([]unknown_t, 1)); err == nil {
+		t.Errorf("expected an error reading an unknown field")
+	}
+	if err := f.ReadTableFields(TABLE_NAME, 0, 2, make([]narrow_t, 1)); err == nil {
+		t.Errorf("expected an error reading into a short slice")
+	}
+}
+
+func TestInsertDeleteTableRecords(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	data := createWideTable(t, f, 4)
+
+	extra := []wide_t{{100, 100, 100, 100}, {101, 101, 101, 101}}
+	if err := f.InsertTableRecords(TABLE_NAME, 1, extra); err != nil {
+		t.Fatalf("InsertTableRecords failed: %s", err)
+	}
+	want := []wide_t{data[0], extra[0], extra[1], data[1], data[2], data[3]}
+	got := make([]wide_t, len(want))
+	if err := f.ReadTableFields(TABLE_NAME, 0, len(got), got); err != nil {
+		t.Fatalf("ReadTableFields failed: %s", err)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("record %d after insert: got %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if err := f.DeleteTableRecords(TABLE_NAME, 0, 3); err != nil {
+		t.Fatalf("DeleteTableRecords failed: %s", err)
+	}
+	want = want[3:]
+	got = make([]wide_t, len(want))
+	if err := f.ReadTableFields(TABLE_NAME, 0, len(got), got); err != nil {
+		t.Fatalf("ReadTableFields failed: %s", err)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("record %d after delete: got %v, want %v", i, got[i], want[i])
+		}
+	}
+	if err := f.ReadTableFields(TABLE_NAME, 0, len(want)+1, make([]wide_t, len(want)+1)); err == nil {
+		t.Errorf("expected an error reading past the end of the table")
+	}
+
+	type partial_t struct {
+		a int32
+	}
+	if err := f.InsertTableRecords(TABLE_NAME, 0, []partial_t{{1}}); err == nil {
+		t.Errorf("expected an error inserting partial records")
+	}
+}
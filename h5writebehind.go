@@ -0,0 +1,209 @@
+package hdf5
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// WriteBehind queues dataset writes and packet table appends and performs
+// them on a background goroutine, so that the caller does not wait for
+// the library. The data is copied when it is queued, so its buffer may be
+// reused at once. Consecutive appends to the same table are batched into
+// a single H5PTappend.
+//
+// The first error of a queued write is returned by Sync, Close and every
+// later Write or Append, and the writes queued after it are dropped. Unless
+// the HDF5 library is built thread-safe, the caller must not use the
+// library from other goroutines while writes are queued, i.e. between
+// queueing and Sync.
+type WriteBehind struct {
+	ops  chan writeOp
+	done chan struct{}
+
+	sendMu sync.RWMutex // held to send on ops, and to close it
+	closed bool
+
+	mu  sync.Mutex
+	err error
+}
+
+// writeOp is a queued write: an append of rows to table, a call of write,
+// or a barrier closing synced once the writes before it are done.
+type writeOp struct {
+	table  *Table
+	rows   reflect.Value
+	write  func() error
+	synced chan struct{}
+}
+
+// NewWriteBehind returns a WriteBehind that queues up to depth writes
+// before Write and Append wait for the queue to drain.
+func NewWriteBehind(depth int) *WriteBehind {
+	if depth < 1 {
+		depth = 1
+	}
+	w := &WriteBehind{
+		ops:  make(chan writeOp, depth),
+		done: make(chan struct{}),
+	}
+	go w.loop()
+	return w
+}
+
+func (w *WriteBehind) loop() {
+	defer close(w.done)
+	var next *writeOp
+	for {
+		var op writeOp
+		if next != nil {
+			op, next = *next, nil
+		} else {
+			var ok bool
+			if op, ok = <-w.ops; !ok {
+				return
+			}
+		}
+		switch {
+		case op.synced != nil:
+			close(op.synced)
+		case op.table != nil:
+			// Batch the appends to the same table that are already queued.
+			rows := op.rows
+		batch:
+			for {
+				select {
+				case o, ok := <-w.ops:
+					if !ok {
+						break batch
+					}
+					if o.table != op.table || o.rows.Type() != rows.Type() {
+						next = &o
+						break batch
+					}
+					rows = reflect.AppendSlice(rows, o.rows)
+				default:
+					break batch
+				}
+			}
+			w.run(func() error { return op.table.Append(rows.Interface()) })
+		default:
+			w.run(op.write)
+		}
+	}
+}
+
+// run calls write unless a previous write failed, and keeps its error.
+func (w *WriteBehind) run(write func() error) {
+	w.mu.Lock()
+	failed := w.err != nil
+	w.mu.Unlock()
+	if failed {
+		return
+	}
+	if err := write(); err != nil {
+		w.mu.Lock()
+		w.err = err
+		w.mu.Unlock()
+	}
+}
+
+func (w *WriteBehind) queue(op writeOp) error {
+	w.mu.Lock()
+	err := w.err
+	w.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	w.sendMu.RLock()
+	defer w.sendMu.RUnlock()
+	if w.closed {
+		return fmt.Errorf("hdf5: write behind a closed WriteBehind")
+	}
+	w.ops <- op
+	return nil
+}
+
+// copyData returns a copy of data, a slice, array or pointer, or the value
+// itself, which the caller may then modify.
+func copyData(data interface{}) interface{} {
+	v := reflect.ValueOf(data)
+	switch v.Kind() {
+	case reflect.Slice:
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(c, v)
+		return c.Interface()
+	case reflect.Ptr:
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(v.Elem())
+		return c.Interface()
+	}
+	return data
+}
+
+// Write queues the write of data, a slice or pointer of values of datatype
+// dtype, to the whole dataset s, as Dataset.Write does.
+func (w *WriteBehind) Write(s *Dataset, data interface{}, dtype *Datatype) error {
+	data = copyData(data)
+	return w.queue(writeOp{write: func() error { return s.Write(data, dtype) }})
+}
+
+// WriteAt queues the write of data, a slice or pointer of values of
+// datatype dtype, to the block of the dataset s of dimensions count that
+// starts at offset, as Dataset.WriteAt does.
+func (w *WriteBehind) WriteAt(s *Dataset, data interface{}, dtype *Datatype, offset, count []uint) error {
+	data = copyData(data)
+	offset = append([]uint(nil), offset...)
+	count = append([]uint(nil), count...)
+	return w.queue(writeOp{write: func() error { return s.WriteAt(data, dtype, offset, count) }})
+}
+
+// Append queues the append of data, a packet, a pointer to a packet, or a
+// slice or array of packets, to the end of the packet table t.
+func (w *WriteBehind) Append(t *Table, data interface{}) error {
+	v := reflect.ValueOf(data)
+	var rows reflect.Value
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		rows = reflect.MakeSlice(reflect.SliceOf(v.Type().Elem()), v.Len(), v.Len())
+		reflect.Copy(rows, v)
+	case reflect.Ptr:
+		rows = reflect.Append(reflect.MakeSlice(reflect.SliceOf(v.Type().Elem()), 0, 1), v.Elem())
+	default:
+		rows = reflect.Append(reflect.MakeSlice(reflect.SliceOf(v.Type()), 0, 1), v)
+	}
+	if rows.Len() == 0 {
+		return nil
+	}
+	return w.queue(writeOp{table: t, rows: rows})
+}
+
+// Sync waits until the writes queued so far are done, and returns the
+// first error of a queued write.
+func (w *WriteBehind) Sync() error {
+	synced := make(chan struct{})
+	if err := w.queue(writeOp{synced: synced}); err != nil {
+		return err
+	}
+	<-synced
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// Close performs the queued writes, stops the background goroutine and
+// returns the first error of a queued write. It does not close the
+// datasets and tables written to.
+func (w *WriteBehind) Close() error {
+	w.sendMu.Lock()
+	closed := w.closed
+	if !closed {
+		w.closed = true
+		close(w.ops)
+	}
+	w.sendMu.Unlock()
+	<-w.done
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
@@ -0,0 +1,89 @@
+package hdf5
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestWriteBehind(t *testing.T) {
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	defer f.Close()
+
+	table, err := f.CreateTableFrom(TABLE_NAME, sample_t{}, 10, 0)
+	if err != nil {
+		t.Fatalf("CreateTableFrom failed: %s", err)
+	}
+	defer table.Close()
+	dspace, err := CreateSimpleDataspace([]uint{4}, nil)
+	if err != nil {
+		t.Fatalf("CreateSimpleDataspace failed: %s", err)
+	}
+	defer dspace.Close()
+	dset, err := f.CreateDataset("dset", T_NATIVE_INT32, dspace, P_DEFAULT)
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %s", err)
+	}
+	defer dset.Close()
+
+	w := NewWriteBehind(4)
+	buf := []int32{1, 2, 3, 4}
+	if err := w.Write(dset, buf, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	// the queued data is a copy
+	buf[0] = 100
+	if err := w.WriteAt(dset, buf[2:], T_NATIVE_INT32, []uint{2}, []uint{2}); err != nil {
+		t.Fatalf("WriteAt failed: %s", err)
+	}
+	for i := 0; i < NRECORDS; i++ {
+		s := sample_t{int32(i), float64(i)}
+		if err := w.Append(table, &s); err != nil {
+			t.Fatalf("Append failed: %s", err)
+		}
+	}
+	if err := w.Sync(); err != nil {
+		t.Fatalf("Sync failed: %s", err)
+	}
+
+	got := make([]int32, 4)
+	if err := dset.Read(got, T_NATIVE_INT32); err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if want := []int32{1, 2, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Read returned %v, want %v", got, want)
+	}
+	if n, err := table.NumPackets(); err != nil || n != NRECORDS {
+		t.Errorf("NumPackets returned %d, %v, want %d", n, err, NRECORDS)
+	}
+	recs := make([]sample_t, NRECORDS)
+	if err := table.ReadPackets(0, NRECORDS, recs); err != nil {
+		t.Fatalf("ReadPackets failed: %s", err)
+	}
+	for i, s := range recs {
+		if s.id != int32(i) {
+			t.Errorf("packet %d has id %d", i, s.id)
+		}
+	}
+
+	// a failed write is reported by Sync and later writes
+	if err := w.WriteAt(dset, buf, T_NATIVE_INT32, []uint{2}, []uint{4}); err != nil {
+		t.Fatalf("WriteAt failed: %s", err)
+	}
+	if err := w.Sync(); err == nil {
+		t.Errorf("expected an error writing past the end of the dataset")
+	}
+	if err := w.Append(table, sample_t{}); err == nil {
+		t.Errorf("expected the error of the failed write")
+	}
+	if err := w.Close(); err == nil {
+		t.Errorf("expected the error of the failed write")
+	}
+	if err := w.Close(); err == nil {
+		t.Errorf("expected the error of the failed write")
+	}
+}
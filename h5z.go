@@ -0,0 +1,144 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// #if H5_VERSION_GE(1,10,1)
+// static herr_t _go_hdf5_H5PLappend(const char *search_path) { return H5PLappend(search_path); }
+// static herr_t _go_hdf5_H5PLprepend(const char *search_path) { return H5PLprepend(search_path); }
+// static herr_t _go_hdf5_H5PLremove(unsigned int index) { return H5PLremove(index); }
+// static ssize_t _go_hdf5_H5PLget(unsigned int index, char *path_buf, size_t buf_size) { return H5PLget(index, path_buf, buf_size); }
+// static herr_t _go_hdf5_H5PLsize(unsigned int *num_paths) { return H5PLsize(num_paths); }
+// #else
+// static herr_t _go_hdf5_H5PLappend(const char *search_path) { return -1; }
+// static herr_t _go_hdf5_H5PLprepend(const char *search_path) { return -1; }
+// static herr_t _go_hdf5_H5PLremove(unsigned int index) { return -1; }
+// static ssize_t _go_hdf5_H5PLget(unsigned int index, char *path_buf, size_t buf_size) { return -1; }
+// static herr_t _go_hdf5_H5PLsize(unsigned int *num_paths) { return -1; }
+// #endif
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// --- Filters and filter plugins ---
+
+// Filter identifies a filter of the filter pipeline, such as a compression
+// method. Filters above 255 are plugins registered with The HDF Group.
+type Filter C.H5Z_filter_t
+
+const (
+	Z_FILTER_DEFLATE     Filter = 1 // deflate (gzip) compression
+	Z_FILTER_SHUFFLE     Filter = 2 // byte shuffling
+	Z_FILTER_FLETCHER32  Filter = 3 // Fletcher32 checksums
+	Z_FILTER_SZIP        Filter = 4 // szip compression
+	Z_FILTER_NBIT        Filter = 5 // n-bit packing
+	Z_FILTER_SCALEOFFSET Filter = 6 // scale and offset packing
+)
+
+// Filter configuration flags returned by FilterInfo.
+const (
+	Z_FILTER_CONFIG_ENCODE_ENABLED uint = 0x0001 // the filter can encode, to write
+	Z_FILTER_CONFIG_DECODE_ENABLED uint = 0x0002 // the filter can decode, to read
+)
+
+// FilterAvailable returns whether the filter f is available, either built
+// in or, for plugins, loadable from the plugin path.
+// htri_t H5Zfilter_avail(H5Z_filter_t id)
+func FilterAvailable(f Filter) (bool, error) {
+	o := C.H5Zfilter_avail(C.H5Z_filter_t(f))
+	if err := h5err(C.herr_t(o)); err != nil {
+		return false, err
+	}
+	return o > 0, nil
+}
+
+// FilterInfo returns the configuration of the available filter f, as a
+// combination of Z_FILTER_CONFIG_ENCODE_ENABLED and
+// Z_FILTER_CONFIG_DECODE_ENABLED.
+// herr_t H5Zget_filter_info(H5Z_filter_t filter, unsigned int *filter_config_flags)
+func FilterInfo(f Filter) (uint, error) {
+	var flags C.uint
+	err := h5err(C.H5Zget_filter_info(C.H5Z_filter_t(f), &flags))
+	return uint(flags), err
+}
+
+// SzipAvailable returns whether the library can decode szip compressed
+// data, to read it, and encode it, to write it. Builds with the szip
+// library under its decode-only license read szip without writing it.
+func SzipAvailable() (decode, encode bool, err error) {
+	ok, err := FilterAvailable(Z_FILTER_SZIP)
+	if err != nil || !ok {
+		return false, false, err
+	}
+	flags, err := FilterInfo(Z_FILTER_SZIP)
+	if err != nil {
+		return false, false, err
+	}
+	return flags&Z_FILTER_CONFIG_DECODE_ENABLED != 0, flags&Z_FILTER_CONFIG_ENCODE_ENABLED != 0, nil
+}
+
+// AppendPluginPath adds path to the end of the directories the library
+// loads filter plugins from, which start with those of the HDF5_PLUGIN_PATH
+// environment variable. It needs HDF5 1.10.1.
+// herr_t H5PLappend(const char *search_path)
+func AppendPluginPath(path string) error {
+	if err := requireVersion("H5PLappend", 1, 10, 1); err != nil {
+		return err
+	}
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	return h5err(C._go_hdf5_H5PLappend(c_path))
+}
+
+// PrependPluginPath adds path to the start of the directories the library
+// loads filter plugins from. It needs HDF5 1.10.1.
+// herr_t H5PLprepend(const char *search_path)
+func PrependPluginPath(path string) error {
+	if err := requireVersion("H5PLprepend", 1, 10, 1); err != nil {
+		return err
+	}
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	return h5err(C._go_hdf5_H5PLprepend(c_path))
+}
+
+// RemovePluginPath removes the directory at position idx from the plugin
+// path. It needs HDF5 1.10.1.
+// herr_t H5PLremove(unsigned int index)
+func RemovePluginPath(idx uint) error {
+	if err := requireVersion("H5PLremove", 1, 10, 1); err != nil {
+		return err
+	}
+	return h5err(C._go_hdf5_H5PLremove(C.uint(idx)))
+}
+
+// PluginPaths returns the directories the library loads filter plugins
+// from, in search order. It needs HDF5 1.10.1.
+// herr_t H5PLsize(unsigned int *num_paths)
+// ssize_t H5PLget(unsigned int index, char *path_buf, size_t buf_size)
+func PluginPaths() ([]string, error) {
+	if err := requireVersion("H5PLsize", 1, 10, 1); err != nil {
+		return nil, err
+	}
+	var n C.uint
+	if err := h5err(C._go_hdf5_H5PLsize(&n)); err != nil {
+		return nil, err
+	}
+	paths := make([]string, int(n))
+	for i := range paths {
+		size := C._go_hdf5_H5PLget(C.uint(i), nil, 0)
+		if size < 0 {
+			return nil, fmt.Errorf("could not get plugin path %d", i)
+		}
+		buf := make([]C.char, size+1)
+		if C._go_hdf5_H5PLget(C.uint(i), &buf[0], C.size_t(size)+1) < 0 {
+			return nil, fmt.Errorf("could not get plugin path %d", i)
+		}
+		paths[i] = C.GoString(&buf[0])
+	}
+	return paths, nil
+}
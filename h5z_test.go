@@ -0,0 +1,87 @@
+package hdf5
+
+import (
+	"testing"
+)
+
+func TestFilterAvailable(t *testing.T) {
+	ok, err := FilterAvailable(Z_FILTER_DEFLATE)
+	if err != nil {
+		t.Fatalf("FilterAvailable failed: %s", err)
+	}
+	if !ok {
+		t.Skip("deflate is not built in")
+	}
+	flags, err := FilterInfo(Z_FILTER_DEFLATE)
+	if err != nil {
+		t.Fatalf("FilterInfo failed: %s", err)
+	}
+	want := Z_FILTER_CONFIG_ENCODE_ENABLED | Z_FILTER_CONFIG_DECODE_ENABLED
+	if flags&want != want {
+		t.Errorf("FilterInfo returned %#x", flags)
+	}
+	if ok, err := FilterAvailable(Filter(31999)); err != nil || ok {
+		t.Errorf("FilterAvailable returned %v, %v for an unknown filter", ok, err)
+	}
+}
+
+func TestPluginPaths(t *testing.T) {
+	if err := requireVersion("", 1, 10, 1); err != nil {
+		if _, err := PluginPaths(); err == nil {
+			t.Errorf("expected an error from libraries before 1.10.1")
+		}
+		t.Skip(err)
+	}
+
+	before, err := PluginPaths()
+	if err != nil {
+		t.Fatalf("PluginPaths failed: %s", err)
+	}
+	if err := PrependPluginPath("/opt/vendor/hdf5/plugins"); err != nil {
+		t.Fatalf("PrependPluginPath failed: %s", err)
+	}
+	if err := AppendPluginPath("/tmp/plugins"); err != nil {
+		t.Fatalf("AppendPluginPath failed: %s", err)
+	}
+	paths, err := PluginPaths()
+	if err != nil {
+		t.Fatalf("PluginPaths failed: %s", err)
+	}
+	if len(paths) != len(before)+2 || paths[0] != "/opt/vendor/hdf5/plugins" || paths[len(paths)-1] != "/tmp/plugins" {
+		t.Errorf("PluginPaths returned %q", paths)
+	}
+	if err := RemovePluginPath(uint(len(paths) - 1)); err != nil {
+		t.Errorf("RemovePluginPath failed: %s", err)
+	}
+	if err := RemovePluginPath(0); err != nil {
+		t.Errorf("RemovePluginPath failed: %s", err)
+	}
+}
+
+func TestSzip(t *testing.T) {
+	decode, encode, err := SzipAvailable()
+	if err != nil {
+		t.Fatalf("SzipAvailable failed: %s", err)
+	}
+	dcpl, err := NewPropList(P_DATASET_CREATE)
+	if err != nil {
+		t.Fatalf("NewPropList failed: %s", err)
+	}
+	defer dcpl.Close()
+	if err := dcpl.SetChunk([]uint{64}); err != nil {
+		t.Fatalf("SetChunk failed: %s", err)
+	}
+	err = dcpl.SetSzip(SZIP_NN_OPTION_MASK, 16)
+	if !encode {
+		if err == nil {
+			t.Errorf("SetSzip succeeded without an encoder")
+		}
+		t.Skipf("szip encoding not available (decoding: %v)", decode)
+	}
+	if err != nil {
+		t.Fatalf("SetSzip failed: %s", err)
+	}
+	if n, err := dcpl.NumFilters(); err != nil || n != 1 {
+		t.Errorf("NumFilters returned %d, %v", n, err)
+	}
+}
@@ -3,6 +3,61 @@ package hdf5
 // #include "hdf5.h"
 // #include <stdlib.h>
 // #include <string.h>
+// static herr_t _go_hdf5_H5is_library_threadsafe(unsigned *is_ts) {
+// #if H5_VERSION_GE(1,8,16)
+//   hbool_t ts = 0;
+//   herr_t err = H5is_library_threadsafe(&ts);
+//   *is_ts = ts;
+//   return err;
+// #elif defined(H5_HAVE_THREADSAFE)
+//   *is_ts = 1;
+//   return 0;
+// #else
+//   *is_ts = 0;
+//   return 0;
+// #endif
+// }
+// enum {
+//   _GO_HDF5_DRIVER_DIRECT = 1 << 0,
+//   _GO_HDF5_DRIVER_MPIO = 1 << 1,
+//   _GO_HDF5_DRIVER_ROS3 = 1 << 2,
+//   _GO_HDF5_DRIVER_HDFS = 1 << 3,
+//   _GO_HDF5_DRIVER_WINDOWS = 1 << 4,
+//   _GO_HDF5_DRIVER_MIRROR = 1 << 5,
+//   _GO_HDF5_DRIVER_SUBFILING = 1 << 6,
+// };
+// static unsigned _go_hdf5_optional_drivers(void) {
+//   unsigned drivers = 0;
+// #ifdef H5_HAVE_DIRECT
+//   drivers |= _GO_HDF5_DRIVER_DIRECT;
+// #endif
+// #ifdef H5_HAVE_PARALLEL
+//   drivers |= _GO_HDF5_DRIVER_MPIO;
+// #endif
+// #ifdef H5_HAVE_ROS3_VFD
+//   drivers |= _GO_HDF5_DRIVER_ROS3;
+// #endif
+// #ifdef H5_HAVE_LIBHDFS
+//   drivers |= _GO_HDF5_DRIVER_HDFS;
+// #endif
+// #ifdef H5_HAVE_WINDOWS
+//   drivers |= _GO_HDF5_DRIVER_WINDOWS;
+// #endif
+// #ifdef H5_HAVE_MIRROR_VFD
+//   drivers |= _GO_HDF5_DRIVER_MIRROR;
+// #endif
+// #ifdef H5_HAVE_SUBFILING_VFD
+//   drivers |= _GO_HDF5_DRIVER_SUBFILING;
+// #endif
+//   return drivers;
+// }
+// #if H5_VERSION_GE(1,10,7)
+// static herr_t _go_hdf5_H5get_free_list_sizes(size_t *reg_size, size_t *arr_size, size_t *blk_size, size_t *fac_size) {
+//   return H5get_free_list_sizes(reg_size, arr_size, blk_size, fac_size);
+// }
+// #else
+// static herr_t _go_hdf5_H5get_free_list_sizes(size_t *reg_size, size_t *arr_size, size_t *blk_size, size_t *fac_size) { return -1; }
+// #endif
 import "C"
 
 import (
@@ -19,19 +74,11 @@ func init() {
 }
 
 // utils
-type hdferror struct {
-	code int
-}
-
-func (h *hdferror) Error() string {
-	return fmt.Sprintf("**hdf5 error** code=%d", h.code)
-}
-
 func h5err(herr C.herr_t) error {
 	if herr >= C.herr_t(0) {
 		return nil
 	}
-	return &hdferror{code: int(herr)}
+	return stackError(int(herr))
 }
 
 // Close flushes all data to disk, closes all open identifiers, and cleans up memory.
@@ -63,11 +110,131 @@ func LibVersion() (Version, error) {
 	return v, err
 }
 
+// headerVersion is the version of the HDF5 headers the package was built
+// against, which bounds the functions it can call.
+var headerVersion = Version{C.H5_VERS_MAJOR, C.H5_VERS_MINOR, C.H5_VERS_RELEASE}
+
+func (v Version) atLeast(major, minor, release uint) bool {
+	if v.Major != major {
+		return v.Major > major
+	}
+	if v.Minor != minor {
+		return v.Minor > minor
+	}
+	return v.Release >= release
+}
+
+// requireVersion returns an error naming fn if the package was built
+// against headers older than major.minor.release.
+func requireVersion(fn string, major, minor, release uint) error {
+	if headerVersion.atLeast(major, minor, release) {
+		return nil
+	}
+	return fmt.Errorf("%s needs HDF5 %d.%d.%d or later, built with %s", fn, major, minor, release, headerVersion)
+}
+
+// ThreadSafe returns whether the library was built thread-safe, so that
+// it can be called from several goroutines at once.
+// herr_t H5is_library_threadsafe(hbool_t *is_ts)
+func ThreadSafe() (bool, error) {
+	var ts C.uint
+	err := h5err(C._go_hdf5_H5is_library_threadsafe(&ts))
+	return ts != 0, err
+}
+
+// Parallel returns whether the library was built with MPI, for parallel
+// I/O with the mpio driver.
+func Parallel() bool {
+	return haveParallel() == nil
+}
+
+// Drivers returns the names of the file drivers of the library, those it
+// always has followed by the optional ones it was built with.
+func Drivers() []string {
+	drivers := []string{"sec2", "stdio", "core", "family", "log", "multi", "split"}
+	if headerVersion.atLeast(1, 12, 0) {
+		drivers = append(drivers, "splitter")
+	}
+	if headerVersion.atLeast(1, 14, 0) {
+		drivers = append(drivers, "onion")
+	}
+	optional := C._go_hdf5_optional_drivers()
+	for _, d := range []struct {
+		bit  C.uint
+		name string
+	}{
+		{C._GO_HDF5_DRIVER_DIRECT, "direct"},
+		{C._GO_HDF5_DRIVER_MPIO, "mpio"},
+		{C._GO_HDF5_DRIVER_ROS3, "ros3"},
+		{C._GO_HDF5_DRIVER_HDFS, "hdfs"},
+		{C._GO_HDF5_DRIVER_WINDOWS, "windows"},
+		{C._GO_HDF5_DRIVER_MIRROR, "mirror"},
+		{C._GO_HDF5_DRIVER_SUBFILING, "subfiling"},
+	} {
+		if optional&d.bit != 0 {
+			drivers = append(drivers, d.name)
+		}
+	}
+	return drivers
+}
+
+// Filters returns the predefined filters available in the library, which
+// may lack szip and, if built without zlib, deflate. Use FilterAvailable
+// for plugins.
+func Filters() ([]Filter, error) {
+	var filters []Filter
+	for _, f := range []Filter{Z_FILTER_DEFLATE, Z_FILTER_SHUFFLE, Z_FILTER_FLETCHER32, Z_FILTER_SZIP, Z_FILTER_NBIT, Z_FILTER_SCALEOFFSET} {
+		ok, err := FilterAvailable(f)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			filters = append(filters, f)
+		}
+	}
+	return filters, nil
+}
+
 // Garbage collects on all free-lists of all types.
 func GarbageCollect() error {
 	return h5err(C.H5garbage_collect())
 }
 
+// FreeListLimits bounds the memory the library keeps on its free lists for
+// reuse, in bytes, for all lists of a kind and for each list. -1 means no
+// limit, the default.
+type FreeListLimits struct {
+	RegularGlobal, RegularList int // fixed-size blocks
+	ArrayGlobal, ArrayList     int // arrays
+	BlockGlobal, BlockList     int // variable-size blocks
+}
+
+// SetFreeListLimits sets the limits of the free lists of the library, which
+// otherwise can grow without bound in long-running processes. Memory above
+// the limits is released when freed.
+// herr_t H5set_free_list_limits(int reg_global_lim, int reg_list_lim, int arr_global_lim, int arr_list_lim, int blk_global_lim, int blk_list_lim)
+func SetFreeListLimits(l FreeListLimits) error {
+	return h5err(C.H5set_free_list_limits(C.int(l.RegularGlobal), C.int(l.RegularList),
+		C.int(l.ArrayGlobal), C.int(l.ArrayList), C.int(l.BlockGlobal), C.int(l.BlockList)))
+}
+
+// FreeListSizes are the bytes on the free lists of the library.
+type FreeListSizes struct {
+	Regular, Array, Block, Factory uint
+}
+
+// GetFreeListSizes returns the bytes on the free lists of the library. It
+// needs HDF5 1.10.7.
+// herr_t H5get_free_list_sizes(size_t *reg_size, size_t *arr_size, size_t *blk_size, size_t *fac_size)
+func GetFreeListSizes() (FreeListSizes, error) {
+	if err := requireVersion("H5get_free_list_sizes", 1, 10, 7); err != nil {
+		return FreeListSizes{}, err
+	}
+	var reg, arr, blk, fac C.size_t
+	err := h5err(C._go_hdf5_H5get_free_list_sizes(&reg, &arr, &blk, &fac))
+	return FreeListSizes{uint(reg), uint(arr), uint(blk), uint(fac)}, err
+}
+
 type Object interface {
 	Name() string
 	Id() int
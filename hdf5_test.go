@@ -1,6 +1,11 @@
 package hdf5
 
-import "testing"
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+)
 
 func TestLibVersion(t *testing.T) {
 	v, err := LibVersion()
@@ -17,3 +22,98 @@ func init() {
 		panic(err)
 	}
 }
+
+func TestCapabilities(t *testing.T) {
+	if _, err := ThreadSafe(); err != nil {
+		t.Errorf("ThreadSafe failed: %s", err)
+	}
+	drivers := Drivers()
+	if len(drivers) < 7 || drivers[0] != "sec2" {
+		t.Errorf("Drivers returned %v", drivers)
+	}
+	hasMPIO := false
+	for _, d := range drivers {
+		hasMPIO = hasMPIO || d == "mpio"
+	}
+	if hasMPIO != Parallel() {
+		t.Errorf("Drivers returned %v, Parallel %v", drivers, Parallel())
+	}
+	filters, err := Filters()
+	if err != nil {
+		t.Fatalf("Filters failed: %s", err)
+	}
+	hasShuffle := false
+	for _, f := range filters {
+		hasShuffle = hasShuffle || f == Z_FILTER_SHUFFLE
+	}
+	if !hasShuffle {
+		t.Errorf("Filters returned %v, without shuffle", filters)
+	}
+}
+
+func TestFreeLists(t *testing.T) {
+	limits := FreeListLimits{1 << 20, 1 << 16, 1 << 20, 1 << 16, 1 << 20, 1 << 16}
+	if err := SetFreeListLimits(limits); err != nil {
+		t.Fatalf("SetFreeListLimits failed: %s", err)
+	}
+	defer SetFreeListLimits(FreeListLimits{-1, -1, -1, -1, -1, -1})
+	if err := GarbageCollect(); err != nil {
+		t.Fatalf("GarbageCollect failed: %s", err)
+	}
+	if err := requireVersion("", 1, 10, 7); err != nil {
+		if _, err := GetFreeListSizes(); err == nil {
+			t.Errorf("expected an error from libraries before 1.10.7")
+		}
+		t.Skip(err)
+	}
+	if _, err := GetFreeListSizes(); err != nil {
+		t.Errorf("GetFreeListSizes failed: %s", err)
+	}
+}
+
+func TestErrorKinds(t *testing.T) {
+	if _, err := OpenFile("no-such-file.h5", F_ACC_RDONLY); !errors.Is(err, ErrNotFound) || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("opening a missing file returned %v", err)
+	}
+
+	f, err := CreateFile(FNAME, F_ACC_TRUNC)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %s", err)
+	}
+	defer os.Remove(FNAME)
+	if _, err := CreateFile(FNAME, F_ACC_EXCL); !errors.Is(err, ErrExists) {
+		t.Errorf("creating an existing file returned %v", err)
+	}
+	g, err := f.CreateGroup("g")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+	g.Close()
+	if _, err := f.CreateGroup("g"); !errors.Is(err, ErrExists) || errors.Is(err, ErrNotFound) {
+		t.Errorf("creating an existing group returned %v", err)
+	}
+	if _, err := f.OpenGroup("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("opening a missing group returned %v", err)
+	}
+	s, err := f.CreateDatasetFromValue("ints", []int32{1, 2, 3}, nil)
+	if err != nil {
+		t.Fatalf("CreateDatasetFromValue failed: %s", err)
+	}
+	if err := s.Write([]string{"a", "b", "c"}, T_GO_STRING); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("writing strings to integers returned %v", err)
+	}
+	if err := ValidateSchema[float32](s); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("ValidateSchema returned %v", err)
+	}
+	s.Close()
+	f.Close()
+
+	f, err = OpenFile(FNAME, F_ACC_RDONLY)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %s", err)
+	}
+	defer f.Close()
+	if _, err := f.CreateGroup("h"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("creating a group in a read-only file returned %v", err)
+	}
+}